@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// GetOffendersArgs are the arguments for calling GetOffenders
+type GetOffendersArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+}
+
+// Offender is a validator that misbehaved recently
+type Offender struct {
+	NodeID string `json:"nodeID"`
+	// Decayed sum of the penalties of the misbehaviors of the validator
+	Score cjson.Float32 `json:"score"`
+	// True if the validator is no longer sampled for queries
+	Benched bool `json:"benched"`
+	// Kind of misbehavior --> number of times the validator misbehaved that way
+	Misbehaviors map[string]cjson.Uint32 `json:"misbehaviors"`
+	// Number of milliseconds since the validator last misbehaved
+	LastMisbehavior cjson.Uint64 `json:"lastMisbehavior"`
+}
+
+// GetOffendersReply is the response from calling GetOffenders
+type GetOffendersReply struct {
+	// Sorted by score, highest first
+	Offenders []Offender `json:"offenders"`
+}
+
+// GetOffenders returns the validators that misbehaved recently on [args.Chain],
+// and whether they are benched from being sampled
+func (service *Admin) GetOffenders(_ *http.Request, args *GetOffendersArgs, reply *GetOffendersReply) error {
+	service.log.Info("Admin: GetOffenders called with Chain: %s", args.Chain)
+
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	offenders, err := service.chainManager.Offenders(chainID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reply.Offenders = make([]Offender, len(offenders))
+	for i, offender := range offenders {
+		misbehaviors := make(map[string]cjson.Uint32)
+		for misbehavior, count := range offender.Counts {
+			if count > 0 {
+				misbehaviors[common.Misbehavior(misbehavior).String()] = cjson.Uint32(count)
+			}
+		}
+		reply.Offenders[i] = Offender{
+			NodeID:          offender.NodeID.PrefixedString(constants.NodeIDPrefix),
+			Score:           cjson.Float32(offender.Score),
+			Benched:         offender.Benched,
+			Misbehaviors:    misbehaviors,
+			LastMisbehavior: cjson.Uint64(now.Sub(offender.LastMisbehavior).Milliseconds()),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// maxProcessing is the maximum number of containers of each kind, of
+	// conflicts of each container, and of polls returned by GetProcessing
+	maxProcessing = 256
+)
+
+// GetProcessingArgs are the arguments for calling GetProcessing
+type GetProcessingArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+}
+
+// ProcessingContainer is a block, vertex or transaction that is processing in
+// consensus
+type ProcessingContainer struct {
+	ID string `json:"id"`
+	// Only set for blocks
+	ParentID string `json:"parentID,omitempty"`
+	// Processing transactions in the container. Only set for vertices.
+	Txs []string `json:"txs,omitempty"`
+
+	Preferred  bool         `json:"preferred"`
+	Rogue      bool         `json:"rogue"`
+	Confidence cjson.Uint32 `json:"confidence"`
+	// Processing containers that conflict with this container
+	Conflicts []string `json:"conflicts,omitempty"`
+	// Number of milliseconds since the container was issued into consensus
+	Age cjson.Uint64 `json:"age"`
+}
+
+// OutstandingPoll is a poll that hasn't finished yet
+type OutstandingPoll struct {
+	RequestID cjson.Uint32 `json:"requestID"`
+	// Number of milliseconds since the poll was issued
+	Age cjson.Uint64 `json:"age"`
+}
+
+// GetProcessingReply is the response from calling GetProcessing
+// The 256 oldest containers of each kind, and the 256 oldest polls, are
+// returned. The numbers of processing containers and of outstanding polls are
+// always exact.
+type GetProcessingReply struct {
+	NumBlocks   cjson.Uint32          `json:"numBlocks"`
+	Blocks      []ProcessingContainer `json:"blocks,omitempty"`
+	NumVertices cjson.Uint32          `json:"numVertices"`
+	Vertices    []ProcessingContainer `json:"vertices,omitempty"`
+	NumTxs      cjson.Uint32          `json:"numTxs"`
+	Txs         []ProcessingContainer `json:"txs,omitempty"`
+	NumPolls    cjson.Uint32          `json:"numPolls"`
+	Polls       []OutstandingPoll     `json:"polls,omitempty"`
+}
+
+// GetProcessing returns the blocks, or the vertices and transactions, that are
+// processing in the consensus of [args.Chain], and its outstanding polls
+func (service *Admin) GetProcessing(_ *http.Request, args *GetProcessingArgs, reply *GetProcessingReply) error {
+	service.log.Info("Admin: GetProcessing called with Chain: %s", args.Chain)
+
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	report, err := service.chainManager.ProcessingReport(chainID, maxProcessing)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reply.NumBlocks = cjson.Uint32(report.NumBlocks)
+	for _, blk := range report.Blocks {
+		reply.Blocks = append(reply.Blocks, ProcessingContainer{
+			ID:         blk.ID.String(),
+			ParentID:   blk.ParentID.String(),
+			Preferred:  blk.Preferred,
+			Rogue:      blk.Rogue,
+			Confidence: cjson.Uint32(blk.Confidence),
+			Conflicts:  idStrings(blk.Conflicts),
+			Age:        cjson.Uint64(now.Sub(blk.Issued).Milliseconds()),
+		})
+	}
+	reply.NumVertices = cjson.Uint32(report.NumVertices)
+	for _, vtx := range report.Vertices {
+		reply.Vertices = append(reply.Vertices, ProcessingContainer{
+			ID:         vtx.ID.String(),
+			Txs:        idStrings(vtx.Txs),
+			Preferred:  vtx.Preferred,
+			Rogue:      vtx.Rogue,
+			Confidence: cjson.Uint32(vtx.Confidence),
+			Age:        cjson.Uint64(now.Sub(vtx.Issued).Milliseconds()),
+		})
+	}
+	reply.NumTxs = cjson.Uint32(report.NumTxs)
+	for _, tx := range report.Txs {
+		reply.Txs = append(reply.Txs, ProcessingContainer{
+			ID:         tx.ID.String(),
+			Preferred:  tx.Preferred,
+			Rogue:      tx.Rogue,
+			Confidence: cjson.Uint32(tx.Confidence),
+			Conflicts:  idStrings(tx.Conflicts),
+			Age:        cjson.Uint64(now.Sub(tx.Issued).Milliseconds()),
+		})
+	}
+	reply.NumPolls = cjson.Uint32(report.NumPolls)
+	for _, poll := range report.Polls {
+		reply.Polls = append(reply.Polls, OutstandingPoll{
+			RequestID: cjson.Uint32(poll.RequestID),
+			Age:       cjson.Uint64(now.Sub(poll.Start).Milliseconds()),
+		})
+	}
+	return nil
+}
+
+// idStrings returns the string representations of [containerIDs]
+func idStrings(containerIDs []ids.ID) []string {
+	if len(containerIDs) == 0 {
+		return nil
+	}
+	strs := make([]string, len(containerIDs))
+	for i, containerID := range containerIDs {
+		strs[i] = containerID.String()
+	}
+	return strs
+}
@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// TraceContainerArgs are the arguments for calling TraceContainer,
+// StopTracingContainer and GetContainerTrace
+type TraceContainerArgs struct {
+	// Alias or ID of the chain the container is in
+	Chain string `json:"chain"`
+	// ID of the block, vertex or transaction
+	ContainerID string `json:"containerID"`
+}
+
+// pollTracer returns the poll tracer of the chain in [args], and the ID of the
+// container in [args]
+func (service *Admin) pollTracer(args *TraceContainerArgs) (*common.PollTracer, ids.ID, error) {
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return nil, ids.ID{}, err
+	}
+	containerID, err := ids.FromString(args.ContainerID)
+	if err != nil {
+		return nil, ids.ID{}, err
+	}
+	tracer, err := service.chainManager.PollTracer(chainID)
+	return tracer, containerID, err
+}
+
+// TraceContainer starts recording every poll the container is part of, and
+// the changes of its state in consensus
+func (service *Admin) TraceContainer(_ *http.Request, args *TraceContainerArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: TraceContainer called with Chain: %s, ContainerID: %s", args.Chain, args.ContainerID)
+
+	tracer, containerID, err := service.pollTracer(args)
+	if err != nil {
+		return err
+	}
+	if err := tracer.Trace(containerID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// StopTracingContainer stops tracing the container and drops its timeline
+func (service *Admin) StopTracingContainer(_ *http.Request, args *TraceContainerArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: StopTracingContainer called with Chain: %s, ContainerID: %s", args.Chain, args.ContainerID)
+
+	tracer, containerID, err := service.pollTracer(args)
+	if err != nil {
+		return err
+	}
+	if err := tracer.StopTracing(containerID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// TraceConsensusState is the state of a traced container in consensus
+type TraceConsensusState struct {
+	Status     string       `json:"status"`
+	Preferred  bool         `json:"preferred"`
+	Confidence cjson.Uint32 `json:"confidence"`
+}
+
+// TraceEvent is an event in the timeline of a traced container
+type TraceEvent struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+
+	RequestID        cjson.Uint32            `json:"requestID,omitempty"`
+	Validators       []string                `json:"validators,omitempty"`
+	Validator        string                  `json:"validator,omitempty"`
+	Votes            map[string]cjson.Uint32 `json:"votes,omitempty"`
+	EarlyTermination bool                    `json:"earlyTermination,omitempty"`
+
+	// Only set for Consensus events
+	Consensus *TraceConsensusState `json:"consensus,omitempty"`
+}
+
+// GetContainerTraceReply is the response from calling GetContainerTrace
+type GetContainerTraceReply struct {
+	// Events recorded for the container, oldest first
+	Timeline []TraceEvent `json:"timeline"`
+}
+
+// GetContainerTrace returns the timeline of the polls of a traced container,
+// and of the changes of its state in consensus
+func (service *Admin) GetContainerTrace(_ *http.Request, args *TraceContainerArgs, reply *GetContainerTraceReply) error {
+	service.log.Info("Admin: GetContainerTrace called with Chain: %s, ContainerID: %s", args.Chain, args.ContainerID)
+
+	tracer, containerID, err := service.pollTracer(args)
+	if err != nil {
+		return err
+	}
+	events, err := tracer.Timeline(containerID)
+	if err != nil {
+		return err
+	}
+
+	reply.Timeline = make([]TraceEvent, len(events))
+	for i, event := range events {
+		reply.Timeline[i] = newTraceEvent(event)
+	}
+	return nil
+}
+
+// newTraceEvent returns the API representation of [event]
+func newTraceEvent(event common.TraceEvent) TraceEvent {
+	reply := TraceEvent{
+		Time: event.Time,
+		Type: event.Type.String(),
+	}
+	if event.Type == common.TraceConsensus {
+		reply.Consensus = &TraceConsensusState{
+			Status:     event.Status.String(),
+			Preferred:  event.Preferred,
+			Confidence: cjson.Uint32(event.Confidence),
+		}
+		return reply
+	}
+
+	reply.RequestID = cjson.Uint32(event.RequestID)
+	for _, vdr := range event.Validators {
+		reply.Validators = append(reply.Validators, vdr.PrefixedString(constants.NodeIDPrefix))
+	}
+	if !event.Validator.IsZero() {
+		reply.Validator = event.Validator.PrefixedString(constants.NodeIDPrefix)
+	}
+	if votes := event.Votes.List(); len(votes) > 0 {
+		reply.Votes = make(map[string]cjson.Uint32, len(votes))
+		for _, vote := range votes {
+			reply.Votes[vote.String()] = cjson.Uint32(event.Votes.Count(vote))
+		}
+	}
+	reply.EarlyTermination = event.EarlyTermination
+	return reply
+}
@@ -163,6 +163,100 @@ func (service *Info) IsBootstrapped(_ *http.Request, args *IsBootstrappedArgs, r
 	return nil
 }
 
+// GetBootstrapStatusArgs are the arguments for calling GetBootstrapStatus
+type GetBootstrapStatusArgs struct {
+	// Alias of the chain
+	// Can also be the string representation of the chain's ID
+	Chain string `json:"chain"`
+}
+
+// GetBootstrapStatusReply are the results from calling GetBootstrapStatus
+type GetBootstrapStatusReply struct {
+	// One of NotStarted, FrontierDiscovery, AcceptedVoting, StateSync,
+	// Fetching, Executing or Finished
+	Phase string `json:"phase"`
+	// Number of containers fetched so far
+	Fetched json.Uint64 `json:"fetched"`
+	// Estimated number of containers that will be fetched
+	EstimatedTotal json.Uint64 `json:"estimatedTotal"`
+	// Number of fetched containers and transactions that haven't been executed
+	JobsRemaining json.Uint64 `json:"jobsRemaining"`
+	// Containers fetched, or jobs executed, per second in the current phase
+	Throughput json.Float32 `json:"throughput"`
+	// Estimated number of seconds until the current phase finishes
+	ETA json.Uint64 `json:"eta"`
+}
+
+// GetBootstrapStatus returns the progress of bootstrapping [args.Chain]
+// Returns an error if the chain doesn't exist
+func (service *Info) GetBootstrapStatus(_ *http.Request, args *GetBootstrapStatusArgs, reply *GetBootstrapStatusReply) error {
+	service.log.Info("Info: GetBootstrapStatus called")
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	status, err := service.chainManager.BootstrapStatus(chainID)
+	if err != nil {
+		return err
+	}
+	reply.Phase = status.Phase.String()
+	reply.Fetched = json.Uint64(status.NumFetched)
+	reply.EstimatedTotal = json.Uint64(status.EstimatedTotal)
+	reply.JobsRemaining = json.Uint64(status.JobsRemaining)
+	reply.Throughput = json.Float32(status.Throughput)
+	reply.ETA = json.Uint64(status.ETA.Seconds())
+	return nil
+}
+
+// GetConsensusParametersArgs are the arguments for calling
+// GetConsensusParameters
+type GetConsensusParametersArgs struct {
+	// Alias of the chain
+	// Can also be the string representation of the chain's ID
+	Chain string `json:"chain"`
+}
+
+// GetConsensusParametersReply are the results from calling
+// GetConsensusParameters
+type GetConsensusParametersReply struct {
+	K                 json.Uint32 `json:"k"`
+	Alpha             json.Uint32 `json:"alpha"`
+	BetaVirtuous      json.Uint32 `json:"betaVirtuous"`
+	BetaRogue         json.Uint32 `json:"betaRogue"`
+	ConcurrentRepolls json.Uint32 `json:"concurrentRepolls"`
+	// Only used by chains that run Avalanche consensus
+	Parents   json.Uint32 `json:"parents"`
+	BatchSize json.Uint32 `json:"batchSize"`
+}
+
+// GetConsensusParameters returns the consensus parameters of [args.Chain]
+// Returns an error if the chain doesn't exist
+func (service *Info) GetConsensusParameters(_ *http.Request, args *GetConsensusParametersArgs, reply *GetConsensusParametersReply) error {
+	service.log.Info("Info: GetConsensusParameters called")
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	params, err := service.chainManager.ConsensusParameters(chainID)
+	if err != nil {
+		return err
+	}
+	reply.K = json.Uint32(params.K)
+	reply.Alpha = json.Uint32(params.Alpha)
+	reply.BetaVirtuous = json.Uint32(params.BetaVirtuous)
+	reply.BetaRogue = json.Uint32(params.BetaRogue)
+	reply.ConcurrentRepolls = json.Uint32(params.ConcurrentRepolls)
+	reply.Parents = json.Uint32(params.Parents)
+	reply.BatchSize = json.Uint32(params.BatchSize)
+	return nil
+}
+
 // GetTxFee returns the transaction fee in nDJTX.
 func (service *Info) GetTxFee(_ *http.Request, args *struct{}, reply *struct {
 	Fee json.Uint64 `json:"txFee"`
@@ -0,0 +1,149 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	avcon "github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+)
+
+// ConsensusParamsOverrides are consensus parameters that replace the ones a
+// chain would otherwise use. Parameters that aren't set are left unchanged.
+type ConsensusParamsOverrides struct {
+	K                 *int `json:"k"`
+	Alpha             *int `json:"alpha"`
+	BetaVirtuous      *int `json:"betaVirtuous"`
+	BetaRogue         *int `json:"betaRogue"`
+	ConcurrentRepolls *int `json:"concurrentRepolls"`
+	Parents           *int `json:"parents"`
+	BatchSize         *int `json:"batchSize"`
+}
+
+// Apply returns [params] with the parameters set in [o] replaced
+func (o *ConsensusParamsOverrides) Apply(params avcon.Parameters) avcon.Parameters {
+	if o.K != nil {
+		params.K = *o.K
+	}
+	if o.Alpha != nil {
+		params.Alpha = *o.Alpha
+	}
+	if o.BetaVirtuous != nil {
+		params.BetaVirtuous = *o.BetaVirtuous
+	}
+	if o.BetaRogue != nil {
+		params.BetaRogue = *o.BetaRogue
+	}
+	if o.ConcurrentRepolls != nil {
+		params.ConcurrentRepolls = *o.ConcurrentRepolls
+	}
+	if o.Parents != nil {
+		params.Parents = *o.Parents
+	}
+	if o.BatchSize != nil {
+		params.BatchSize = *o.BatchSize
+	}
+	return params
+}
+
+// ConsensusConfig specifies the consensus parameters of the chains of some
+// subnets, and of some chains, in place of the node's default parameters
+type ConsensusConfig struct {
+	// Subnet ID --> Parameters used by the chains the subnet validates
+	Subnets map[[32]byte]*ConsensusParamsOverrides
+	// Chain ID --> Parameters used by the chain. These are applied on top of
+	// the parameters of the chain's subnet.
+	Chains map[[32]byte]*ConsensusParamsOverrides
+}
+
+// ParseConsensusConfig parses a consensus config of the form:
+//
+//	{
+//	    "subnets": {
+//	        "<subnetID>": {"k": 3, "alpha": 2}
+//	    },
+//	    "chains": {
+//	        "<chainID>": {"betaVirtuous": 10, "betaRogue": 15}
+//	    }
+//	}
+//
+// Every parameter is optional. The parameters that result from applying each
+// subnet's and each chain's overrides to [defaults] must be valid.
+func ParseConsensusConfig(configBytes []byte, defaults avcon.Parameters) (ConsensusConfig, error) {
+	rawConfig := struct {
+		Subnets map[string]*ConsensusParamsOverrides `json:"subnets"`
+		Chains  map[string]*ConsensusParamsOverrides `json:"chains"`
+	}{}
+	if err := json.Unmarshal(configBytes, &rawConfig); err != nil {
+		return ConsensusConfig{}, fmt.Errorf("couldn't parse consensus config: %w", err)
+	}
+
+	config := ConsensusConfig{
+		Subnets: make(map[[32]byte]*ConsensusParamsOverrides, len(rawConfig.Subnets)),
+		Chains:  make(map[[32]byte]*ConsensusParamsOverrides, len(rawConfig.Chains)),
+	}
+	for subnetIDStr, overrides := range rawConfig.Subnets {
+		subnetID, err := ids.FromString(subnetIDStr)
+		if err != nil {
+			return ConsensusConfig{}, fmt.Errorf("couldn't parse subnet ID %q: %w", subnetIDStr, err)
+		}
+		if overrides == nil {
+			continue
+		}
+		if err := overrides.Apply(defaults).Valid(); err != nil {
+			return ConsensusConfig{}, fmt.Errorf("invalid consensus parameters for subnet %s: %w", subnetID, err)
+		}
+		config.Subnets[subnetID.Key()] = overrides
+	}
+	for chainIDStr, overrides := range rawConfig.Chains {
+		chainID, err := ids.FromString(chainIDStr)
+		if err != nil {
+			return ConsensusConfig{}, fmt.Errorf("couldn't parse chain ID %q: %w", chainIDStr, err)
+		}
+		if overrides == nil {
+			continue
+		}
+		// The chain's subnet isn't known yet, so the parameters are checked
+		// again once the chain is created
+		if err := overrides.Apply(defaults).Valid(); err != nil {
+			return ConsensusConfig{}, fmt.Errorf("invalid consensus parameters for chain %s: %w", chainID, err)
+		}
+		config.Chains[chainID.Key()] = overrides
+	}
+	return config, nil
+}
+
+// Params returns the consensus parameters of the chain [chainID], which is
+// validated by the subnet [subnetID], where [defaults] are the node's default
+// parameters.
+// Returns true if the parameters were specified for the subnet or the chain.
+func (c *ConsensusConfig) Params(defaults avcon.Parameters, subnetID, chainID ids.ID) (avcon.Parameters, bool) {
+	params := defaults
+	subnetOverrides, subnetOK := c.Subnets[subnetID.Key()]
+	if subnetOK {
+		params = subnetOverrides.Apply(params)
+	}
+	chainOverrides, chainOK := c.Chains[chainID.Key()]
+	if chainOK {
+		params = chainOverrides.Apply(params)
+	}
+	return params, subnetOK || chainOK
+}
+
+// ValidateConsensusParams returns an error if [params] aren't valid for a
+// chain validated by [numValidators] validators.
+// If [numValidators] is 0, the size of the validator set isn't known, so only
+// the parameters themselves are checked.
+func ValidateConsensusParams(params avcon.Parameters, numValidators int) error {
+	if err := params.Valid(); err != nil {
+		return err
+	}
+	if numValidators > 0 && params.K > numValidators {
+		return fmt.Errorf("K = %d, validators = %d: Fails the condition that: K <= validators", params.K, numValidators)
+	}
+	return nil
+}
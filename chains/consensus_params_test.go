@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+
+	avcon "github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+)
+
+var defaultConsensusParams = avcon.Parameters{
+	Parameters: snowball.Parameters{
+		K:                 5,
+		Alpha:             4,
+		BetaVirtuous:      20,
+		BetaRogue:         30,
+		ConcurrentRepolls: 1,
+	},
+	Parents:   5,
+	BatchSize: 30,
+}
+
+func TestConsensusConfig(t *testing.T) {
+	subnetID := ids.NewID([32]byte{1})
+	chainID := ids.NewID([32]byte{2})
+	otherChainID := ids.NewID([32]byte{3})
+
+	configBytes := []byte(fmt.Sprintf(
+		`{"subnets":{"%s":{"k":3,"alpha":2}},"chains":{"%s":{"betaVirtuous":10,"betaRogue":15}}}`,
+		subnetID,
+		chainID,
+	))
+	config, err := ParseConsensusConfig(configBytes, defaultConsensusParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params, configured := config.Params(defaultConsensusParams, subnetID, chainID)
+	if !configured {
+		t.Fatalf("should have reported the parameters as configured")
+	}
+	expected := defaultConsensusParams
+	expected.K = 3
+	expected.Alpha = 2
+	expected.BetaVirtuous = 10
+	expected.BetaRogue = 15
+	if params != expected {
+		t.Fatalf("wrong parameters for the chain. Expected %+v, got %+v", expected, params)
+	}
+
+	params, configured = config.Params(defaultConsensusParams, subnetID, otherChainID)
+	if !configured {
+		t.Fatalf("should have reported the parameters as configured")
+	}
+	expected = defaultConsensusParams
+	expected.K = 3
+	expected.Alpha = 2
+	if params != expected {
+		t.Fatalf("wrong parameters for the subnet. Expected %+v, got %+v", expected, params)
+	}
+
+	params, configured = config.Params(defaultConsensusParams, ids.Empty, otherChainID)
+	if configured {
+		t.Fatalf("shouldn't have reported the parameters as configured")
+	}
+	if params != defaultConsensusParams {
+		t.Fatalf("should have used the default parameters but got %+v", params)
+	}
+}
+
+func TestConsensusConfigInvalid(t *testing.T) {
+	subnetID := ids.NewID([32]byte{1})
+
+	configs := []string{
+		`{"subnets":{"notAnID":{"k":3}}}`,
+		fmt.Sprintf(`{"subnets":{"%s":{"k":3}}}`, subnetID), // K < Alpha
+		fmt.Sprintf(`{"chains":{"%s":{"betaRogue":10}}}`, subnetID),
+		`{"subnets":`,
+	}
+	for _, config := range configs {
+		if _, err := ParseConsensusConfig([]byte(config), defaultConsensusParams); err == nil {
+			t.Fatalf("should have failed to parse %s", config)
+		}
+	}
+}
+
+func TestValidateConsensusParams(t *testing.T) {
+	if err := ValidateConsensusParams(defaultConsensusParams, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateConsensusParams(defaultConsensusParams, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateConsensusParams(defaultConsensusParams, 4); err == nil {
+		t.Fatalf("should have failed because K is larger than the number of validators")
+	}
+}
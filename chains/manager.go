@@ -83,6 +83,24 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the progress of bootstrapping the chain with the given ID
+	BootstrapStatus(ids.ID) (common.BootstrapStatus, error)
+
+	// Returns the consensus parameters of the chain with the given ID
+	ConsensusParameters(ids.ID) (avcon.Parameters, error)
+
+	// Returns the tracer of the polls of the chain with the given ID
+	PollTracer(ids.ID) (*common.PollTracer, error)
+
+	// Returns up to [max] of the containers of each kind processing in the
+	// consensus of the chain with the given ID, and up to [max] of its
+	// outstanding polls
+	ProcessingReport(id ids.ID, max int) (common.ProcessingReport, error)
+
+	// Returns the validators that misbehaved recently on the chain with the
+	// given ID
+	Offenders(ids.ID) ([]common.Offender, error)
+
 	Shutdown()
 }
 
@@ -103,6 +121,7 @@ type chain struct {
 	Ctx     *snow.Context
 	VM      interface{}
 	Beacons validators.Set
+	Params  avcon.Parameters
 }
 
 // ManagerConfig ...
@@ -111,6 +130,10 @@ type ManagerConfig struct {
 	MaxNonStakerPendingMsgs uint32
 	StakerMSGPortion        float64
 	StakerCPUPortion        float64
+	BootstrapFetchConfig    common.FetchConfig                  // Tuning parameters of fetching containers while bootstrapping
+	BootstrapStateSync      bool                                // Sync the state of Snowman VMs that support it to a checkpoint while bootstrapping
+	BootstrapCheckpoints    map[[32]byte]smbootstrap.Checkpoint // Chain ID --> Trusted block to sync the state of the chain to
+	Misbehavior             common.MisbehaviorConfig            // Parameters of benching misbehaving validators from being sampled
 	Log                     logging.Logger
 	LogFactory              logging.Factory
 	VMManager               vms.Manager // Manage mappings from vm ID --> vm
@@ -120,6 +143,7 @@ type ManagerConfig struct {
 	Router                  router.Router      // Routes incoming messages to the appropriate chain
 	Net                     network.Network    // Sends consensus messages to other validators
 	ConsensusParams         avcon.Parameters   // The consensus parameters (alpha, beta, etc.) for new chains
+	ConsensusConfig         ConsensusConfig    // The consensus parameters of specific subnets and chains, in place of [ConsensusParams]
 	Validators              validators.Manager // Validators validating on this chain
 	NodeID                  ids.ShortID        // The ID of this node
 	NetworkID               uint32             // ID of the network this node is connected to
@@ -140,6 +164,10 @@ type manager struct {
 
 	registrants []Registrant // Those notified when a chain is created
 
+	// Provides the validator sets of subnets at P-Chain heights. Set once the
+	// P-Chain is created.
+	validatorState validators.State
+
 	unblocked     bool
 	blockedChains []ChainParameters
 
@@ -147,6 +175,9 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[[32]byte]*router.Handler
+	// Key: Chain's ID
+	// Value: The consensus parameters of the chain
+	chainParams map[[32]byte]avcon.Parameters
 }
 
 // New returns a new Manager where:
@@ -158,6 +189,7 @@ func New(config *ManagerConfig) Manager {
 	m := &manager{
 		ManagerConfig: *config,
 		chains:        make(map[[32]byte]*router.Handler),
+		chainParams:   make(map[[32]byte]avcon.Parameters),
 	}
 	m.Initialize()
 	return m
@@ -201,6 +233,7 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	m.chainsLock.Lock()
 	m.chains[chainID] = chain.Handler
+	m.chainParams[chainID] = chain.Params
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
@@ -245,6 +278,7 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 		SNLookup:            m,
 		Namespace:           fmt.Sprintf("%s_%s_vm", constants.PlatformName, primaryAlias),
 		Metrics:             m.ConsensusParams.Metrics,
+		ValidatorState:      m.validatorState,
 	}
 
 	// Get a factory for the vm we want to use on our chain
@@ -260,6 +294,13 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 	}
 	// TODO: Shutdown VM if an error occurs
 
+	// The P-Chain provides the validator sets of the chains created after it
+	if chainParams.ID.Equals(constants.PlatformChainID) {
+		if vdrState, ok := vm.(validators.State); ok {
+			m.validatorState = validators.NewLockedState(&ctx.Lock, vdrState)
+		}
+	}
+
 	fxs := make([]*common.Fx, len(chainParams.FxAliases))
 	for i, fxAlias := range chainParams.FxAliases {
 		fxID, err := m.VMManager.Lookup(fxAlias)
@@ -285,9 +326,6 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 		}
 	}
 
-	consensusParams := m.ConsensusParams
-	consensusParams.Namespace = fmt.Sprintf("%s_%s", constants.PlatformName, primaryAlias)
-
 	// The validators of this blockchain
 	var vdrs validators.Set // Validators validating this blockchain
 	var ok bool
@@ -300,6 +338,22 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 		return nil, fmt.Errorf("couldn't get validator set of subnet with ID %s. The subnet may not exist", chainParams.SubnetID)
 	}
 
+	consensusParams, configured := m.ConsensusConfig.Params(m.ConsensusParams, chainParams.SubnetID, chainParams.ID)
+	consensusParams.Namespace = fmt.Sprintf("%s_%s", constants.PlatformName, primaryAlias)
+	// When staking is disabled, the validators are the peers this node connects
+	// to, so the size of the validator set isn't known yet
+	numValidators := 0
+	if m.StakingEnabled {
+		numValidators = vdrs.Len()
+	}
+	if err := ValidateConsensusParams(consensusParams, numValidators); err != nil {
+		if configured {
+			return nil, fmt.Errorf("invalid consensus parameters for chain %s: %w", chainParams.ID, err)
+		}
+		// The node's default parameters were checked on startup
+		m.Log.Warn("the default consensus parameters don't fit chain %s: %s", chainParams.ID, err)
+	}
+
 	beacons := vdrs
 	if chainParams.CustomBeacons != nil {
 		beacons = chainParams.CustomBeacons
@@ -341,6 +395,8 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 		return nil, fmt.Errorf("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
 	}
 
+	chain.Params = consensusParams
+
 	// Allows messages to be routed to the new chain
 	m.ManagerConfig.Router.AddChain(chain.Handler)
 	// If the X or P Chain panics, do not attempt to recover
@@ -434,16 +490,25 @@ func (m *manager) createAvalancheChain(
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager)
 
+	// Records the misbehavior of validators, and benches them from being
+	// sampled by this chain
+	misbehavior := &common.MisbehaviorLedger{}
+	if err := misbehavior.Initialize(m.Misbehavior, validators, ctx.Log, consensusParams.Namespace, consensusParams.Metrics); err != nil {
+		return nil, fmt.Errorf("error initializing misbehavior ledger: %w", err)
+	}
+
 	// The engine handles consensus
 	engine := &aveng.Transitive{}
 	if err := engine.Initialize(aveng.Config{
 		Config: avbootstrap.Config{
 			Config: common.Config{
-				Ctx:        ctx,
-				Validators: validators,
-				Beacons:    beacons,
-				Alpha:      bootstrapWeight/2 + 1, // must be > 50%
-				Sender:     &sender,
+				Ctx:         ctx,
+				Validators:  misbehavior.Validators(),
+				Beacons:     beacons,
+				Alpha:       bootstrapWeight/2 + 1, // must be > 50%
+				Sender:      &sender,
+				Fetch:       m.BootstrapFetchConfig,
+				Misbehavior: misbehavior,
 			},
 			VtxBlocked: vtxBlocker,
 			TxBlocked:  txBlocker,
@@ -469,6 +534,7 @@ func (m *manager) createAvalancheChain(
 		fmt.Sprintf("%s_handler", consensusParams.Namespace),
 		consensusParams.Metrics,
 	)
+	handler.SetMisbehaviorLedger(misbehavior)
 
 	return &chain{
 		Engine:  engine,
@@ -514,20 +580,33 @@ func (m *manager) createSnowmanChain(
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager)
 
+	// Records the misbehavior of validators, and benches them from being
+	// sampled by this chain
+	misbehavior := &common.MisbehaviorLedger{}
+	if err := misbehavior.Initialize(m.Misbehavior, validators, ctx.Log, consensusParams.Namespace, consensusParams.Metrics); err != nil {
+		return nil, fmt.Errorf("error initializing misbehavior ledger: %w", err)
+	}
+
 	// The engine handles consensus
 	engine := &smeng.Transitive{}
 	if err := engine.Initialize(smeng.Config{
 		Config: smbootstrap.Config{
 			Config: common.Config{
-				Ctx:        ctx,
-				Validators: validators,
-				Beacons:    beacons,
-				Alpha:      bootstrapWeight/2 + 1, // must be > 50%
-				Sender:     &sender,
+				Ctx:         ctx,
+				Validators:  misbehavior.Validators(),
+				Beacons:     beacons,
+				Alpha:       bootstrapWeight/2 + 1, // must be > 50%
+				Sender:      &sender,
+				Fetch:       m.BootstrapFetchConfig,
+				Misbehavior: misbehavior,
 			},
 			Blocked:      blocked,
 			VM:           vm,
 			Bootstrapped: m.unblockChains,
+			StateSync: smbootstrap.StateSyncConfig{
+				Enabled: m.BootstrapStateSync,
+				Trusted: m.BootstrapCheckpoints[ctx.ChainID.Key()],
+			},
 		},
 		Params:    consensusParams,
 		Consensus: &smcon.Topological{},
@@ -548,6 +627,7 @@ func (m *manager) createSnowmanChain(
 		fmt.Sprintf("%s_handler", consensusParams.Namespace),
 		consensusParams.Metrics,
 	)
+	handler.SetMisbehaviorLedger(misbehavior)
 
 	return &chain{
 		Engine:  engine,
@@ -579,6 +659,85 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Engine().IsBootstrapped()
 }
 
+func (m *manager) BootstrapStatus(id ids.ID) (common.BootstrapStatus, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id.Key()]
+	m.chainsLock.Unlock()
+	if !exists {
+		return common.BootstrapStatus{}, errors.New("unknown chain ID")
+	}
+
+	engine := chain.Engine()
+	if reporter, ok := engine.(common.BootstrapStatusReporter); ok {
+		return reporter.BootstrapStatus(), nil
+	}
+	if engine.IsBootstrapped() {
+		return common.BootstrapStatus{Phase: common.PhaseFinished}, nil
+	}
+	return common.BootstrapStatus{}, fmt.Errorf("chain %s doesn't report its bootstrapping progress", id)
+}
+
+func (m *manager) ConsensusParameters(id ids.ID) (avcon.Parameters, error) {
+	m.chainsLock.Lock()
+	defer m.chainsLock.Unlock()
+
+	params, exists := m.chainParams[id.Key()]
+	if !exists {
+		return avcon.Parameters{}, errors.New("unknown chain ID")
+	}
+	return params, nil
+}
+
+func (m *manager) PollTracer(id ids.ID) (*common.PollTracer, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id.Key()]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errors.New("unknown chain ID")
+	}
+
+	traceable, ok := chain.Engine().(common.PollTraceable)
+	if !ok {
+		return nil, fmt.Errorf("chain %s doesn't support tracing polls", id)
+	}
+	return traceable.PollTracer(), nil
+}
+
+func (m *manager) ProcessingReport(id ids.ID, max int) (common.ProcessingReport, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id.Key()]
+	m.chainsLock.Unlock()
+	if !exists {
+		return common.ProcessingReport{}, errors.New("unknown chain ID")
+	}
+
+	reporter, ok := chain.Engine().(common.ProcessingReporter)
+	if !ok {
+		return common.ProcessingReport{}, fmt.Errorf("chain %s doesn't report its processing containers", id)
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return reporter.ProcessingReport(max)
+}
+
+func (m *manager) Offenders(id ids.ID) ([]common.Offender, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id.Key()]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errors.New("unknown chain ID")
+	}
+
+	ledger := chain.MisbehaviorLedger()
+	if ledger == nil {
+		return nil, fmt.Errorf("chain %s doesn't record misbehavior", id)
+	}
+	return ledger.Offenders(), nil
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.ManagerConfig.Router.Shutdown()
@@ -2,7 +2,10 @@ package chains
 
 import (
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+
+	avcon "github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 )
 
 // MockManager implements Manager but does nothing. Always returns nil error.
@@ -53,3 +56,26 @@ func (mm MockManager) SubnetID(ids.ID) (ids.ID, error) { return ids.ID{}, nil }
 
 // IsBootstrapped ...
 func (mm MockManager) IsBootstrapped(ids.ID) bool { return false }
+
+// BootstrapStatus ...
+func (mm MockManager) BootstrapStatus(ids.ID) (common.BootstrapStatus, error) {
+	return common.BootstrapStatus{}, nil
+}
+
+// ConsensusParameters ...
+func (mm MockManager) ConsensusParameters(ids.ID) (avcon.Parameters, error) {
+	return avcon.Parameters{}, nil
+}
+
+// PollTracer ...
+func (mm MockManager) PollTracer(ids.ID) (*common.PollTracer, error) {
+	return &common.PollTracer{}, nil
+}
+
+// ProcessingReport ...
+func (mm MockManager) ProcessingReport(ids.ID, int) (common.ProcessingReport, error) {
+	return common.ProcessingReport{}, nil
+}
+
+// Offenders ...
+func (mm MockManager) Offenders(ids.ID) ([]common.Offender, error) { return nil, nil }
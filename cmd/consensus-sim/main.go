@@ -0,0 +1,47 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// consensus-sim simulates a network of nodes running the snowman, snowstorm or
+// avalanche consensus implementations, and reports how long the honest nodes
+// take to finalize and whether they ever accept conflicting choices.
+//
+// The network is simulated in virtual time: messages have a random latency,
+// may be dropped, and adversaries may not respond or may respond dishonestly.
+// Every random choice the simulator makes is drawn from the seed, so runs of
+// the same seed have the same outcome.
+//
+// It exits with status 1 if a safety violation occurred.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	config, err := parseConfig(os.Args[1:])
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Printf("parsing parameters returned with error %s\n", err)
+		os.Exit(2)
+	}
+
+	results := make([]runResult, config.Runs)
+	for i := range results {
+		seed := config.Seed + int64(i)
+		results[i], err = simulate(config, seed)
+		if err != nil {
+			fmt.Printf("run with seed %d failed with error %s\n", seed, err)
+			os.Exit(2)
+		}
+	}
+
+	r := newReport(results)
+	r.Write(os.Stdout, config)
+	if r.violations > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,431 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+// Prefixes of the IDs of the simulated choices
+const (
+	blockPrefix uint64 = iota
+	inputPrefix
+	txPrefix
+	vertexPrefix
+	heightPrefix
+)
+
+// model describes the choices that the simulated nodes decide between
+type model interface {
+	// newInstance returns a consensus instance that every choice was added to.
+	// The order the choices are added in, and so the instance's initial
+	// preference, depends on [rng].
+	newInstance(params avalanche.Parameters, rng *rand.Rand) (instance, error)
+
+	// biasedVote returns the response of a biased adversary to a query
+	biasedVote() []ids.ID
+
+	// randomVote returns the response of an equivocating adversary to a query
+	randomVote(rng *rand.Rand) []ids.ID
+
+	// conflictKeys returns the keys of the choice [choiceID]. Two choices
+	// conflict if they share a key.
+	conflictKeys(choiceID ids.ID) []ids.ID
+}
+
+// instance is the consensus instance of an honest node
+type instance interface {
+	// vote returns the response of this node to a query
+	vote() []ids.ID
+
+	// recordPoll records the responses to a poll. The i'th response is nil if
+	// the i'th sampled node didn't respond.
+	recordPoll(responses [][]ids.ID) error
+
+	// finalized returns true if every choice was decided
+	finalized() bool
+
+	// accepted returns the choices that were accepted
+	accepted() []ids.ID
+}
+
+// newModel returns the model of the choices described by [config]
+func newModel(config Config, rng *rand.Rand) model {
+	switch config.Engine {
+	case snowmanEngine:
+		return newSnowmanModel(config.Colors, rng)
+	case snowstormEngine:
+		return newTxModel(config.Colors, config.Inputs)
+	default:
+		return newAvalancheModel(config.Colors, config.Inputs, config.Params, rng)
+	}
+}
+
+// snowmanModel is a tree of conflicting blocks
+type snowmanModel struct {
+	genesisID ids.ID
+	blkIDs    []ids.ID
+	// Index of the parent of each block. -1 is the genesis block.
+	parents []int
+	heights []uint64
+}
+
+func newSnowmanModel(numBlocks int, rng *rand.Rand) *snowmanModel {
+	m := &snowmanModel{
+		genesisID: ids.Empty.Prefix(blockPrefix, 0),
+		blkIDs:    make([]ids.ID, numBlocks),
+		parents:   make([]int, numBlocks),
+		heights:   make([]uint64, numBlocks),
+	}
+	for i := range m.blkIDs {
+		m.blkIDs[i] = ids.Empty.Prefix(blockPrefix, uint64(i+1))
+		// Each block is a child of the genesis block or of an earlier block
+		m.parents[i] = rng.Intn(i+1) - 1
+		m.heights[i] = 1
+		if parent := m.parents[i]; parent >= 0 {
+			m.heights[i] = m.heights[parent] + 1
+		}
+	}
+	return m
+}
+
+func (m *snowmanModel) newInstance(params avalanche.Parameters, rng *rand.Rand) (instance, error) {
+	genesis := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     m.genesisID,
+		StatusV: choices.Accepted,
+	}}
+	consensus := &snowman.Topological{}
+	consensus.Initialize(snow.DefaultContextTest(), params.Parameters, m.genesisID)
+
+	// Parents are added before their children, and otherwise the blocks are
+	// added in a random order
+	order := rng.Perm(len(m.blkIDs))
+	sort.SliceStable(order, func(i, j int) bool { return m.heights[order[i]] < m.heights[order[j]] })
+
+	blks := make([]*snowman.TestBlock, len(m.blkIDs))
+	for _, i := range order {
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     m.blkIDs[i],
+				StatusV: choices.Processing,
+			},
+			ParentV: genesis,
+			HeightV: m.heights[i],
+		}
+		if parent := m.parents[i]; parent >= 0 {
+			blk.ParentV = blks[parent]
+		}
+		blks[i] = blk
+		if err := consensus.Add(blk); err != nil {
+			return nil, err
+		}
+	}
+	return &snowmanInstance{consensus: consensus, blks: blks}, nil
+}
+
+// A biased adversary votes for the most recently created block, which is
+// usually a leaf
+func (m *snowmanModel) biasedVote() []ids.ID { return []ids.ID{m.blkIDs[len(m.blkIDs)-1]} }
+
+func (m *snowmanModel) randomVote(rng *rand.Rand) []ids.ID {
+	return []ids.ID{m.blkIDs[rng.Intn(len(m.blkIDs))]}
+}
+
+// Blocks conflict if they're at the same height
+func (m *snowmanModel) conflictKeys(blkID ids.ID) []ids.ID {
+	for i, id := range m.blkIDs {
+		if id.Equals(blkID) {
+			return []ids.ID{ids.Empty.Prefix(heightPrefix, m.heights[i])}
+		}
+	}
+	return nil
+}
+
+type snowmanInstance struct {
+	consensus snowman.Consensus
+	blks      []*snowman.TestBlock
+}
+
+func (i *snowmanInstance) vote() []ids.ID { return []ids.ID{i.consensus.Preference()} }
+
+func (i *snowmanInstance) recordPoll(responses [][]ids.ID) error {
+	votes := ids.Bag{}
+	for _, response := range responses {
+		if len(response) > 0 {
+			votes.Add(response[0])
+		}
+	}
+	return i.consensus.RecordPoll(votes)
+}
+
+func (i *snowmanInstance) finalized() bool { return i.consensus.Finalized() }
+
+func (i *snowmanInstance) accepted() []ids.ID {
+	accepted := []ids.ID(nil)
+	for _, blk := range i.blks {
+		if blk.Status() == choices.Accepted {
+			accepted = append(accepted, blk.ID())
+		}
+	}
+	return accepted
+}
+
+// txModel is a set of inputs that are each consumed by several conflicting
+// transactions
+type txModel struct {
+	inputIDs []ids.ID
+	// txIDs[color][input] is the ID of the transaction of that color that
+	// consumes that input
+	txIDs [][]ids.ID
+	// Transaction ID --> Index of the input it consumes
+	txInputs map[[32]byte]int
+}
+
+func newTxModel(numColors, numInputs int) *txModel {
+	m := &txModel{
+		inputIDs: make([]ids.ID, numInputs),
+		txIDs:    make([][]ids.ID, numColors),
+		txInputs: make(map[[32]byte]int, numColors*numInputs),
+	}
+	for input := range m.inputIDs {
+		m.inputIDs[input] = ids.Empty.Prefix(inputPrefix, uint64(input))
+	}
+	for color := range m.txIDs {
+		m.txIDs[color] = make([]ids.ID, numInputs)
+		for input := range m.inputIDs {
+			txID := ids.Empty.Prefix(txPrefix, uint64(color), uint64(input))
+			m.txIDs[color][input] = txID
+			m.txInputs[txID.Key()] = input
+		}
+	}
+	return m
+}
+
+// newTxs returns a copy of the transactions of this model
+func (m *txModel) newTxs() [][]*snowstorm.TestTx {
+	txs := make([][]*snowstorm.TestTx, len(m.txIDs))
+	for color, colorTxIDs := range m.txIDs {
+		txs[color] = make([]*snowstorm.TestTx, len(colorTxIDs))
+		for input, txID := range colorTxIDs {
+			inputIDs := ids.Set{}
+			inputIDs.Add(m.inputIDs[input])
+			txs[color][input] = &snowstorm.TestTx{
+				TestDecidable: choices.TestDecidable{
+					IDV:     txID,
+					StatusV: choices.Processing,
+				},
+				InputIDsV: inputIDs,
+			}
+		}
+	}
+	return txs
+}
+
+func (m *txModel) newInstance(params avalanche.Parameters, rng *rand.Rand) (instance, error) {
+	consensus := &snowstorm.Directed{}
+	if err := consensus.Initialize(snow.DefaultContextTest(), params.Parameters); err != nil {
+		return nil, err
+	}
+
+	txs := m.newTxs()
+	numInputs := len(m.inputIDs)
+	for _, i := range rng.Perm(len(txs) * numInputs) {
+		if err := consensus.Add(txs[i/numInputs][i%numInputs]); err != nil {
+			return nil, err
+		}
+	}
+	return &snowstormInstance{consensus: consensus, txs: txs}, nil
+}
+
+// A biased adversary votes for the transactions of the last color
+func (m *txModel) biasedVote() []ids.ID { return m.txIDs[len(m.txIDs)-1] }
+
+// An equivocating adversary votes for a random transaction consuming each input
+func (m *txModel) randomVote(rng *rand.Rand) []ids.ID {
+	vote := make([]ids.ID, len(m.inputIDs))
+	for input := range vote {
+		vote[input] = m.txIDs[rng.Intn(len(m.txIDs))][input]
+	}
+	return vote
+}
+
+// Transactions conflict if they consume the same input
+func (m *txModel) conflictKeys(txID ids.ID) []ids.ID {
+	input, ok := m.txInputs[txID.Key()]
+	if !ok {
+		return nil
+	}
+	return []ids.ID{m.inputIDs[input]}
+}
+
+// acceptedTxs returns the IDs of the accepted transactions in [txs]
+func acceptedTxs(txs [][]*snowstorm.TestTx) []ids.ID {
+	accepted := []ids.ID(nil)
+	for _, colorTxs := range txs {
+		for _, tx := range colorTxs {
+			if tx.Status() == choices.Accepted {
+				accepted = append(accepted, tx.ID())
+			}
+		}
+	}
+	return accepted
+}
+
+type snowstormInstance struct {
+	consensus snowstorm.Consensus
+	txs       [][]*snowstorm.TestTx
+}
+
+// Accepted transactions are removed from the conflict graph, so they're no
+// longer preferred, but an honest node still votes for them
+func (i *snowstormInstance) vote() []ids.ID {
+	return append(i.consensus.Preferences().List(), acceptedTxs(i.txs)...)
+}
+
+func (i *snowstormInstance) recordPoll(responses [][]ids.ID) error {
+	votes := ids.Bag{}
+	for _, response := range responses {
+		votes.Add(response...)
+	}
+	_, err := i.consensus.RecordPoll(votes)
+	return err
+}
+
+func (i *snowstormInstance) finalized() bool { return i.consensus.Finalized() }
+
+func (i *snowstormInstance) accepted() []ids.ID { return acceptedTxs(i.txs) }
+
+// avalancheModel is a set of conflicting transactions, issued in vertices.
+// Each vertex holds up to BatchSize transactions of the same color, and its
+// parents are up to Parents earlier vertices of the same color, so a vertex
+// never depends on a transaction it conflicts with.
+type avalancheModel struct {
+	*txModel
+
+	genesisID ids.ID
+	vtxIDs    [][]ids.ID
+	// vtxInputs[color][vertex] are the indices of the inputs consumed by the
+	// transactions in the vertex
+	vtxInputs [][][]int
+	// vtxParents[color][vertex] are the indices of the parents of the vertex.
+	// If there are none, the genesis vertex is the parent.
+	vtxParents [][][]int
+}
+
+func newAvalancheModel(numColors, numInputs int, params avalanche.Parameters, rng *rand.Rand) *avalancheModel {
+	m := &avalancheModel{
+		txModel:    newTxModel(numColors, numInputs),
+		genesisID:  ids.Empty.Prefix(vertexPrefix, 0),
+		vtxIDs:     make([][]ids.ID, numColors),
+		vtxInputs:  make([][][]int, numColors),
+		vtxParents: make([][][]int, numColors),
+	}
+	numVertices := 0
+	for color := 0; color < numColors; color++ {
+		for start := 0; start < numInputs; start += params.BatchSize {
+			end := start + params.BatchSize
+			if end > numInputs {
+				end = numInputs
+			}
+			inputs := make([]int, 0, end-start)
+			for input := start; input < end; input++ {
+				inputs = append(inputs, input)
+			}
+
+			vtx := len(m.vtxIDs[color])
+			numParents := params.Parents
+			if numParents > vtx {
+				numParents = vtx
+			}
+			parents := rng.Perm(vtx)[:numParents]
+			sort.Ints(parents)
+
+			numVertices++
+			m.vtxIDs[color] = append(m.vtxIDs[color], ids.Empty.Prefix(vertexPrefix, uint64(numVertices)))
+			m.vtxInputs[color] = append(m.vtxInputs[color], inputs)
+			m.vtxParents[color] = append(m.vtxParents[color], parents)
+		}
+	}
+	return m
+}
+
+func (m *avalancheModel) newInstance(params avalanche.Parameters, rng *rand.Rand) (instance, error) {
+	genesis := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     m.genesisID,
+		StatusV: choices.Accepted,
+	}}
+	consensus := &avalanche.Topological{}
+	if err := consensus.Initialize(snow.DefaultContextTest(), params, []avalanche.Vertex{genesis}); err != nil {
+		return nil, err
+	}
+
+	// The colors are added in a random order, and each color's vertices are
+	// added after their parents
+	txs := m.newTxs()
+	for _, color := range rng.Perm(len(m.vtxIDs)) {
+		vtxs := make([]*avalanche.TestVertex, len(m.vtxIDs[color]))
+		for i, vtxID := range m.vtxIDs[color] {
+			vtx := &avalanche.TestVertex{
+				TestDecidable: choices.TestDecidable{
+					IDV:     vtxID,
+					StatusV: choices.Processing,
+				},
+				ParentsV: []avalanche.Vertex{genesis},
+				HeightV:  1,
+			}
+			if parents := m.vtxParents[color][i]; len(parents) > 0 {
+				vtx.ParentsV = make([]avalanche.Vertex, len(parents))
+				for j, parent := range parents {
+					vtx.ParentsV[j] = vtxs[parent]
+					if height := vtxs[parent].HeightV + 1; height > vtx.HeightV {
+						vtx.HeightV = height
+					}
+				}
+			}
+			for _, input := range m.vtxInputs[color][i] {
+				vtx.TxsV = append(vtx.TxsV, txs[color][input])
+			}
+			vtxs[i] = vtx
+			if err := consensus.Add(vtx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &avalancheInstance{consensus: consensus, txs: txs}, nil
+}
+
+// A biased adversary votes for the vertices of the last color
+func (m *avalancheModel) biasedVote() []ids.ID { return m.vtxIDs[len(m.vtxIDs)-1] }
+
+// An equivocating adversary votes for the vertices of a random color
+func (m *avalancheModel) randomVote(rng *rand.Rand) []ids.ID {
+	return m.vtxIDs[rng.Intn(len(m.vtxIDs))]
+}
+
+type avalancheInstance struct {
+	consensus avalanche.Consensus
+	txs       [][]*snowstorm.TestTx
+}
+
+func (i *avalancheInstance) vote() []ids.ID { return i.consensus.Preferences().List() }
+
+func (i *avalancheInstance) recordPoll(responses [][]ids.ID) error {
+	votes := ids.UniqueBag{}
+	for voter, response := range responses {
+		votes.Add(uint(voter), response...)
+	}
+	return i.consensus.RecordPoll(votes)
+}
+
+func (i *avalancheInstance) finalized() bool { return i.consensus.Finalized() }
+
+func (i *avalancheInstance) accepted() []ids.ID { return acceptedTxs(i.txs) }
@@ -0,0 +1,167 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+)
+
+// Engines that can be simulated
+const (
+	snowmanEngine   = "snowman"
+	snowstormEngine = "snowstorm"
+	avalancheEngine = "avalanche"
+)
+
+// Distributions of stake across the simulated nodes
+const (
+	// Every node has the same stake
+	uniformStake = "uniform"
+	// The stake of the i'th node is proportional to i
+	linearStake = "linear"
+	// The stake of the i'th node is proportional to 1/i
+	zipfStake = "zipf"
+)
+
+// maxK is the largest sample size a poll's voters can be tracked for
+const maxK = 64
+
+var (
+	errUnknownEngine       = errors.New("unknown engine")
+	errUnknownDistribution = errors.New("unknown stake distribution")
+	errTooManyAdversaries  = errors.New("there must be at least one honest node")
+	errInvalidLatency      = errors.New("latency must satisfy 0 <= min-latency <= max-latency")
+	errInvalidDropRate     = errors.New("drop rate must be in [0, 1]")
+	errInvalidTimeout      = errors.New("poll timeout must be positive")
+	errInvalidRuns         = errors.New("there must be at least one run")
+	errInvalidColors       = errors.New("there must be at least one color, and at least one input")
+	errSampleTooLarge      = fmt.Errorf("sample size can't be larger than %d", maxK)
+)
+
+// Config describes the simulated network
+type Config struct {
+	// One of snowman, snowstorm or avalanche
+	Engine string
+	// Consensus parameters of every honest node. Parents and BatchSize are
+	// only used by avalanche.
+	Params avalanche.Parameters
+
+	// Seed of the first run. Run i uses Seed+i.
+	Seed int64
+	// Number of independent runs
+	Runs int
+
+	// Number of nodes, including adversaries
+	Nodes int
+	// One of uniform, linear or zipf
+	StakeDistribution string
+	// Number of nodes that never respond to queries
+	Silent int
+	// Number of nodes that respond to each query with a random choice
+	Equivocating int
+	// Number of nodes that always vote for the same choice, which isn't
+	// necessarily preferred by any honest node
+	Biased int
+
+	// Number of conflicting choices. For snowman, the number of blocks, which
+	// form a tree of conflicting branches. For snowstorm and avalanche, the
+	// number of transactions consuming each input.
+	Colors int
+	// Number of inputs consumed by the transactions. Only used by snowstorm
+	// and avalanche.
+	Inputs int
+
+	// The one-way latency of a message is uniformly distributed in
+	// [MinLatency, MaxLatency]
+	MinLatency, MaxLatency time.Duration
+	// Probability that a message is dropped
+	DropRate float64
+	// Time a node waits for the responses to a poll
+	PollTimeout time.Duration
+	// Simulated time after which a run stops, even if some honest nodes
+	// haven't finalized
+	MaxTime time.Duration
+}
+
+// Valid returns an error if the config can't be simulated
+func (c *Config) Valid() error {
+	switch c.Engine {
+	case snowmanEngine, snowstormEngine:
+		if err := c.Params.Parameters.Valid(); err != nil {
+			return err
+		}
+	case avalancheEngine:
+		if err := c.Params.Valid(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %q", errUnknownEngine, c.Engine)
+	}
+	switch c.StakeDistribution {
+	case uniformStake, linearStake, zipfStake:
+	default:
+		return fmt.Errorf("%w: %q", errUnknownDistribution, c.StakeDistribution)
+	}
+	switch {
+	case c.Params.K > maxK:
+		return errSampleTooLarge
+	case c.Silent < 0 || c.Equivocating < 0 || c.Biased < 0 || c.Silent+c.Equivocating+c.Biased >= c.Nodes:
+		return errTooManyAdversaries
+	case c.MinLatency < 0 || c.MaxLatency < c.MinLatency:
+		return errInvalidLatency
+	case c.DropRate < 0 || c.DropRate > 1:
+		return errInvalidDropRate
+	case c.PollTimeout <= 0:
+		return errInvalidTimeout
+	case c.Runs <= 0:
+		return errInvalidRuns
+	case c.Colors <= 0 || (c.Engine != snowmanEngine && c.Inputs <= 0):
+		return errInvalidColors
+	default:
+		return nil
+	}
+}
+
+// parseConfig parses the config from the command line arguments [args]
+func parseConfig(args []string) (Config, error) {
+	config := Config{}
+	fs := flag.NewFlagSet("consensus-sim", flag.ContinueOnError)
+
+	fs.StringVar(&config.Engine, "engine", snowmanEngine, "Consensus engine to simulate. One of {snowman, snowstorm, avalanche}")
+	fs.Int64Var(&config.Seed, "seed", 0, "Seed of the first run. Run i uses seed+i, so results are reproducible")
+	fs.IntVar(&config.Runs, "runs", 10, "Number of independent runs")
+
+	fs.IntVar(&config.Nodes, "nodes", 100, "Number of nodes, including adversaries")
+	fs.StringVar(&config.StakeDistribution, "stake-distribution", uniformStake, "Distribution of stake across nodes. One of {uniform, linear, zipf}")
+	fs.IntVar(&config.Silent, "silent", 0, "Number of adversaries that never respond to queries")
+	fs.IntVar(&config.Equivocating, "equivocating", 0, "Number of adversaries that respond to each query with a random choice")
+	fs.IntVar(&config.Biased, "biased", 0, "Number of adversaries that always vote for the same choice")
+
+	fs.IntVar(&config.Colors, "colors", 2, "Number of conflicting choices. For snowman, the number of blocks. For snowstorm and avalanche, the number of transactions consuming each input")
+	fs.IntVar(&config.Inputs, "inputs", 1, "Number of inputs consumed by the transactions. Ignored by snowman")
+
+	fs.DurationVar(&config.MinLatency, "min-latency", 10*time.Millisecond, "Minimum one-way latency of a message")
+	fs.DurationVar(&config.MaxLatency, "max-latency", 100*time.Millisecond, "Maximum one-way latency of a message")
+	fs.Float64Var(&config.DropRate, "drop-rate", 0, "Probability that a message is dropped")
+	fs.DurationVar(&config.PollTimeout, "poll-timeout", 2*time.Second, "Time a node waits for the responses to a poll")
+	fs.DurationVar(&config.MaxTime, "max-time", 10*time.Minute, "Simulated time after which a run stops, even if some honest nodes haven't finalized")
+
+	fs.IntVar(&config.Params.K, "snow-sample-size", 20, "Number of nodes to query for each network poll")
+	fs.IntVar(&config.Params.Alpha, "snow-quorum-size", 14, "Alpha value to use for required number positive results")
+	fs.IntVar(&config.Params.BetaVirtuous, "snow-virtuous-commit-threshold", 15, "Beta value to use for virtuous transactions")
+	fs.IntVar(&config.Params.BetaRogue, "snow-rogue-commit-threshold", 20, "Beta value to use for rogue transactions")
+	fs.IntVar(&config.Params.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
+	fs.IntVar(&config.Params.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
+	fs.IntVar(&config.Params.ConcurrentRepolls, "snow-concurrent-repolls", 1, "Minimum number of concurrent polls for finalizing consensus")
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	return config, config.Valid()
+}
@@ -0,0 +1,91 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// report summarizes the results of several runs
+type report struct {
+	runs int
+	// Time to finality of every honest node that finalized, in every run
+	finalityTimes []time.Duration
+	unfinalized   int
+	// Number of runs with at least one safety violation
+	unsafeRuns int
+	violations int
+
+	polls, messages, dropped uint64
+	adversaryStake           float64
+}
+
+// newReport summarizes [results]
+func newReport(results []runResult) *report {
+	r := &report{runs: len(results)}
+	for _, result := range results {
+		r.finalityTimes = append(r.finalityTimes, result.finalityTimes...)
+		r.unfinalized += result.unfinalized
+		if result.violations > 0 {
+			r.unsafeRuns++
+		}
+		r.violations += result.violations
+		r.polls += result.polls
+		r.messages += result.messages
+		r.dropped += result.dropped
+		r.adversaryStake += result.adversaryStake
+	}
+	if r.runs > 0 {
+		r.adversaryStake /= float64(r.runs)
+	}
+	sort.Slice(r.finalityTimes, func(i, j int) bool { return r.finalityTimes[i] < r.finalityTimes[j] })
+	return r
+}
+
+// percentile returns the [p]'th percentile of the finality times. Assumes
+// there is at least one finality time.
+func (r *report) percentile(p float64) time.Duration {
+	return r.finalityTimes[int(p*float64(len(r.finalityTimes)-1))]
+}
+
+// mean returns the mean of the finality times. Assumes there is at least one
+// finality time.
+func (r *report) mean() time.Duration {
+	total := time.Duration(0)
+	for _, t := range r.finalityTimes {
+		total += t
+	}
+	return total / time.Duration(len(r.finalityTimes))
+}
+
+// Write the report to [w]
+func (r *report) Write(w io.Writer, config Config) {
+	fmt.Fprintf(w, "engine:            %s\n", config.Engine)
+	fmt.Fprintf(w, "runs:              %d (seeds %d to %d)\n", r.runs, config.Seed, config.Seed+int64(r.runs)-1)
+	fmt.Fprintf(w, "nodes:             %d (%d silent, %d equivocating, %d biased, %.1f%% of stake adversarial)\n",
+		config.Nodes, config.Silent, config.Equivocating, config.Biased, 100*r.adversaryStake)
+	fmt.Fprintf(w, "parameters:        k=%d alpha=%d betaVirtuous=%d betaRogue=%d concurrentRepolls=%d\n",
+		config.Params.K, config.Params.Alpha, config.Params.BetaVirtuous, config.Params.BetaRogue, config.Params.ConcurrentRepolls)
+
+	honestNodes := len(r.finalityTimes) + r.unfinalized
+	fmt.Fprintf(w, "finalized:         %d of %d honest nodes\n", len(r.finalityTimes), honestNodes)
+	if len(r.finalityTimes) > 0 {
+		fmt.Fprintf(w, "time to finality:  min=%s p50=%s p90=%s p99=%s max=%s mean=%s\n",
+			r.finalityTimes[0],
+			r.percentile(.5),
+			r.percentile(.9),
+			r.percentile(.99),
+			r.finalityTimes[len(r.finalityTimes)-1],
+			r.mean(),
+		)
+	}
+	if honestNodes > 0 {
+		fmt.Fprintf(w, "polls:             %.1f per honest node\n", float64(r.polls)/float64(honestNodes))
+	}
+	fmt.Fprintf(w, "messages:          %d sent, %d dropped\n", r.messages, r.dropped)
+	fmt.Fprintf(w, "safety violations: %d in %d of %d runs\n", r.violations, r.unsafeRuns, r.runs)
+}
@@ -0,0 +1,381 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// stakeUnit is the weight of one unit of stake. Validators are sampled by
+// weight without replacement, as validators.Set samples them, so a large unit
+// makes sampling the same validator twice in a poll about as likely as it is
+// with real stake.
+const stakeUnit = 1 << 20
+
+// behavior is how a simulated node responds to queries
+type behavior int
+
+const (
+	honest behavior = iota
+	silent
+	equivocating
+	biased
+)
+
+// event is something that happens at a point in simulated time
+type event struct {
+	time time.Duration
+	// Events that happen at the same time happen in the order they were
+	// scheduled in, so that runs are reproducible
+	seq uint64
+	f   func() error
+}
+
+// eventQueue is a min-heap of events
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].time != q[j].time {
+		return q[i].time < q[j].time
+	}
+	return q[i].seq < q[j].seq
+}
+func (q eventQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
+
+// simNode is a simulated node
+type simNode struct {
+	index    int
+	behavior behavior
+	// The consensus instance of an honest node. nil for adversaries.
+	instance instance
+
+	finalized bool
+}
+
+// poll is a poll issued by an honest node
+type poll struct {
+	node *simNode
+	// responses[i] is the response of the i'th sampled node, or nil if it
+	// hasn't responded
+	responses [][]ids.ID
+	received  int
+	done      bool
+}
+
+// runResult is the outcome of one simulated run
+type runResult struct {
+	// Simulated time it took each honest node that finalized to finalize
+	finalityTimes []time.Duration
+	// Number of honest nodes that hadn't finalized when the run stopped
+	unfinalized int
+	// Number of sets of conflicting choices of which honest nodes accepted
+	// more than one
+	violations int
+	// Number of polls completed by honest nodes
+	polls uint64
+	// Number of messages sent and dropped
+	messages, dropped uint64
+	// Fraction of the stake held by adversaries
+	adversaryStake float64
+}
+
+// simulation is one run of a simulated network
+type simulation struct {
+	config Config
+	rng    *rand.Rand
+	model  model
+	nodes  []*simNode
+	// cumulativeWeights[i] is the total weight of nodes [0, i]
+	cumulativeWeights []uint64
+
+	now    time.Duration
+	seq    uint64
+	events eventQueue
+
+	numUnfinalized int
+	result         runResult
+}
+
+// simulate runs the network described by [config], with all randomness drawn
+// from [seed]
+func simulate(config Config, seed int64) (runResult, error) {
+	s := &simulation{
+		config: config,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+	s.model = newModel(config, s.rng)
+
+	// Assign stake and behavior to each node
+	weights := stakeWeights(config.StakeDistribution, config.Nodes)
+	behaviors := make([]behavior, config.Nodes)
+	order := s.rng.Perm(config.Nodes)
+	for i, index := range order {
+		switch {
+		case i < config.Silent:
+			behaviors[index] = silent
+		case i < config.Silent+config.Equivocating:
+			behaviors[index] = equivocating
+		case i < config.Silent+config.Equivocating+config.Biased:
+			behaviors[index] = biased
+		}
+	}
+
+	totalWeight, adversaryWeight := uint64(0), uint64(0)
+	s.cumulativeWeights = make([]uint64, config.Nodes)
+	s.nodes = make([]*simNode, config.Nodes)
+	for i := range s.nodes {
+		node := &simNode{
+			index:    i,
+			behavior: behaviors[i],
+		}
+		if node.behavior == honest {
+			// Each instance registers its metrics in a registry of its own
+			params := config.Params
+			params.Metrics = prometheus.NewRegistry()
+			instance, err := s.model.newInstance(params, s.rng)
+			if err != nil {
+				return runResult{}, fmt.Errorf("couldn't create the consensus instance of node %d: %w", i, err)
+			}
+			node.instance = instance
+			s.numUnfinalized++
+		} else {
+			adversaryWeight += weights[i]
+		}
+		totalWeight += weights[i]
+		s.cumulativeWeights[i] = totalWeight
+		s.nodes[i] = node
+	}
+	if totalWeight < uint64(config.Params.K) {
+		return runResult{}, fmt.Errorf("total weight %d is less than the sample size %d", totalWeight, config.Params.K)
+	}
+	s.result.adversaryStake = float64(adversaryWeight) / float64(totalWeight)
+
+	// Every honest node keeps ConcurrentRepolls polls outstanding until it
+	// finalizes
+	for _, node := range s.nodes {
+		if node.instance == nil {
+			continue
+		}
+		node := node
+		for i := 0; i < config.Params.ConcurrentRepolls; i++ {
+			s.schedule(0, func() error { return s.startPoll(node) })
+		}
+	}
+
+	for s.numUnfinalized > 0 && s.events.Len() > 0 {
+		e := heap.Pop(&s.events).(*event)
+		if e.time > config.MaxTime {
+			break
+		}
+		s.now = e.time
+		if err := e.f(); err != nil {
+			return runResult{}, err
+		}
+	}
+
+	s.result.unfinalized = s.numUnfinalized
+	s.result.violations = s.safetyViolations()
+	return s.result, nil
+}
+
+// stakeWeights returns the weight of each of [numNodes] nodes
+func stakeWeights(distribution string, numNodes int) []uint64 {
+	weights := make([]uint64, numNodes)
+	for i := range weights {
+		switch distribution {
+		case linearStake:
+			weights[i] = uint64(i+1) * stakeUnit
+		case zipfStake:
+			weights[i] = uint64(numNodes) * stakeUnit / uint64(i+1)
+		default:
+			weights[i] = stakeUnit
+		}
+	}
+	return weights
+}
+
+// schedule [f] to happen [delay] from now
+func (s *simulation) schedule(delay time.Duration, f func() error) {
+	s.seq++
+	heap.Push(&s.events, &event{
+		time: s.now + delay,
+		seq:  s.seq,
+		f:    f,
+	})
+}
+
+// send a message from [from] to [to], which is delivered by calling [f]
+// unless it's dropped
+func (s *simulation) send(from, to *simNode, f func() error) {
+	// Messages a node sends to itself are delivered locally
+	if from == to {
+		s.schedule(0, f)
+		return
+	}
+	s.result.messages++
+	if s.rng.Float64() < s.config.DropRate {
+		s.result.dropped++
+		return
+	}
+	latency := s.config.MinLatency
+	if spread := s.config.MaxLatency - s.config.MinLatency; spread > 0 {
+		latency += time.Duration(s.rng.Int63n(int64(spread) + 1))
+	}
+	s.schedule(latency, f)
+}
+
+// sample K nodes by weight, without replacement of weight
+func (s *simulation) sample() []*simNode {
+	k := s.config.Params.K
+	totalWeight := s.cumulativeWeights[len(s.cumulativeWeights)-1]
+
+	// A partial Fisher-Yates shuffle of the units of weight
+	swapped := make(map[uint64]uint64, k)
+	sampled := make([]*simNode, k)
+	for i := 0; i < k; i++ {
+		draw := uint64(i) + uint64(s.rng.Int63n(int64(totalWeight-uint64(i))))
+		unit, ok := swapped[draw]
+		if !ok {
+			unit = draw
+		}
+		if replacement, ok := swapped[uint64(i)]; ok {
+			swapped[draw] = replacement
+		} else {
+			swapped[draw] = uint64(i)
+		}
+
+		index := sort.Search(len(s.cumulativeWeights), func(j int) bool { return s.cumulativeWeights[j] > unit })
+		sampled[i] = s.nodes[index]
+	}
+	return sampled
+}
+
+// startPoll issues a new poll from [node]
+func (s *simulation) startPoll(node *simNode) error {
+	if node.finalized {
+		return nil
+	}
+
+	voters := s.sample()
+	p := &poll{
+		node:      node,
+		responses: make([][]ids.ID, len(voters)),
+	}
+	s.schedule(s.config.PollTimeout, func() error { return s.finishPoll(p) })
+	for i, voter := range voters {
+		i, voter := i, voter
+		s.send(node, voter, func() error {
+			response := s.respond(voter)
+			if response == nil {
+				return nil
+			}
+			s.send(voter, node, func() error { return s.receive(p, i, response) })
+			return nil
+		})
+	}
+	return nil
+}
+
+// respond returns the response of [node] to a query, or nil if it doesn't
+// respond
+func (s *simulation) respond(node *simNode) []ids.ID {
+	switch node.behavior {
+	case silent:
+		return nil
+	case equivocating:
+		return s.model.randomVote(s.rng)
+	case biased:
+		return s.model.biasedVote()
+	default:
+		// An empty response is still a response
+		response := node.instance.vote()
+		if response == nil {
+			response = []ids.ID{}
+		}
+		return response
+	}
+}
+
+// receive the response of the [i]'th sampled node to [p]
+func (s *simulation) receive(p *poll, i int, response []ids.ID) error {
+	if p.done || p.responses[i] != nil {
+		return nil
+	}
+	p.responses[i] = response
+	p.received++
+	if p.received < len(p.responses) {
+		return nil
+	}
+	return s.finishPoll(p)
+}
+
+// finishPoll records the responses to [p], which either all arrived or timed
+// out, and issues the next poll
+func (s *simulation) finishPoll(p *poll) error {
+	if p.done {
+		return nil
+	}
+	p.done = true
+
+	node := p.node
+	if err := node.instance.recordPoll(p.responses); err != nil {
+		return fmt.Errorf("node %d failed to record a poll: %w", node.index, err)
+	}
+	s.result.polls++
+
+	if !node.finalized && node.instance.finalized() {
+		node.finalized = true
+		s.result.finalityTimes = append(s.result.finalityTimes, s.now)
+		s.numUnfinalized--
+		return nil
+	}
+	return s.startPoll(node)
+}
+
+// safetyViolations returns the number of sets of conflicting choices of which
+// honest nodes accepted more than one
+func (s *simulation) safetyViolations() int {
+	// Conflict key --> The choices accepted with that key
+	acceptedByKey := make(map[[32]byte]ids.Set)
+	for _, node := range s.nodes {
+		if node.instance == nil {
+			continue
+		}
+		for _, choiceID := range node.instance.accepted() {
+			for _, key := range s.model.conflictKeys(choiceID) {
+				accepted, ok := acceptedByKey[key.Key()]
+				if !ok {
+					accepted = ids.Set{}
+					acceptedByKey[key.Key()] = accepted
+				}
+				accepted.Add(choiceID)
+			}
+		}
+	}
+
+	violations := 0
+	for _, accepted := range acceptedByKey {
+		if accepted.Len() > 1 {
+			violations++
+		}
+	}
+	return violations
+}
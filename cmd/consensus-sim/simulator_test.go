@@ -0,0 +1,198 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestSimulateHonestNetwork(t *testing.T) {
+	for _, engine := range []string{snowmanEngine, snowstormEngine, avalancheEngine} {
+		config, err := parseConfig([]string{
+			"-engine", engine,
+			"-nodes", "20",
+			"-colors", "3",
+			"-inputs", "4",
+			"-snow-sample-size", "5",
+			"-snow-quorum-size", "4",
+			"-snow-virtuous-commit-threshold", "5",
+			"-snow-rogue-commit-threshold", "10",
+			"-snow-avalanche-batch-size", "2",
+			"-snow-avalanche-num-parents", "2",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := simulate(config, 1)
+		if err != nil {
+			t.Fatalf("%s: %s", engine, err)
+		}
+		if result.unfinalized != 0 {
+			t.Fatalf("%s: %d honest nodes didn't finalize", engine, result.unfinalized)
+		}
+		if len(result.finalityTimes) != config.Nodes {
+			t.Fatalf("%s: %d honest nodes finalized, expected %d", engine, len(result.finalityTimes), config.Nodes)
+		}
+		if result.violations != 0 {
+			t.Fatalf("%s: %d safety violations", engine, result.violations)
+		}
+		if result.adversaryStake != 0 {
+			t.Fatalf("%s: adversaries hold %f of the stake, expected none", engine, result.adversaryStake)
+		}
+	}
+}
+
+func TestSimulateAdversaries(t *testing.T) {
+	config, err := parseConfig([]string{
+		"-nodes", "20",
+		"-silent", "2",
+		"-equivocating", "2",
+		"-biased", "1",
+		"-drop-rate", "0.1",
+		"-stake-distribution", linearStake,
+		"-snow-sample-size", "5",
+		"-snow-quorum-size", "4",
+		"-snow-virtuous-commit-threshold", "5",
+		"-snow-rogue-commit-threshold", "10",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := simulate(config, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if honest := len(result.finalityTimes) + result.unfinalized; honest != 15 {
+		t.Fatalf("simulated %d honest nodes, expected 15", honest)
+	}
+	if result.dropped == 0 {
+		t.Fatalf("should have dropped messages")
+	}
+	if result.adversaryStake <= 0 || result.adversaryStake >= 1 {
+		t.Fatalf("adversaries hold %f of the stake", result.adversaryStake)
+	}
+}
+
+func TestSimulateReproducible(t *testing.T) {
+	for _, engine := range []string{snowmanEngine, snowstormEngine, avalancheEngine} {
+		config, err := parseConfig([]string{
+			"-engine", engine,
+			"-nodes", "30",
+			"-colors", "4",
+			"-inputs", "6",
+			"-silent", "1",
+			"-equivocating", "3",
+			"-biased", "2",
+			"-drop-rate", "0.1",
+			"-snow-sample-size", "7",
+			"-snow-quorum-size", "5",
+			"-snow-virtuous-commit-threshold", "5",
+			"-snow-rogue-commit-threshold", "10",
+			"-snow-avalanche-batch-size", "2",
+			"-snow-avalanche-num-parents", "2",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The adversaries split the votes of polls across conflicting
+		// choices, which is when the order the votes are applied in matters
+		for seed := int64(0); seed < 5; seed++ {
+			first, err := simulate(config, seed)
+			if err != nil {
+				t.Fatalf("%s: %s", engine, err)
+			}
+			second, err := simulate(config, seed)
+			if err != nil {
+				t.Fatalf("%s: %s", engine, err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("%s: runs with seed %d differ:\n%+v\n%+v", engine, seed, first, second)
+			}
+		}
+	}
+}
+
+func TestSimulateMaxTime(t *testing.T) {
+	config, err := parseConfig([]string{
+		"-nodes", "10",
+		"-silent", "9",
+		"-snow-sample-size", "5",
+		"-snow-quorum-size", "4",
+		"-max-time", "1m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The only honest node can't gather a quorum of votes
+	result, err := simulate(config, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.unfinalized != 1 {
+		t.Fatalf("the honest node shouldn't have finalized")
+	}
+}
+
+func TestSafetyViolations(t *testing.T) {
+	m := &snowmanModel{
+		blkIDs:  []ids.ID{ids.Empty.Prefix(blockPrefix, 1), ids.Empty.Prefix(blockPrefix, 2)},
+		parents: []int{-1, -1},
+		heights: []uint64{1, 1},
+	}
+	s := &simulation{model: m}
+
+	// The nodes accepted conflicting blocks
+	s.nodes = []*simNode{
+		{instance: &acceptedInstance{choiceIDs: m.blkIDs[:1]}},
+		{instance: &acceptedInstance{choiceIDs: m.blkIDs[1:]}},
+		{behavior: biased},
+	}
+	if violations := s.safetyViolations(); violations != 1 {
+		t.Fatalf("found %d safety violations, expected 1", violations)
+	}
+
+	s.nodes[1] = &simNode{instance: &acceptedInstance{choiceIDs: m.blkIDs[:1]}}
+	if violations := s.safetyViolations(); violations != 0 {
+		t.Fatalf("found %d safety violations, expected none", violations)
+	}
+}
+
+// acceptedInstance is an instance that accepted [choiceIDs]
+type acceptedInstance struct{ choiceIDs []ids.ID }
+
+func (i *acceptedInstance) vote() []ids.ID                { return i.choiceIDs }
+func (i *acceptedInstance) recordPoll(_ [][]ids.ID) error { return nil }
+func (i *acceptedInstance) finalized() bool               { return true }
+func (i *acceptedInstance) accepted() []ids.ID            { return i.choiceIDs }
+
+func TestConfigValid(t *testing.T) {
+	invalid := [][]string{
+		{"-engine", "snowball"},
+		{"-stake-distribution", "normal"},
+		{"-nodes", "5", "-silent", "5"},
+		{"-min-latency", "2s", "-max-latency", "1s"},
+		{"-drop-rate", "1.5"},
+		{"-poll-timeout", "0s"},
+		{"-runs", "0"},
+		{"-colors", "0"},
+		{"-engine", avalancheEngine, "-inputs", "0"},
+		{"-snow-sample-size", "100", "-snow-quorum-size", "60"},
+		{"-snow-quorum-size", "5"},
+	}
+	for _, args := range invalid {
+		if _, err := parseConfig(args); err == nil {
+			t.Fatalf("should have failed to parse %v", args)
+		}
+	}
+	if _, err := parseConfig(nil); err != nil {
+		t.Fatalf("the default config should be valid: %s", err)
+	}
+}
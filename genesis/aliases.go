@@ -40,8 +40,8 @@ func Aliases(networkID uint32) (map[string][]string, map[[32]byte][]string, map[
 		return nil, nil, nil, err
 	}
 
-	genesis := &platformvm.Genesis{} // TODO let's not re-create genesis to do aliasing
-	if err := platformvm.Codec.Unmarshal(genesisBytes, genesis); err != nil {
+	genesis, err := platformvm.ParseGenesis(genesisBytes) // TODO let's not re-create genesis to do aliasing
+	if err != nil {
 		return nil, nil, nil, err
 	}
 	if err := genesis.Initialize(); err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
 )
 
 // Note that since an Avalanche network has exactly one Platform Chain, and the
@@ -24,6 +25,10 @@ type Config struct {
 	ParsedMintAddresses, ParsedFundedAddresses, ParsedStakerIDs []ids.ShortID
 	EVMBytes                                                    []byte
 	Message                                                     string
+
+	// Economic parameters of staking on the primary network. If nil, the
+	// default parameters are used.
+	StakingParameters *platformvm.StakingParameters
 }
 
 func (c *Config) init() error {
@@ -106,6 +106,10 @@ func FromConfig(config *Config) ([]byte, ids.ID, error) {
 		Time:        json.Uint64(genesisTime.Unix()),
 		Message:     config.Message,
 	}
+	if config.StakingParameters != nil {
+		stakingParams := platformvm.NewAPIStakingParameters(*config.StakingParameters)
+		platformvmArgs.StakingParameters = &stakingParams
+	}
 	for _, addr := range config.FundedAddresses {
 		platformvmArgs.UTXOs = append(platformvmArgs.UTXOs,
 			platformvm.APIUTXO{
@@ -186,8 +190,8 @@ func VMGenesis(networkID uint32, vmID ids.ID) (*platformvm.Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	genesis := platformvm.Genesis{}
-	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+	genesis, err := platformvm.ParseGenesis(genesisBytes)
+	if err != nil {
 		return nil, fmt.Errorf("couldn't unmarshal genesis bytes due to: %w", err)
 	}
 	if err := genesis.Initialize(); err != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 )
@@ -137,6 +138,50 @@ func TestGenesis(t *testing.T) {
 	}
 }
 
+func TestGenesisID(t *testing.T) {
+	tests := []struct {
+		networkID  uint32
+		expectedID string
+	}{
+		{
+			networkID:  constants.MainnetID,
+			expectedID: "W63Cy81M9hDZg8qKnadpc2W13zT6uf29mBaUeaoGaroQ7DnSw",
+		},
+		{
+			networkID:  constants.EverestID,
+			expectedID: "gqp1iMhy7sNmzKQH6q6MHow2GpBgqTdN6VxmYY26TgFcD67Bq",
+		},
+		{
+			networkID:  constants.DenaliID,
+			expectedID: "cHdp5Ha95PtSAV2euqNjHdgYso59o6SbNjUWxGjXinYyn1JTe",
+		},
+		{
+			networkID:  constants.CascadeID,
+			expectedID: "2fd1rb6ka5LM2WS3ZhTv7XiMJQYRswqZXFov7vLu3JHBr8yX8L",
+		},
+		{
+			networkID:  constants.LocalID,
+			expectedID: "27qXfz6CkMkzwiE8ghGZw3qmJvcb1qh6xUn38W9PZAgJdbkWv2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(constants.NetworkIDToNetworkName[test.networkID], func(t *testing.T) {
+			genesisBytes, _, err := Genesis(test.networkID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			genesisID := ids.NewID(hashing.ComputeHash256Array(genesisBytes))
+			if result := genesisID.String(); test.expectedID != result {
+				t.Fatalf("genesisID with networkID %d was expected to be %s but was %s",
+					test.networkID,
+					test.expectedID,
+					result)
+			}
+		})
+	}
+}
+
 func TestVMGenesis(t *testing.T) {
 	tests := []struct {
 		networkID  uint32
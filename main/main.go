@@ -60,6 +60,10 @@ func main() {
 		log.Warn("Staking and p2p encryption are disabled. Packet spoofing is possible.")
 	}
 
+	if minStakeIgnored {
+		log.Warn("min-stake is deprecated and ignored. The minimum stake is defined by the staking parameters in the P-Chain genesis")
+	}
+
 	// Check if transaction signatures should be checked
 	if !Config.EnableCrypto {
 		log.Warn("transaction signatures are not being checked")
@@ -7,13 +7,16 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/genesis"
@@ -21,6 +24,8 @@ import (
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
@@ -43,6 +48,9 @@ var (
 	Err                error
 	defaultNetworkName = constants.TestnetName
 
+	// True if the deprecated min-stake flag was set. It's ignored.
+	minStakeIgnored bool
+
 	homeDir                = os.ExpandEnv("$HOME")
 	dataDirName            = fmt.Sprintf(".%s", constants.AppName)
 	defaultDbDir           = filepath.Join(homeDir, dataDirName, "db")
@@ -169,11 +177,16 @@ func init() {
 	// DJTX fees:
 	fs.Uint64Var(&Config.TxFee, "tx-fee", units.MilliDjtx, "Transaction fee, in nDJTX")
 
+	// Asset holder index:
+	fs.BoolVar(&Config.IndexAssetHolders, "index-asset-holders", false, "If true, the X-Chain indexes the balance of every holder of every asset")
+
 	// Uptime requirement:
 	fs.Float64Var(&Config.UptimeRequirement, "uptime-requirement", 0, "Percent of time a validator must be online to receive rewards")
 
-	// Minimum stake, in nDJTX, required to validate the primary network
-	fs.Uint64Var(&Config.MinStake, "min-stake", 5*units.MilliDjtx, "Minimum stake, in nDJTX, required to validate the primary network")
+	// Deprecated minimum stake. The minimum stake is part of the genesis so that
+	// every node on the network uses the same value. The flag is still accepted
+	// so that existing configs keep working, but it's ignored.
+	minStake := fs.Uint64("min-stake", 0, "Deprecated and ignored. The minimum stake is defined by the staking parameters in the P-Chain genesis")
 
 	// Assertions:
 	fs.BoolVar(&loggingConfig.Assertions, "assertions-enabled", true, "Turn on assertion execution")
@@ -200,6 +213,13 @@ func init() {
 	// Bootstrapping:
 	bootstrapIPs := fs.String("bootstrap-ips", "default", "Comma separated list of bootstrap peer ips to connect to. Example: 127.0.0.1:9630,127.0.0.1:9631")
 	bootstrapIDs := fs.String("bootstrap-ids", "default", "Comma separated list of bootstrap peer ids to connect to. Example: NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,NodeID-8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
+	fs.IntVar(&Config.BootstrapFetchConfig.MaxOutstandingRequests, "bootstrap-max-outstanding-requests", 0, "Maximum number of ancestor requests outstanding at once while bootstrapping. If 0, bootstrap-max-outstanding-requests-per-peer per bootstrap peer.")
+	fs.IntVar(&Config.BootstrapFetchConfig.MaxOutstandingRequestsPerPeer, "bootstrap-max-outstanding-requests-per-peer", common.DefaultMaxOutstandingRequestsPerPeer, "Number of ancestor requests outstanding to a bootstrap peer before other peers are preferred")
+	fs.IntVar(&Config.BootstrapFetchConfig.MaxRequestsPerContainer, "bootstrap-max-requests-per-block", common.DefaultMaxRequestsPerContainer, "Number of bootstrap peers a block of a linear chain is requested from at once while there are spare ancestor requests")
+	fs.Float64Var(&Config.BootstrapFetchConfig.ObservationWeight, "bootstrap-observation-weight", common.DefaultObservationWeight, "Weight, in (0, 1], of the latest response in a bootstrap peer's moving averages of latency and response size")
+	fs.BoolVar(&Config.BootstrapFetchConfig.ExecuteWhileFetching, "bootstrap-execute-while-fetching", false, "Execute fetched containers whose dependencies are accepted while bootstrapping continues to fetch")
+	fs.BoolVar(&Config.BootstrapStateSync, "bootstrap-state-sync", false, "Sync the state of linear chains whose VM supports it to a recent accepted block, rather than executing every block since genesis, while bootstrapping")
+	bootstrapCheckpoints := fs.String("bootstrap-checkpoints", "", "Comma separated list of trusted blocks to sync the state of chains to, rather than the highest block that the bootstrap peers summarize the state after. Example: <chainID>:<blockID>:<height>")
 
 	// Staking:
 	consensusPort := fs.Uint("staking-port", 9651, "Port of the consensus server")
@@ -237,6 +257,7 @@ func init() {
 	fs.IntVar(&Config.ConsensusParams.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
 	fs.IntVar(&Config.ConsensusParams.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
 	fs.IntVar(&Config.ConsensusParams.ConcurrentRepolls, "snow-concurrent-repolls", 1, "Minimum number of concurrent polls for finalizing consensus")
+	consensusConfigFile := fs.String("snow-config-file", "", "JSON file specifying the consensus parameters of specific subnets and chains, in place of the snow-* flags. Example: {\"subnets\":{\"<subnetID>\":{\"k\":3,\"alpha\":2}},\"chains\":{\"<chainID>\":{\"betaVirtuous\":10}}}")
 
 	// Enable/Disable APIs:
 	fs.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", false, "If true, this node exposes the Admin API")
@@ -257,6 +278,8 @@ func init() {
 	// Router Configuration:
 	consensusGossipFrequency := fs.Int64("consensus-gossip-frequency", int64(10*time.Second), "Frequency of gossiping accepted frontiers.")
 	consensusShutdownTimeout := fs.Int64("consensus-shutdown-timeout", int64(1*time.Second), "Timeout before killing an unresponsive chain.")
+	fs.Float64Var(&Config.Misbehavior.BenchThreshold, "misbehavior-bench-threshold", common.DefaultMisbehaviorBenchThreshold, "Misbehavior score at which a validator is no longer sampled for queries, until its score decays below half of it. If 0, validators are never benched.")
+	misbehaviorHalfLife := fs.Int64("misbehavior-half-life", int64(common.DefaultMisbehaviorHalfLife), "Time it takes for the misbehavior score of a validator to halve.")
 
 	ferr := fs.Parse(os.Args[1:])
 
@@ -283,6 +306,8 @@ func init() {
 		os.Exit(2)
 	}
 
+	minStakeIgnored = *minStake != 0
+
 	networkID, err := genesis.NetworkID(*networkName)
 	if errs.Add(err); err != nil {
 		return
@@ -391,6 +416,50 @@ func init() {
 		}
 	}
 
+	Config.BootstrapCheckpoints = make(map[[32]byte]bootstrap.Checkpoint)
+	for _, checkpointStr := range strings.Split(*bootstrapCheckpoints, ",") {
+		if checkpointStr == "" {
+			continue
+		}
+		fields := strings.Split(checkpointStr, ":")
+		if len(fields) != 3 {
+			errs.Add(fmt.Errorf("couldn't parse bootstrap checkpoint %q", checkpointStr))
+			return
+		}
+		chainID, err := ids.FromString(fields[0])
+		if err != nil {
+			errs.Add(fmt.Errorf("couldn't parse bootstrap checkpoint chain ID: %w", err))
+			return
+		}
+		blkID, err := ids.FromString(fields[1])
+		if err != nil {
+			errs.Add(fmt.Errorf("couldn't parse bootstrap checkpoint block ID: %w", err))
+			return
+		}
+		height, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			errs.Add(fmt.Errorf("couldn't parse bootstrap checkpoint height: %w", err))
+			return
+		}
+		Config.BootstrapCheckpoints[chainID.Key()] = bootstrap.Checkpoint{
+			BlockID: blkID,
+			Height:  height,
+		}
+	}
+
+	if *consensusConfigFile != "" {
+		configBytes, err := ioutil.ReadFile(*consensusConfigFile)
+		if err != nil {
+			errs.Add(fmt.Errorf("couldn't read consensus config file: %w", err))
+			return
+		}
+		Config.ConsensusConfig, err = chains.ParseConsensusConfig(configBytes, Config.ConsensusParams)
+		if err != nil {
+			errs.Add(err)
+			return
+		}
+	}
+
 	// Plugins
 	if _, err := os.Stat(Config.PluginDir); os.IsNotExist(err) {
 		for _, dir := range defaultPluginDirs {
@@ -500,4 +569,12 @@ func init() {
 	}
 	Config.ConsensusGossipFrequency = time.Duration(*consensusGossipFrequency)
 	Config.ConsensusShutdownTimeout = time.Duration(*consensusShutdownTimeout)
+
+	if Config.Misbehavior.BenchThreshold < 0 {
+		errs.Add(errors.New("misbehavior bench threshold can't be negative"))
+	}
+	if *misbehaviorHalfLife <= 0 {
+		errs.Add(errors.New("misbehavior half life must be positive"))
+	}
+	Config.Misbehavior.HalfLife = time.Duration(*misbehaviorHalfLife)
 }
@@ -160,3 +160,41 @@ func (m Builder) Chits(chainID ids.ID, requestID uint32, containerIDs ids.Set) (
 		ContainerIDs: containerIDBytes,
 	})
 }
+
+// GetStateChunk message
+func (m Builder) GetStateChunk(chainID ids.ID, requestID uint32, deadline uint64, containerID ids.ID, index uint32) (Msg, error) {
+	return m.Pack(GetStateChunk, map[Field]interface{}{
+		ChainID:     chainID.Bytes(),
+		RequestID:   requestID,
+		Deadline:    deadline,
+		ContainerID: containerID.Bytes(),
+		ChunkIndex:  index,
+	})
+}
+
+// StateChunk message
+func (m Builder) StateChunk(chainID ids.ID, requestID uint32, chunk []byte) (Msg, error) {
+	return m.Pack(StateChunk, map[Field]interface{}{
+		ChainID:        chainID.Bytes(),
+		RequestID:      requestID,
+		ContainerBytes: chunk,
+	})
+}
+
+// GetStateSummary message
+func (m Builder) GetStateSummary(chainID ids.ID, requestID uint32, deadline uint64) (Msg, error) {
+	return m.Pack(GetStateSummary, map[Field]interface{}{
+		ChainID:   chainID.Bytes(),
+		RequestID: requestID,
+		Deadline:  deadline,
+	})
+}
+
+// StateSummary message
+func (m Builder) StateSummary(chainID ids.ID, requestID uint32, summary []byte) (Msg, error) {
+	return m.Pack(StateSummary, map[Field]interface{}{
+		ChainID:        chainID.Bytes(),
+		RequestID:      requestID,
+		ContainerBytes: summary,
+	})
+}
@@ -324,3 +324,97 @@ func TestBuildChits(t *testing.T) {
 	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
 	assert.Equal(t, containerIDs, parsedMsg.Get(ContainerIDs))
 }
+
+func TestBuildGetStateChunk(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+	containerID := ids.Empty.Prefix(1)
+	index := uint32(3)
+
+	msg, err := TestBuilder.GetStateChunk(chainID, requestID, deadline, containerID, index)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetStateChunk, msg.Op())
+	assert.Equal(t, chainID.Bytes(), msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, deadline, msg.Get(Deadline))
+	assert.Equal(t, containerID.Bytes(), msg.Get(ContainerID))
+	assert.Equal(t, index, msg.Get(ChunkIndex))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetStateChunk, parsedMsg.Op())
+	assert.Equal(t, chainID.Bytes(), parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+	assert.Equal(t, containerID.Bytes(), parsedMsg.Get(ContainerID))
+	assert.Equal(t, index, parsedMsg.Get(ChunkIndex))
+}
+
+func TestBuildStateChunk(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	chunk := []byte{2}
+
+	msg, err := TestBuilder.StateChunk(chainID, requestID, chunk)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, StateChunk, msg.Op())
+	assert.Equal(t, chainID.Bytes(), msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, chunk, msg.Get(ContainerBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, StateChunk, parsedMsg.Op())
+	assert.Equal(t, chainID.Bytes(), parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, chunk, parsedMsg.Get(ContainerBytes))
+}
+
+func TestBuildGetStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+
+	msg, err := TestBuilder.GetStateSummary(chainID, requestID, deadline)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetStateSummary, msg.Op())
+	assert.Equal(t, chainID.Bytes(), msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, deadline, msg.Get(Deadline))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetStateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID.Bytes(), parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+}
+
+func TestBuildStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	summary := []byte{2}
+
+	msg, err := TestBuilder.StateSummary(chainID, requestID, summary)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, StateSummary, msg.Op())
+	assert.Equal(t, chainID.Bytes(), msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, summary, msg.Get(ContainerBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, StateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID.Bytes(), parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, summary, parsedMsg.Get(ContainerBytes))
+}
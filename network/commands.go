@@ -25,6 +25,7 @@ const (
 	ContainerBytes                   // Used for gossiping
 	ContainerIDs                     // Used for querying
 	MultiContainerBytes              // Used in MultiPut
+	ChunkIndex                       // Used for state sync
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -56,6 +57,8 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case MultiContainerBytes:
 		return wrappers.TryPack2DBytes
+	case ChunkIndex:
+		return wrappers.TryPackInt
 	default:
 		return nil
 	}
@@ -90,6 +93,8 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case MultiContainerBytes:
 		return wrappers.TryUnpack2DBytes
+	case ChunkIndex:
+		return wrappers.TryUnpackInt
 	default:
 		return nil
 	}
@@ -123,6 +128,8 @@ func (f Field) String() string {
 		return "Container IDs"
 	case MultiContainerBytes:
 		return "MultiContainerBytes"
+	case ChunkIndex:
+		return "ChunkIndex"
 	default:
 		return "Unknown Field"
 	}
@@ -167,6 +174,14 @@ func (op Op) String() string {
 		return "pull_query"
 	case Chits:
 		return "chits"
+	case GetStateChunk:
+		return "get_state_chunk"
+	case StateChunk:
+		return "state_chunk"
+	case GetStateSummary:
+		return "get_state_summary"
+	case StateSummary:
+		return "state_summary"
 	default:
 		return "Unknown Op"
 	}
@@ -194,6 +209,11 @@ const (
 	PushQuery
 	PullQuery
 	Chits
+	// State sync:
+	GetStateChunk
+	StateChunk
+	GetStateSummary
+	StateSummary
 )
 
 // Defines the messages that can be sent/received with this network
@@ -219,5 +239,10 @@ var (
 		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
 		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
 		Chits:     {ChainID, RequestID, ContainerIDs},
+		// State sync:
+		GetStateChunk:   {ChainID, RequestID, Deadline, ContainerID, ChunkIndex},
+		StateChunk:      {ChainID, RequestID, ContainerBytes},
+		GetStateSummary: {ChainID, RequestID, Deadline},
+		StateSummary:    {ChainID, RequestID, ContainerBytes},
 	}
 )
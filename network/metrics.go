@@ -60,7 +60,9 @@ type metrics struct {
 	getAcceptedFrontier, acceptedFrontier,
 	getAccepted, accepted,
 	get, getAncestors, put, multiPut,
-	pushQuery, pullQuery, chits messageMetrics
+	pushQuery, pullQuery, chits,
+	getStateChunk, stateChunk,
+	getStateSummary, stateSummary messageMetrics
 }
 
 func (m *metrics) initialize(registerer prometheus.Registerer) error {
@@ -94,6 +96,10 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 	errs.Add(m.pushQuery.initialize(PushQuery, registerer))
 	errs.Add(m.pullQuery.initialize(PullQuery, registerer))
 	errs.Add(m.chits.initialize(Chits, registerer))
+	errs.Add(m.getStateChunk.initialize(GetStateChunk, registerer))
+	errs.Add(m.stateChunk.initialize(StateChunk, registerer))
+	errs.Add(m.getStateSummary.initialize(GetStateSummary, registerer))
+	errs.Add(m.stateSummary.initialize(StateSummary, registerer))
 
 	return errs.Err
 }
@@ -134,6 +140,14 @@ func (m *metrics) message(msgType Op) *messageMetrics {
 		return &m.pullQuery
 	case Chits:
 		return &m.chits
+	case GetStateChunk:
+		return &m.getStateChunk
+	case StateChunk:
+		return &m.stateChunk
+	case GetStateSummary:
+		return &m.getStateSummary
+	case StateSummary:
+		return &m.stateSummary
 	default:
 		return nil
 	}
@@ -612,6 +612,112 @@ func (n *network) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint3
 	}
 }
 
+// GetStateChunk implements the Sender interface.
+func (n *network) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, index uint32) {
+	msg, err := n.b.GetStateChunk(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), containerID, index)
+	if err != nil {
+		n.log.Error("failed to build GetStateChunk message: %s", err)
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	peer, sent := n.peers[validatorID.Key()]
+	if sent {
+		sent = peer.send(msg)
+	}
+	if !sent {
+		n.log.Debug("failed to send GetStateChunk(%s, %s, %d, %s, %d)",
+			validatorID,
+			chainID,
+			requestID,
+			containerID,
+			index)
+		n.executor.Add(func() { n.router.GetStateChunkFailed(validatorID, chainID, requestID) })
+		n.getStateChunk.numFailed.Inc()
+	} else {
+		n.getStateChunk.numSent.Inc()
+	}
+}
+
+// StateChunk implements the Sender interface.
+func (n *network) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	msg, err := n.b.StateChunk(chainID, requestID, chunk)
+	if err != nil {
+		n.log.Error("failed to build StateChunk message because of chunk of size %d", len(chunk))
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	peer, sent := n.peers[validatorID.Key()]
+	if sent {
+		sent = peer.send(msg)
+	}
+	if !sent {
+		n.log.Debug("failed to send StateChunk(%s, %s, %d, %d)",
+			validatorID,
+			chainID,
+			requestID,
+			len(chunk))
+		n.stateChunk.numFailed.Inc()
+	} else {
+		n.stateChunk.numSent.Inc()
+	}
+}
+
+// GetStateSummary implements the Sender interface.
+func (n *network) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) {
+	msg, err := n.b.GetStateSummary(chainID, requestID, uint64(deadline.Sub(n.clock.Time())))
+	n.log.AssertNoError(err)
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, validatorID := range validatorIDs.List() {
+		vID := validatorID
+		peer, sent := n.peers[vID.Key()]
+		if sent {
+			sent = peer.send(msg)
+		}
+		if !sent {
+			n.executor.Add(func() { n.router.GetStateSummaryFailed(vID, chainID, requestID) })
+			n.getStateSummary.numFailed.Inc()
+		} else {
+			n.getStateSummary.numSent.Inc()
+		}
+	}
+}
+
+// StateSummary implements the Sender interface.
+func (n *network) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	msg, err := n.b.StateSummary(chainID, requestID, summary)
+	if err != nil {
+		n.log.Error("failed to build StateSummary message because of summary of size %d", len(summary))
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	peer, sent := n.peers[validatorID.Key()]
+	if sent {
+		sent = peer.send(msg)
+	}
+	if !sent {
+		n.log.Debug("failed to send StateSummary(%s, %s, %d, %d)",
+			validatorID,
+			chainID,
+			requestID,
+			len(summary))
+		n.stateSummary.numFailed.Inc()
+	} else {
+		n.stateSummary.numSent.Inc()
+	}
+}
+
 // Gossip attempts to gossip the container to the network
 func (n *network) Gossip(chainID, containerID ids.ID, container []byte) {
 	if err := n.gossipContainer(chainID, containerID, container); err != nil {
@@ -620,6 +726,15 @@ func (n *network) Gossip(chainID, containerID ids.ID, container []byte) {
 	}
 }
 
+// GossipTx attempts to gossip the unconfirmed transaction to the validators
+// of the network
+func (n *network) GossipTx(chainID, txID ids.ID, tx []byte) {
+	if err := n.gossipTx(chainID, txID, tx); err != nil {
+		n.log.Debug("failed to GossipTx(%s, %s): %s", chainID, txID, err)
+		n.log.Verbo("tx:\n%s", formatting.DumpBytes{Bytes: tx})
+	}
+}
+
 // Accept is called after every consensus decision
 func (n *network) Accept(chainID, containerID ids.ID, container []byte) error {
 	return n.gossipContainer(chainID, containerID, container)
@@ -742,7 +857,38 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte)
 	for _, peer := range n.peers {
 		allPeers = append(allPeers, peer)
 	}
+	return n.sendToSample(msg, allPeers)
+}
+
+// gossipTx sends the transaction to a random sample of the connected
+// validators. If no validators are connected, the transaction is sent to a
+// random sample of all the connected peers.
+func (n *network) gossipTx(chainID, txID ids.ID, tx []byte) error {
+	msg, err := n.b.Put(chainID, constants.GossipTxRequestID, txID, tx)
+	if err != nil {
+		return fmt.Errorf("attempted to pack too large of a Put message.\nTx length: %d", len(tx))
+	}
 
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	allPeers := make([]*peer, 0, len(n.peers))
+	stakers := []*peer(nil)
+	for _, peer := range n.peers {
+		allPeers = append(allPeers, peer)
+		if n.vdrs.Contains(peer.id) {
+			stakers = append(stakers, peer)
+		}
+	}
+	if len(stakers) > 0 {
+		return n.sendToSample(msg, stakers)
+	}
+	return n.sendToSample(msg, allPeers)
+}
+
+// sendToSample sends [msg] to a random sample of [allPeers].
+// assumes the stateLock is held.
+func (n *network) sendToSample(msg Msg, allPeers []*peer) error {
 	numToGossip := n.gossipSize
 	if numToGossip > len(allPeers) {
 		numToGossip = len(allPeers)
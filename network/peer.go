@@ -305,6 +305,14 @@ func (p *peer) handle(msg Msg) {
 		p.pullQuery(msg)
 	case Chits:
 		p.chits(msg)
+	case GetStateChunk:
+		p.getStateChunk(msg)
+	case StateChunk:
+		p.stateChunk(msg)
+	case GetStateSummary:
+		p.getStateSummary(msg)
+	case StateSummary:
+		p.stateSummary(msg)
 	default:
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.id, op.String())
 	}
@@ -695,6 +703,49 @@ func (p *peer) chits(msg Msg) {
 	p.net.router.Chits(p.id, chainID, requestID, containerIDs)
 }
 
+// assumes the stateLock is not held
+func (p *peer) getStateChunk(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+	index := msg.Get(ChunkIndex).(uint32)
+
+	p.net.router.GetStateChunk(p.id, chainID, requestID, deadline, containerID, index)
+}
+
+// assumes the stateLock is not held
+func (p *peer) stateChunk(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	chunk := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.StateChunk(p.id, chainID, requestID, chunk)
+}
+
+// assumes the stateLock is not held
+func (p *peer) getStateSummary(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+
+	p.net.router.GetStateSummary(p.id, chainID, requestID, deadline)
+}
+
+// assumes the stateLock is not held
+func (p *peer) stateSummary(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	summary := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.StateSummary(p.id, chainID, requestID, summary)
+}
+
 // assumes the stateLock is not held
 func (p *peer) discardIP() {
 	// By clearing the IP, we will not attempt to reconnect to this peer
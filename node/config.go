@@ -6,9 +6,12 @@ package node
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -26,12 +29,12 @@ type Config struct {
 	// Transaction fee configuration
 	TxFee uint64
 
+	// Index the holders of every X-Chain asset
+	IndexAssetHolders bool
+
 	// Staking uptime requirements
 	UptimeRequirement float64
 
-	// Minimum stake, in nDJTX, required to validate the primary network
-	MinStake uint64
-
 	// Assertions configuration
 	EnableAssertions bool
 
@@ -57,7 +60,15 @@ type Config struct {
 	NetworkConfig timer.AdaptiveTimeoutConfig
 
 	// Bootstrapping configuration
-	BootstrapPeers []*Peer
+	BootstrapPeers       []*Peer
+	BootstrapFetchConfig common.FetchConfig
+	BootstrapStateSync   bool
+	// Key: Chain ID
+	// Value: Trusted block to sync the state of the chain to
+	BootstrapCheckpoints map[[32]byte]bootstrap.Checkpoint
+
+	// Parameters of benching misbehaving validators from being sampled
+	Misbehavior common.MisbehaviorConfig
 
 	// HTTP configuration
 	HTTPHost            string
@@ -83,6 +94,8 @@ type Config struct {
 
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
+	// Consensus parameters of specific subnets and chains
+	ConsensusConfig chains.ConsensusConfig
 
 	// Throughput configuration
 	ThroughputPort          uint16
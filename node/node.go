@@ -438,6 +438,10 @@ func (n *Node) initChainManager(djtxAssetID ids.ID) error {
 		MaxNonStakerPendingMsgs: uint32(n.Config.MaxNonStakerPendingMsgs),
 		StakerMSGPortion:        n.Config.StakerMSGPortion,
 		StakerCPUPortion:        n.Config.StakerCPUPortion,
+		BootstrapFetchConfig:    n.Config.BootstrapFetchConfig,
+		BootstrapStateSync:      n.Config.BootstrapStateSync,
+		BootstrapCheckpoints:    n.Config.BootstrapCheckpoints,
+		Misbehavior:             n.Config.Misbehavior,
 		Log:                     n.Log,
 		LogFactory:              n.LogFactory,
 		VMManager:               n.vmManager,
@@ -447,6 +451,7 @@ func (n *Node) initChainManager(djtxAssetID ids.ID) error {
 		Router:                  n.Config.ConsensusRouter,
 		Net:                     n.Net,
 		ConsensusParams:         n.Config.ConsensusParams,
+		ConsensusConfig:         n.Config.ConsensusConfig,
 		Validators:              n.vdrs,
 		NodeID:                  n.ID,
 		NetworkID:               n.Config.NetworkID,
@@ -475,11 +480,11 @@ func (n *Node) initChainManager(djtxAssetID ids.ID) error {
 			Validators:       vdrs,
 			StakingEnabled:   n.Config.EnableStaking,
 			Fee:              n.Config.TxFee,
-			MinStake:         n.Config.MinStake,
 			UptimePercentage: n.Config.UptimeRequirement,
 		}),
 		n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{
-			Fee: n.Config.TxFee,
+			Fee:          n.Config.TxFee,
+			IndexHolders: n.Config.IndexAssetHolders,
 		}),
 		n.vmManager.RegisterVMFactory(genesis.EVMID, &rpcchainvm.Factory{
 			Path: filepath.Join(n.Config.PluginDir, "evm"),
@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+const (
+	benchK           = 20
+	benchAlpha       = 15
+	benchTxsPerVtx   = 10
+	benchNumTxs      = 10000
+	benchNumBranches = 2
+)
+
+// newBenchConsensus returns consensus with [numBranches] chains of vertices
+// processing, with [benchNumTxs] transactions in total. The i-th transaction of
+// every branch spends the same input, so if there are multiple branches, every
+// transaction is rogue. The polls can never finalize a transaction. Returns the
+// tips of the branches.
+func newBenchConsensus(b *testing.B, factory Factory, numBranches int) (Consensus, []Vertex) {
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:           prometheus.NewRegistry(),
+			K:                 benchK,
+			Alpha:             benchAlpha,
+			BetaVirtuous:      math.MaxInt32,
+			BetaRogue:         math.MaxInt32,
+			ConcurrentRepolls: 1,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	genesis := &TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	avl := factory.New()
+	if err := avl.Initialize(snow.DefaultContextTest(), params, []Vertex{genesis}); err != nil {
+		b.Fatal(err)
+	}
+
+	numVtxs := benchNumTxs / benchTxsPerVtx / numBranches
+	inputIDs := make([]ids.ID, numVtxs*benchTxsPerVtx)
+	for i := range inputIDs {
+		inputIDs[i] = ids.GenerateTestID()
+	}
+
+	tips := make([]Vertex, numBranches)
+	for branch := range tips {
+		tips[branch] = genesis
+		for height := 0; height < numVtxs; height++ {
+			txs := make([]snowstorm.Tx, benchTxsPerVtx)
+			for i := range txs {
+				tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+					IDV:     ids.GenerateTestID(),
+					StatusV: choices.Processing,
+				}}
+				tx.InputIDsV.Add(inputIDs[height*benchTxsPerVtx+i])
+				txs[i] = tx
+			}
+			vtx := &TestVertex{
+				TestDecidable: choices.TestDecidable{
+					IDV:     ids.GenerateTestID(),
+					StatusV: choices.Processing,
+				},
+				ParentsV: []Vertex{tips[branch]},
+				HeightV:  uint64(height + 1),
+				TxsV:     txs,
+			}
+			if err := avl.Add(vtx); err != nil {
+				b.Fatal(err)
+			}
+			tips[branch] = vtx
+		}
+	}
+	return avl, tips
+}
+
+// benchmarkRecordPoll records polls where [benchAlpha] validators vote for the
+// tip of the first branch, and the others for the tips of the other branches
+func benchmarkRecordPoll(b *testing.B, factory Factory, numBranches int) {
+	avl, tips := newBenchConsensus(b, factory, numBranches)
+
+	votes := ids.UniqueBag{}
+	for vdr := 0; vdr < benchK; vdr++ {
+		tip := tips[0]
+		if vdr >= benchAlpha {
+			tip = tips[vdr%numBranches]
+		}
+		votes.Add(uint(vdr), tip.ID())
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := avl.RecordPoll(votes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRecordPollVirtuous(b *testing.B) {
+	benchmarkRecordPoll(b, TopologicalFactory{}, 1)
+}
+
+func BenchmarkRecordPollRogue(b *testing.B) {
+	benchmarkRecordPoll(b, TopologicalFactory{}, benchNumBranches)
+}
@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
@@ -53,6 +55,22 @@ type Consensus interface {
 	// Returns a set of vertex IDs that are preferred
 	Preferences() ids.Set
 
+	// Returns the set of transaction IDs that are preferred
+	TxPreferences() ids.Set
+
+	// Returns the number of consecutive successful polls the transaction has
+	// received. Returns false if the transaction isn't processing.
+	TxConfidence(txID ids.ID) (int, bool)
+
+	// Returns the [max] oldest processing vertices, oldest first, and the
+	// number of processing vertices
+	ProcessingVertices(max int) ([]VertexInfo, int, error)
+
+	// Returns the [max] oldest processing transactions, each with its [max]
+	// oldest conflicts, oldest first, and the number of processing
+	// transactions
+	ProcessingTxs(max int) ([]snowstorm.TxInfo, int)
+
 	// RecordPoll collects the results of a network poll. If a result has not
 	// been added, the result is dropped. Returns if a critical error has
 	// occurred.
@@ -68,3 +86,21 @@ type Consensus interface {
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
 }
+
+// VertexInfo describes the state of a processing vertex
+type VertexInfo struct {
+	ID ids.ID
+
+	// Time the vertex was added
+	Issued time.Time
+
+	// Preferred is true if the vertex is strongly preferred
+	Preferred bool
+	// Rogue is true if the vertex isn't strongly virtuous
+	Rogue bool
+	// Lowest confidence of the processing transactions in the vertex
+	Confidence int
+
+	// Transactions in the vertex that are still processing
+	Txs []ids.ID
+}
@@ -29,6 +29,7 @@ var (
 		VirtuousTest,
 		VirtuousSkippedUpdateTest,
 		VotingTest,
+		ProcessingTest,
 		IgnoreInvalidVotingTest,
 		TransitiveVotingTest,
 		SplitVotingTest,
@@ -682,6 +683,132 @@ func VotingTest(t *testing.T, factory Factory) {
 	}
 }
 
+func ProcessingTest(t *testing.T, factory Factory) {
+	avl := factory.New()
+
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:           prometheus.NewRegistry(),
+			K:                 2,
+			Alpha:             2,
+			BetaVirtuous:      1,
+			BetaRogue:         2,
+			ConcurrentRepolls: 1,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	vts := []Vertex{
+		&TestVertex{TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		}},
+		&TestVertex{TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		}},
+	}
+	utxos := []ids.ID{ids.GenerateTestID()}
+
+	avl.Initialize(snow.DefaultContextTest(), params, vts)
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV.Add(utxos[0])
+
+	vtx0 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+
+	tx1 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx1.InputIDsV.Add(utxos[0])
+
+	vtx1 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx1},
+	}
+
+	if err := avl.Add(vtx0); err != nil {
+		t.Fatal(err)
+	} else if err := avl.Add(vtx1); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := ids.UniqueBag{}
+	sm.Add(0, vtx1.IDV)
+	sm.Add(1, vtx1.IDV)
+	if err := avl.RecordPoll(sm); err != nil {
+		t.Fatal(err)
+	}
+
+	vtxInfos, numProcessing, err := avl.ProcessingVertices(10)
+	if err != nil {
+		t.Fatal(err)
+	} else if numProcessing != 2 {
+		t.Fatalf("Wrong number of processing vertices. Expected 2 got %d", numProcessing)
+	} else if len(vtxInfos) != 2 {
+		t.Fatalf("Wrong number of vertices returned. Expected 2 got %d", len(vtxInfos))
+	}
+	for _, vtxInfo := range vtxInfos {
+		switch {
+		case vtxInfo.ID.Equals(vtx0.IDV):
+			if vtxInfo.Preferred || !vtxInfo.Rogue || vtxInfo.Confidence != 0 {
+				t.Fatalf("Vtx0 should be rogue and not preferred")
+			} else if !ids.UnsortedEquals([]ids.ID{tx0.IDV}, vtxInfo.Txs) {
+				t.Fatalf("Wrong processing txs in vtx0")
+			}
+		case vtxInfo.ID.Equals(vtx1.IDV):
+			if !vtxInfo.Preferred || !vtxInfo.Rogue || vtxInfo.Confidence != 1 {
+				t.Fatalf("Vtx1 should be rogue and preferred with a confidence of 1")
+			} else if !ids.UnsortedEquals([]ids.ID{tx1.IDV}, vtxInfo.Txs) {
+				t.Fatalf("Wrong processing txs in vtx1")
+			}
+		default:
+			t.Fatalf("Unexpected vertex %s", vtxInfo.ID)
+		}
+	}
+
+	if txInfos, numProcessing := avl.ProcessingTxs(10); numProcessing != 2 {
+		t.Fatalf("Wrong number of processing txs. Expected 2 got %d", numProcessing)
+	} else if len(txInfos) != 2 {
+		t.Fatalf("Wrong number of txs returned. Expected 2 got %d", len(txInfos))
+	}
+
+	if vtxInfos, numProcessing, err := avl.ProcessingVertices(1); err != nil {
+		t.Fatal(err)
+	} else if numProcessing != 2 {
+		t.Fatalf("Wrong number of processing vertices. Expected 2 got %d", numProcessing)
+	} else if len(vtxInfos) != 1 {
+		t.Fatalf("Wrong number of vertices returned. Expected 1 got %d", len(vtxInfos))
+	}
+
+	if err := avl.RecordPoll(sm); err != nil {
+		t.Fatal(err)
+	} else if vtxInfos, numProcessing, err := avl.ProcessingVertices(10); err != nil {
+		t.Fatal(err)
+	} else if numProcessing != 0 || len(vtxInfos) != 0 {
+		t.Fatalf("No vertices should be processing")
+	} else if txInfos, numProcessing := avl.ProcessingTxs(10); numProcessing != 0 || len(txInfos) != 0 {
+		t.Fatalf("No txs should be processing")
+	}
+}
+
 func IgnoreInvalidVotingTest(t *testing.T, factory Factory) {
 	avl := factory.New()
 
@@ -4,7 +4,9 @@
 package avalanche
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -82,3 +84,20 @@ func (m *metrics) Rejected(id ids.ID) {
 	m.latRejected.Observe(float64(end.Sub(start).Milliseconds()))
 	m.numProcessing.Dec()
 }
+
+// oldest sorts [vtxIDs] by the time they were issued, oldest first, and returns
+// up to [max] of them
+func (m *metrics) oldest(vtxIDs []ids.ID, max int) []ids.ID {
+	sort.Slice(vtxIDs, func(i, j int) bool {
+		iIssued := m.processing[vtxIDs[i].Key()]
+		jIssued := m.processing[vtxIDs[j].Key()]
+		if !iIssued.Equal(jIssued) {
+			return iIssued.Before(jIssued)
+		}
+		return bytes.Compare(vtxIDs[i].Bytes(), vtxIDs[j].Bytes()) == -1
+	})
+	if len(vtxIDs) > max {
+		vtxIDs = vtxIDs[:max]
+	}
+	return vtxIDs
+}
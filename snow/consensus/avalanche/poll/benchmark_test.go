@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	benchK     = 20
+	benchAlpha = 15
+)
+
+// benchmarkSet issues polls of [benchK] validators, where every validator votes
+// for the same two vertices, until each poll finishes
+func benchmarkSet(b *testing.B, factory Factory) {
+	s := NewSet(factory, logging.NoLog{}, "", prometheus.NewRegistry())
+
+	vdrs := make([]ids.ShortID, benchK)
+	for i := range vdrs {
+		vdrs[i] = ids.GenerateTestShortID()
+	}
+	votes := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		requestID := uint32(n)
+
+		polled := ids.ShortBag{}
+		polled.Add(vdrs...)
+		if !s.Add(requestID, polled) {
+			b.Fatal("should have added the poll")
+		}
+
+		finished := false
+		for _, vdr := range vdrs {
+			if _, finished = s.Vote(requestID, vdr, votes); finished {
+				break
+			}
+		}
+		if !finished {
+			b.Fatal("should have finished the poll")
+		}
+	}
+}
+
+func BenchmarkSetNoEarlyTerm(b *testing.B) {
+	benchmarkSet(b, NewNoEarlyTermFactory())
+}
+
+func BenchmarkSetEarlyTermNoTraversal(b *testing.B) {
+	benchmarkSet(b, NewEarlyTermNoTraversalFactory(benchAlpha))
+}
@@ -30,7 +30,7 @@ func (f *earlyTermNoTraversalFactory) New(vdrs ids.ShortBag) Poll {
 // the result of the poll. However, does not terminate tightly with this bound.
 // It terminates as quickly as it can without performing any DAG traversals.
 type earlyTermNoTraversalPoll struct {
-	votes  ids.UniqueBag
+	votes  tally
 	polled ids.ShortBag
 	alpha  int
 }
@@ -43,7 +43,7 @@ func (p *earlyTermNoTraversalPoll) Vote(vdr ids.ShortID, votes []ids.ID) {
 
 	// track the votes the validator responded with
 	for i := 0; i < count; i++ {
-		p.votes.Add(uint(p.polled.Len()+i), votes...)
+		p.votes.add(uint(p.polled.Len()+i), votes)
 	}
 }
 
@@ -65,17 +65,12 @@ func (p *earlyTermNoTraversalPoll) Finished() bool {
 	// votes will be applied to a single shared ancestor. In this case, the poll
 	// can terminate early, iff there are not enough pending votes for this
 	// ancestor to receive alpha votes.
-	partialVotes := ids.BitSet(0)
-	for _, vote := range p.votes.List() {
-		if voters := p.votes.GetSet(vote); voters.Len() < p.alpha {
-			partialVotes.Union(voters)
-		}
-	}
+	partialVotes := p.votes.partialVoters(p.alpha)
 	return partialVotes.Len()+numPending < p.alpha
 }
 
 // Result returns the result of this poll
-func (p *earlyTermNoTraversalPoll) Result() ids.UniqueBag { return p.votes }
+func (p *earlyTermNoTraversalPoll) Result() ids.UniqueBag { return p.votes.UniqueBag() }
 
 func (p *earlyTermNoTraversalPoll) PrefixedString(prefix string) string {
 	return fmt.Sprintf("waiting on %s", p.polled.PrefixedString(prefix))
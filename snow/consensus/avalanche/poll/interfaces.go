@@ -5,6 +5,7 @@ package poll
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 )
@@ -16,6 +17,14 @@ type Set interface {
 	Add(requestID uint32, vdrs ids.ShortBag) bool
 	Vote(requestID uint32, vdr ids.ShortID, votes []ids.ID) (ids.UniqueBag, bool)
 	Len() int
+	Outstanding(max int) []Info
+}
+
+// Info describes an outstanding poll
+type Info struct {
+	RequestID uint32
+	// Time the poll was issued
+	Start time.Time
 }
 
 // Poll is an outstanding poll
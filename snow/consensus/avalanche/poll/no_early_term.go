@@ -22,7 +22,7 @@ func (noEarlyTermFactory) New(vdrs ids.ShortBag) Poll {
 // noEarlyTermPoll finishes when all polled validators either respond to the
 // query or a timeout occurs
 type noEarlyTermPoll struct {
-	votes  ids.UniqueBag
+	votes  tally
 	polled ids.ShortBag
 }
 
@@ -34,7 +34,7 @@ func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, votes []ids.ID) {
 
 	for i := 0; i < count; i++ {
 		// track the votes the validator responded with
-		p.votes.Add(uint(p.polled.Len()+i), votes...)
+		p.votes.add(uint(p.polled.Len()+i), votes)
 	}
 }
 
@@ -42,7 +42,7 @@ func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, votes []ids.ID) {
 func (p *noEarlyTermPoll) Finished() bool { return p.polled.Len() == 0 }
 
 // Result returns the result of this poll
-func (p *noEarlyTermPoll) Result() ids.UniqueBag { return p.votes }
+func (p *noEarlyTermPoll) Result() ids.UniqueBag { return p.votes.UniqueBag() }
 
 func (p *noEarlyTermPoll) PrefixedString(prefix string) string {
 	return fmt.Sprintf("waiting on %s", p.polled.PrefixedString(prefix))
@@ -97,3 +97,46 @@ func TestSetString(t *testing.T) {
 			str)
 	}
 }
+
+func TestSetOutstanding(t *testing.T) {
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(ids.NewShortID([20]byte{1}))
+
+	if polls := s.Outstanding(10); len(polls) != 0 {
+		t.Fatalf("Shouldn't have any outstanding polls yet")
+	} else if !s.Add(0, vdrs) {
+		t.Fatalf("Should have been able to add a new poll")
+	} else if !s.Add(1, vdrs) {
+		t.Fatalf("Should have been able to add a new poll")
+	}
+
+	polls := s.Outstanding(10)
+	if len(polls) != 2 {
+		t.Fatalf("Should have two outstanding polls")
+	} else if polls[0].RequestID == polls[1].RequestID {
+		t.Fatalf("Returned the same poll twice")
+	} else if polls[0].Start.After(polls[1].Start) {
+		t.Fatalf("Polls should be sorted by the time they were issued")
+	}
+
+	for requestID := uint32(2); requestID < 10; requestID++ {
+		if !s.Add(requestID, vdrs) {
+			t.Fatalf("Should have been able to add a new poll")
+		}
+	}
+	polls = s.Outstanding(3)
+	if len(polls) != 3 {
+		t.Fatalf("Should have returned three polls")
+	}
+	for i, poll := range polls {
+		if poll.RequestID != uint32(i) {
+			t.Fatalf("Should have returned the oldest polls, but returned poll %d at index %d", poll.RequestID, i)
+		}
+	}
+}
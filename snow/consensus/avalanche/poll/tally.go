@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// tally records the voters of each vertex voted for in a poll.
+// A poll receives at most k responses, which are almost always for a few
+// vertices, so the voters are kept in a slice rather than a map. Scanning the
+// slice is faster than hashing, and recording a vote never allocates once the
+// slice holds every vertex voted for.
+type tally struct {
+	voters []idVoters
+}
+
+type idVoters struct {
+	id     ids.ID
+	voters ids.BitSet
+}
+
+// add [voter] to the voters of each of [votes]
+func (t *tally) add(voter uint, votes []ids.ID) {
+	for _, vote := range votes {
+		t.addVoter(voter, vote)
+	}
+}
+
+func (t *tally) addVoter(voter uint, vote ids.ID) {
+	for i := range t.voters {
+		if v := &t.voters[i]; v.id.Equals(vote) {
+			v.voters.Add(voter)
+			return
+		}
+	}
+
+	v := idVoters{id: vote}
+	v.voters.Add(voter)
+	t.voters = append(t.voters, v)
+}
+
+// partialVoters returns the voters of the vertices with less than [alpha]
+// voters
+func (t *tally) partialVoters(alpha int) ids.BitSet {
+	partial := ids.BitSet(0)
+	for _, v := range t.voters {
+		if v.voters.Len() < alpha {
+			partial.Union(v.voters)
+		}
+	}
+	return partial
+}
+
+// UniqueBag returns the voters of each vertex
+func (t *tally) UniqueBag() ids.UniqueBag {
+	bag := make(ids.UniqueBag, len(t.voters))
+	for _, v := range t.voters {
+		bag.UnionSet(v.id, v.voters)
+	}
+	return bag
+}
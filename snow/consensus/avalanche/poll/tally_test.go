@@ -0,0 +1,35 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestTally(t *testing.T) {
+	vtxID0 := ids.NewID([32]byte{1})
+	vtxID1 := ids.NewID([32]byte{2})
+
+	votes := tally{}
+	votes.add(0, []ids.ID{vtxID0, vtxID1})
+	votes.add(1, []ids.ID{vtxID0})
+	votes.add(2, []ids.ID{ids.NewID([32]byte{1})})
+
+	if partial := votes.partialVoters(3); partial.Len() != 1 || !partial.Contains(0) {
+		t.Fatalf("Wrong partial voters: %s", partial)
+	} else if partial := votes.partialVoters(4); partial.Len() != 3 {
+		t.Fatalf("Wrong partial voters: %s", partial)
+	}
+
+	bag := votes.UniqueBag()
+	if len(bag) != 2 {
+		t.Fatalf("Wrong number of vertices in the bag")
+	} else if voters := bag.GetSet(vtxID0); voters.Len() != 3 {
+		t.Fatalf("Wrong voters for %s: %s", vtxID0, voters)
+	} else if voters := bag.GetSet(vtxID1); voters.Len() != 1 || !voters.Contains(0) {
+		t.Fatalf("Wrong voters for %s: %s", vtxID1, voters)
+	}
+}
@@ -51,6 +51,17 @@ type Topological struct {
 	// preferenceCache is the cache for strongly preferred checks
 	// virtuousCache is the cache for strongly virtuous checks
 	preferenceCache, virtuousCache map[[32]byte]bool
+
+	// Reused by every poll, so the traversals of the processing vertices don't
+	// allocate once these have grown to the size of the processing DAG.
+	// kahnNodes are the vertices reachable from the votes of the poll
+	// leaves are the vertices whose votes can be pushed to their parents
+	// frontierScratch are the ancestors left to be traversed
+	// txVotes are the voters of each transaction
+	kahnNodes       map[[32]byte]kahnNode
+	leaves          [][32]byte
+	frontierScratch []Vertex
+	txVotes         map[[32]byte]txVotes
 }
 
 type kahnNode struct {
@@ -58,6 +69,11 @@ type kahnNode struct {
 	votes    ids.BitSet
 }
 
+type txVotes struct {
+	tx    snowstorm.Tx
+	votes ids.BitSet
+}
+
 // Initialize implements the Avalanche interface
 func (ta *Topological) Initialize(
 	ctx *snow.Context,
@@ -76,6 +92,8 @@ func (ta *Topological) Initialize(
 	}
 
 	ta.nodes = make(map[[32]byte]Vertex, minMapSize)
+	ta.kahnNodes = make(map[[32]byte]kahnNode, minMapSize)
+	ta.txVotes = make(map[[32]byte]txVotes, minMapSize)
 
 	ta.cg = &snowstorm.Directed{}
 	if err := ta.cg.Initialize(ctx, params.Parameters); err != nil {
@@ -149,14 +167,60 @@ func (ta *Topological) Virtuous() ids.Set { return ta.virtuous }
 // Preferences implements the Avalanche interface
 func (ta *Topological) Preferences() ids.Set { return ta.preferred }
 
+// TxPreferences implements the Avalanche interface
+func (ta *Topological) TxPreferences() ids.Set { return ta.cg.Preferences() }
+
+// TxConfidence implements the Avalanche interface
+func (ta *Topological) TxConfidence(txID ids.ID) (int, bool) { return ta.cg.Confidence(txID) }
+
+// ProcessingVertices implements the Avalanche interface
+func (ta *Topological) ProcessingVertices(max int) ([]VertexInfo, int, error) {
+	// The oldest processing vertices are returned
+	vtxIDs := make([]ids.ID, 0, len(ta.nodes))
+	for key := range ta.nodes {
+		vtxIDs = append(vtxIDs, ids.NewID(key))
+	}
+
+	vts := []VertexInfo(nil)
+	for _, vtxID := range ta.oldest(vtxIDs, max) {
+		key := vtxID.Key()
+		txs, err := ta.nodes[key].Txs()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		info := VertexInfo{
+			ID:        vtxID,
+			Issued:    ta.processing[key],
+			Preferred: ta.preferenceCache[key],
+			Rogue:     !ta.virtuousCache[key],
+		}
+		for _, tx := range txs {
+			txID := tx.ID()
+			confidence, processing := ta.cg.Confidence(txID)
+			if !processing {
+				continue
+			}
+			if len(info.Txs) == 0 || confidence < info.Confidence {
+				info.Confidence = confidence
+			}
+			info.Txs = append(info.Txs, txID)
+		}
+		vts = append(vts, info)
+	}
+	return vts, len(ta.nodes), nil
+}
+
+// ProcessingTxs implements the Avalanche interface
+func (ta *Topological) ProcessingTxs(max int) ([]snowstorm.TxInfo, int) { return ta.cg.Processing(max) }
+
 // RecordPoll implements the Avalanche interface
 func (ta *Topological) RecordPoll(responses ids.UniqueBag) error {
 	// If it isn't possible to have alpha votes for any transaction, then we can
 	// just reset the confidence values in the conflict graph and not perform
 	// any traversals.
 	partialVotes := ids.BitSet(0)
-	for _, vote := range responses.List() {
-		votes := responses.GetSet(vote)
+	for _, votes := range responses {
 		partialVotes.Union(votes)
 		if partialVotes.Len() >= ta.params.Alpha {
 			break
@@ -169,12 +233,11 @@ func (ta *Topological) RecordPoll(responses ids.UniqueBag) error {
 	}
 
 	// Set up the topological sort: O(|Live Set|)
-	kahns, leaves, err := ta.calculateInDegree(responses)
-	if err != nil {
+	if err := ta.calculateInDegree(responses); err != nil {
 		return err
 	}
 	// Collect the votes for each transaction: O(|Live Set|)
-	votes, err := ta.pushVotes(kahns, leaves)
+	votes, err := ta.pushVotes()
 	if err != nil {
 		return err
 	}
@@ -195,52 +258,50 @@ func (ta *Topological) Quiesce() bool { return ta.cg.Quiesce() }
 // Finalized implements the Avalanche interface
 func (ta *Topological) Finalized() bool { return ta.cg.Finalized() }
 
-// Takes in a list of votes and sets up the topological ordering. Returns the
-// reachable section of the graph annotated with the number of inbound edges and
-// the non-transitively applied votes. Also returns the list of leaf nodes.
-func (ta *Topological) calculateInDegree(responses ids.UniqueBag) (
-	map[[32]byte]kahnNode,
-	[]ids.ID,
-	error,
-) {
-	kahns := make(map[[32]byte]kahnNode, minMapSize)
-	leaves := ids.Set{}
-
-	for _, vote := range responses.List() {
-		key := vote.Key()
+// Takes in a list of votes and sets up the topological ordering. Sets
+// [ta.kahnNodes] to the reachable section of the graph annotated with the
+// number of inbound edges and the non-transitively applied votes, and
+// [ta.leaves] to the leaf nodes.
+func (ta *Topological) calculateInDegree(responses ids.UniqueBag) error {
+	for key := range ta.kahnNodes {
+		delete(ta.kahnNodes, key)
+	}
+
+	for key, votes := range responses {
 		// If it is not found, then the vote is either for something decided,
 		// or something we haven't heard of yet.
 		if vtx := ta.nodes[key]; vtx != nil {
-			kahn, previouslySeen := kahns[key]
+			kahn, previouslySeen := ta.kahnNodes[key]
 			// Add this new vote to the current bag of votes
-			kahn.votes.Union(responses.GetSet(vote))
-			kahns[key] = kahn
+			kahn.votes.Union(votes)
+			ta.kahnNodes[key] = kahn
 
 			if !previouslySeen {
-				// If I've never seen this node before, it is currently a leaf.
-				leaves.Add(vote)
 				parents, err := vtx.Parents()
 				if err != nil {
-					return nil, nil, err
+					return err
 				}
-				kahns, leaves, err = ta.markAncestorInDegrees(kahns, leaves, parents)
-				if err != nil {
-					return nil, nil, err
+				if err := ta.markAncestorInDegrees(parents); err != nil {
+					return err
 				}
 			}
 		}
 	}
 
-	return kahns, leaves.List(), nil
+	// The leaves are the voted for vertices that aren't an ancestor of another
+	// voted for vertex
+	ta.leaves = ta.leaves[:0]
+	for key := range responses {
+		if kahn, exists := ta.kahnNodes[key]; exists && kahn.inDegree == 0 {
+			ta.leaves = append(ta.leaves, key)
+		}
+	}
+	return nil
 }
 
 // adds a new in-degree reference for all nodes
-func (ta *Topological) markAncestorInDegrees(
-	kahns map[[32]byte]kahnNode,
-	leaves ids.Set,
-	deps []Vertex,
-) (map[[32]byte]kahnNode, ids.Set, error) {
-	frontier := make([]Vertex, 0, len(deps))
+func (ta *Topological) markAncestorInDegrees(deps []Vertex) error {
+	frontier := ta.frontierScratch[:0]
 	for _, vtx := range deps {
 		// The vertex may have been decided, no need to vote in that case
 		if !vtx.Status().Decided() {
@@ -253,25 +314,18 @@ func (ta *Topological) markAncestorInDegrees(
 		current := frontier[newLen]
 		frontier = frontier[:newLen]
 
-		currentID := current.ID()
-		currentKey := currentID.Key()
-		kahn, alreadySeen := kahns[currentKey]
+		currentKey := current.ID().Key()
+		kahn, alreadySeen := ta.kahnNodes[currentKey]
 		// I got here through a transitive edge, so increase the in-degree
 		kahn.inDegree++
-		kahns[currentKey] = kahn
-
-		if kahn.inDegree == 1 {
-			// If I am transitively seeing this node for the first
-			// time, it is no longer a leaf.
-			leaves.Remove(currentID)
-		}
+		ta.kahnNodes[currentKey] = kahn
 
 		if !alreadySeen {
 			// If I am seeing this node for the first time, I need to check its
 			// parents
 			parents, err := current.Parents()
 			if err != nil {
-				return nil, nil, err
+				return err
 			}
 			for _, depVtx := range parents {
 				// No need to traverse to a decided vertex
@@ -281,24 +335,23 @@ func (ta *Topological) markAncestorInDegrees(
 			}
 		}
 	}
-	return kahns, leaves, nil
+	ta.frontierScratch = frontier[:0]
+	return nil
 }
 
 // count the number of votes for each operation
-func (ta *Topological) pushVotes(
-	kahnNodes map[[32]byte]kahnNode,
-	leaves []ids.ID,
-) (ids.Bag, error) {
-	votes := make(ids.UniqueBag)
-	txConflicts := make(map[[32]byte]ids.Set, minMapSize)
+func (ta *Topological) pushVotes() (ids.Bag, error) {
+	for key := range ta.txVotes {
+		delete(ta.txVotes, key)
+	}
 
+	leaves := ta.leaves
 	for len(leaves) > 0 {
 		newLeavesSize := len(leaves) - 1
-		leaf := leaves[newLeavesSize]
+		key := leaves[newLeavesSize]
 		leaves = leaves[:newLeavesSize]
 
-		key := leaf.Key()
-		kahn := kahnNodes[key]
+		kahn := ta.kahnNodes[key]
 
 		if vtx := ta.nodes[key]; vtx != nil {
 			txs, err := vtx.Txs()
@@ -307,14 +360,11 @@ func (ta *Topological) pushVotes(
 			}
 			for _, tx := range txs {
 				// Give the votes to the consumer
-				txID := tx.ID()
-				votes.UnionSet(txID, kahn.votes)
-
-				// Map txID to set of Conflicts
-				txKey := txID.Key()
-				if _, exists := txConflicts[txKey]; !exists {
-					txConflicts[txKey] = ta.cg.Conflicts(tx)
-				}
+				txKey := tx.ID().Key()
+				txVotes := ta.txVotes[txKey]
+				txVotes.tx = tx
+				txVotes.votes.Union(kahn.votes)
+				ta.txVotes[txKey] = txVotes
 			}
 
 			parents, err := vtx.Parents()
@@ -322,35 +372,44 @@ func (ta *Topological) pushVotes(
 				return ids.Bag{}, err
 			}
 			for _, dep := range parents {
-				depID := dep.ID()
-				depKey := depID.Key()
-				if depNode, notPruned := kahnNodes[depKey]; notPruned {
+				depKey := dep.ID().Key()
+				if depNode, notPruned := ta.kahnNodes[depKey]; notPruned {
 					depNode.inDegree--
 					// Give the votes to my parents
 					depNode.votes.Union(kahn.votes)
-					kahnNodes[depKey] = depNode
+					ta.kahnNodes[depKey] = depNode
 
 					if depNode.inDegree == 0 {
 						// Only traverse into the leaves
-						leaves = append(leaves, depID)
+						leaves = append(leaves, depKey)
 					}
 				}
 			}
 		}
 	}
-
-	// Create bag of votes for conflicting transactions
-	conflictingVotes := make(ids.UniqueBag)
-	for txHash, conflicts := range txConflicts {
-		txID := ids.NewID(txHash)
-		for conflictTxHash := range conflicts {
-			conflictTxID := ids.NewID(conflictTxHash)
-			conflictingVotes.UnionSet(txID, votes.GetSet(conflictTxID))
+	ta.leaves = leaves[:0]
+
+	// Only transactions that met the alpha threshold are recorded by the
+	// conflict graph, and votes for conflicting transactions can only remove
+	// voters. So, the conflicts are only looked up for the transactions that
+	// received alpha votes and could have conflicts.
+	votes := ids.Bag{}
+	votes.SetThreshold(ta.params.Alpha)
+	for _, txVotes := range ta.txVotes {
+		if txVotes.votes.Len() < ta.params.Alpha {
+			continue
+		}
+		voters := txVotes.votes
+		if !ta.cg.IsVirtuous(txVotes.tx) {
+			for conflictKey := range ta.cg.Conflicts(txVotes.tx) {
+				voters.Difference(ta.txVotes[conflictKey].votes)
+			}
+		}
+		if voters.Len() >= ta.params.Alpha {
+			votes.AddCount(txVotes.tx.ID(), voters.Len())
 		}
 	}
-
-	votes.Difference(&conflictingVotes)
-	return votes.Bag(ta.params.Alpha), nil
+	return votes, nil
 }
 
 // If I've already checked, do nothing
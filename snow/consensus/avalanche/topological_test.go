@@ -5,6 +5,82 @@ package avalanche
 
 import (
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 )
 
 func TestTopological(t *testing.T) { ConsensusTest(t, TopologicalFactory{}) }
+
+func TestTopologicalProcessingVerticesOldest(t *testing.T) {
+	ta := &Topological{}
+
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:           prometheus.NewRegistry(),
+			K:                 1,
+			Alpha:             1,
+			BetaVirtuous:      3,
+			BetaRogue:         3,
+			ConcurrentRepolls: 1,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	vts := []Vertex{&TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}}
+	if err := ta.Initialize(snow.DefaultContextTest(), params, vts); err != nil {
+		t.Fatal(err)
+	}
+
+	vtxs := make([]*TestVertex, 3)
+	for i := range vtxs {
+		tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		}}
+		tx.InputIDsV.Add(ids.GenerateTestID())
+
+		vtxs[i] = &TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: vts,
+			HeightV:  1,
+			TxsV:     []snowstorm.Tx{tx},
+		}
+	}
+
+	start := time.Unix(1000, 0)
+	for i, vtx := range vtxs {
+		ta.clock.Set(start.Add(time.Duration(i) * time.Second))
+		if err := ta.Add(vtx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for max := 1; max <= len(vtxs); max++ {
+		vtxInfos, numProcessing, err := ta.ProcessingVertices(max)
+		if err != nil {
+			t.Fatal(err)
+		} else if numProcessing != len(vtxs) {
+			t.Fatalf("Wrong number of processing vertices. Expected %d got %d", len(vtxs), numProcessing)
+		} else if len(vtxInfos) != max {
+			t.Fatalf("Wrong number of vertices returned. Expected %d got %d", max, len(vtxInfos))
+		}
+		for i, vtxInfo := range vtxInfos {
+			if !vtxInfo.ID.Equals(vtxs[i].ID()) {
+				t.Fatalf("Should have returned the %d oldest vertices, oldest first", max)
+			}
+		}
+	}
+}
@@ -39,6 +39,16 @@ func (sb *binarySnowball) Preference() int {
 	return sb.preference
 }
 
+// Confidence implements the BinarySnowball interface
+func (sb *binarySnowball) Confidence() int {
+	// The snowflake counter tracks the choice of the last successful poll,
+	// which may not be the snowball preference
+	if sb.binarySnowflake.Preference() != sb.Preference() {
+		return 0
+	}
+	return sb.binarySnowflake.Confidence()
+}
+
 // RecordSuccessfulPoll implements the BinarySnowball interface
 func (sb *binarySnowball) RecordSuccessfulPoll(choice int) {
 	sb.numSuccessfulPolls[choice]++
@@ -52,6 +52,9 @@ func (sf *binarySnowflake) RecordSuccessfulPoll(choice int) {
 // RecordUnsuccessfulPoll implements the BinarySnowflake interface
 func (sf *binarySnowflake) RecordUnsuccessfulPoll() { sf.confidence = 0 }
 
+// Confidence implements the BinarySnowflake interface
+func (sf *binarySnowflake) Confidence() int { return sf.confidence }
+
 // Finalized implements the BinarySnowflake interface
 func (sf *binarySnowflake) Finalized() bool { return sf.finalized }
 
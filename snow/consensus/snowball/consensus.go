@@ -34,6 +34,10 @@ type Consensus interface {
 	// instance
 	RecordUnsuccessfulPoll()
 
+	// Returns the number of consecutive successful polls that [choice] has
+	// been preferred in. Returns 0 if [choice] isn't preferred.
+	Confidence(choice ids.ID) int
+
 	// Return whether a choice has been finalized
 	Finalized() bool
 }
@@ -65,6 +69,9 @@ type NnarySnowflake interface {
 	// RecordUnsuccessfulPoll resets the snowflake counter of this instance
 	RecordUnsuccessfulPoll()
 
+	// Returns the number of consecutive successful polls of the preference
+	Confidence() int
+
 	// Return whether a choice has been finalized
 	Finalized() bool
 }
@@ -110,6 +117,9 @@ type BinarySnowflake interface {
 	// RecordUnsuccessfulPoll resets the snowflake counter of this instance
 	RecordUnsuccessfulPoll()
 
+	// Returns the number of consecutive successful polls of the preference
+	Confidence() int
+
 	// Return whether a choice has been finalized
 	Finalized() bool
 }
@@ -146,6 +156,9 @@ type UnarySnowball interface {
 	// RecordUnsuccessfulPoll resets the snowflake counter of this instance
 	RecordUnsuccessfulPoll()
 
+	// Returns the number of consecutive successful polls of the preference
+	Confidence() int
+
 	// Return whether a choice has been finalized
 	Finalized() bool
 
@@ -172,6 +185,9 @@ type UnarySnowflake interface {
 	// RecordUnsuccessfulPoll resets the snowflake counter of this instance
 	RecordUnsuccessfulPoll()
 
+	// Returns the number of consecutive successful polls of the preference
+	Confidence() int
+
 	// Return whether a choice has been finalized
 	Finalized() bool
 
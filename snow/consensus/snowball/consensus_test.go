@@ -41,6 +41,9 @@ func (b *Byzantine) RecordPoll(votes ids.Bag) {}
 // RecordUnsuccessfulPoll implements the Consensus interface
 func (b *Byzantine) RecordUnsuccessfulPoll() {}
 
+// Confidence implements the Consensus interface
+func (b *Byzantine) Confidence(choice ids.ID) int { return 0 }
+
 // Finalized implements the Consensus interface
 func (b *Byzantine) Finalized() bool { return true }
 func (b *Byzantine) String() string  { return b.preference.String() }
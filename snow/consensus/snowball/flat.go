@@ -39,3 +39,11 @@ func (f *Flat) RecordPoll(votes ids.Bag) {
 		f.RecordUnsuccessfulPoll()
 	}
 }
+
+// Confidence implements the Consensus interface
+func (f *Flat) Confidence(choice ids.ID) int {
+	if !f.Preference().Equals(choice) {
+		return 0
+	}
+	return f.nnarySnowball.Confidence()
+}
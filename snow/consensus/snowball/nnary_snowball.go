@@ -46,6 +46,16 @@ func (sb *nnarySnowball) Preference() ids.ID {
 	return sb.preference
 }
 
+// Confidence implements the NnarySnowball interface
+func (sb *nnarySnowball) Confidence() int {
+	// The snowflake counter tracks the choice of the last successful poll,
+	// which may not be the snowball preference
+	if !sb.nnarySnowflake.Preference().Equals(sb.Preference()) {
+		return 0
+	}
+	return sb.nnarySnowflake.Confidence()
+}
+
 // RecordSuccessfulPoll implements the NnarySnowball interface
 func (sb *nnarySnowball) RecordSuccessfulPoll(choice ids.ID) {
 	key := choice.Key()
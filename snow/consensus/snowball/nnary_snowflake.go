@@ -67,6 +67,9 @@ func (sf *nnarySnowflake) RecordSuccessfulPoll(choice ids.ID) {
 // RecordUnsuccessfulPoll implements the NnarySnowflake interface
 func (sf *nnarySnowflake) RecordUnsuccessfulPoll() { sf.confidence = 0 }
 
+// Confidence implements the NnarySnowflake interface
+func (sf *nnarySnowflake) Confidence() int { return sf.confidence }
+
 // Finalized implements the NnarySnowflake interface
 func (sf *nnarySnowflake) Finalized() bool { return sf.finalized }
 
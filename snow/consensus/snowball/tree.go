@@ -85,6 +85,19 @@ func (t *Tree) RecordPoll(votes ids.Bag) {
 // RecordUnsuccessfulPoll implements the Consensus interface
 func (t *Tree) RecordUnsuccessfulPoll() { t.shouldReset = true }
 
+// Confidence implements the Consensus interface
+//
+// The confidence of a choice is the minimum confidence of the snowball
+// instances on its path through the tree, as each of them must finalize for
+// the choice to be accepted.
+func (t *Tree) Confidence(choice ids.ID) int {
+	// If the choice was already decided against, it has no confidence
+	if !ids.EqualSubset(0, t.node.DecidedPrefix(), t.Preference(), choice) {
+		return 0
+	}
+	return t.node.Confidence(choice, t.shouldReset)
+}
+
 func (t *Tree) String() string {
 	builder := strings.Builder{}
 
@@ -125,6 +138,9 @@ type node interface {
 	Add(newChoice ids.ID) node
 	// Apply the votes, reset the model if needed
 	RecordPoll(votes ids.Bag, shouldReset bool) (newChild node)
+	// Returns the confidence of the choice in this sub-tree, accounting for a
+	// reset that hasn't been applied yet
+	Confidence(choice ids.ID, shouldReset bool) int
 	// Returns true if consensus has been reached on this node
 	Finalized() bool
 
@@ -431,6 +447,21 @@ func (u *unaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 	return u
 }
 
+func (u *unaryNode) Confidence(choice ids.ID, reset bool) int {
+	if reset || !ids.EqualSubset(u.decidedPrefix, u.commonPrefix, u.preference, choice) {
+		return 0
+	}
+
+	confidence := u.snowball.Confidence()
+	if u.child == nil {
+		return confidence
+	}
+	if childConfidence := u.child.Confidence(choice, u.shouldReset); childConfidence < confidence {
+		return childConfidence
+	}
+	return confidence
+}
+
 func (u *unaryNode) Finalized() bool { return u.snowball.Finalized() }
 
 func (u *unaryNode) Printable() (string, []node) {
@@ -534,6 +565,27 @@ func (b *binaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 	return b
 }
 
+func (b *binaryNode) Confidence(choice ids.ID, reset bool) int {
+	bit := choice.Bit(uint(b.bit))
+	if reset || bit != b.snowball.Preference() {
+		return 0
+	}
+
+	confidence := b.snowball.Confidence()
+	child := b.children[bit]
+	if child == nil {
+		return confidence
+	}
+	// + 1 is used because we already explicitly check the b.bit bit
+	if !ids.EqualSubset(b.bit+1, child.DecidedPrefix(), b.preferences[bit], choice) {
+		return 0
+	}
+	if childConfidence := child.Confidence(choice, b.shouldReset[bit]); childConfidence < confidence {
+		return childConfidence
+	}
+	return confidence
+}
+
 func (b *binaryNode) Finalized() bool { return b.snowball.Finalized() }
 
 func (b *binaryNode) Printable() (string, []node) {
@@ -879,3 +879,82 @@ func TestSnowballFilterBinaryChildren(t *testing.T) {
 		}
 	}
 }
+
+func TestSnowballConfidence(t *testing.T) {
+	zero := ids.NewID([32]byte{0b00000000})
+	two := ids.NewID([32]byte{0b00000010})
+	eight := ids.NewID([32]byte{0b00001000})
+
+	params := Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 2, BetaRogue: 2,
+	}
+	tree := Tree{}
+	tree.Initialize(params, zero)
+	tree.Add(two)
+	tree.Add(eight)
+
+	if confidence := tree.Confidence(zero); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	}
+
+	zeroBag := ids.Bag{}
+	zeroBag.Add(zero)
+	tree.RecordPoll(zeroBag)
+
+	if confidence := tree.Confidence(zero); confidence != 1 {
+		t.Fatalf("Wrong confidence. Expected 1 got %d", confidence)
+	} else if confidence := tree.Confidence(two); confidence != 0 {
+		t.Fatalf("Non-preferred choice should have no confidence, got %d", confidence)
+	} else if confidence := tree.Confidence(eight); confidence != 0 {
+		t.Fatalf("Non-preferred choice should have no confidence, got %d", confidence)
+	}
+
+	// The reset of the children of the root is deferred, but the confidence
+	// should reflect it
+	tree.RecordPoll(ids.Bag{})
+
+	if confidence := tree.Confidence(zero); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	}
+
+	tree.RecordPoll(zeroBag)
+	tree.RecordPoll(zeroBag)
+
+	if confidence := tree.Confidence(zero); confidence != 2 {
+		t.Fatalf("Wrong confidence. Expected 2 got %d", confidence)
+	} else if !tree.Finalized() {
+		t.Fatalf("Finalized too late")
+	}
+}
+
+func TestSnowballConfidenceSnowflakeMismatch(t *testing.T) {
+	params := Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 3,
+	}
+	tree := Tree{}
+	tree.Initialize(params, Red)
+	tree.Add(Blue)
+
+	oneBlue := ids.Bag{}
+	oneBlue.Add(Blue)
+	tree.RecordPoll(oneBlue)
+
+	if confidence := tree.Confidence(Blue); confidence != 1 {
+		t.Fatalf("Wrong confidence. Expected 1 got %d", confidence)
+	}
+
+	// Snowball still prefers Blue, but the snowflake counter is for Red
+	oneRed := ids.Bag{}
+	oneRed.Add(Red)
+	tree.RecordPoll(oneRed)
+
+	if pref := tree.Preference(); !Blue.Equals(pref) {
+		t.Fatalf("Wrong preference. Expected %s got %s", Blue, pref)
+	} else if confidence := tree.Confidence(Blue); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	} else if confidence := tree.Confidence(Red); confidence != 0 {
+		t.Fatalf("Non-preferred choice should have no confidence, got %d", confidence)
+	}
+}
@@ -34,6 +34,9 @@ func (sf *unarySnowflake) RecordSuccessfulPoll() {
 // RecordUnsuccessfulPoll implements the UnarySnowflake interface
 func (sf *unarySnowflake) RecordUnsuccessfulPoll() { sf.confidence = 0 }
 
+// Confidence implements the UnarySnowflake interface
+func (sf *unarySnowflake) Confidence() int { return sf.confidence }
+
 // Finalized implements the UnarySnowflake interface
 func (sf *unarySnowflake) Finalized() bool { return sf.finalized }
 
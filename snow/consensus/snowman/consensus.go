@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
@@ -29,6 +31,19 @@ type Consensus interface {
 	// decisions.
 	Preference() ids.ID
 
+	// IsPreferred returns true if the block is processing and on the strongly
+	// preferred sequence of decisions
+	IsPreferred(Block) bool
+
+	// Confidence returns the number of consecutive successful polls the block
+	// has been preferred in by the snowball instance deciding between it and
+	// its siblings. Returns false if the block isn't processing.
+	Confidence(ids.ID) (int, bool)
+
+	// Processing returns the [max] oldest processing blocks, each with its
+	// [max] oldest siblings, oldest first, and the number of processing blocks
+	Processing(max int) ([]BlockInfo, int)
+
 	// RecordPoll collects the results of a network poll. Assumes all decisions
 	// have been previously added. Returns if a critical error has occurred.
 	RecordPoll(ids.Bag) error
@@ -38,3 +53,20 @@ type Consensus interface {
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
 }
+
+// BlockInfo describes the state of a processing block
+type BlockInfo struct {
+	ID       ids.ID
+	ParentID ids.ID
+
+	// Time the block was added
+	Issued time.Time
+
+	Preferred bool
+	// Rogue is true if the block has processing siblings
+	Rogue      bool
+	Confidence int
+
+	// Processing blocks with the same parent as this block
+	Conflicts []ids.ID
+}
@@ -37,9 +37,12 @@ var (
 		RecordPollWhenFinalizedTest,
 		RecordPollRejectTransitivelyTest,
 		RecordPollTransitivelyResetConfidenceTest,
+		RecordPollConfidenceTest,
+		ProcessingTest,
 		RecordPollInvalidVoteTest,
 		RecordPollTransitiveVotingTest,
 		RecordPollDivergedVotingTest,
+		RecordPollVotesForAncestorTest,
 		MetricsProcessingErrorTest,
 		MetricsAcceptedErrorTest,
 		MetricsRejectedErrorTest,
@@ -620,6 +623,202 @@ func RecordPollTransitivelyResetConfidenceTest(t *testing.T, factory Factory) {
 	}
 }
 
+func RecordPollConfidenceTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      3,
+		BetaRogue:         3,
+		ConcurrentRepolls: 1,
+	}
+	sm.Initialize(ctx, params, GenesisID)
+
+	block0 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block2 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(3),
+			StatusV: choices.Processing,
+		},
+		ParentV: block1,
+	}
+
+	if err := sm.Add(block0); err != nil {
+		t.Fatal(err)
+	} else if err := sm.Add(block1); err != nil {
+		t.Fatal(err)
+	} else if err := sm.Add(block2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Current graph structure:
+	//   G
+	//  / \
+	// 0   1
+	//     |
+	//     2
+
+	if sm.IsPreferred(Genesis) {
+		t.Fatalf("The last accepted block shouldn't be preferred")
+	} else if _, processing := sm.Confidence(GenesisID); processing {
+		t.Fatalf("The last accepted block shouldn't be processing")
+	} else if !sm.IsPreferred(block0) {
+		t.Fatalf("The first block issued should be preferred")
+	} else if sm.IsPreferred(block2) {
+		t.Fatalf("A child of a non-preferred block shouldn't be preferred")
+	} else if confidence, processing := sm.Confidence(block2.ID()); !processing || confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	}
+
+	votesFor2 := ids.Bag{}
+	votesFor2.Add(block2.ID())
+	if err := sm.RecordPoll(votesFor2); err != nil {
+		t.Fatal(err)
+	} else if sm.IsPreferred(block0) {
+		t.Fatalf("The sibling of the preferred block shouldn't be preferred")
+	} else if !sm.IsPreferred(block1) || !sm.IsPreferred(block2) {
+		t.Fatalf("The voted for branch should be preferred")
+	} else if confidence, _ := sm.Confidence(block1.ID()); confidence != 1 {
+		t.Fatalf("Wrong confidence. Expected 1 got %d", confidence)
+	} else if confidence, _ := sm.Confidence(block2.ID()); confidence != 1 {
+		t.Fatalf("Wrong confidence. Expected 1 got %d", confidence)
+	} else if confidence, _ := sm.Confidence(block0.ID()); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	}
+
+	// The confidence of the whole tree is reset lazily
+	if err := sm.RecordPoll(ids.Bag{}); err != nil {
+		t.Fatal(err)
+	} else if confidence, _ := sm.Confidence(block2.ID()); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	} else if !sm.IsPreferred(block2) {
+		t.Fatalf("An unsuccessful poll shouldn't change the preference")
+	}
+
+	if err := sm.RecordPoll(votesFor2); err != nil {
+		t.Fatal(err)
+	} else if confidence, _ := sm.Confidence(block2.ID()); confidence != 1 {
+		t.Fatalf("Wrong confidence. Expected 1 got %d", confidence)
+	}
+}
+
+func ProcessingTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      3,
+		BetaRogue:         3,
+		ConcurrentRepolls: 1,
+	}
+	sm.Initialize(ctx, params, GenesisID)
+
+	block0 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block2 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(3),
+			StatusV: choices.Processing,
+		},
+		ParentV: block1,
+	}
+
+	if blks, numProcessing := sm.Processing(10); numProcessing != 0 || len(blks) != 0 {
+		t.Fatalf("No blocks should be processing")
+	}
+
+	if err := sm.Add(block0); err != nil {
+		t.Fatal(err)
+	} else if err := sm.Add(block1); err != nil {
+		t.Fatal(err)
+	} else if err := sm.Add(block2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Current graph structure:
+	//   G
+	//  / \
+	// 0   1
+	//     |
+	//     2
+
+	votesFor2 := ids.Bag{}
+	votesFor2.Add(block2.ID())
+	if err := sm.RecordPoll(votesFor2); err != nil {
+		t.Fatal(err)
+	}
+
+	blks, numProcessing := sm.Processing(10)
+	if numProcessing != 3 {
+		t.Fatalf("Wrong number of processing blocks. Expected 3 got %d", numProcessing)
+	} else if len(blks) != 3 {
+		t.Fatalf("Wrong number of blocks returned. Expected 3 got %d", len(blks))
+	}
+	for _, blk := range blks {
+		switch {
+		case blk.ID.Equals(block0.ID()):
+			if blk.Preferred || !blk.Rogue || blk.Confidence != 0 {
+				t.Fatalf("Block0 should be rogue and not preferred")
+			} else if len(blk.Conflicts) != 1 || !blk.Conflicts[0].Equals(block1.ID()) {
+				t.Fatalf("Block0 should only conflict with block1")
+			}
+		case blk.ID.Equals(block1.ID()):
+			if !blk.Preferred || !blk.Rogue || blk.Confidence != 1 {
+				t.Fatalf("Block1 should be rogue and preferred with a confidence of 1")
+			} else if len(blk.Conflicts) != 1 || !blk.Conflicts[0].Equals(block0.ID()) {
+				t.Fatalf("Block1 should only conflict with block0")
+			}
+		case blk.ID.Equals(block2.ID()):
+			if !blk.Preferred || blk.Rogue || blk.Confidence != 1 {
+				t.Fatalf("Block2 should be virtuous and preferred with a confidence of 1")
+			} else if !blk.ParentID.Equals(block1.ID()) {
+				t.Fatalf("Wrong parent of block2")
+			} else if len(blk.Conflicts) != 0 {
+				t.Fatalf("Block2 shouldn't have any conflicts")
+			}
+		default:
+			t.Fatalf("Unexpected block %s", blk.ID)
+		}
+	}
+
+	if blks, numProcessing := sm.Processing(1); numProcessing != 3 {
+		t.Fatalf("Wrong number of processing blocks. Expected 3 got %d", numProcessing)
+	} else if len(blks) != 1 {
+		t.Fatalf("Wrong number of blocks returned. Expected 1 got %d", len(blks))
+	}
+}
+
 func RecordPollInvalidVoteTest(t *testing.T, factory Factory) {
 	sm := factory.New()
 
@@ -877,6 +1076,83 @@ func RecordPollDivergedVotingTest(t *testing.T, factory Factory) {
 	}
 }
 
+func RecordPollVotesForAncestorTest(t *testing.T, factory Factory) {
+	// The votes are applied in map order, so the poll is recorded by several
+	// instances to make sure that the order doesn't change the outcome
+	for i := 0; i < 20; i++ {
+		sm := factory.New()
+
+		ctx := snow.DefaultContextTest()
+		params := snowball.Parameters{
+			Metrics:           prometheus.NewRegistry(),
+			K:                 2,
+			Alpha:             2,
+			BetaVirtuous:      1,
+			BetaRogue:         1,
+			ConcurrentRepolls: 1,
+		}
+		sm.Initialize(ctx, params, GenesisID)
+
+		block0 := &TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(1),
+				StatusV: choices.Processing,
+			},
+			ParentV: Genesis,
+		}
+		block1 := &TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(2),
+				StatusV: choices.Processing,
+			},
+			ParentV: block0,
+		}
+		block2 := &TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(3),
+				StatusV: choices.Processing,
+			},
+			ParentV: block1,
+		}
+
+		if err := sm.Add(block0); err != nil {
+			t.Fatal(err)
+		} else if err := sm.Add(block1); err != nil {
+			t.Fatal(err)
+		} else if err := sm.Add(block2); err != nil {
+			t.Fatal(err)
+		}
+
+		// Current graph structure:
+		// G
+		// |
+		// 0
+		// |
+		// 1
+		// |
+		// 2
+		//
+		// Both votes are transitively votes for 0 and 1
+
+		votes := ids.Bag{}
+		votes.Add(block1.ID())
+		votes.Add(block2.ID())
+		if err := sm.RecordPoll(votes); err != nil {
+			t.Fatal(err)
+		} else if sm.Finalized() {
+			t.Fatalf("Finalized too early")
+		} else if pref := sm.Preference(); !block2.ID().Equals(pref) {
+			t.Fatalf("Wrong preference listed")
+		} else if status := block0.Status(); status != choices.Accepted {
+			t.Fatalf("Block0 should be %s but is %s", choices.Accepted, status)
+		} else if status := block1.Status(); status != choices.Accepted {
+			t.Fatalf("Block1 should be %s but is %s", choices.Accepted, status)
+		} else if status := block2.Status(); status != choices.Processing {
+			t.Fatalf("Block2 should be %s but is %s", choices.Processing, status)
+		}
+	}
+}
+
 func MetricsProcessingErrorTest(t *testing.T, factory Factory) {
 	sm := factory.New()
 
@@ -4,7 +4,9 @@
 package snowman
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -82,3 +84,20 @@ func (m *metrics) Rejected(id ids.ID) {
 	m.latRejected.Observe(float64(end.Sub(start).Milliseconds()))
 	m.numProcessing.Dec()
 }
+
+// oldest sorts [blkIDs] by the time they were issued, oldest first, and returns
+// up to [max] of them
+func (m *metrics) oldest(blkIDs []ids.ID, max int) []ids.ID {
+	sort.Slice(blkIDs, func(i, j int) bool {
+		iIssued := m.processing[blkIDs[i].Key()]
+		jIssued := m.processing[blkIDs[j].Key()]
+		if !iIssued.Equal(jIssued) {
+			return iIssued.Before(jIssued)
+		}
+		return bytes.Compare(blkIDs[i].Bytes(), blkIDs[j].Bytes()) == -1
+	})
+	if len(blkIDs) > max {
+		blkIDs = blkIDs[:max]
+	}
+	return blkIDs
+}
@@ -30,7 +30,7 @@ func (f *earlyTermNoTraversalFactory) New(vdrs ids.ShortBag) Poll {
 // the result of the poll. However, does not terminate tightly with this bound.
 // It terminates as quickly as it can without performing any DAG traversals.
 type earlyTermNoTraversalPoll struct {
-	votes  ids.Bag
+	votes  tally
 	polled ids.ShortBag
 	alpha  int
 }
@@ -42,7 +42,7 @@ func (p *earlyTermNoTraversalPoll) Vote(vdr ids.ShortID, vote ids.ID) {
 	p.polled.Remove(vdr)
 
 	// track the votes the validator responded with
-	p.votes.AddCount(vote, count)
+	p.votes.add(vote, count)
 }
 
 // Drop any future response for this poll
@@ -54,14 +54,14 @@ func (p *earlyTermNoTraversalPoll) Drop(vdr ids.ShortID) {
 func (p *earlyTermNoTraversalPoll) Finished() bool {
 	remaining := p.polled.Len()
 	received := p.votes.Len()
-	_, freq := p.votes.Mode()
+	freq := p.votes.ModeFreq()
 	return remaining == 0 || // All k nodes responded
 		freq >= p.alpha || // An alpha majority has returned
 		received+remaining < p.alpha // An alpha majority can never return
 }
 
 // Result returns the result of this poll
-func (p *earlyTermNoTraversalPoll) Result() ids.Bag { return p.votes }
+func (p *earlyTermNoTraversalPoll) Result() ids.Bag { return p.votes.Bag() }
 
 func (p *earlyTermNoTraversalPoll) PrefixedString(prefix string) string {
 	return fmt.Sprintf("waiting on %s", p.polled.PrefixedString(prefix))
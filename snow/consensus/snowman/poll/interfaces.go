@@ -5,6 +5,7 @@ package poll
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 )
@@ -17,6 +18,14 @@ type Set interface {
 	Vote(requestID uint32, vdr ids.ShortID, vote ids.ID) (ids.Bag, bool)
 	Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool)
 	Len() int
+	Outstanding(max int) []Info
+}
+
+// Info describes an outstanding poll
+type Info struct {
+	RequestID uint32
+	// Time the poll was issued
+	Start time.Time
 }
 
 // Poll is an outstanding poll
@@ -22,7 +22,7 @@ func (noEarlyTermFactory) New(vdrs ids.ShortBag) Poll {
 // noEarlyTermPoll finishes when all polled validators either respond to the
 // query or a timeout occurs
 type noEarlyTermPoll struct {
-	votes  ids.Bag
+	votes  tally
 	polled ids.ShortBag
 }
 
@@ -33,7 +33,7 @@ func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, vote ids.ID) {
 	p.polled.Remove(vdr)
 
 	// track the votes the validator responded with
-	p.votes.AddCount(vote, count)
+	p.votes.add(vote, count)
 }
 
 // Drop any future response for this poll
@@ -43,7 +43,7 @@ func (p *noEarlyTermPoll) Drop(vdr ids.ShortID) { p.polled.Remove(vdr) }
 func (p *noEarlyTermPoll) Finished() bool { return p.polled.Len() == 0 }
 
 // Result returns the result of this poll
-func (p *noEarlyTermPoll) Result() ids.Bag { return p.votes }
+func (p *noEarlyTermPoll) Result() ids.Bag { return p.votes.Bag() }
 
 func (p *noEarlyTermPoll) PrefixedString(prefix string) string {
 	return fmt.Sprintf("waiting on %s", p.polled.PrefixedString(prefix))
@@ -5,6 +5,7 @@ package poll
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -99,10 +100,14 @@ func (s *set) Vote(
 		return ids.Bag{}, false
 	}
 
-	s.log.Verbo("processing vote from %s in the poll with requestID: %d with the vote %s",
-		vdr,
-		requestID,
-		vote)
+	// The level is checked first, as formatting the vote allocates even if it
+	// isn't logged
+	if s.log.GetLevel() >= logging.Verbo {
+		s.log.Verbo("processing vote from %s in the poll with requestID: %d with the vote %s",
+			vdr,
+			requestID,
+			vote)
+	}
 
 	poll.Vote(vdr, vote)
 	if !poll.Finished() {
@@ -128,9 +133,11 @@ func (s *set) Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
 		return ids.Bag{}, false
 	}
 
-	s.log.Verbo("processing dropped vote from %s in the poll with requestID: %d",
-		vdr,
-		requestID)
+	if s.log.GetLevel() >= logging.Verbo {
+		s.log.Verbo("processing dropped vote from %s in the poll with requestID: %d",
+			vdr,
+			requestID)
+	}
 
 	poll.Drop(vdr)
 	if !poll.Finished() {
@@ -148,6 +155,28 @@ func (s *set) Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
 // Len returns the number of outstanding polls
 func (s *set) Len() int { return len(s.polls) }
 
+// Outstanding returns up to [max] of the oldest outstanding polls, sorted by
+// the time they were issued
+func (s *set) Outstanding(max int) []Info {
+	polls := make([]Info, 0, len(s.polls))
+	for requestID, poll := range s.polls {
+		polls = append(polls, Info{
+			RequestID: requestID,
+			Start:     poll.start,
+		})
+	}
+	sort.Slice(polls, func(i, j int) bool {
+		if !polls[i].Start.Equal(polls[j].Start) {
+			return polls[i].Start.Before(polls[j].Start)
+		}
+		return polls[i].RequestID < polls[j].RequestID
+	})
+	if len(polls) > max {
+		polls = polls[:max]
+	}
+	return polls
+}
+
 func (s *set) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("current polls: (Size = %d)", len(s.polls)))
@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// tally counts the votes received by a poll.
+// A poll receives at most k votes, which are almost always for a few blocks, so
+// the counts are kept in a slice rather than a map. Scanning the slice is
+// faster than hashing, and recording a vote never allocates once the slice
+// holds every block voted for.
+type tally struct {
+	counts   []idCount
+	size     int
+	modeFreq int
+}
+
+type idCount struct {
+	id    ids.ID
+	count int
+}
+
+// add [count] votes for [id]
+func (t *tally) add(id ids.ID, count int) {
+	if count <= 0 {
+		return
+	}
+	t.size += count
+
+	for i := range t.counts {
+		if c := &t.counts[i]; c.id.Equals(id) {
+			c.count += count
+			if c.count > t.modeFreq {
+				t.modeFreq = c.count
+			}
+			return
+		}
+	}
+
+	t.counts = append(t.counts, idCount{id: id, count: count})
+	if count > t.modeFreq {
+		t.modeFreq = count
+	}
+}
+
+// Len returns the number of votes
+func (t *tally) Len() int { return t.size }
+
+// ModeFreq returns the number of votes for the block voted for the most
+func (t *tally) ModeFreq() int { return t.modeFreq }
+
+// Bag returns the votes, in the order the blocks were first voted for
+func (t *tally) Bag() ids.Bag {
+	bag := ids.Bag{}
+	for _, c := range t.counts {
+		bag.AddCount(c.id, c.count)
+	}
+	return bag
+}
@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestTally(t *testing.T) {
+	blkID0 := ids.NewID([32]byte{1})
+	blkID1 := ids.NewID([32]byte{2})
+
+	votes := tally{}
+	votes.add(blkID0, 1)
+	votes.add(blkID1, 2)
+	votes.add(ids.NewID([32]byte{1}), 2)
+	votes.add(blkID1, 0)
+
+	if votes.Len() != 5 {
+		t.Fatalf("Wrong number of votes")
+	} else if votes.ModeFreq() != 3 {
+		t.Fatalf("Wrong number of votes for the mode")
+	}
+
+	bag := votes.Bag()
+	if bag.Len() != 5 {
+		t.Fatalf("Wrong number of votes in the bag")
+	} else if bag.Count(blkID0) != 3 {
+		t.Fatalf("Wrong number of votes for %s", blkID0)
+	} else if bag.Count(blkID1) != 2 {
+		t.Fatalf("Wrong number of votes for %s", blkID1)
+	}
+}
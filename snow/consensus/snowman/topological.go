@@ -136,6 +136,97 @@ func (ts *Topological) Issued(blk Block) bool {
 // Preference implements the Snowman interface
 func (ts *Topological) Preference() ids.ID { return ts.tail }
 
+// IsPreferred implements the Snowman interface
+func (ts *Topological) IsPreferred(blk Block) bool {
+	// The last accepted block isn't processing
+	blkID := blk.ID()
+	if blkID.Equals(ts.head) {
+		return false
+	}
+
+	// Every block between [blk] and the last accepted block must be the
+	// preferred child of its parent
+	for !blkID.Equals(ts.head) {
+		node, ok := ts.blocks[blkID.Key()]
+		if !ok {
+			return false
+		}
+		parentID := node.blk.Parent().ID()
+		if !ts.blocks[parentID.Key()].sb.Preference().Equals(blkID) {
+			return false
+		}
+		blkID = parentID
+	}
+	return true
+}
+
+// Confidence implements the Snowman interface
+func (ts *Topological) Confidence(blkID ids.ID) (int, bool) {
+	node, ok := ts.blocks[blkID.Key()]
+	if !ok || blkID.Equals(ts.head) {
+		return 0, false
+	}
+	parentID := node.blk.Parent().ID()
+	parentNode := ts.blocks[parentID.Key()]
+
+	// If the poll that last reached the parent's snowball instance, or any
+	// of its ancestors, didn't get an alpha majority, the confidence will be
+	// reset before the next vote is applied
+	for ancestor := parentNode; ; ancestor = ts.blocks[ancestor.blk.Parent().ID().Key()] {
+		if ancestor.shouldFalter {
+			return 0, true
+		}
+		if ancestor.blk == nil || ancestor.blk.ID().Equals(ts.head) {
+			break
+		}
+	}
+	return parentNode.sb.Confidence(blkID), true
+}
+
+// Processing implements the Snowman interface
+func (ts *Topological) Processing(max int) ([]BlockInfo, int) {
+	// Blocks on the strongly preferred sequence of decisions
+	preferred := ids.Set{}
+	for node := ts.blocks[ts.head.Key()]; node.sb != nil; {
+		blkID := node.sb.Preference()
+		preferred.Add(blkID)
+		node = ts.blocks[blkID.Key()]
+	}
+
+	// The oldest processing blocks are returned
+	blkIDs := make([]ids.ID, 0, len(ts.blocks))
+	for key := range ts.blocks {
+		if blkID := ids.NewID(key); !blkID.Equals(ts.head) {
+			blkIDs = append(blkIDs, blkID)
+		}
+	}
+
+	blks := []BlockInfo(nil)
+	for _, blkID := range ts.oldest(blkIDs, max) {
+		key := blkID.Key()
+		parentID := ts.blocks[key].blk.Parent().ID()
+		parentNode := ts.blocks[parentID.Key()]
+
+		siblingIDs := make([]ids.ID, 0, len(parentNode.children))
+		for siblingKey := range parentNode.children {
+			if siblingKey != key {
+				siblingIDs = append(siblingIDs, ids.NewID(siblingKey))
+			}
+		}
+		confidence, _ := ts.Confidence(blkID)
+		blks = append(blks, BlockInfo{
+			ID:         blkID,
+			ParentID:   parentID,
+			Issued:     ts.processing[key],
+			Preferred:  preferred.Contains(blkID),
+			Rogue:      len(parentNode.children) > 1,
+			Confidence: confidence,
+			Conflicts:  ts.oldest(siblingIDs, max),
+		})
+	}
+	return blks, len(ts.blocks) - 1
+}
+
 // RecordPoll implements the Snowman interface
 //
 // The votes bag contains at most K votes for blocks in the tree. If there is a
@@ -237,24 +328,28 @@ func (ts *Topological) calculateInDegree(
 			parentIDKey = parentID.Key() // move the loop variable forward
 
 			// Increase the inDegree by one
-			kahn := kahns[parentIDKey]
+			kahn, previouslySeen := kahns[parentIDKey]
 			kahn.inDegree++
 			kahns[parentIDKey] = kahn
 
-			// If we have already seen this block, then we shouldn't increase
-			// the inDegree of the ancestors through this block again.
-			if kahn.inDegree != 1 {
+			// Now that the block has an inbound edge, it isn't a leaf, even if
+			// it was previously a leaf.
+			leaves.Remove(parentID)
+
+			// If we have already seen this block, either through another
+			// descendant or because it was voted for, then we shouldn't
+			// increase the inDegree of the ancestors through this block again.
+			if previouslySeen {
 				break
 			}
-
-			// If I am transitively seeing this block for the first time, either
-			// the block was previously unknown or it was previously a leaf.
-			// Regardless, it shouldn't be tracked as a leaf.
-			leaves.Remove(parentID)
 		}
 	}
 
-	return kahns, leaves.List()
+	// The leaves are sorted so that the votes are applied in the same order
+	// every time
+	leafIDs := leaves.List()
+	ids.SortIDs(leafIDs)
+	return kahns, leafIDs
 }
 
 // convert the tree into a branch of snowball instances with at least alpha
@@ -5,6 +5,67 @@ package snowman
 
 import (
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
 
 func TestTopological(t *testing.T) { ConsensusTest(t, TopologicalFactory{}) }
+
+func TestTopologicalProcessingOldest(t *testing.T) {
+	ts := &Topological{}
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      3,
+		BetaRogue:         3,
+		ConcurrentRepolls: 1,
+	}
+	ts.Initialize(ctx, params, GenesisID)
+
+	blocks := make([]*TestBlock, 3)
+	for i := range blocks {
+		blocks[i] = &TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i + 1)),
+				StatusV: choices.Processing,
+			},
+			ParentV: Genesis,
+		}
+	}
+
+	// The blocks are issued in the reverse of the order they're defined in
+	start := time.Unix(1000, 0)
+	for i := range blocks {
+		ts.clock.Set(start.Add(time.Duration(i) * time.Second))
+		if err := ts.Add(blocks[len(blocks)-1-i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blks, numProcessing := ts.Processing(2)
+	if numProcessing != 3 {
+		t.Fatalf("Wrong number of processing blocks. Expected 3 got %d", numProcessing)
+	} else if len(blks) != 2 {
+		t.Fatalf("Wrong number of blocks returned. Expected 2 got %d", len(blks))
+	} else if !blks[0].ID.Equals(blocks[2].ID()) || !blks[1].ID.Equals(blocks[1].ID()) {
+		t.Fatalf("Should have returned the oldest blocks")
+	} else if conflicts := blks[0].Conflicts; len(conflicts) != 2 || !conflicts[0].Equals(blocks[1].ID()) || !conflicts[1].Equals(blocks[0].ID()) {
+		t.Fatalf("Should have returned the siblings of the oldest block, oldest first")
+	}
+
+	blks, _ = ts.Processing(1)
+	if len(blks) != 1 || !blks[0].ID.Equals(blocks[2].ID()) {
+		t.Fatalf("Should have returned the oldest block")
+	} else if conflicts := blks[0].Conflicts; len(conflicts) != 1 || !conflicts[0].Equals(blocks[1].ID()) {
+		t.Fatalf("Should have returned the oldest sibling of the oldest block")
+	}
+}
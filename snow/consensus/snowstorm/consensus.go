@@ -5,6 +5,7 @@ package snowstorm
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -45,6 +46,15 @@ type Consensus interface {
 	// Returns the set of transactions conflicting with <Tx>
 	Conflicts(Tx) ids.Set
 
+	// Returns the number of consecutive successful polls the transaction has
+	// received. Returns false if the transaction isn't processing.
+	Confidence(txID ids.ID) (int, bool)
+
+	// Returns the [max] oldest processing transactions, each with its [max]
+	// oldest conflicts, oldest first, and the number of processing
+	// transactions
+	Processing(max int) ([]TxInfo, int)
+
 	// Collects the results of a network poll. Assumes all transactions
 	// have been previously added. Returns true is any statuses or preferences
 	// changed. Returns if a critical error has occurred.
@@ -66,3 +76,18 @@ type Consensus interface {
 	// Reject all the provided txs and remove them from the graph
 	reject(txIDs ...ids.ID) error
 }
+
+// TxInfo describes the state of a processing transaction
+type TxInfo struct {
+	ID ids.ID
+
+	// Time the transaction was added
+	Issued time.Time
+
+	Preferred  bool
+	Rogue      bool
+	Confidence int
+
+	// Processing transactions that conflict with this transaction
+	Conflicts []ids.ID
+}
@@ -6,6 +6,7 @@ package snowstorm
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/timer"
 
 	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
@@ -26,6 +28,9 @@ var (
 		LeftoverInputTest,
 		LowerConfidenceTest,
 		MiddleConfidenceTest,
+		ConfidenceTest,
+		ProcessingTest,
+		ProcessingOldestTest,
 		IndependentTest,
 		VirtuousTest,
 		IsVirtuousTest,
@@ -333,6 +338,168 @@ func MiddleConfidenceTest(t *testing.T, factory Factory) {
 	}
 }
 
+func ConfidenceTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 2,
+		Alpha:             2,
+		BetaVirtuous:      3,
+		BetaRogue:         3,
+		ConcurrentRepolls: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	if err := graph.Add(Red); err != nil {
+		t.Fatal(err)
+	} else if err := graph.Add(Green); err != nil {
+		t.Fatal(err)
+	} else if _, processing := graph.Confidence(Blue.ID()); processing {
+		t.Fatalf("Blue was never issued")
+	} else if confidence, processing := graph.Confidence(Red.ID()); !processing || confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	}
+
+	r := ids.Bag{}
+	r.SetThreshold(2)
+	r.AddCount(Red.ID(), 2)
+	for expected := 1; expected <= 2; expected++ {
+		if _, err := graph.RecordPoll(r); err != nil {
+			t.Fatal(err)
+		} else if confidence, _ := graph.Confidence(Red.ID()); confidence != expected {
+			t.Fatalf("Wrong confidence. Expected %d got %d", expected, confidence)
+		} else if confidence, _ := graph.Confidence(Green.ID()); confidence != 0 {
+			t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+		}
+	}
+
+	if _, err := graph.RecordPoll(ids.Bag{}); err != nil {
+		t.Fatal(err)
+	} else if confidence, _ := graph.Confidence(Red.ID()); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0 got %d", confidence)
+	}
+}
+
+func ProcessingTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 2,
+		Alpha:             2,
+		BetaVirtuous:      1,
+		BetaRogue:         2,
+		ConcurrentRepolls: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	if err := graph.Add(Red); err != nil {
+		t.Fatal(err)
+	} else if err := graph.Add(Green); err != nil {
+		t.Fatal(err)
+	} else if err := graph.Add(Alpha); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, numProcessing := graph.Processing(10)
+	if numProcessing != 3 {
+		t.Fatalf("Wrong number of processing txs. Expected 3 got %d", numProcessing)
+	} else if len(txs) != 3 {
+		t.Fatalf("Wrong number of txs returned. Expected 3 got %d", len(txs))
+	}
+	for _, tx := range txs {
+		switch {
+		case tx.ID.Equals(Red.ID()):
+			if !tx.Preferred || !tx.Rogue {
+				t.Fatalf("Red should be preferred and rogue")
+			} else if len(tx.Conflicts) != 1 || !tx.Conflicts[0].Equals(Green.ID()) {
+				t.Fatalf("Red should only conflict with Green")
+			}
+		case tx.ID.Equals(Green.ID()):
+			if tx.Preferred || !tx.Rogue {
+				t.Fatalf("Green should be rogue and not preferred")
+			} else if len(tx.Conflicts) != 1 || !tx.Conflicts[0].Equals(Red.ID()) {
+				t.Fatalf("Green should only conflict with Red")
+			}
+		case tx.ID.Equals(Alpha.ID()):
+			if !tx.Preferred || tx.Rogue {
+				t.Fatalf("Alpha should be preferred and virtuous")
+			} else if len(tx.Conflicts) != 0 {
+				t.Fatalf("Alpha shouldn't have any conflicts")
+			}
+		default:
+			t.Fatalf("Unexpected tx %s", tx.ID)
+		}
+		if tx.Issued.IsZero() {
+			t.Fatalf("Issuance time of %s wasn't recorded", tx.ID)
+		}
+	}
+
+	if txs, numProcessing := graph.Processing(1); numProcessing != 3 {
+		t.Fatalf("Wrong number of processing txs. Expected 3 got %d", numProcessing)
+	} else if len(txs) != 1 {
+		t.Fatalf("Wrong number of txs returned. Expected 1 got %d", len(txs))
+	}
+}
+
+func ProcessingOldestTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 2,
+		Alpha:             2,
+		BetaVirtuous:      1,
+		BetaRogue:         2,
+		ConcurrentRepolls: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	var clock *timer.Clock
+	switch graph := graph.(type) {
+	case *Directed:
+		clock = &graph.clock
+	case *Input:
+		clock = &graph.clock
+	default:
+		t.Fatalf("Unexpected consensus implementation %T", graph)
+	}
+
+	// The txs are issued in the reverse of the order they're defined in
+	start := time.Unix(1000, 0)
+	for i, tx := range []*TestTx{Alpha, Blue, Green, Red} {
+		clock.Set(start.Add(time.Duration(i) * time.Second))
+		if err := graph.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txs, numProcessing := graph.Processing(2)
+	if numProcessing != 4 {
+		t.Fatalf("Wrong number of processing txs. Expected 4 got %d", numProcessing)
+	} else if len(txs) != 2 {
+		t.Fatalf("Wrong number of txs returned. Expected 2 got %d", len(txs))
+	} else if !txs[0].ID.Equals(Alpha.ID()) || !txs[1].ID.Equals(Blue.ID()) {
+		t.Fatalf("Should have returned the oldest txs, Alpha and Blue")
+	} else if conflicts := txs[1].Conflicts; len(conflicts) != 2 || !conflicts[0].Equals(Alpha.ID()) || !conflicts[1].Equals(Green.ID()) {
+		t.Fatalf("Blue should conflict with Alpha and Green, oldest first")
+	}
+
+	txs, _ = graph.Processing(1)
+	if len(txs) != 1 || !txs[0].ID.Equals(Alpha.ID()) {
+		t.Fatalf("Should have returned the oldest tx, Alpha")
+	} else if conflicts := txs[0].Conflicts; len(conflicts) != 1 || !conflicts[0].Equals(Blue.ID()) {
+		t.Fatalf("Alpha should only conflict with Blue")
+	}
+}
+
 func IndependentTest(t *testing.T, factory Factory) {
 	Setup()
 
@@ -7,6 +7,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/logging"
 
 	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
@@ -205,9 +206,11 @@ func (dg *Directed) RecordPoll(votes ids.Bag) (bool, error) {
 	votes.SetThreshold(dg.params.Alpha)
 	// Get the set of IDs that meet this alpha threshold
 	metThreshold := votes.Threshold()
-	for _, txID := range metThreshold.List() {
+	// The set is iterated directly, rather than listed, to avoid allocating an
+	// ID for each tx
+	for txKey := range metThreshold {
 		// Get the node this tx represents
-		txNode, exist := dg.txs[txID.Key()]
+		txNode, exist := dg.txs[txKey]
 		if !exist {
 			// This tx may have already been accepted because of tx
 			// dependencies. If this is the case, we can just drop the vote.
@@ -216,8 +219,12 @@ func (dg *Directed) RecordPoll(votes ids.Bag) (bool, error) {
 
 		txNode.RecordSuccessfulPoll(dg.currentVote)
 
-		dg.ctx.Log.Verbo("Updated TxID=%s to have consensus state=%s",
-			txID, &txNode.snowball)
+		// The level is checked first, as the tx ID is only allocated to be
+		// logged
+		if dg.ctx.Log.GetLevel() >= logging.Verbo {
+			dg.ctx.Log.Verbo("Updated TxID=%s to have consensus state=%s",
+				ids.NewID(txKey), &txNode.snowball)
+		}
 
 		// If the tx should be accepted, then we should defer its acceptance
 		// until its dependencies are decided. If this tx was already marked to
@@ -246,6 +253,40 @@ func (dg *Directed) RecordPoll(votes ids.Bag) (bool, error) {
 	return changed, dg.errs.Err
 }
 
+// Confidence implements the Consensus interface
+func (dg *Directed) Confidence(txID ids.ID) (int, bool) {
+	txNode, exists := dg.txs[txID.Key()]
+	if !exists {
+		return 0, false
+	}
+	return txNode.Confidence(dg.currentVote), true
+}
+
+// Processing implements the Consensus interface
+func (dg *Directed) Processing(max int) ([]TxInfo, int) {
+	// The oldest processing transactions are returned
+	txIDs := make([]ids.ID, 0, len(dg.txs))
+	for key := range dg.txs {
+		txIDs = append(txIDs, ids.NewID(key))
+	}
+
+	txs := []TxInfo(nil)
+	for _, txID := range dg.oldest(txIDs, max) {
+		key := txID.Key()
+		txNode := dg.txs[key]
+		conflicts := append(txNode.outs.List(), txNode.ins.List()...)
+		txs = append(txs, TxInfo{
+			ID:         txID,
+			Issued:     dg.processing[key],
+			Preferred:  dg.preferences.Contains(txID),
+			Rogue:      txNode.rogue,
+			Confidence: txNode.Confidence(dg.currentVote),
+			Conflicts:  dg.oldest(conflicts, max),
+		})
+	}
+	return txs, len(dg.txs)
+}
+
 func (dg *Directed) String() string {
 	nodes := make([]*snowballNode, 0, len(dg.txs))
 	for _, txNode := range dg.txs {
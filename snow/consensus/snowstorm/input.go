@@ -210,9 +210,9 @@ func (ig *Input) RecordPoll(votes ids.Bag) (bool, error) {
 	votes.SetThreshold(ig.params.Alpha)
 	// Get the set of IDs that meet this alpha threshold
 	metThreshold := votes.Threshold()
-	for _, txID := range metThreshold.List() {
-		txKey := txID.Key()
-
+	// The set is iterated directly, rather than listed, to avoid allocating an
+	// ID for each tx
+	for txKey := range metThreshold {
 		// Get the node this tx represents
 		txNode, exist := ig.txs[txKey]
 		if !exist {
@@ -220,6 +220,7 @@ func (ig *Input) RecordPoll(votes ids.Bag) (bool, error) {
 			// dependencies. If this is the case, we can just drop the vote.
 			continue
 		}
+		txID := txNode.tx.ID()
 
 		txNode.numSuccessfulPolls++
 		txNode.lastVote = ig.currentVote
@@ -315,27 +316,66 @@ func (ig *Input) RecordPoll(votes ids.Bag) (bool, error) {
 	return changed, ig.errs.Err
 }
 
+// Confidence implements the Consensus interface
+func (ig *Input) Confidence(txID ids.ID) (int, bool) {
+	txNode, exists := ig.txs[txID.Key()]
+	if !exists {
+		return 0, false
+	}
+	return ig.confidence(txID, txNode), true
+}
+
+// confidence returns the minimum confidence of the conflict sets of the tx
+func (ig *Input) confidence(txID ids.ID, txNode *inputTx) int {
+	confidence := ig.params.BetaRogue
+	for _, inputID := range txNode.tx.InputIDs().List() {
+		input := ig.utxos[inputID.Key()]
+		if input.lastVote != ig.currentVote || !txID.Equals(input.color) {
+			return 0
+		}
+		if input.confidence < confidence {
+			confidence = input.confidence
+		}
+	}
+	return confidence
+}
+
+// Processing implements the Consensus interface
+func (ig *Input) Processing(max int) ([]TxInfo, int) {
+	// The oldest processing transactions are returned
+	txIDs := make([]ids.ID, 0, len(ig.txs))
+	for key := range ig.txs {
+		txIDs = append(txIDs, ids.NewID(key))
+	}
+
+	txs := []TxInfo(nil)
+	for _, txID := range ig.oldest(txIDs, max) {
+		key := txID.Key()
+		txNode := ig.txs[key]
+		rogue := false
+		for _, inputID := range txNode.tx.InputIDs().List() {
+			rogue = rogue || ig.utxos[inputID.Key()].rogue
+		}
+		txs = append(txs, TxInfo{
+			ID:         txID,
+			Issued:     ig.processing[key],
+			Preferred:  ig.preferences.Contains(txID),
+			Rogue:      rogue,
+			Confidence: ig.confidence(txID, txNode),
+			Conflicts:  ig.oldest(ig.Conflicts(txNode.tx).List(), max),
+		})
+	}
+	return txs, len(ig.txs)
+}
+
 func (ig *Input) String() string {
 	nodes := make([]*snowballNode, 0, len(ig.txs))
 	for _, tx := range ig.txs {
 		txID := tx.tx.ID()
-
-		confidence := ig.params.BetaRogue
-		for _, inputID := range tx.tx.InputIDs().List() {
-			input := ig.utxos[inputID.Key()]
-			if input.lastVote != ig.currentVote || !txID.Equals(input.color) {
-				confidence = 0
-				break
-			}
-			if input.confidence < confidence {
-				confidence = input.confidence
-			}
-		}
-
 		nodes = append(nodes, &snowballNode{
 			txID:               txID,
 			numSuccessfulPolls: tx.numSuccessfulPolls,
-			confidence:         confidence,
+			confidence:         ig.confidence(txID, tx),
 		})
 	}
 	return ConsensusString("IG", nodes)
@@ -4,6 +4,8 @@
 package snowstorm
 
 import (
+	"bytes"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -102,3 +104,20 @@ func (m *metrics) Rejected(id ids.ID) {
 	m.rejected.Observe(float64(end.Sub(start).Milliseconds()))
 	m.numProcessing.Dec()
 }
+
+// oldest sorts [txIDs] by the time they were issued, oldest first, and returns
+// up to [max] of them
+func (m *metrics) oldest(txIDs []ids.ID, max int) []ids.ID {
+	sort.Slice(txIDs, func(i, j int) bool {
+		iIssued := m.processing[txIDs[i].Key()]
+		jIssued := m.processing[txIDs[j].Key()]
+		if !iIssued.Equal(jIssued) {
+			return iIssued.Before(jIssued)
+		}
+		return bytes.Compare(txIDs[i].Bytes(), txIDs[j].Bytes()) == -1
+	})
+	if len(txIDs) > max {
+		txIDs = txIDs[:max]
+	}
+	return txIDs
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
@@ -62,6 +63,10 @@ type Context struct {
 	BCLookup            AliasLookup
 	SNLookup            SubnetLookup
 
+	// ValidatorState looks up the validator set of a subnet at a given height
+	// of the P-Chain. It's nil in the P-Chain's own context.
+	ValidatorState validators.State
+
 	// Non-zero iff this chain bootstrapped. Should only be accessed atomically.
 	bootstrapped uint32
 	Namespace    string
@@ -59,6 +59,11 @@ type Bootstrapper struct {
 
 	// Contains IDs of vertices that have recently been processed
 	processedCache *cache.LRU
+
+	// Height of the accepted frontier when fetching started, and the height of
+	// the highest vertex fetched. Only used to estimate the number of vertices
+	// to fetch.
+	startingHeight, tipHeight uint64
 }
 
 // Initialize this engine.
@@ -78,6 +83,11 @@ func (b *Bootstrapper) Initialize(
 	if err := b.metrics.Initialize(namespace, registerer); err != nil {
 		return err
 	}
+	if err := b.Progress.Initialize(namespace, registerer); err != nil {
+		return err
+	}
+
+	b.Scheduler.Initialize(config.Fetch, config.Beacons)
 
 	b.VtxBlocked.SetParser(&vtxParser{
 		log:         config.Ctx.Log,
@@ -122,12 +132,12 @@ func (b *Bootstrapper) FilterAccepted(containerIDs ids.Set) ids.Set {
 // to fetch or we are at the maximum number of outstanding requests.
 func (b *Bootstrapper) fetch(vtxIDs ...ids.ID) error {
 	b.needToFetch.Add(vtxIDs...)
-	for b.needToFetch.Len() > 0 && b.OutstandingRequests.Len() < common.MaxOutstandingRequests {
+	for b.needToFetch.Len() > 0 && b.Scheduler.CanSend() {
 		vtxID := b.needToFetch.CappedList(1)[0]
 		b.needToFetch.Remove(vtxID)
 
 		// Make sure we haven't already requested this vertex
-		if b.OutstandingRequests.Contains(vtxID) {
+		if b.Scheduler.Contains(vtxID) {
 			continue
 		}
 
@@ -136,14 +146,13 @@ func (b *Bootstrapper) fetch(vtxIDs ...ids.ID) error {
 			continue
 		}
 
-		validators, err := b.Beacons.Sample(1) // validator to send request to
+		validatorID, err := b.Scheduler.Sample(vtxID) // validator to send request to
 		if err != nil {
 			return fmt.Errorf("dropping request for %s as there are no validators", vtxID)
 		}
-		validatorID := validators[0].ID()
-		b.RequestID++
+		b.IncrementRequestID()
 
-		b.OutstandingRequests.Add(validatorID, b.RequestID, vtxID)
+		b.Scheduler.Sent(validatorID, b.RequestID, vtxID)
 		b.Sender.GetAncestors(validatorID, b.RequestID, vtxID) // request vertex and ancestors
 	}
 	return b.finish()
@@ -185,6 +194,7 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 				if b.NumFetched%common.StatusUpdateFrequency == 0 {
 					b.Ctx.Log.Info("fetched %d vertices", b.NumFetched)
 				}
+				b.Progress.AddFetched(1)
 			} else {
 				b.Ctx.Log.Verbo("couldn't push to vtxBlocked: %s", err)
 			}
@@ -217,6 +227,12 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 			if err != nil {
 				return err
 			}
+			// There's at least one vertex at each height between the accepted
+			// frontier and the highest vertex we've seen
+			if height > b.tipHeight {
+				b.tipHeight = height
+				b.Progress.SetEstimatedTotal(height - b.startingHeight)
+			}
 			if height%stripeDistance < stripeWidth { // See comment for stripeDistance
 				b.processedCache.Put(vtx.ID(), nil)
 			}
@@ -229,6 +245,18 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 	if err := b.TxBlocked.Commit(); err != nil {
 		return err
 	}
+	if err := b.updateJobsRemaining(); err != nil {
+		return err
+	}
+
+	// Transactions whose dependencies have all been accepted don't need to
+	// wait for the rest of the vertices to be fetched. Vertices are only
+	// executed once all their transactions are, so they still wait.
+	if b.Scheduler.ExecuteWhileFetching && !b.Ctx.IsBootstrapped() {
+		if _, err := b.execute(b.TxBlocked, b.Ctx.DecisionDispatcher); err != nil {
+			return err
+		}
+	}
 
 	return b.fetch()
 }
@@ -244,7 +272,7 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte
 		return b.GetAncestorsFailed(vdr, requestID)
 	}
 
-	requestedVtxID, requested := b.OutstandingRequests.Remove(vdr, requestID)
+	requestedVtxID, requested := b.Scheduler.Requested(vdr, requestID)
 	vtx, err := b.Manager.ParseVertex(vtxs[0]) // first vertex should be the one we requested in GetAncestors request
 	if err != nil {
 		if !requested {
@@ -254,6 +282,8 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte
 
 		b.Ctx.Log.Debug("failed to parse requested vertex %s: %s", requestedVtxID, err)
 		b.Ctx.Log.Verbo("vertex: %s", formatting.DumpBytes{Bytes: vtxs[0]})
+		b.Misbehavior.Record(vdr, common.InvalidContainer)
+		b.Scheduler.Failed(vdr, requestID)
 		return b.fetch(requestedVtxID)
 	}
 
@@ -261,9 +291,10 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte
 	// If the vertex is neither the requested vertex nor a needed vertex, return early and re-fetch if necessary
 	if requested && !requestedVtxID.Equals(vtxID) {
 		b.Ctx.Log.Debug("received incorrect vertex from %s with vertexID %s", vdr, vtxID)
+		b.Scheduler.Failed(vdr, requestID)
 		return b.fetch(requestedVtxID)
 	}
-	if !requested && !b.OutstandingRequests.Contains(vtxID) && !b.needToFetch.Contains(vtxID) {
+	if !requested && !b.Scheduler.Contains(vtxID) && !b.needToFetch.Contains(vtxID) {
 		b.Ctx.Log.Debug("received un-needed vertex from %s with vertexID %s", vdr, vtxID)
 		return nil
 	}
@@ -276,6 +307,7 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte
 	// All vertices added to [processVertices] have received transitive votes from the accepted frontier
 	processVertices := make([]avalanche.Vertex, 1, len(vtxs)) // Process all of the valid vertices in this message
 	processVertices[0] = vtx
+	numBytes := len(vtxs[0])
 	eligibleVertices := ids.Set{}
 	parents, err := vtx.Parents()
 	if err != nil {
@@ -306,20 +338,22 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte
 			eligibleVertices.Add(parent.ID())
 		}
 		processVertices = append(processVertices, vtx)
+		numBytes += len(vtxBytes)
 		b.needToFetch.Remove(vtxID) // No need to fetch this vertex since we have it now
 	}
+	b.Scheduler.Received(vdr, requestID, numBytes)
 
 	return b.process(processVertices...)
 }
 
 // GetAncestorsFailed is called when a GetAncestors message we sent fails
 func (b *Bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) error {
-	vtxID, ok := b.OutstandingRequests.Remove(vdr, requestID)
+	vtxID, ok := b.Scheduler.Failed(vdr, requestID)
 	if !ok {
 		b.Ctx.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
 		return nil
 	}
-	// Send another request for the vertex
+	// Send another request for the vertex, which prefers a different validator
 	return b.fetch(vtxID)
 }
 
@@ -330,6 +364,15 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) error {
 			err)
 	}
 
+	for _, vtxID := range b.Manager.Edge() {
+		if vtx, err := b.Manager.GetVertex(vtxID); err == nil {
+			if height, err := vtx.Height(); err == nil && height > b.startingHeight {
+				b.startingHeight = height
+			}
+		}
+	}
+	b.tipHeight = b.startingHeight
+
 	toProcess := make([]avalanche.Vertex, 0, acceptedContainerIDs.Len())
 	for _, vtxID := range acceptedContainerIDs.List() {
 		if vtx, err := b.Manager.GetVertex(vtxID); err == nil {
@@ -344,12 +387,13 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) error {
 // Finish bootstrapping
 func (b *Bootstrapper) finish() error {
 	// If there are outstanding requests for vertices or we still need to fetch vertices, we can't finish
-	if b.Ctx.IsBootstrapped() || b.OutstandingRequests.Len() > 0 || b.needToFetch.Len() > 0 {
+	if b.Ctx.IsBootstrapped() || b.Scheduler.Len() > 0 || b.needToFetch.Len() > 0 {
 		return nil
 	}
 
 	b.Ctx.Log.Info("bootstrapping fetched %d vertices. executing transaction state transitions...",
 		b.NumFetched)
+	b.Progress.SetPhase(common.PhaseExecuting)
 	if err := b.executeAll(b.TxBlocked, b.Ctx.DecisionDispatcher); err != nil {
 		return err
 	}
@@ -369,29 +413,58 @@ func (b *Bootstrapper) finish() error {
 		return err
 	}
 	b.Ctx.Bootstrapped()
+	b.Progress.SetPhase(common.PhaseFinished)
 
 	return nil
 }
 
 func (b *Bootstrapper) executeAll(jobs *queue.Jobs, events *triggers.EventDispatcher) error {
-	numExecuted := 0
+	numExecuted, err := b.execute(jobs, events)
+	if err != nil {
+		return err
+	}
+	b.Ctx.Log.Info("executed %d operations", numExecuted)
+	return nil
+}
 
+// execute the operations in [jobs] that are ready to be executed. Returns the
+// number of operations executed.
+func (b *Bootstrapper) execute(jobs *queue.Jobs, events *triggers.EventDispatcher) (int, error) {
+	numExecuted := 0
 	for job, err := jobs.Pop(); err == nil; job, err = jobs.Pop() {
 		b.Ctx.Log.Debug("Executing: %s", job.ID())
 		if err := jobs.Execute(job); err != nil {
 			b.Ctx.Log.Error("Error executing: %s", err)
-			return err
+			return numExecuted, err
 		}
 		if err := jobs.Commit(); err != nil {
-			return err
+			return numExecuted, err
 		}
 		numExecuted++
 		if numExecuted%common.StatusUpdateFrequency == 0 { // Periodically print progress
 			b.Ctx.Log.Info("executed %d operations", numExecuted)
 		}
+		b.Progress.AddExecuted(1)
+		if err := b.updateJobsRemaining(); err != nil {
+			return numExecuted, err
+		}
 
 		events.Accept(b.Ctx.ChainID, job.ID(), job.Bytes())
 	}
-	b.Ctx.Log.Info("executed %d operations", numExecuted)
+	return numExecuted, nil
+}
+
+// updateJobsRemaining reports the number of vertices and transactions waiting
+// to be executed
+func (b *Bootstrapper) updateJobsRemaining() error {
+	numPendingVts, err := b.VtxBlocked.NumPending()
+	if err != nil {
+		return err
+	}
+	numPendingTxs, err := b.TxBlocked.NumPending()
+	if err != nil {
+		return err
+	}
+	b.Progress.SetJobsRemaining(uint64(numPendingVts) + uint64(numPendingTxs))
 	return nil
 }
@@ -48,6 +48,7 @@ func newConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *vertex.T
 	vm.Default(true)
 
 	sender.CantGetAcceptedFrontier = false
+	manager.CantEdge = false
 
 	peer := ids.GenerateTestShortID()
 	peers.AddWeight(peer, 1)
@@ -476,6 +477,158 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	}
 }
 
+// Transactions whose dependencies are accepted are executed while the rest of
+// the vertices are fetched
+func TestBootstrapperExecutesWhileFetching(t *testing.T) {
+	config, peerID, sender, manager, vm := newConfig(t)
+	config.Fetch.ExecuteWhileFetching = true
+
+	txID0 := ids.GenerateTestID()
+	txID1 := ids.GenerateTestID()
+
+	txBytes0 := []byte{0}
+	txBytes1 := []byte{1}
+
+	tx0 := &snowstorm.TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     txID0,
+			StatusV: choices.Processing,
+		},
+		BytesV: txBytes0,
+	}
+	tx0.InputIDsV.Add(ids.GenerateTestID())
+
+	// Depends on tx0
+	tx1 := &snowstorm.TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     txID1,
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []snowstorm.Tx{tx0},
+		BytesV:        txBytes1,
+	}
+	tx1.InputIDsV.Add(ids.GenerateTestID())
+
+	vtxID0 := ids.GenerateTestID()
+	vtxID1 := ids.GenerateTestID()
+
+	vtxBytes0 := []byte{2}
+	vtxBytes1 := []byte{3}
+	vm.ParseTxF = func(b []byte) (snowstorm.Tx, error) {
+		switch {
+		case bytes.Equal(b, txBytes0):
+			return tx0, nil
+		case bytes.Equal(b, txBytes1):
+			return tx1, nil
+		default:
+			return nil, errors.New("wrong tx")
+		}
+	}
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     vtxID0,
+			StatusV: choices.Unknown,
+		},
+		HeightV: 0,
+		TxsV:    []snowstorm.Tx{tx1},
+		BytesV:  vtxBytes0,
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     vtxID1,
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{vtx0}, // Depends on vtx0
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+		BytesV:   vtxBytes1,
+	}
+
+	bs := Bootstrapper{}
+	finished := new(bool)
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s_bs", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(vtxID1)
+
+	manager.ParseVertexF = func(vtxBytes []byte) (avalanche.Vertex, error) {
+		switch {
+		case bytes.Equal(vtxBytes, vtxBytes1):
+			return vtx1, nil
+		case bytes.Equal(vtxBytes, vtxBytes0):
+			vtx0.StatusV = choices.Processing
+			return vtx0, nil
+		}
+		t.Fatal(errParsedUnknownVertex)
+		return nil, errParsedUnknownVertex
+	}
+	manager.GetVertexF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case vtxID.Equals(vtxID1):
+			return vtx1, nil
+		case vtxID.Equals(vtxID0):
+			return nil, errUnknownVertex
+		default:
+			t.Fatal(errUnknownVertex)
+			panic(errUnknownVertex)
+		}
+	}
+
+	reqIDPtr := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+		if !vdr.Equals(peerID) {
+			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
+		}
+		if !vtxID.Equals(vtxID0) {
+			t.Fatal(errUnknownVertex)
+		}
+		*reqIDPtr = reqID
+	}
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request vtx0
+		t.Fatal(err)
+	}
+
+	switch {
+	case *finished:
+		t.Fatalf("Shouldn't have finished bootstrapping")
+	case tx0.Status() != choices.Accepted:
+		t.Fatalf("Tx should be accepted while fetching")
+	case tx1.Status() != choices.Processing:
+		t.Fatalf("Tx shouldn't be accepted before its dependency is fetched")
+	case vtx1.Status() != choices.Processing:
+		t.Fatalf("Vertex shouldn't be accepted while fetching")
+	}
+
+	vm.CantBootstrapped = false
+
+	if err := bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0}); err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case !*finished:
+		t.Fatalf("Should have finished bootstrapping")
+	case tx1.Status() != choices.Accepted:
+		t.Fatalf("Tx should be accepted")
+	case vtx0.Status() != choices.Accepted:
+		t.Fatalf("Vertex should be accepted")
+	case vtx1.Status() != choices.Accepted:
+		t.Fatalf("Vertex should be accepted")
+	}
+}
+
 // Unfulfilled tx dependency
 func TestBootstrapperMissingTxDependency(t *testing.T) {
 	config, peerID, sender, manager, vm := newConfig(t)
@@ -84,6 +84,8 @@ func (i *issuer) Update() {
 		return
 	}
 
+	i.t.traceConsensus()
+
 	// Issue a poll for this vertex.
 	p := i.t.Consensus.Parameters()
 	vdrs, err := i.t.Validators.Sample(p.K) // Validators to sample
@@ -96,8 +98,9 @@ func (i *issuer) Update() {
 	vdrSet := ids.ShortSet{}
 	vdrSet.Add(vdrBag.List()...)
 
-	i.t.RequestID++
+	i.t.IncrementRequestID()
 	if err == nil && i.t.polls.Add(i.t.RequestID, vdrBag) {
+		i.t.tracePoll(i.t.RequestID, vtxID, vdrBag)
 		i.t.Sender.PushQuery(vdrSet, i.t.RequestID, vtxID, i.vtx.Bytes())
 	} else if err != nil {
 		i.t.Ctx.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
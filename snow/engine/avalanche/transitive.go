@@ -43,6 +43,10 @@ type Transitive struct {
 	// The set of vertices that have been requested in Get messages but not yet received
 	outstandingVtxReqs common.Requests
 
+	// The vertices in [outstandingVtxReqs] that were requested from a
+	// validator that voted for them
+	votedVtxReqs ids.Set
+
 	// missingTxs tracks transaction that are missing
 	missingTxs ids.Set
 
@@ -54,6 +58,13 @@ type Transitive struct {
 	// txBlocked tracks operations that are blocked on transactions
 	vtxBlocked, txBlocked events.Blocker
 
+	// tracer records the polls of the vertices and transactions being traced
+	tracer common.PollTracer
+
+	// rejections tells the votes for vertices that were already rejected when
+	// the query was sent apart from the votes that raced with the rejection
+	rejections common.Rejections
+
 	errs wrappers.Errs
 }
 
@@ -75,6 +86,11 @@ func (t *Transitive) Initialize(config Config) error {
 		return err
 	}
 
+	t.rejections.Initialize(&t.RequestID)
+	if err := config.Ctx.ConsensusDispatcher.RegisterChain(config.Ctx.ChainID, common.RejectionsHandlerID, &t.rejections); err != nil {
+		return err
+	}
+
 	return t.Bootstrapper.Initialize(
 		config.Config,
 		t.finishBootstrapping,
@@ -130,6 +146,9 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
+	if err := t.Ctx.ConsensusDispatcher.DeregisterChain(t.Ctx.ChainID, common.RejectionsHandlerID); err != nil {
+		t.Ctx.Log.Warn("failed to deregister the rejections tracker due to: %s", err)
+	}
 	return t.VM.Shutdown()
 }
 
@@ -195,7 +214,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 	t.Ctx.Log.Verbo("Put(%s, %d, %s) called", vdr, requestID, vtxID)
 
 	if !t.Ctx.IsBootstrapped() { // Bootstrapping unfinished --> didn't call Get --> this message is invalid
-		if requestID == constants.GossipMsgRequestID {
+		if requestID == constants.GossipMsgRequestID || requestID == constants.GossipTxRequestID {
 			t.Ctx.Log.Verbo("dropping gossip Put(%s, %d, %s) due to bootstrapping", vdr, requestID, vtxID)
 		} else {
 			t.Ctx.Log.Debug("dropping Put(%s, %d, %s) due to bootstrapping", vdr, requestID, vtxID)
@@ -203,10 +222,16 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		return nil
 	}
 
+	if requestID == constants.GossipTxRequestID {
+		t.Ctx.Log.Verbo("dropping gossiped tx %s from %s as tx gossip isn't supported", vtxID, vdr)
+		return nil
+	}
+
 	vtx, err := t.Manager.ParseVertex(vtxBytes)
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse vertex %s due to: %s", vtxID, err)
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
+		t.Misbehavior.Record(vdr, common.InvalidContainer)
 		return t.GetFailed(vdr, requestID)
 	}
 	_, err = t.issueFrom(vdr, vtx)
@@ -226,6 +251,14 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 		return nil
 	}
 
+	// A validator that voted for a vertex should be able to provide it
+	if t.votedVtxReqs.Contains(vtxID) {
+		t.votedVtxReqs.Remove(vtxID)
+		if _, err := t.Manager.GetVertex(vtxID); err != nil {
+			t.Misbehavior.Record(vdr, common.UnknownVote)
+		}
+	}
+
 	t.vtxBlocked.Abandon(vtxID)
 
 	if t.outstandingVtxReqs.Len() == 0 {
@@ -287,6 +320,7 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID,
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse vertex %s due to: %s", vtxID, err)
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
+		t.Misbehavior.Record(vdr, common.InvalidContainer)
 		return nil
 	}
 
@@ -312,7 +346,21 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 	}
 	voteList := votes.List()
 	for _, vote := range voteList {
-		if added, err := t.issueFromByID(vdr, vote); err != nil {
+		vtx, err := t.Manager.GetVertex(vote)
+		if err != nil {
+			if !t.outstandingVtxReqs.Contains(vote) {
+				t.sendRequest(vdr, vote)
+				t.votedVtxReqs.Add(vote)
+			}
+			v.deps.Add(vote)
+			continue
+		}
+		// a vote for a vertex that was already rejected when the query was
+		// sent conflicts with the accepted frontier
+		if vtx.Status() == choices.Rejected && t.rejectedBefore(vote, requestID) {
+			t.Misbehavior.Record(vdr, common.ConflictingVote)
+		}
+		if added, err := t.issueFrom(vdr, vtx); err != nil {
 			return err
 		} else if !added {
 			v.deps.Add(vote)
@@ -323,11 +371,62 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 	return t.errs.Err
 }
 
+// rejectedBefore returns true if [vtxID], which is rejected, was already
+// rejected when the query [requestID] was sent
+func (t *Transitive) rejectedBefore(vtxID ids.ID, requestID uint32) bool {
+	// Forget the rejections that happened before the oldest outstanding poll
+	// was issued, as the votes of the other polls are no longer applied
+	oldestRequestID := t.RequestID + 1
+	if polls := t.polls.Outstanding(1); len(polls) > 0 {
+		oldestRequestID = polls[0].RequestID
+	}
+	t.rejections.Prune(oldestRequestID)
+	return t.rejections.RejectedBefore(vtxID, requestID)
+}
+
 // QueryFailed implements the Engine interface
 func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 	return t.Chits(vdr, requestID, ids.Set{})
 }
 
+// GetStateChunk implements the Engine interface
+// The state of a DAG can't be synced, so the request is dropped
+func (t *Transitive) GetStateChunk(vdr ids.ShortID, requestID uint32, vtxID ids.ID, index uint32) error {
+	t.Ctx.Log.Debug("dropping GetStateChunk(%s, %d, %s, %d) as state sync isn't supported", vdr, requestID, vtxID, index)
+	return nil
+}
+
+// StateChunk implements the Engine interface
+func (t *Transitive) StateChunk(vdr ids.ShortID, requestID uint32, chunk []byte) error {
+	t.Ctx.Log.Debug("received unexpected StateChunk from %s with ID %d", vdr, requestID)
+	return nil
+}
+
+// GetStateChunkFailed implements the Engine interface
+func (t *Transitive) GetStateChunkFailed(vdr ids.ShortID, requestID uint32) error {
+	t.Ctx.Log.Debug("GetStateChunkFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+	return nil
+}
+
+// GetStateSummary implements the Engine interface
+// The state of a DAG can't be synced, so the request is dropped
+func (t *Transitive) GetStateSummary(vdr ids.ShortID, requestID uint32) error {
+	t.Ctx.Log.Debug("dropping GetStateSummary(%s, %d) as state sync isn't supported", vdr, requestID)
+	return nil
+}
+
+// StateSummary implements the Engine interface
+func (t *Transitive) StateSummary(vdr ids.ShortID, requestID uint32, summary []byte) error {
+	t.Ctx.Log.Debug("received unexpected StateSummary from %s with ID %d", vdr, requestID)
+	return nil
+}
+
+// GetStateSummaryFailed implements the Engine interface
+func (t *Transitive) GetStateSummaryFailed(vdr ids.ShortID, requestID uint32) error {
+	t.Ctx.Log.Debug("GetStateSummaryFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+	return nil
+}
+
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) error {
 	if !t.Ctx.IsBootstrapped() {
@@ -431,6 +530,7 @@ func (t *Transitive) issue(vtx avalanche.Vertex) error {
 	// Add to set of vertices that have been queued up to be issued but haven't been yet
 	t.pending.Add(vtxID)
 	t.outstandingVtxReqs.RemoveAny(vtxID)
+	t.votedVtxReqs.Remove(vtxID)
 
 	// Will put [vtx] into consensus once dependencies are met
 	i := &issuer{
@@ -569,8 +669,9 @@ func (t *Transitive) issueRepoll() {
 	vdrSet.Add(vdrBag.List()...)
 
 	// Poll the network
-	t.RequestID++
+	t.IncrementRequestID()
 	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+		t.tracePoll(t.RequestID, vtxID, vdrBag)
 		t.Sender.PullQuery(vdrSet, t.RequestID, vtxID)
 	} else if err != nil {
 		t.Ctx.Log.Error("re-query for %s was dropped due to an insufficient number of validators", vtxID)
@@ -617,8 +718,131 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, vtxID ids.ID) {
 		t.Ctx.Log.Debug("not sending request for vertex %s because there is already an outstanding request for it", vtxID)
 		return
 	}
-	t.RequestID++
+	t.IncrementRequestID()
 	t.outstandingVtxReqs.Add(vdr, t.RequestID, vtxID) // Mark that there is an outstanding request for this vertex
 	t.Sender.Get(vdr, t.RequestID, vtxID)
 	t.numVtxRequests.Set(float64(t.outstandingVtxReqs.Len())) // Tracks performance statistics
 }
+
+// tracePoll records the poll [requestID] of [vtxID] in the timelines of the
+// traced vertices and transactions that are part of it. A vertex, and its
+// transactions, are part of the polls of itself and of its processing
+// descendants, as votes for a vertex are applied to its ancestors.
+func (t *Transitive) tracePoll(requestID uint32, vtxID ids.ID, vdrs ids.ShortBag) {
+	tracedIDs := t.tracer.Traced()
+	if len(tracedIDs) == 0 {
+		return
+	}
+	traced := ids.Set{}
+	traced.Add(tracedIDs...)
+
+	vtx, err := t.Manager.GetVertex(vtxID)
+	if err != nil {
+		return
+	}
+	containerIDs := []ids.ID(nil)
+	visited := ids.Set{}
+	frontier := []avalanche.Vertex{vtx}
+	for len(frontier) > 0 {
+		newLen := len(frontier) - 1
+		vtx := frontier[newLen]
+		frontier = frontier[:newLen]
+
+		vtxID := vtx.ID()
+		if visited.Contains(vtxID) || vtx.Status() != choices.Processing {
+			continue
+		}
+		visited.Add(vtxID)
+		if traced.Contains(vtxID) {
+			containerIDs = append(containerIDs, vtxID)
+		}
+
+		txs, err := vtx.Txs()
+		if err != nil {
+			continue
+		}
+		for _, tx := range txs {
+			if txID := tx.ID(); traced.Contains(txID) {
+				containerIDs = append(containerIDs, txID)
+			}
+		}
+
+		parents, err := vtx.Parents()
+		if err != nil {
+			continue
+		}
+		frontier = append(frontier, parents...)
+	}
+	t.tracer.PollIssued(requestID, containerIDs, vdrs)
+}
+
+// traceConsensus records the state in consensus of the traced vertices and
+// transactions. A vertex is preferred if all its processing transactions are,
+// and its confidence is the lowest confidence of its processing transactions.
+func (t *Transitive) traceConsensus() {
+	tracedIDs := t.tracer.Traced()
+	if len(tracedIDs) == 0 {
+		return
+	}
+	txPreferences := t.Consensus.TxPreferences()
+	for _, containerID := range tracedIDs {
+		if confidence, processing := t.Consensus.TxConfidence(containerID); processing {
+			t.tracer.ConsensusUpdated(containerID, choices.Processing, txPreferences.Contains(containerID), confidence)
+			continue
+		}
+
+		if vtx, err := t.Manager.GetVertex(containerID); err == nil {
+			switch status := vtx.Status(); {
+			case status.Decided():
+				t.tracer.ConsensusUpdated(containerID, status, false, 0)
+			case t.Consensus.VertexIssued(vtx):
+				txs, err := vtx.Txs()
+				if err != nil {
+					continue
+				}
+				preferred, confidence, anyProcessing := true, 0, false
+				for _, tx := range txs {
+					txID := tx.ID()
+					txConfidence, processing := t.Consensus.TxConfidence(txID)
+					if !processing {
+						continue
+					}
+					preferred = preferred && txPreferences.Contains(txID)
+					if !anyProcessing || txConfidence < confidence {
+						confidence = txConfidence
+					}
+					anyProcessing = true
+				}
+				t.tracer.ConsensusUpdated(containerID, status, preferred, confidence)
+			}
+			continue
+		}
+
+		if tx, err := t.VM.GetTx(containerID); err == nil {
+			if status := tx.Status(); status.Decided() {
+				t.tracer.ConsensusUpdated(containerID, status, false, 0)
+			}
+		}
+	}
+}
+
+// PollTracer implements the common.PollTraceable interface
+func (t *Transitive) PollTracer() *common.PollTracer { return &t.tracer }
+
+// ProcessingReport implements the common.ProcessingReporter interface
+func (t *Transitive) ProcessingReport(max int) (common.ProcessingReport, error) {
+	report := common.ProcessingReport{NumPolls: t.polls.Len()}
+	vts, numVertices, err := t.Consensus.ProcessingVertices(max)
+	if err != nil {
+		return common.ProcessingReport{}, err
+	}
+	report.Vertices, report.NumVertices = vts, numVertices
+	report.Txs, report.NumTxs = t.Consensus.ProcessingTxs(max)
+	for _, poll := range t.polls.Outstanding(max) {
+		report.Polls = append(report.Polls, common.OutstandingPoll{
+			RequestID: poll.RequestID,
+			Start:     poll.Start,
+		})
+	}
+	return report, nil
+}
@@ -2362,8 +2362,11 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 		*requestID = reqID
 	}
 
+	manager.EdgeF = func() []ids.ID { return nil }
+
 	te.Accepted(vdr, *requestID, acceptedFrontier)
 
+	manager.EdgeF = nil
 	manager.GetVertexF = nil
 	sender.GetF = nil
 
@@ -3289,3 +3292,78 @@ func TestEngineDoubleChit(t *testing.T) {
 		t.Fatalf("Wrong tx status: %s ; expected: %s", status, choices.Accepted)
 	}
 }
+
+func TestEngineRecordsUnknownVote(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	vals.AddWeight(vdr, 1)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	manager := &vertex.TestManager{T: t}
+	config.Manager = manager
+
+	manager.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVertexF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		return nil, errUnknownVertex
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	ledger := &common.MisbehaviorLedger{}
+	if err := ledger.Initialize(common.MisbehaviorConfig{}, vals, te.Ctx.Log, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	te.Misbehavior = ledger
+
+	unknownID := ids.GenerateTestID()
+	reqID := new(uint32)
+	sender.GetF = func(inVdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+		if !vtxID.Equals(unknownID) {
+			t.Fatalf("Wrong vertex requested")
+		}
+		*reqID = requestID
+	}
+
+	votes := ids.Set{}
+	votes.Add(unknownID)
+	if err := te.Chits(vdr, 0, votes); err != nil {
+		t.Fatal(err)
+	}
+	if offenders := ledger.Offenders(); len(offenders) != 0 {
+		t.Fatalf("the vote shouldn't be recorded before the vertex fails to be fetched")
+	}
+
+	if err := te.GetFailed(vdr, *reqID); err != nil {
+		t.Fatal(err)
+	}
+	offenders := ledger.Offenders()
+	if len(offenders) != 1 {
+		t.Fatalf("expected 1 offender, got %d", len(offenders))
+	}
+	if count := offenders[0].Count(common.UnknownVote); count != 1 {
+		t.Fatalf("expected 1 unknown vote, got %d", count)
+	}
+}
@@ -34,10 +34,17 @@ func (v *voter) Update() {
 		return
 	}
 
+	// An empty response is applied to the poll as a failed query
+	if v.response.Len() == 0 {
+		v.t.tracer.QueryFailed(v.requestID, v.vdr)
+	} else {
+		v.t.tracer.Chits(v.requestID, v.vdr, v.response.List())
+	}
 	results, finished := v.t.polls.Vote(v.requestID, v.vdr, v.response.List())
 	if !finished {
 		return
 	}
+	v.tracePollFinished(results)
 	results, err := v.bubbleVotes(results)
 	if err != nil {
 		v.t.errs.Add(err)
@@ -49,6 +56,7 @@ func (v *voter) Update() {
 		v.t.errs.Add(err)
 		return
 	}
+	v.t.traceConsensus()
 
 	txs := []snowstorm.Tx(nil)
 	for _, orphanID := range v.t.Consensus.Orphans().List() {
@@ -75,6 +83,16 @@ func (v *voter) Update() {
 	v.t.errs.Add(v.t.repoll())
 }
 
+// tracePollFinished records the result of the poll. Each vertex is counted
+// once for every validator that voted for it.
+func (v *voter) tracePollFinished(results ids.UniqueBag) {
+	counts := ids.Bag{}
+	for _, vtxID := range results.List() {
+		counts.AddCount(vtxID, results.GetSet(vtxID).Len())
+	}
+	v.t.tracer.PollFinished(v.requestID, counts)
+}
+
 func (v *voter) bubbleVotes(votes ids.UniqueBag) (ids.UniqueBag, error) {
 	bubbledVotes := ids.UniqueBag{}
 	vertexHeap := vertex.NewHeap()
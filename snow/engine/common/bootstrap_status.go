@@ -0,0 +1,258 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// BootstrapPhase is a stage of bootstrapping a chain
+type BootstrapPhase uint32
+
+// List of the phases of bootstrapping, in the order they happen
+// [PhaseNotStarted] means bootstrapping hasn't started yet
+// [PhaseFrontier] means the accepted frontier is being requested from the
+// bootstrap validators
+// [PhaseAccepted] means the bootstrap validators are voting on which
+// containers of the frontier are accepted
+// [PhaseStateSync] means the state of the VM is being synced to a recent
+// accepted container, rather than executing every container since genesis
+// [PhaseFetching] means the accepted containers and their ancestors are being
+// fetched
+// [PhaseExecuting] means the fetched containers are being executed
+// [PhaseFinished] means the chain is done bootstrapping
+const (
+	PhaseNotStarted BootstrapPhase = iota
+	PhaseFrontier
+	PhaseAccepted
+	PhaseStateSync
+	PhaseFetching
+	PhaseExecuting
+	PhaseFinished
+)
+
+func (p BootstrapPhase) String() string {
+	switch p {
+	case PhaseNotStarted:
+		return "NotStarted"
+	case PhaseFrontier:
+		return "FrontierDiscovery"
+	case PhaseAccepted:
+		return "AcceptedVoting"
+	case PhaseStateSync:
+		return "StateSync"
+	case PhaseFetching:
+		return "Fetching"
+	case PhaseExecuting:
+		return "Executing"
+	case PhaseFinished:
+		return "Finished"
+	default:
+		return "Invalid phase"
+	}
+}
+
+// BootstrapStatus is a snapshot of the progress of bootstrapping a chain
+type BootstrapStatus struct {
+	Phase BootstrapPhase
+
+	// Number of containers fetched so far
+	NumFetched uint64
+	// Estimate of the number of containers that will be fetched. This is
+	// derived from the heights of the accepted frontier, so for a DAG it's a
+	// lower bound.
+	EstimatedTotal uint64
+	// Number of fetched containers and transactions that haven't been executed
+	JobsRemaining uint64
+
+	// Containers fetched per second while fetching, or jobs executed per
+	// second while executing
+	Throughput float64
+	// Estimated time until the current phase finishes. Zero if the phase isn't
+	// fetching or executing, or if nothing has been done in the phase yet.
+	ETA time.Duration
+}
+
+// BootstrapStatusReporter is implemented by engines that report the progress of
+// bootstrapping their chain
+type BootstrapStatusReporter interface {
+	BootstrapStatus() BootstrapStatus
+}
+
+// BootstrapTracker tracks the progress of bootstrapping a chain.
+// It's safe to call from multiple goroutines, so the status can be read without
+// holding the chain's lock.
+type BootstrapTracker struct {
+	lock sync.Mutex
+
+	// Clock used to measure throughput. Exposed for testing.
+	Clock timer.Clock
+
+	phase                                                  BootstrapPhase
+	phaseStart                                             time.Time
+	numFetched, estimatedTotal, jobsRemaining, numExecuted uint64
+
+	// Number of containers fetched, and jobs executed, when the current phase
+	// started
+	phaseStartFetched, phaseStartExecuted uint64
+
+	phaseGauge, fetchedGauge, estimatedTotalGauge, jobsRemainingGauge,
+	throughputGauge, etaGauge prometheus.Gauge
+}
+
+// Initialize the metrics of this tracker
+func (t *BootstrapTracker) Initialize(namespace string, registerer prometheus.Registerer) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.phaseGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "phase",
+		Help:      "Current phase of bootstrapping. 0: not started, 1: frontier discovery, 2: accepted voting, 3: state sync, 4: fetching, 5: executing, 6: finished",
+	})
+	t.fetchedGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "progress_fetched",
+		Help:      "Number of containers fetched so far during bootstrapping",
+	})
+	t.estimatedTotalGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "progress_estimated_total",
+		Help:      "Estimated number of containers that will be fetched during bootstrapping",
+	})
+	t.jobsRemainingGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "progress_jobs_remaining",
+		Help:      "Number of fetched containers and transactions that haven't been executed",
+	})
+	t.throughputGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "progress_throughput",
+		Help:      "Containers fetched, or jobs executed, per second in the current phase of bootstrapping",
+	})
+	t.etaGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "progress_eta_seconds",
+		Help:      "Estimated number of seconds until the current phase of bootstrapping finishes",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(t.phaseGauge),
+		registerer.Register(t.fetchedGauge),
+		registerer.Register(t.estimatedTotalGauge),
+		registerer.Register(t.jobsRemainingGauge),
+		registerer.Register(t.throughputGauge),
+		registerer.Register(t.etaGauge),
+	)
+	return errs.Err
+}
+
+// SetPhase marks that bootstrapping entered [phase]
+func (t *BootstrapTracker) SetPhase(phase BootstrapPhase) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.phase == phase {
+		return
+	}
+	t.phase = phase
+	t.phaseStart = t.Clock.Time()
+	t.phaseStartFetched = t.numFetched
+	t.phaseStartExecuted = t.numExecuted
+	if phase == PhaseFinished {
+		t.jobsRemaining = 0
+	}
+	t.update()
+}
+
+// AddFetched marks that [numFetched] more containers were fetched
+func (t *BootstrapTracker) AddFetched(numFetched uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.numFetched += numFetched
+	t.update()
+}
+
+// AddExecuted marks that [numExecuted] more jobs were executed
+func (t *BootstrapTracker) AddExecuted(numExecuted uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.numExecuted += numExecuted
+	t.update()
+}
+
+// SetEstimatedTotal sets the estimated number of containers that will be
+// fetched
+func (t *BootstrapTracker) SetEstimatedTotal(estimatedTotal uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.estimatedTotal = estimatedTotal
+	t.update()
+}
+
+// SetJobsRemaining sets the number of jobs waiting to be executed
+func (t *BootstrapTracker) SetJobsRemaining(jobsRemaining uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.jobsRemaining = jobsRemaining
+	t.update()
+}
+
+// Status returns the current progress of bootstrapping
+func (t *BootstrapTracker) Status() BootstrapStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.update()
+}
+
+// update the metrics and return the current status
+// Assumes [t.lock] is held
+func (t *BootstrapTracker) update() BootstrapStatus {
+	status := BootstrapStatus{
+		Phase:          t.phase,
+		NumFetched:     t.numFetched,
+		EstimatedTotal: t.estimatedTotal,
+		JobsRemaining:  t.jobsRemaining,
+	}
+	// The estimate can be low, but it's never less than what has been fetched
+	if status.EstimatedTotal < status.NumFetched {
+		status.EstimatedTotal = status.NumFetched
+	}
+
+	var done, remaining uint64
+	switch t.phase {
+	case PhaseFetching:
+		done = t.numFetched - t.phaseStartFetched
+		remaining = status.EstimatedTotal - status.NumFetched
+	case PhaseExecuting:
+		done = t.numExecuted - t.phaseStartExecuted
+		remaining = t.jobsRemaining
+	}
+	if elapsed := t.Clock.Time().Sub(t.phaseStart).Seconds(); done > 0 && elapsed > 0 {
+		status.Throughput = float64(done) / elapsed
+		status.ETA = time.Duration(float64(remaining) / status.Throughput * float64(time.Second))
+	}
+
+	if t.phaseGauge != nil {
+		t.phaseGauge.Set(float64(status.Phase))
+		t.fetchedGauge.Set(float64(status.NumFetched))
+		t.estimatedTotalGauge.Set(float64(status.EstimatedTotal))
+		t.jobsRemainingGauge.Set(float64(status.JobsRemaining))
+		t.throughputGauge.Set(status.Throughput)
+		t.etaGauge.Set(status.ETA.Seconds())
+	}
+	return status
+}
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBootstrapTracker(t *testing.T) {
+	tracker := BootstrapTracker{}
+	assert.NoError(t, tracker.Initialize("", prometheus.NewRegistry()))
+
+	start := time.Unix(1000, 0)
+	tracker.Clock.Set(start)
+
+	status := tracker.Status()
+	assert.Equal(t, PhaseNotStarted, status.Phase)
+	assert.Zero(t, status.ETA)
+
+	// Fetch 100 of an estimated 400 containers in 10 seconds
+	tracker.SetPhase(PhaseFetching)
+	tracker.SetEstimatedTotal(400)
+	tracker.AddFetched(100)
+	tracker.SetJobsRemaining(100)
+	tracker.Clock.Set(start.Add(10 * time.Second))
+
+	status = tracker.Status()
+	assert.Equal(t, PhaseFetching, status.Phase)
+	assert.EqualValues(t, 100, status.NumFetched)
+	assert.EqualValues(t, 400, status.EstimatedTotal)
+	assert.EqualValues(t, 100, status.JobsRemaining)
+	assert.Equal(t, float64(10), status.Throughput)
+	assert.Equal(t, 30*time.Second, status.ETA)
+
+	// The estimate is never less than what has been fetched
+	tracker.AddFetched(400)
+	status = tracker.Status()
+	assert.EqualValues(t, 500, status.EstimatedTotal)
+	assert.Zero(t, status.ETA)
+
+	// Execute 50 of 500 jobs in 5 seconds
+	tracker.SetPhase(PhaseExecuting)
+	tracker.AddExecuted(50)
+	tracker.SetJobsRemaining(450)
+	tracker.Clock.Set(start.Add(15 * time.Second))
+
+	status = tracker.Status()
+	assert.Equal(t, PhaseExecuting, status.Phase)
+	assert.Equal(t, float64(10), status.Throughput)
+	assert.Equal(t, 45*time.Second, status.ETA)
+
+	tracker.SetPhase(PhaseFinished)
+	status = tracker.Status()
+	assert.Equal(t, PhaseFinished, status.Phase)
+	assert.Zero(t, status.JobsRemaining)
+	assert.Zero(t, status.Throughput)
+	assert.Zero(t, status.ETA)
+}
@@ -9,6 +9,7 @@ import (
 	stdmath "math"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/math"
 )
 
@@ -42,7 +43,12 @@ type Bootstrapper struct {
 	pendingAccepted ids.ShortSet
 	acceptedVotes   map[[32]byte]uint64
 
+	// ID of the last request sent. Never one of the request IDs reserved for
+	// gossip.
 	RequestID uint32
+
+	// Tracks the progress of bootstrapping
+	Progress BootstrapTracker
 }
 
 // Initialize implements the Engine interface.
@@ -58,10 +64,21 @@ func (b *Bootstrapper) Initialize(config Config) {
 	b.acceptedVotes = make(map[[32]byte]uint64)
 }
 
+// IncrementRequestID sets [RequestID] to the ID of the next request to send.
+// The request IDs reserved for gossip are skipped when the counter wraps
+// around, so that a response is never mistaken for gossip.
+func (b *Bootstrapper) IncrementRequestID() {
+	b.RequestID++
+	for b.RequestID == constants.GossipMsgRequestID || b.RequestID == constants.GossipTxRequestID {
+		b.RequestID++
+	}
+}
+
 // Startup implements the Engine interface.
 func (b *Bootstrapper) Startup() error {
 	if b.pendingAcceptedFrontier.Len() == 0 {
 		b.Ctx.Log.Info("Bootstrapping skipped due to no provided bootstraps")
+		b.Progress.SetPhase(PhaseFetching)
 		return b.Bootstrapable.ForceAccepted(ids.Set{})
 	}
 
@@ -69,7 +86,8 @@ func (b *Bootstrapper) Startup() error {
 	vdrs := ids.ShortSet{}
 	vdrs.Union(b.pendingAcceptedFrontier)
 
-	b.RequestID++
+	b.Progress.SetPhase(PhaseFrontier)
+	b.IncrementRequestID()
 	b.Sender.GetAcceptedFrontier(vdrs, b.RequestID)
 	return nil
 }
@@ -106,7 +124,8 @@ func (b *Bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 		vdrs := ids.ShortSet{}
 		vdrs.Union(b.pendingAccepted)
 
-		b.RequestID++
+		b.Progress.SetPhase(PhaseAccepted)
+		b.IncrementRequestID()
 		b.Sender.GetAccepted(vdrs, b.RequestID, b.acceptedFrontier)
 	}
 	return nil
@@ -168,5 +187,9 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 		b.Ctx.Log.Info("Bootstrapping started syncing with %d vertices in the accepted frontier", size)
 	}
 
+	b.Progress.SetPhase(PhaseFetching)
 	return b.Bootstrapable.ForceAccepted(accepted)
 }
+
+// BootstrapStatus implements the BootstrapStatusReporter interface
+func (b *Bootstrapper) BootstrapStatus() BootstrapStatus { return b.Progress.Status() }
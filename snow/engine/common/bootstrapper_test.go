@@ -0,0 +1,26 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestIncrementRequestIDSkipsGossipRequestIDs(t *testing.T) {
+	b := Bootstrapper{RequestID: constants.GossipTxRequestID - 2}
+
+	b.IncrementRequestID()
+	assert.Equal(t, uint32(constants.GossipTxRequestID-1), b.RequestID)
+
+	// The counter wraps around without sending a request with an ID reserved
+	// for gossip
+	b.IncrementRequestID()
+	assert.Equal(t, uint32(0), b.RequestID)
+	b.IncrementRequestID()
+	assert.Equal(t, uint32(1), b.RequestID)
+}
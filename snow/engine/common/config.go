@@ -18,6 +18,12 @@ type Config struct {
 	Alpha         uint64
 	Sender        Sender
 	Bootstrapable Bootstrapable
+
+	// Tuning parameters of fetching containers while bootstrapping
+	Fetch FetchConfig
+
+	// Records the misbehavior of validators. May be nil.
+	Misbehavior *MisbehaviorLedger
 }
 
 // Context implements the Engine interface
@@ -32,6 +32,7 @@ type ExternalHandler interface {
 	AcceptedHandler
 	FetchHandler
 	QueryHandler
+	StateSyncHandler
 }
 
 // FrontierHandler defines how a consensus engine reacts to frontier messages
@@ -272,6 +273,79 @@ type QueryHandler interface {
 	QueryFailed(validatorID ids.ShortID, requestID uint32) error
 }
 
+// StateSyncHandler defines how a consensus engine reacts to messages
+// pertaining to syncing the state of a chain from other validators. Functions
+// only return fatal errors if they occur.
+type StateSyncHandler interface {
+	// Notify this engine of a request for a chunk of its state.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID. It is also not safe to
+	// assume the requested summaryID exists. However, the validatorID is
+	// assumed to be authenticated.
+	//
+	// This engine should respond with a StateChunk message with the same
+	// requestID, which contains chunk [index] of the state summarized by the
+	// summary with ID [summaryID]. If this engine doesn't have that state, it
+	// can ignore this message.
+	GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) error
+
+	// Notify this engine of a chunk of state.
+	//
+	// This should only be called while bootstrapping, in response to a
+	// GetStateChunk message to [validatorID] with request ID [requestID].
+	//
+	// It is not safe to assume this message is in response to a GetStateChunk
+	// message, that this message has a unique requestID or that [chunk] is
+	// valid. However, the validatorID is assumed to be authenticated.
+	StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) error
+
+	// Notify this engine that a GetStateChunk request it issued has failed.
+	//
+	// This function will be called if the engine sent a GetStateChunk message
+	// that is not anticipated to be responded to. This could be because the
+	// recipient of the message is unknown or if the message request has timed
+	// out.
+	//
+	// The validatorID and requestID are assumed to be the same as those sent in
+	// the GetStateChunk message.
+	GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of a request for a summary of the most recent state
+	// it can send chunks of.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID. However, the validatorID
+	// is assumed to be authenticated.
+	//
+	// This engine should respond with a StateSummary message with the same
+	// requestID. If this engine doesn't have a summary, it can ignore this
+	// message.
+	GetStateSummary(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of a summary of the state of another validator.
+	//
+	// This should only be called while bootstrapping, in response to a
+	// GetStateSummary message to [validatorID] with request ID [requestID].
+	//
+	// It is not safe to assume this message is in response to a
+	// GetStateSummary message, that this message has a unique requestID or
+	// that [summary] is valid. However, the validatorID is assumed to be
+	// authenticated.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) error
+
+	// Notify this engine that a GetStateSummary request it issued has failed.
+	//
+	// This function will be called if the engine sent a GetStateSummary
+	// message that is not anticipated to be responded to. This could be
+	// because the recipient of the message is unknown or if the message
+	// request has timed out.
+	//
+	// The validatorID and requestID are assumed to be the same as those sent in
+	// the GetStateSummary message.
+	GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error
+}
+
 // InternalHandler defines how this consensus engine reacts to messages from
 // other components of this validator. Functions only return fatal errors if
 // they occur.
@@ -0,0 +1,364 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// DefaultMaxOutstandingRequestsPerPeer is the default maximum number of
+	// GetAncestors requests outstanding to a single peer
+	DefaultMaxOutstandingRequestsPerPeer = 2
+
+	// DefaultMaxRequestsPerContainer is the default maximum number of peers a
+	// container is requested from at once
+	DefaultMaxRequestsPerContainer = 2
+
+	// DefaultObservationWeight is the default weight of a new observation in a
+	// peer's moving averages
+	DefaultObservationWeight = .2
+
+	// Latencies are considered to be at least this long, so that responses
+	// measured with a coarse clock don't make a peer infinitely fast
+	minLatency = time.Millisecond
+
+	// Scores are scaled to this precision before sampling
+	maxSampleWeight = 1 << 20
+)
+
+var (
+	errNoBeacons         = errors.New("there are no beacons to fetch from")
+	errAlreadyRequesting = errors.New("the container is already being requested from every beacon that hasn't failed to return it")
+)
+
+// FetchConfig are the tuning parameters of fetching containers while
+// bootstrapping
+type FetchConfig struct {
+	// Maximum number of GetAncestors requests outstanding at once. If zero,
+	// this is [MaxOutstandingRequestsPerPeer] per beacon, but at least
+	// [MaxOutstandingRequests].
+	MaxOutstandingRequests int
+
+	// Number of GetAncestors requests that can be outstanding to a single peer
+	// before other peers are preferred. If zero,
+	// [DefaultMaxOutstandingRequestsPerPeer] is used.
+	MaxOutstandingRequestsPerPeer int
+
+	// Number of peers a block of a linear chain can be requested from at once.
+	// While there are spare requests, a block is requested from several peers,
+	// the first valid response is used and the other requests are abandoned,
+	// so that a single slow peer doesn't stall fetching the chain. If zero,
+	// [DefaultMaxRequestsPerContainer] is used.
+	MaxRequestsPerContainer int
+
+	// Weight, in (0, 1], of a new observation in a peer's moving averages of
+	// latency and response size. If zero, [DefaultObservationWeight] is used.
+	ObservationWeight float64
+
+	// If true, the fetched containers whose dependencies have all been
+	// accepted are executed while fetching continues
+	ExecuteWhileFetching bool
+}
+
+// DefaultFetchConfig returns the fetch parameters used by the node
+func DefaultFetchConfig() FetchConfig {
+	return FetchConfig{
+		MaxOutstandingRequestsPerPeer: DefaultMaxOutstandingRequestsPerPeer,
+		MaxRequestsPerContainer:       DefaultMaxRequestsPerContainer,
+		ObservationWeight:             DefaultObservationWeight,
+	}
+}
+
+// FetchScheduler decides which peers containers are fetched from while
+// bootstrapping, and tracks the outstanding requests.
+// Requests are spread across the beacons, weighted by the throughput observed
+// from each beacon. A container that a peer failed to return is retried on a
+// different peer.
+type FetchScheduler struct {
+	FetchConfig
+
+	// Clock used to measure latencies. Exposed for testing.
+	Clock timer.Clock
+
+	beacons validators.Set
+
+	// Key: Peer ID
+	// Value: What has been observed about the peer
+	peers map[[20]byte]*peerStats
+
+	// Key: Container ID
+	// Value: Peers that failed to return the container
+	failed map[[32]byte]ids.ShortSet
+
+	// Key: Container ID
+	// Value: Peers the container is outstanding from, and the IDs of the
+	// requests it was requested with
+	requested map[[32]byte]map[[20]byte]uint32
+
+	numOutstanding int
+}
+
+type peerStats struct {
+	// Moving averages of the seconds a peer took to respond, and of the number
+	// of bytes in its responses
+	latency, size float64
+	observed      bool
+
+	// Key: Request ID
+	// Value: The outstanding request
+	outstanding map[uint32]request
+}
+
+// request is a request for a container that was sent to a peer
+type request struct {
+	containerID ids.ID
+	sent        time.Time
+}
+
+// score is the expected throughput of the peer, in bytes per second
+func (p *peerStats) score() float64 { return p.size / p.latency }
+
+// Initialize the scheduler to fetch from [beacons]
+func (s *FetchScheduler) Initialize(config FetchConfig, beacons validators.Set) {
+	s.FetchConfig = config
+	if s.MaxOutstandingRequestsPerPeer <= 0 {
+		s.MaxOutstandingRequestsPerPeer = DefaultMaxOutstandingRequestsPerPeer
+	}
+	if s.MaxRequestsPerContainer <= 0 {
+		s.MaxRequestsPerContainer = DefaultMaxRequestsPerContainer
+	}
+	if s.ObservationWeight <= 0 || s.ObservationWeight > 1 {
+		s.ObservationWeight = DefaultObservationWeight
+	}
+	if s.MaxOutstandingRequests <= 0 {
+		s.MaxOutstandingRequests = s.MaxOutstandingRequestsPerPeer * beacons.Len()
+		if s.MaxOutstandingRequests < MaxOutstandingRequests {
+			s.MaxOutstandingRequests = MaxOutstandingRequests
+		}
+	}
+	s.beacons = beacons
+	s.peers = make(map[[20]byte]*peerStats)
+	s.failed = make(map[[32]byte]ids.ShortSet)
+	s.requested = make(map[[32]byte]map[[20]byte]uint32)
+	s.numOutstanding = 0
+}
+
+// CanSend returns true if another request can be outstanding
+func (s *FetchScheduler) CanSend() bool { return s.numOutstanding < s.MaxOutstandingRequests }
+
+// Len returns the number of outstanding requests
+func (s *FetchScheduler) Len() int { return s.numOutstanding }
+
+// Contains returns true if there is an outstanding request for [containerID]
+func (s *FetchScheduler) Contains(containerID ids.ID) bool {
+	_, ok := s.requested[containerID.Key()]
+	return ok
+}
+
+// Outstanding returns the containers that have outstanding requests
+func (s *FetchScheduler) Outstanding() []ids.ID {
+	containerIDs := make([]ids.ID, 0, len(s.requested))
+	for containerKey := range s.requested {
+		containerIDs = append(containerIDs, ids.NewID(containerKey))
+	}
+	return containerIDs
+}
+
+// CanRequestAgain returns true if [containerID], which has an outstanding
+// request, can also be requested from another peer
+func (s *FetchScheduler) CanRequestAgain(containerID ids.ID) bool {
+	numRequests := len(s.requested[containerID.Key()])
+	return numRequests < s.MaxRequestsPerContainer && numRequests < s.beacons.Len()
+}
+
+// Requested returns the container that was requested from [vdr] with request
+// [requestID], and true if the request is outstanding
+func (s *FetchScheduler) Requested(vdr ids.ShortID, requestID uint32) (ids.ID, bool) {
+	peer, ok := s.peers[vdr.Key()]
+	if !ok {
+		return ids.ID{}, false
+	}
+	req, ok := peer.outstanding[requestID]
+	return req.containerID, ok
+}
+
+// Sample returns the peer to fetch [containerID] from.
+// Peers that [containerID] is outstanding from are never chosen. Peers that
+// failed to return [containerID] are only chosen if every beacon has failed to
+// return it. Peers with [MaxOutstandingRequestsPerPeer] outstanding requests
+// are only chosen if every other peer has as many.
+func (s *FetchScheduler) Sample(containerID ids.ID) (ids.ShortID, error) {
+	beacons := s.beacons.List()
+	if len(beacons) == 0 {
+		return ids.ShortID{}, errNoBeacons
+	}
+
+	failed := s.failed[containerID.Key()]
+	requested := s.requested[containerID.Key()]
+	candidates := make([]ids.ShortID, 0, len(beacons))
+	for _, allowBusy := range []bool{false, true} {
+		for _, beacon := range beacons {
+			vdrID := beacon.ID()
+			if failed.Contains(vdrID) {
+				continue
+			}
+			if _, ok := requested[vdrID.Key()]; ok {
+				continue
+			}
+			if peer, ok := s.peers[vdrID.Key()]; !allowBusy && ok && len(peer.outstanding) >= s.MaxOutstandingRequestsPerPeer {
+				continue
+			}
+			candidates = append(candidates, vdrID)
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+	if len(candidates) == 0 {
+		if len(requested) > 0 {
+			return ids.ShortID{}, errAlreadyRequesting
+		}
+		// Every beacon failed to return this container, so start over
+		delete(s.failed, containerID.Key())
+		return s.Sample(containerID)
+	}
+
+	// Peers that haven't been observed yet are assumed to be as good as the
+	// best observed peer, so that they're tried
+	maxScore := 0.
+	for _, vdrID := range candidates {
+		if peer, ok := s.peers[vdrID.Key()]; ok && peer.observed && peer.score() > maxScore {
+			maxScore = peer.score()
+		}
+	}
+	weights := make([]uint64, len(candidates))
+	totalWeight := uint64(0)
+	for i, vdrID := range candidates {
+		// Every candidate has a chance of being chosen, so a peer that
+		// performed poorly once isn't ignored forever
+		weight := uint64(1)
+		if peer, ok := s.peers[vdrID.Key()]; ok && peer.observed && maxScore > 0 {
+			weight += uint64(peer.score() / maxScore * maxSampleWeight)
+		} else {
+			weight += maxSampleWeight
+		}
+		weights[i] = weight
+		totalWeight += weight
+	}
+
+	sampleValue := uint64(rand.Int63n(int64(totalWeight)))
+	for i, weight := range weights {
+		if sampleValue < weight {
+			return candidates[i], nil
+		}
+		sampleValue -= weight
+	}
+	return candidates[len(candidates)-1], nil
+}
+
+// Sent marks that request [requestID] for [containerID] was sent to [vdr]
+func (s *FetchScheduler) Sent(vdr ids.ShortID, requestID uint32, containerID ids.ID) {
+	vdrKey := vdr.Key()
+	peer, ok := s.peers[vdrKey]
+	if !ok {
+		peer = &peerStats{outstanding: make(map[uint32]request)}
+		s.peers[vdrKey] = peer
+	}
+	peer.outstanding[requestID] = request{
+		containerID: containerID,
+		sent:        s.Clock.Time(),
+	}
+	s.numOutstanding++
+
+	containerKey := containerID.Key()
+	requested, ok := s.requested[containerKey]
+	if !ok {
+		requested = make(map[[20]byte]uint32)
+		s.requested[containerKey] = requested
+	}
+	requested[vdrKey] = requestID
+}
+
+// Received marks that [vdr] responded to request [requestID] with [numBytes]
+// bytes of containers, which include the requested container. The other
+// outstanding requests for the container are abandoned. Returns false if the
+// request isn't outstanding.
+func (s *FetchScheduler) Received(vdr ids.ShortID, requestID uint32, numBytes int) bool {
+	containerID, ok := s.observe(vdr, requestID, float64(numBytes))
+	if !ok {
+		return false
+	}
+	containerKey := containerID.Key()
+	for vdrKey, otherRequestID := range s.requested[containerKey] {
+		s.remove(ids.NewShortID(vdrKey), otherRequestID)
+	}
+	delete(s.failed, containerKey)
+	return true
+}
+
+// Failed marks that [vdr] didn't return the container requested with request
+// [requestID]. Returns the container, and false if the request isn't
+// outstanding.
+func (s *FetchScheduler) Failed(vdr ids.ShortID, requestID uint32) (ids.ID, bool) {
+	containerID, ok := s.observe(vdr, requestID, 0)
+	if !ok {
+		return ids.ID{}, false
+	}
+	containerKey := containerID.Key()
+	failed := s.failed[containerKey]
+	failed.Add(vdr)
+	s.failed[containerKey] = failed
+	return containerID, true
+}
+
+// observe the response to request [requestID] sent to [vdr], and remove the
+// request. Returns the requested container, and false if the request isn't
+// outstanding.
+func (s *FetchScheduler) observe(vdr ids.ShortID, requestID uint32, size float64) (ids.ID, bool) {
+	peer, ok := s.peers[vdr.Key()]
+	if !ok {
+		return ids.ID{}, false
+	}
+	req, ok := peer.outstanding[requestID]
+	if !ok {
+		return ids.ID{}, false
+	}
+	s.remove(vdr, requestID)
+
+	latency := s.Clock.Time().Sub(req.sent)
+	if latency < minLatency {
+		latency = minLatency
+	}
+	if !peer.observed {
+		peer.latency = latency.Seconds()
+		peer.size = size
+		peer.observed = true
+		return req.containerID, true
+	}
+	peer.latency += s.ObservationWeight * (latency.Seconds() - peer.latency)
+	peer.size += s.ObservationWeight * (size - peer.size)
+	return req.containerID, true
+}
+
+// remove the outstanding request [requestID] sent to [vdr]
+func (s *FetchScheduler) remove(vdr ids.ShortID, requestID uint32) {
+	peer := s.peers[vdr.Key()]
+	req := peer.outstanding[requestID]
+	delete(peer.outstanding, requestID)
+	s.numOutstanding--
+
+	containerKey := req.containerID.Key()
+	requested := s.requested[containerKey]
+	delete(requested, vdr.Key())
+	if len(requested) == 0 {
+		delete(s.requested, containerKey)
+	}
+}
@@ -0,0 +1,175 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func newTestBeacons(t *testing.T, num int) (validators.Set, []ids.ShortID) {
+	beacons := validators.NewSet()
+	beaconIDs := make([]ids.ShortID, num)
+	for i := range beaconIDs {
+		beaconIDs[i] = ids.GenerateTestShortID()
+		assert.NoError(t, beacons.AddWeight(beaconIDs[i], 1))
+	}
+	return beacons, beaconIDs
+}
+
+func TestFetchSchedulerDefaults(t *testing.T) {
+	beacons, _ := newTestBeacons(t, 5)
+
+	s := FetchScheduler{}
+	s.Initialize(FetchConfig{}, beacons)
+	assert.Equal(t, DefaultMaxOutstandingRequestsPerPeer, s.MaxOutstandingRequestsPerPeer)
+	assert.Equal(t, DefaultMaxRequestsPerContainer, s.MaxRequestsPerContainer)
+	assert.Equal(t, DefaultObservationWeight, s.ObservationWeight)
+	assert.Equal(t, 5*DefaultMaxOutstandingRequestsPerPeer, s.MaxOutstandingRequests)
+
+	// Never fewer outstanding requests than before the scheduler was added
+	beacons, _ = newTestBeacons(t, 1)
+	s.Initialize(FetchConfig{}, beacons)
+	assert.Equal(t, MaxOutstandingRequests, s.MaxOutstandingRequests)
+
+	s.Initialize(FetchConfig{MaxOutstandingRequests: 1}, beacons)
+	assert.True(t, s.CanSend())
+	containerID := ids.GenerateTestID()
+	vdrID, err := s.Sample(containerID)
+	assert.NoError(t, err)
+	s.Sent(vdrID, 1, containerID)
+	assert.False(t, s.CanSend())
+	assert.True(t, s.Received(vdrID, 1, 1))
+	assert.True(t, s.CanSend())
+	assert.Zero(t, s.Len())
+
+	s.Initialize(FetchConfig{}, validators.NewSet())
+	_, err = s.Sample(ids.GenerateTestID())
+	assert.Error(t, err)
+}
+
+func TestFetchSchedulerRetriesOnDifferentPeer(t *testing.T) {
+	beacons, beaconIDs := newTestBeacons(t, 2)
+	containerID := ids.GenerateTestID()
+
+	s := FetchScheduler{}
+	s.Initialize(FetchConfig{}, beacons)
+
+	s.Sent(beaconIDs[0], 1, containerID)
+	failedID, ok := s.Failed(beaconIDs[0], 1)
+	assert.True(t, ok)
+	assert.Equal(t, containerID, failedID)
+	for i := 0; i < 100; i++ {
+		vdrID, err := s.Sample(containerID)
+		assert.NoError(t, err)
+		assert.Equal(t, beaconIDs[1], vdrID)
+	}
+
+	// Once every peer failed to return the container, any peer can be retried
+	s.Sent(beaconIDs[1], 2, containerID)
+	s.Failed(beaconIDs[1], 2)
+	_, err := s.Sample(containerID)
+	assert.NoError(t, err)
+
+	// A successful response clears the failures
+	s.Sent(beaconIDs[0], 3, containerID)
+	s.Failed(beaconIDs[0], 3)
+	s.Sent(beaconIDs[1], 4, containerID)
+	s.Received(beaconIDs[1], 4, 1)
+	assert.Empty(t, s.failed)
+
+	// Responses to requests that weren't sent are ignored
+	_, ok = s.Failed(beaconIDs[1], 5)
+	assert.False(t, ok)
+	assert.Empty(t, s.failed)
+}
+
+func TestFetchSchedulerPrefersFasterPeers(t *testing.T) {
+	beacons, beaconIDs := newTestBeacons(t, 2)
+	containerID := ids.GenerateTestID()
+
+	s := FetchScheduler{}
+	s.Initialize(FetchConfig{}, beacons)
+	start := time.Unix(1000, 0)
+	s.Clock.Set(start)
+
+	s.Sent(beaconIDs[0], 1, ids.GenerateTestID())
+	s.Sent(beaconIDs[1], 2, ids.GenerateTestID())
+	s.Clock.Set(start.Add(10 * time.Millisecond))
+	s.Received(beaconIDs[0], 1, 1<<20)
+	s.Clock.Set(start.Add(10 * time.Second))
+	s.Received(beaconIDs[1], 2, 1)
+
+	numFast := 0
+	for i := 0; i < 1000; i++ {
+		vdrID, err := s.Sample(containerID)
+		assert.NoError(t, err)
+		if vdrID.Equals(beaconIDs[0]) {
+			numFast++
+		}
+	}
+	assert.Greater(t, numFast, 990)
+
+	// A busy peer is only chosen if every peer is busy
+	s.Sent(beaconIDs[0], 3, ids.GenerateTestID())
+	s.Sent(beaconIDs[0], 4, ids.GenerateTestID())
+	for i := 0; i < 100; i++ {
+		vdrID, err := s.Sample(containerID)
+		assert.NoError(t, err)
+		assert.Equal(t, beaconIDs[1], vdrID)
+	}
+	s.Sent(beaconIDs[1], 5, ids.GenerateTestID())
+	s.Sent(beaconIDs[1], 6, ids.GenerateTestID())
+	_, err := s.Sample(containerID)
+	assert.NoError(t, err)
+}
+
+func TestFetchSchedulerRequestsFromSeveralPeers(t *testing.T) {
+	beacons, beaconIDs := newTestBeacons(t, 3)
+	containerID := ids.GenerateTestID()
+
+	s := FetchScheduler{}
+	s.Initialize(FetchConfig{}, beacons)
+
+	s.Sent(beaconIDs[0], 1, containerID)
+	assert.True(t, s.Contains(containerID))
+	assert.Equal(t, []ids.ID{containerID}, s.Outstanding())
+	assert.True(t, s.CanRequestAgain(containerID))
+
+	// A peer that the container is outstanding from isn't chosen again
+	for i := 0; i < 100; i++ {
+		vdrID, err := s.Sample(containerID)
+		assert.NoError(t, err)
+		assert.NotEqual(t, beaconIDs[0], vdrID)
+	}
+	s.Sent(beaconIDs[1], 2, containerID)
+	assert.False(t, s.CanRequestAgain(containerID))
+	assert.Equal(t, 2, s.Len())
+
+	requestedID, ok := s.Requested(beaconIDs[1], 2)
+	assert.True(t, ok)
+	assert.Equal(t, containerID, requestedID)
+
+	// The first response abandons the other requests for the container
+	assert.True(t, s.Received(beaconIDs[1], 2, 1))
+	assert.False(t, s.Contains(containerID))
+	assert.Zero(t, s.Len())
+	_, ok = s.Requested(beaconIDs[0], 1)
+	assert.False(t, ok)
+	assert.False(t, s.Received(beaconIDs[0], 1, 1))
+
+	// Once the container is outstanding from every peer that hasn't failed to
+	// return it, no peer is chosen
+	s.Sent(beaconIDs[0], 3, containerID)
+	s.Failed(beaconIDs[0], 3)
+	s.Sent(beaconIDs[1], 4, containerID)
+	s.Sent(beaconIDs[2], 5, containerID)
+	_, err := s.Sample(containerID)
+	assert.Error(t, err)
+}
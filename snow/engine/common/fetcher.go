@@ -8,8 +8,9 @@ type Fetcher struct {
 	// number of containers fetched so far
 	NumFetched uint32
 
-	// tracks which validators were asked for which containers in which requests
-	OutstandingRequests Requests
+	// decides which peers containers are fetched from, and tracks which
+	// validators were asked for which containers in which requests
+	Scheduler FetchScheduler
 
 	// Called when bootstrapping is done
 	OnFinished func() error
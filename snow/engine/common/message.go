@@ -17,12 +17,19 @@ const (
 	// its VM has pending transactions
 	// (i.e. it would like to add a new block/vertex to consensus)
 	PendingTxs Message = iota
+
+	// GossipTxs notifies a consensus engine that
+	// its VM has new unconfirmed transactions
+	// that should be gossiped to the network
+	GossipTxs
 )
 
 func (msg Message) String() string {
 	switch msg {
 	case PendingTxs:
 		return "Pending Transactions"
+	case GossipTxs:
+		return "Gossip Transactions"
 	default:
 		return fmt.Sprintf("Unknown Message: %d", msg)
 	}
@@ -0,0 +1,370 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// DefaultMisbehaviorBenchThreshold is the default score at which a
+	// validator is benched
+	DefaultMisbehaviorBenchThreshold = 50
+
+	// DefaultMisbehaviorHalfLife is the default time it takes for the score of
+	// a validator to halve
+	DefaultMisbehaviorHalfLife = 5 * time.Minute
+
+	// Validators that aren't benched are forgotten once their score decayed
+	// below this
+	minMisbehaviorScore = .01
+)
+
+// Misbehavior is a kind of misbehavior of a validator
+type Misbehavior uint32
+
+// List of the misbehaviors recorded by the engines and the router
+// [InvalidContainer] means the validator sent a container that couldn't be
+// parsed
+// [MalformedChits] means the validator responded to a query with chits that
+// can't be a valid response
+// [ConflictingVote] means the validator voted for a container that was already
+// rejected when it was queried
+// [LateResponse] means the validator responded to a request after it timed
+// out, or to a request that was never sent
+// [FailedGet] means a request for a container sent to the validator timed out.
+// A single timeout may just be a slow or disconnected validator, so only
+// repeated timeouts lead to benching.
+// [UnknownVote] means the validator voted for a container that couldn't be
+// fetched from it
+const (
+	InvalidContainer Misbehavior = iota
+	MalformedChits
+	ConflictingVote
+	LateResponse
+	FailedGet
+	UnknownVote
+
+	numMisbehaviors
+)
+
+var (
+	// Score added to a validator for each misbehavior
+	misbehaviorPenalties = [numMisbehaviors]float64{
+		InvalidContainer: 10,
+		MalformedChits:   10,
+		ConflictingVote:  1,
+		LateResponse:     1,
+		FailedGet:        .5,
+		UnknownVote:      1,
+	}
+
+	// Suffix of the name of the metric counting each misbehavior
+	misbehaviorMetricNames = [numMisbehaviors]string{
+		InvalidContainer: "invalid_container",
+		MalformedChits:   "malformed_chits",
+		ConflictingVote:  "conflicting_vote",
+		LateResponse:     "late_response",
+		FailedGet:        "failed_get",
+		UnknownVote:      "unknown_vote",
+	}
+)
+
+func (m Misbehavior) String() string {
+	switch m {
+	case InvalidContainer:
+		return "InvalidContainer"
+	case MalformedChits:
+		return "MalformedChits"
+	case ConflictingVote:
+		return "ConflictingVote"
+	case LateResponse:
+		return "LateResponse"
+	case FailedGet:
+		return "FailedGet"
+	case UnknownVote:
+		return "UnknownVote"
+	default:
+		return "Unknown misbehavior"
+	}
+}
+
+// MisbehaviorConfig are the parameters of scoring the misbehavior of
+// validators
+type MisbehaviorConfig struct {
+	// Score at which a validator is benched from being sampled. It's unbenched
+	// once its score decayed below half of this. If zero, validators are never
+	// benched.
+	BenchThreshold float64
+
+	// Time it takes for the score of a validator to halve. If zero,
+	// [DefaultMisbehaviorHalfLife] is used.
+	HalfLife time.Duration
+}
+
+// DefaultMisbehaviorConfig returns the misbehavior parameters used by the node
+func DefaultMisbehaviorConfig() MisbehaviorConfig {
+	return MisbehaviorConfig{
+		BenchThreshold: DefaultMisbehaviorBenchThreshold,
+		HalfLife:       DefaultMisbehaviorHalfLife,
+	}
+}
+
+// Offender is a validator that misbehaved recently
+type Offender struct {
+	NodeID ids.ShortID
+	// Decayed sum of the penalties of the misbehaviors of the validator
+	Score float64
+	// True if the validator is excluded from being sampled
+	Benched bool
+	// Number of times the validator misbehaved in each way, since it was last
+	// forgotten
+	Counts [numMisbehaviors]int
+	// Time of the last misbehavior
+	LastMisbehavior time.Time
+}
+
+// Count returns the number of times the offender misbehaved in the way
+// [misbehavior]
+func (o *Offender) Count(misbehavior Misbehavior) int { return o.Counts[misbehavior] }
+
+// offender is the state of a validator that misbehaved recently
+type offender struct {
+	Offender
+	// Time [Score] was last decayed at
+	updated time.Time
+}
+
+// MisbehaviorLedger records the misbehavior of validators on a chain, and
+// benches the validators whose score reached the threshold from being sampled
+// by the chain. Benching is local to the ledger, so a validator benched on one
+// chain is still sampled by the other chains validated by the same subnet.
+// Recording on a nil ledger is a noop. It's safe to call from multiple
+// goroutines, so both the engine and the router can record misbehavior.
+type MisbehaviorLedger struct {
+	lock sync.Mutex
+
+	// Clock used to decay scores. Exposed for testing.
+	Clock timer.Clock
+
+	config     MisbehaviorConfig
+	validators validators.Set
+	log        logging.Logger
+
+	// validator ID --> state of the validator
+	offenders map[[20]byte]*offender
+	// Number of offenders that are benched
+	benched int
+
+	// Samples the validators that aren't benched
+	sampler sampler.WeightedWithoutReplacement
+
+	misbehaviors [numMisbehaviors]prometheus.Counter
+	numBenched   prometheus.Gauge
+}
+
+// Initialize the ledger. [vdrs] are the validators of the chain, which may be
+// shared with other chains. They aren't modified by the ledger.
+func (l *MisbehaviorLedger) Initialize(
+	config MisbehaviorConfig,
+	vdrs validators.Set,
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+) error {
+	if config.HalfLife == 0 {
+		config.HalfLife = DefaultMisbehaviorHalfLife
+	}
+	l.config = config
+	l.validators = vdrs
+	l.log = log
+	l.offenders = make(map[[20]byte]*offender)
+	l.sampler = sampler.NewWeightedWithoutReplacement()
+
+	errs := wrappers.Errs{}
+	for misbehavior, name := range misbehaviorMetricNames {
+		l.misbehaviors[misbehavior] = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "misbehavior_" + name,
+			Help:      fmt.Sprintf("Number of times validators misbehaved with %s", Misbehavior(misbehavior)),
+		})
+		errs.Add(registerer.Register(l.misbehaviors[misbehavior]))
+	}
+	l.numBenched = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "benched",
+		Help:      "Number of validators benched from being sampled due to misbehavior",
+	})
+	errs.Add(registerer.Register(l.numBenched))
+	return errs.Err
+}
+
+// Record that [vdr] misbehaved in the way [misbehavior]
+func (l *MisbehaviorLedger) Record(vdr ids.ShortID, misbehavior Misbehavior) {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.misbehaviors[misbehavior].Inc()
+
+	now := l.Clock.Time()
+	l.update(now)
+
+	key := vdr.Key()
+	o, exists := l.offenders[key]
+	if !exists {
+		o = &offender{
+			Offender: Offender{NodeID: vdr},
+			updated:  now,
+		}
+		l.offenders[key] = o
+	}
+	o.Score += misbehaviorPenalties[misbehavior]
+	o.Counts[misbehavior]++
+	o.LastMisbehavior = now
+
+	l.log.Debug("%s misbehaved with %s. Its score is now %f", vdr, misbehavior, o.Score)
+
+	if o.Benched || l.config.BenchThreshold <= 0 || o.Score < l.config.BenchThreshold {
+		return
+	}
+	l.log.Info("benching %s from being sampled due to misbehavior", vdr)
+	o.Benched = true
+	l.benched++
+	l.numBenched.Inc()
+}
+
+// Offenders returns the validators that misbehaved recently, highest score
+// first
+func (l *MisbehaviorLedger) Offenders() []Offender {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.update(l.Clock.Time())
+
+	offenders := make([]Offender, 0, len(l.offenders))
+	for _, o := range l.offenders {
+		offenders = append(offenders, o.Offender)
+	}
+	sort.Slice(offenders, func(i, j int) bool { return offenders[i].Score > offenders[j].Score })
+	return offenders
+}
+
+// update decays the scores of the validators to [now], unbenches the
+// validators whose score decayed enough, and forgets the validators whose
+// score decayed to nothing.
+// Assumes [l.lock] is held
+func (l *MisbehaviorLedger) update(now time.Time) {
+	for key, o := range l.offenders {
+		if elapsed := now.Sub(o.updated); elapsed > 0 {
+			o.Score *= math.Exp2(-elapsed.Seconds() / l.config.HalfLife.Seconds())
+			o.updated = now
+		}
+
+		if o.Benched && o.Score < l.config.BenchThreshold/2 {
+			l.log.Info("unbenching %s as its misbehavior score decayed", o.NodeID)
+			o.Benched = false
+			l.benched--
+			l.numBenched.Dec()
+		}
+		if !o.Benched && o.Score < minMisbehaviorScore {
+			delete(l.offenders, key)
+		}
+	}
+}
+
+// Validators returns a view of the validators of the chain that doesn't sample
+// the benched validators
+func (l *MisbehaviorLedger) Validators() validators.Set {
+	return &benchedSet{
+		vdrs:   l.validators,
+		ledger: l,
+	}
+}
+
+// sample returns [size] validators that aren't benched. If the validators that
+// aren't benched don't have enough weight, every validator may be sampled.
+func (l *MisbehaviorLedger) sample(size int) ([]validators.Validator, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// Unbench the validators whose score decayed since the last update
+	l.update(l.Clock.Time())
+	if l.benched == 0 {
+		return l.validators.Sample(size)
+	}
+
+	vdrList := l.validators.List()
+	weights := make([]uint64, len(vdrList))
+	for i, vdr := range vdrList {
+		if o, exists := l.offenders[vdr.ID().Key()]; !exists || !o.Benched {
+			weights[i] = vdr.Weight()
+		}
+	}
+	if err := l.sampler.Initialize(weights); err != nil {
+		return nil, err
+	}
+	indices, err := l.sampler.Sample(size)
+	if err != nil {
+		// Fall back to sampling every validator if the validators that aren't
+		// benched don't have enough weight
+		return l.validators.Sample(size)
+	}
+
+	sampled := make([]validators.Validator, size)
+	for i, index := range indices {
+		sampled[i] = vdrList[index]
+	}
+	return sampled, nil
+}
+
+// benchedSet is a view of a validator set that doesn't sample the validators
+// benched by a ledger. Every other call is passed through to the set.
+type benchedSet struct {
+	vdrs   validators.Set
+	ledger *MisbehaviorLedger
+}
+
+func (s *benchedSet) Set(vdrs []validators.Validator) error { return s.vdrs.Set(vdrs) }
+
+func (s *benchedSet) AddWeight(vdrID ids.ShortID, weight uint64) error {
+	return s.vdrs.AddWeight(vdrID, weight)
+}
+
+func (s *benchedSet) GetWeight(vdrID ids.ShortID) (uint64, bool) { return s.vdrs.GetWeight(vdrID) }
+
+func (s *benchedSet) RemoveWeight(vdrID ids.ShortID, weight uint64) error {
+	return s.vdrs.RemoveWeight(vdrID, weight)
+}
+
+func (s *benchedSet) Contains(vdrID ids.ShortID) bool { return s.vdrs.Contains(vdrID) }
+
+func (s *benchedSet) Len() int { return s.vdrs.Len() }
+
+func (s *benchedSet) List() []validators.Validator { return s.vdrs.List() }
+
+func (s *benchedSet) Weight() uint64 { return s.vdrs.Weight() }
+
+func (s *benchedSet) String() string { return s.vdrs.String() }
+
+// Sample implements the validators.Set interface
+func (s *benchedSet) Sample(size int) ([]validators.Validator, error) {
+	return s.ledger.sample(size)
+}
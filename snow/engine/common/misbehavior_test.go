@@ -0,0 +1,169 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestMisbehaviorLedgerBench(t *testing.T) {
+	vdrs, vdrIDs := newTestBeacons(t, 2)
+
+	l := MisbehaviorLedger{}
+	assert.NoError(t, l.Initialize(MisbehaviorConfig{
+		BenchThreshold: 20,
+		HalfLife:       time.Minute,
+	}, vdrs, logging.NoLog{}, "", prometheus.NewRegistry()))
+	now := time.Now()
+	l.Clock.Set(now)
+
+	l.Record(vdrIDs[0], InvalidContainer)
+	l.Record(vdrIDs[0], LateResponse)
+
+	offenders := l.Offenders()
+	assert.Len(t, offenders, 1)
+	assert.Equal(t, vdrIDs[0], offenders[0].NodeID)
+	assert.Equal(t, 11., offenders[0].Score)
+	assert.False(t, offenders[0].Benched)
+	assert.Equal(t, 1, offenders[0].Count(InvalidContainer))
+	assert.Equal(t, 1, offenders[0].Count(LateResponse))
+	assert.Equal(t, 0, offenders[0].Count(ConflictingVote))
+
+	l.Record(vdrIDs[0], MalformedChits)
+	l.Record(vdrIDs[1], ConflictingVote)
+
+	offenders = l.Offenders()
+	assert.Len(t, offenders, 2)
+	assert.Equal(t, vdrIDs[0], offenders[0].NodeID)
+	assert.True(t, offenders[0].Benched)
+	assert.Equal(t, vdrIDs[1], offenders[1].NodeID)
+	assert.False(t, offenders[1].Benched)
+
+	// The benched validator is no longer sampled by the chain
+	chainVdrs := l.Validators()
+	for i := 0; i < 10; i++ {
+		sampled, err := chainVdrs.Sample(1)
+		assert.NoError(t, err)
+		assert.Equal(t, vdrIDs[1], sampled[0].ID())
+	}
+
+	// The validators benched on one chain are still sampled by the other
+	// chains
+	sampled, err := vdrs.Sample(2)
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 2)
+
+	// If the validators that aren't benched don't have enough weight, every
+	// validator is sampled
+	sampled, err = chainVdrs.Sample(2)
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 2)
+}
+
+func TestMisbehaviorLedgerDecay(t *testing.T) {
+	vdrs, vdrIDs := newTestBeacons(t, 2)
+
+	l := MisbehaviorLedger{}
+	assert.NoError(t, l.Initialize(MisbehaviorConfig{
+		BenchThreshold: 20,
+		HalfLife:       time.Minute,
+	}, vdrs, logging.NoLog{}, "", prometheus.NewRegistry()))
+	now := time.Now()
+	l.Clock.Set(now)
+
+	l.Record(vdrIDs[0], InvalidContainer)
+	l.Record(vdrIDs[0], InvalidContainer)
+
+	offenders := l.Offenders()
+	assert.Len(t, offenders, 1)
+	assert.True(t, offenders[0].Benched)
+
+	// The score halved to the unbenching threshold, which isn't enough
+	l.Clock.Set(now.Add(time.Minute))
+	offenders = l.Offenders()
+	assert.Len(t, offenders, 1)
+	assert.InDelta(t, 10, offenders[0].Score, .001)
+	assert.True(t, offenders[0].Benched)
+
+	l.Clock.Set(now.Add(2 * time.Minute))
+	offenders = l.Offenders()
+	assert.Len(t, offenders, 1)
+	assert.InDelta(t, 5, offenders[0].Score, .001)
+	assert.False(t, offenders[0].Benched)
+
+	// The unbenched validator is sampled again
+	sampled, err := l.Validators().Sample(2)
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 2)
+
+	// The validator is forgotten once its score decayed to nothing
+	l.Clock.Set(now.Add(time.Hour))
+	assert.Empty(t, l.Offenders())
+}
+
+func TestMisbehaviorLedgerNoBenching(t *testing.T) {
+	vdrs, vdrIDs := newTestBeacons(t, 1)
+
+	l := MisbehaviorLedger{}
+	assert.NoError(t, l.Initialize(MisbehaviorConfig{}, vdrs, logging.NoLog{}, "", prometheus.NewRegistry()))
+
+	for i := 0; i < 100; i++ {
+		l.Record(vdrIDs[0], MalformedChits)
+	}
+
+	offenders := l.Offenders()
+	assert.Len(t, offenders, 1)
+	assert.False(t, offenders[0].Benched)
+	assert.Equal(t, 100, offenders[0].Count(MalformedChits))
+}
+
+func TestMisbehaviorLedgerNil(t *testing.T) {
+	var l *MisbehaviorLedger
+	vdrs, vdrIDs := newTestBeacons(t, 1)
+
+	l.Record(vdrIDs[0], InvalidContainer)
+
+	sampled, err := vdrs.Sample(1)
+	assert.NoError(t, err)
+	assert.Equal(t, vdrIDs[0], sampled[0].ID())
+}
+
+func TestMisbehaviorLedgerUnbenchWhenSampling(t *testing.T) {
+	vdrs, vdrIDs := newTestBeacons(t, 2)
+
+	l := MisbehaviorLedger{}
+	assert.NoError(t, l.Initialize(MisbehaviorConfig{
+		BenchThreshold: 20,
+		HalfLife:       time.Minute,
+	}, vdrs, logging.NoLog{}, "", prometheus.NewRegistry()))
+	now := time.Now()
+	l.Clock.Set(now)
+
+	l.Record(vdrIDs[0], InvalidContainer)
+	l.Record(vdrIDs[0], InvalidContainer)
+
+	chainVdrs := l.Validators()
+	for i := 0; i < 10; i++ {
+		sampled, err := chainVdrs.Sample(1)
+		assert.NoError(t, err)
+		assert.Equal(t, vdrIDs[1], sampled[0].ID())
+	}
+
+	// The validator is unbenched once its score decayed, without any other
+	// misbehavior being recorded
+	l.Clock.Set(now.Add(2 * time.Minute))
+	sampledIDs := make(map[[20]byte]bool)
+	for i := 0; i < 100; i++ {
+		sampled, err := chainVdrs.Sample(1)
+		assert.NoError(t, err)
+		sampledIDs[sampled[0].ID().Key()] = true
+	}
+	assert.True(t, sampledIDs[vdrIDs[0].Key()])
+}
@@ -0,0 +1,339 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// MaxTracedContainers is the maximum number of containers that can be
+	// traced at once in a chain
+	MaxTracedContainers = 16
+
+	// MaxTraceEvents is the maximum number of events kept in the timeline of a
+	// traced container. Once reached, the oldest events are dropped.
+	MaxTraceEvents = 1024
+)
+
+var (
+	errNotTraced = errors.New("container isn't being traced")
+)
+
+// TraceEventType is the kind of an event in the timeline of a traced container
+type TraceEventType uint32
+
+// List of the events recorded while tracing a container
+// [TracePollIssued] means a poll the container is part of was sent to
+// [Validators]
+// [TraceChits] means [Validator] responded to the poll with [Votes]
+// [TraceQueryFailed] means [Validator] didn't respond to the poll in time, or
+// couldn't be reached
+// [TracePollFinished] means the poll finished with the votes [Votes]. If the
+// poll finished before every sampled validator responded, [EarlyTermination]
+// is set.
+// [TraceConsensus] means the state of the container in consensus changed to
+// [Status], [Preferred] and [Confidence]
+const (
+	TracePollIssued TraceEventType = iota
+	TraceChits
+	TraceQueryFailed
+	TracePollFinished
+	TraceConsensus
+)
+
+func (t TraceEventType) String() string {
+	switch t {
+	case TracePollIssued:
+		return "PollIssued"
+	case TraceChits:
+		return "Chits"
+	case TraceQueryFailed:
+		return "QueryFailed"
+	case TracePollFinished:
+		return "PollFinished"
+	case TraceConsensus:
+		return "Consensus"
+	default:
+		return "Invalid event type"
+	}
+}
+
+// TraceEvent is an event in the timeline of a traced container
+//
+// Responses are recorded when they are applied to the poll, which may be after
+// they were received if the engine had to fetch the containers voted for.
+type TraceEvent struct {
+	Time time.Time
+	Type TraceEventType
+
+	// Request ID of the poll. Unset for [TraceConsensus] events.
+	RequestID uint32
+	// Validators sampled for the poll
+	Validators []ids.ShortID
+	// Validator that responded, or failed to respond, to the poll
+	Validator ids.ShortID
+	// Votes in the response of a validator, or the result of a poll
+	Votes ids.Bag
+	// True if the poll finished before every sampled validator responded
+	EarlyTermination bool
+
+	// State of the container in consensus
+	Status     choices.Status
+	Preferred  bool
+	Confidence int
+}
+
+// PollTraceable is implemented by engines that can trace the polls of
+// containers
+type PollTraceable interface {
+	PollTracer() *PollTracer
+}
+
+// containerTrace is the timeline of a traced container
+type containerTrace struct {
+	events []TraceEvent
+
+	// The last state of the container in consensus that was recorded
+	recordedConsensus bool
+	status            choices.Status
+	preferred         bool
+	confidence        int
+}
+
+// tracedPoll is an outstanding poll that traced containers are part of
+type tracedPoll struct {
+	containerIDs []ids.ID
+	// Sampled validators that haven't responded yet
+	pending ids.ShortSet
+}
+
+// PollTracer records the timelines of the polls of traced containers, and the
+// changes of their state in consensus.
+// Nothing is recorded unless a container is traced. It's safe to call from
+// multiple goroutines, so timelines can be read without holding the chain's
+// lock.
+type PollTracer struct {
+	lock sync.Mutex
+
+	// Clock used to timestamp events. Exposed for testing.
+	Clock timer.Clock
+
+	// container ID --> timeline of the container
+	traces map[[32]byte]*containerTrace
+	// request ID --> outstanding poll of traced containers
+	polls map[uint32]*tracedPoll
+}
+
+// Trace starts recording the timeline of [containerID]. Tracing a container
+// that is already traced keeps its timeline.
+func (t *PollTracer) Trace(containerID ids.ID) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := containerID.Key()
+	if _, exists := t.traces[key]; exists {
+		return nil
+	}
+	if len(t.traces) >= MaxTracedContainers {
+		return fmt.Errorf("can't trace more than %d containers at once", MaxTracedContainers)
+	}
+	if t.traces == nil {
+		t.traces = make(map[[32]byte]*containerTrace)
+		t.polls = make(map[uint32]*tracedPoll)
+	}
+	t.traces[key] = &containerTrace{}
+	return nil
+}
+
+// StopTracing stops recording the timeline of [containerID] and drops it
+func (t *PollTracer) StopTracing(containerID ids.ID) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := containerID.Key()
+	if _, exists := t.traces[key]; !exists {
+		return errNotTraced
+	}
+	delete(t.traces, key)
+	return nil
+}
+
+// Timeline returns the events recorded for [containerID], oldest first
+func (t *PollTracer) Timeline(containerID ids.ID) ([]TraceEvent, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	trace, exists := t.traces[containerID.Key()]
+	if !exists {
+		return nil, errNotTraced
+	}
+	events := make([]TraceEvent, len(trace.events))
+	copy(events, trace.events)
+	return events, nil
+}
+
+// Traced returns the IDs of the traced containers
+func (t *PollTracer) Traced() []ids.ID {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.traces) == 0 {
+		return nil
+	}
+	containerIDs := make([]ids.ID, 0, len(t.traces))
+	for key := range t.traces {
+		containerIDs = append(containerIDs, ids.NewID(key))
+	}
+	return containerIDs
+}
+
+// PollIssued records that the poll [requestID], which the traced containers
+// [containerIDs] are part of, was sent to [vdrs]
+func (t *PollTracer) PollIssued(requestID uint32, containerIDs []ids.ID, vdrs ids.ShortBag) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// The containers may have stopped being traced since they were found to be
+	// part of the poll
+	tracedIDs := []ids.ID(nil)
+	for _, containerID := range containerIDs {
+		if _, exists := t.traces[containerID.Key()]; exists {
+			tracedIDs = append(tracedIDs, containerID)
+		}
+	}
+	if len(tracedIDs) == 0 {
+		return
+	}
+
+	validators := vdrs.List()
+	poll := &tracedPoll{containerIDs: tracedIDs}
+	poll.pending.Add(validators...)
+	t.polls[requestID] = poll
+
+	t.record(poll, TraceEvent{
+		Type:       TracePollIssued,
+		RequestID:  requestID,
+		Validators: validators,
+	})
+}
+
+// Chits records that [vdr] responded to the poll [requestID] with [votes]
+func (t *PollTracer) Chits(requestID uint32, vdr ids.ShortID, votes []ids.ID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	poll, exists := t.polls[requestID]
+	if !exists || !poll.pending.Contains(vdr) {
+		return
+	}
+	poll.pending.Remove(vdr)
+
+	event := TraceEvent{
+		Type:      TraceChits,
+		RequestID: requestID,
+		Validator: vdr,
+	}
+	event.Votes.Add(votes...)
+	t.record(poll, event)
+}
+
+// QueryFailed records that [vdr] failed to respond to the poll [requestID]
+func (t *PollTracer) QueryFailed(requestID uint32, vdr ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	poll, exists := t.polls[requestID]
+	if !exists || !poll.pending.Contains(vdr) {
+		return
+	}
+	poll.pending.Remove(vdr)
+
+	t.record(poll, TraceEvent{
+		Type:      TraceQueryFailed,
+		RequestID: requestID,
+		Validator: vdr,
+	})
+}
+
+// PollFinished records that the poll [requestID] finished with [results]
+func (t *PollTracer) PollFinished(requestID uint32, results ids.Bag) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	poll, exists := t.polls[requestID]
+	if !exists {
+		return
+	}
+	delete(t.polls, requestID)
+
+	event := TraceEvent{
+		Type:             TracePollFinished,
+		RequestID:        requestID,
+		EarlyTermination: poll.pending.Len() > 0,
+	}
+	for _, vote := range results.List() {
+		event.Votes.AddCount(vote, results.Count(vote))
+	}
+	t.record(poll, event)
+}
+
+// ConsensusUpdated records the state of the traced container [containerID] in
+// consensus, if it changed since it was last recorded
+func (t *PollTracer) ConsensusUpdated(containerID ids.ID, status choices.Status, preferred bool, confidence int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	trace, exists := t.traces[containerID.Key()]
+	if !exists {
+		return
+	}
+	if trace.recordedConsensus &&
+		trace.status == status &&
+		trace.preferred == preferred &&
+		trace.confidence == confidence {
+		return
+	}
+	trace.recordedConsensus = true
+	trace.status = status
+	trace.preferred = preferred
+	trace.confidence = confidence
+
+	trace.append(TraceEvent{
+		Time:       t.Clock.Time(),
+		Type:       TraceConsensus,
+		Status:     status,
+		Preferred:  preferred,
+		Confidence: confidence,
+	})
+}
+
+// record [event] in the timeline of every container that is part of [poll]
+// and is still traced
+// Assumes [t.lock] is held
+func (t *PollTracer) record(poll *tracedPoll, event TraceEvent) {
+	event.Time = t.Clock.Time()
+	for _, containerID := range poll.containerIDs {
+		if trace, exists := t.traces[containerID.Key()]; exists {
+			trace.append(event)
+		}
+	}
+}
+
+// append [event] to the timeline, dropping the oldest event if the timeline is
+// full
+func (c *containerTrace) append(event TraceEvent) {
+	if len(c.events) >= MaxTraceEvents {
+		copy(c.events, c.events[1:])
+		c.events = c.events[:len(c.events)-1]
+	}
+	c.events = append(c.events, event)
+}
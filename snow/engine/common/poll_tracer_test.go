@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+func TestPollTracer(t *testing.T) {
+	tracer := PollTracer{}
+	start := time.Unix(1000, 0)
+	tracer.Clock.Set(start)
+
+	tracedID := ids.Empty.Prefix(0)
+	untracedID := ids.Empty.Prefix(1)
+	vdr0 := ids.NewShortID([20]byte{0})
+	vdr1 := ids.NewShortID([20]byte{1})
+	vdr2 := ids.NewShortID([20]byte{2})
+	vdrs := ids.ShortBag{}
+	vdrs.Add(vdr0, vdr1, vdr2)
+
+	// Nothing is recorded before the container is traced
+	tracer.PollIssued(1, []ids.ID{tracedID}, vdrs)
+	_, err := tracer.Timeline(tracedID)
+	assert.Error(t, err)
+
+	assert.NoError(t, tracer.Trace(tracedID))
+	assert.Equal(t, []ids.ID{tracedID}, tracer.Traced())
+
+	tracer.PollIssued(2, []ids.ID{tracedID, untracedID}, vdrs)
+	tracer.PollIssued(3, []ids.ID{untracedID}, vdrs)
+	tracer.Clock.Set(start.Add(time.Second))
+	tracer.Chits(2, vdr0, []ids.ID{tracedID})
+	tracer.Chits(3, vdr0, []ids.ID{tracedID})
+	// A validator that wasn't sampled, or already responded, is ignored
+	tracer.Chits(2, vdr0, []ids.ID{untracedID})
+	tracer.QueryFailed(2, ids.NewShortID([20]byte{3}))
+	tracer.QueryFailed(2, vdr1)
+
+	results := ids.Bag{}
+	results.Add(tracedID)
+	tracer.PollFinished(2, results)
+	// The poll is no longer tracked once it finished
+	tracer.Chits(2, vdr2, []ids.ID{tracedID})
+
+	tracer.ConsensusUpdated(tracedID, choices.Processing, true, 1)
+	// Only changes of the state in consensus are recorded
+	tracer.ConsensusUpdated(tracedID, choices.Processing, true, 1)
+	tracer.ConsensusUpdated(tracedID, choices.Accepted, false, 0)
+	tracer.ConsensusUpdated(untracedID, choices.Accepted, false, 0)
+
+	timeline, err := tracer.Timeline(tracedID)
+	assert.NoError(t, err)
+	assert.Len(t, timeline, 6)
+
+	assert.Equal(t, TracePollIssued, timeline[0].Type)
+	assert.EqualValues(t, 2, timeline[0].RequestID)
+	assert.Len(t, timeline[0].Validators, 3)
+	assert.Equal(t, start, timeline[0].Time)
+
+	assert.Equal(t, TraceChits, timeline[1].Type)
+	assert.Equal(t, vdr0, timeline[1].Validator)
+	assert.Equal(t, 1, timeline[1].Votes.Count(tracedID))
+	assert.Equal(t, start.Add(time.Second), timeline[1].Time)
+
+	assert.Equal(t, TraceQueryFailed, timeline[2].Type)
+	assert.Equal(t, vdr1, timeline[2].Validator)
+
+	// [vdr2] hadn't responded when the poll finished
+	assert.Equal(t, TracePollFinished, timeline[3].Type)
+	assert.True(t, timeline[3].EarlyTermination)
+	assert.Equal(t, 1, timeline[3].Votes.Count(tracedID))
+
+	assert.Equal(t, TraceConsensus, timeline[4].Type)
+	assert.Equal(t, choices.Processing, timeline[4].Status)
+	assert.True(t, timeline[4].Preferred)
+	assert.Equal(t, 1, timeline[4].Confidence)
+
+	assert.Equal(t, TraceConsensus, timeline[5].Type)
+	assert.Equal(t, choices.Accepted, timeline[5].Status)
+
+	assert.NoError(t, tracer.StopTracing(tracedID))
+	assert.Error(t, tracer.StopTracing(tracedID))
+	assert.Empty(t, tracer.Traced())
+}
+
+func TestPollTracerLimits(t *testing.T) {
+	tracer := PollTracer{}
+
+	for i := 0; i < MaxTracedContainers; i++ {
+		assert.NoError(t, tracer.Trace(ids.Empty.Prefix(uint64(i))))
+	}
+	assert.Error(t, tracer.Trace(ids.Empty.Prefix(MaxTracedContainers)))
+	// Tracing a traced container again is fine
+	assert.NoError(t, tracer.Trace(ids.Empty.Prefix(0)))
+
+	// Only the latest events are kept
+	tracedID := ids.Empty.Prefix(0)
+	for i := 0; i < MaxTraceEvents+1; i++ {
+		tracer.ConsensusUpdated(tracedID, choices.Processing, true, i)
+	}
+	timeline, err := tracer.Timeline(tracedID)
+	assert.NoError(t, err)
+	assert.Len(t, timeline, MaxTraceEvents)
+	assert.Equal(t, 1, timeline[0].Confidence)
+	assert.Equal(t, MaxTraceEvents, timeline[MaxTraceEvents-1].Confidence)
+}
@@ -0,0 +1,46 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+// ProcessingReport describes the containers that are processing in consensus,
+// and the outstanding polls of the engine
+type ProcessingReport struct {
+	// Processing blocks of a Snowman chain
+	NumBlocks int
+	Blocks    []snowman.BlockInfo
+
+	// Processing vertices and transactions of an Avalanche chain
+	NumVertices int
+	Vertices    []avalanche.VertexInfo
+	NumTxs      int
+	Txs         []snowstorm.TxInfo
+
+	// Polls that haven't finished yet
+	NumPolls int
+	Polls    []OutstandingPoll
+}
+
+// OutstandingPoll describes a poll that hasn't finished yet
+type OutstandingPoll struct {
+	RequestID uint32
+	// Time the poll was issued
+	Start time.Time
+}
+
+// ProcessingReporter is implemented by engines that describe the containers
+// processing in consensus
+type ProcessingReporter interface {
+	// ProcessingReport returns the [max] oldest processing containers of each
+	// kind, and the [max] oldest outstanding polls.
+	// Assumes the context lock is held.
+	ProcessingReport(max int) (ProcessingReport, error)
+}
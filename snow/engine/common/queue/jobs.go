@@ -36,9 +36,23 @@ func New(db database.Database) (*Jobs, error) {
 	jobs.state.jobs = jobs
 
 	if _, err := jobs.HasNext(); err == nil {
+		// Queues written before the number of pending jobs was tracked only
+		// know about the jobs that are ready to execute
+		if _, err := jobs.state.NumPending(jobs.db); err == database.ErrNotFound {
+			size, err := jobs.state.StackSize(jobs.db)
+			if err != nil {
+				return jobs, err
+			}
+			return jobs, jobs.state.SetNumPending(jobs.db, size)
+		}
 		return jobs, nil
 	}
-	return jobs, jobs.state.SetStackSize(jobs.db, 0)
+	errs := wrappers.Errs{}
+	errs.Add(
+		jobs.state.SetStackSize(jobs.db, 0),
+		jobs.state.SetNumPending(jobs.db, 0),
+	)
+	return jobs, errs.Err
 }
 
 // SetParser ...
@@ -51,10 +65,19 @@ func (j *Jobs) Push(job Job) error {
 		return err
 	}
 	if deps.Len() != 0 {
-		return j.block(job, deps)
+		err = j.block(job, deps)
+	} else {
+		err = j.push(job)
+	}
+	if err != nil {
+		return err
 	}
 
-	return j.push(job)
+	pending, err := j.state.NumPending(j.db)
+	if err != nil {
+		return err
+	}
+	return j.state.SetNumPending(j.db, pending+1)
 }
 
 // Pop ...
@@ -69,6 +92,16 @@ func (j *Jobs) Pop() (Job, error) {
 	if err := j.state.SetStackSize(j.db, size-1); err != nil {
 		return nil, err
 	}
+	pending, err := j.state.NumPending(j.db)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		pending--
+	}
+	if err := j.state.SetNumPending(j.db, pending); err != nil {
+		return nil, err
+	}
 	job, err := j.state.StackIndex(j.db, size-1)
 	if err != nil {
 		return nil, err
@@ -82,6 +115,10 @@ func (j *Jobs) HasNext() (bool, error) {
 	return size > 0, err
 }
 
+// NumPending returns the number of jobs that have been pushed but not popped,
+// including the jobs that are blocked on missing dependencies
+func (j *Jobs) NumPending() (uint32, error) { return j.state.NumPending(j.db) }
+
 // Execute ...
 func (j *Jobs) Execute(job Job) error {
 	if err := job.Execute(); err != nil {
@@ -370,3 +370,80 @@ func TestDuplicatedNotExecutablePush(t *testing.T) {
 		t.Fatalf("Shouldn't have a container ready to pop")
 	}
 }
+
+// Test that the number of pending jobs includes blocked jobs and persists
+// across restarts
+func TestNumPending(t *testing.T) {
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	jobs, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	id0 := ids.Empty.Prefix(0)
+	job0 := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return id0 },
+		MissingDependenciesF: func() (ids.Set, error) { return ids.Set{}, nil },
+		ExecuteF:             func() error { return nil },
+		BytesF:               func() []byte { return []byte{0} },
+	}
+
+	id1 := ids.Empty.Prefix(1)
+	job1 := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return id1 },
+		MissingDependenciesF: func() (ids.Set, error) { return ids.Set{id0.Key(): true}, nil },
+		ExecuteF:             func() error { return nil },
+		BytesF:               func() []byte { return []byte{1} },
+	}
+
+	if err := jobs.Push(job0); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Push(job1); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Push(job0); err == nil {
+		t.Fatalf("Shouldn't have been able to push a duplicated job")
+	}
+	if err := jobs.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	if numPending, err := jobs.NumPending(); err != nil {
+		t.Fatal(err)
+	} else if numPending != 2 {
+		t.Fatalf("Should have 2 pending jobs but have %d", numPending)
+	}
+
+	parser.ParseF = func(b []byte) (Job, error) {
+		if !bytes.Equal(b, []byte{0}) {
+			t.Fatalf("Unknown job")
+		}
+		return job0, nil
+	}
+
+	if _, err := jobs.Pop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if numPending, err := jobs.NumPending(); err != nil {
+		t.Fatal(err)
+	} else if numPending != 1 {
+		t.Fatalf("Should have 1 pending job but have %d", numPending)
+	}
+}
@@ -16,10 +16,12 @@ const (
 	stackID
 	jobID
 	blockingID
+	numPendingID
 )
 
 var (
-	stackSize = []byte{stackSizeID}
+	stackSize  = []byte{stackSizeID}
+	numPending = []byte{numPendingID}
 )
 
 type prefixedState struct{ state }
@@ -32,6 +34,14 @@ func (ps *prefixedState) StackSize(db database.Database) (uint32, error) {
 	return ps.state.Int(db, stackSize)
 }
 
+func (ps *prefixedState) SetNumPending(db database.Database, size uint32) error {
+	return ps.state.SetInt(db, numPending, size)
+}
+
+func (ps *prefixedState) NumPending(db database.Database) (uint32, error) {
+	return ps.state.Int(db, numPending)
+}
+
 func (ps *prefixedState) SetStackIndex(db database.Database, index uint32, job Job) error {
 	p := wrappers.Packer{Bytes: make([]byte, 1+wrappers.IntLen)}
 
@@ -0,0 +1,64 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// RejectionsHandlerID is the name an engine registers its Rejections under
+// with the consensus dispatcher of its chain
+const RejectionsHandlerID = "engine_rejections"
+
+// Rejections remembers which request the engine had last sent when each
+// container was rejected. This tells a vote for a container that was already
+// rejected when the query was sent apart from a vote that raced with the
+// rejection, which is a normal part of consensus.
+type Rejections struct {
+	// ID of the last request sent by the engine
+	requestID *uint32
+
+	// Requests sent before this one are no longer tracked
+	oldestRequestID uint32
+
+	// container ID --> [*requestID] when the container was rejected
+	rejected map[[32]byte]uint32
+}
+
+// Initialize the tracker. [requestID] points to the ID of the last request
+// sent by the engine.
+func (r *Rejections) Initialize(requestID *uint32) {
+	r.requestID = requestID
+	r.rejected = make(map[[32]byte]uint32)
+}
+
+// Reject implements the triggers.Rejector interface
+func (r *Rejections) Reject(_, containerID ids.ID, _ []byte) error {
+	r.rejected[containerID.Key()] = *r.requestID
+	return nil
+}
+
+// RejectedBefore returns true if [containerID], which is rejected, was already
+// rejected when the request [requestID] was sent. Returns false if the request
+// is older than the requests that are tracked.
+func (r *Rejections) RejectedBefore(containerID ids.ID, requestID uint32) bool {
+	if requestID < r.oldestRequestID {
+		return false
+	}
+	rejectedAt, exists := r.rejected[containerID.Key()]
+	// Containers that aren't tracked were rejected before any tracked request
+	// was sent
+	return !exists || rejectedAt < requestID
+}
+
+// Prune stops tracking the requests sent before [oldestRequestID], and forgets
+// the containers rejected before it was sent
+func (r *Rejections) Prune(oldestRequestID uint32) {
+	r.oldestRequestID = oldestRequestID
+	for key, rejectedAt := range r.rejected {
+		if rejectedAt < oldestRequestID {
+			delete(r.rejected, key)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestRejections(t *testing.T) {
+	requestID := uint32(0)
+	r := Rejections{}
+	r.Initialize(&requestID)
+
+	oldID := ids.GenerateTestID()
+	raceID := ids.GenerateTestID()
+
+	// [oldID] was rejected before any request was sent, so it isn't tracked.
+	// [raceID] is rejected after requests 1 and 2 were sent.
+	requestID = 2
+	assert.NoError(t, r.Reject(ids.Empty, raceID, nil))
+
+	assert.True(t, r.RejectedBefore(oldID, 1))
+	assert.True(t, r.RejectedBefore(raceID, 3))
+	assert.False(t, r.RejectedBefore(raceID, 2))
+	assert.False(t, r.RejectedBefore(raceID, 1))
+
+	// Once request 2 is no longer tracked, its response can't be told apart
+	r.Prune(3)
+	assert.False(t, r.RejectedBefore(raceID, 2))
+	assert.True(t, r.RejectedBefore(raceID, 3))
+	assert.Empty(t, r.rejected)
+}
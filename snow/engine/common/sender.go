@@ -14,6 +14,7 @@ type Sender interface {
 	AcceptedSender
 	FetchSender
 	QuerySender
+	StateSyncSender
 	Gossiper
 }
 
@@ -80,9 +81,33 @@ type QuerySender interface {
 	Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set)
 }
 
+// StateSyncSender defines how a consensus engine sends messages pertaining to
+// syncing the state of a chain to other validators
+type StateSyncSender interface {
+	// GetStateChunk requests that the validator with ID [validatorID] send
+	// chunk [index] of the state summarized by the summary with ID
+	// [summaryID].
+	GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32)
+
+	// StateChunk responds to a GetStateChunk message with the requested chunk
+	// of the state.
+	StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte)
+
+	// GetStateSummary requests that every validator in [validatorIDs] send a
+	// summary of the most recent state it can send chunks of.
+	GetStateSummary(validatorIDs ids.ShortSet, requestID uint32)
+
+	// StateSummary responds to a GetStateSummary message with a summary of
+	// the most recent state this node can send chunks of.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte)
+}
+
 // Gossiper defines how a consensus engine gossips a container on the accepted
-// frontier to other validators
+// frontier, or an unconfirmed transaction, to other validators
 type Gossiper interface {
 	// Gossip gossips the provided container throughout the network
 	Gossip(containerID ids.ID, container []byte)
+
+	// GossipTx gossips the provided unconfirmed transaction to the validators
+	GossipTx(txID ids.ID, tx []byte)
 }
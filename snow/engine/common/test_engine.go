@@ -42,7 +42,15 @@ type EngineTest struct {
 	CantPushQuery,
 	CantPullQuery,
 	CantQueryFailed,
-	CantChits bool
+	CantChits,
+
+	CantGetStateChunk,
+	CantStateChunk,
+	CantGetStateChunkFailed,
+
+	CantGetStateSummary,
+	CantStateSummary,
+	CantGetStateSummaryFailed bool
 
 	IsBootstrappedF                                    func() bool
 	ContextF                                           func() *snow.Context
@@ -53,7 +61,11 @@ type EngineTest struct {
 	MultiPutF                                          func(validatorID ids.ShortID, requestID uint32, containers [][]byte) error
 	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) error
 	GetAcceptedFrontierF, GetFailedF, GetAncestorsFailedF,
-	QueryFailedF, GetAcceptedFrontierFailedF, GetAcceptedFailedF func(validatorID ids.ShortID, requestID uint32) error
+	QueryFailedF, GetAcceptedFrontierFailedF, GetAcceptedFailedF,
+	GetStateChunkFailedF, GetStateSummaryF,
+	GetStateSummaryFailedF func(validatorID ids.ShortID, requestID uint32) error
+	GetStateChunkF             func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, index uint32) error
+	StateChunkF, StateSummaryF func(validatorID ids.ShortID, requestID uint32, chunk []byte) error
 }
 
 var _ Engine = &EngineTest{}
@@ -89,6 +101,13 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantPullQuery = cant
 	e.CantQueryFailed = cant
 	e.CantChits = cant
+
+	e.CantGetStateChunk = cant
+	e.CantStateChunk = cant
+	e.CantGetStateChunkFailed = cant
+	e.CantGetStateSummary = cant
+	e.CantStateSummary = cant
+	e.CantGetStateSummaryFailed = cant
 }
 
 // Context ...
@@ -383,6 +402,90 @@ func (e *EngineTest) Chits(validatorID ids.ShortID, requestID uint32, containerI
 	return errors.New("unexpectedly called Chits")
 }
 
+// GetStateChunk ...
+func (e *EngineTest) GetStateChunk(validatorID ids.ShortID, requestID uint32, containerID ids.ID, index uint32) error {
+	if e.GetStateChunkF != nil {
+		return e.GetStateChunkF(validatorID, requestID, containerID, index)
+	}
+	if !e.CantGetStateChunk {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateChunk")
+	}
+	return errors.New("unexpectedly called GetStateChunk")
+}
+
+// StateChunk ...
+func (e *EngineTest) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) error {
+	if e.StateChunkF != nil {
+		return e.StateChunkF(validatorID, requestID, chunk)
+	}
+	if !e.CantStateChunk {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateChunk")
+	}
+	return errors.New("unexpectedly called StateChunk")
+}
+
+// GetStateChunkFailed ...
+func (e *EngineTest) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateChunkFailedF != nil {
+		return e.GetStateChunkFailedF(validatorID, requestID)
+	}
+	if !e.CantGetStateChunkFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateChunkFailed")
+	}
+	return errors.New("unexpectedly called GetStateChunkFailed")
+}
+
+// GetStateSummary ...
+func (e *EngineTest) GetStateSummary(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryF != nil {
+		return e.GetStateSummaryF(validatorID, requestID)
+	}
+	if !e.CantGetStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+	return errors.New("unexpectedly called GetStateSummary")
+}
+
+// StateSummary ...
+func (e *EngineTest) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) error {
+	if e.StateSummaryF != nil {
+		return e.StateSummaryF(validatorID, requestID, summary)
+	}
+	if !e.CantStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateSummary")
+	}
+	return errors.New("unexpectedly called StateSummary")
+}
+
+// GetStateSummaryFailed ...
+func (e *EngineTest) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryFailedF != nil {
+		return e.GetStateSummaryFailedF(validatorID, requestID)
+	}
+	if !e.CantGetStateSummaryFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummaryFailed")
+	}
+	return errors.New("unexpectedly called GetStateSummaryFailed")
+}
+
 // IsBootstrapped ...
 func (e *EngineTest) IsBootstrapped() bool {
 	if e.IsBootstrappedF != nil {
@@ -17,7 +17,9 @@ type SenderTest struct {
 	CantGetAccepted, CantAccepted,
 	CantGet, CantGetAncestors, CantPut, CantMultiPut,
 	CantPullQuery, CantPushQuery, CantChits,
-	CantGossip bool
+	CantGetStateChunk, CantStateChunk,
+	CantGetStateSummary, CantStateSummary,
+	CantGossip, CantGossipTx bool
 
 	GetAcceptedFrontierF func(ids.ShortSet, uint32)
 	AcceptedFrontierF    func(ids.ShortID, uint32, ids.Set)
@@ -30,7 +32,12 @@ type SenderTest struct {
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
 	PullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	ChitsF               func(ids.ShortID, uint32, ids.Set)
+	GetStateChunkF       func(ids.ShortID, uint32, ids.ID, uint32)
+	StateChunkF          func(ids.ShortID, uint32, []byte)
+	GetStateSummaryF     func(ids.ShortSet, uint32)
+	StateSummaryF        func(ids.ShortID, uint32, []byte)
 	GossipF              func(ids.ID, []byte)
+	GossipTxF            func(ids.ID, []byte)
 }
 
 // Default set the default callable value to [cant]
@@ -46,7 +53,12 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantPullQuery = cant
 	s.CantPushQuery = cant
 	s.CantChits = cant
+	s.CantGetStateChunk = cant
+	s.CantStateChunk = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
 	s.CantGossip = cant
+	s.CantGossipTx = cant
 }
 
 // GetAcceptedFrontier calls GetAcceptedFrontierF if it was initialized. If it
@@ -180,3 +192,58 @@ func (s *SenderTest) Gossip(containerID ids.ID, container []byte) {
 		s.T.Fatalf("Unexpectedly called Gossip")
 	}
 }
+
+// GossipTx calls GossipTxF if it was initialized. If it wasn't initialized and
+// this function shouldn't be called and testing was initialized, then testing
+// will fail.
+func (s *SenderTest) GossipTx(txID ids.ID, tx []byte) {
+	if s.GossipTxF != nil {
+		s.GossipTxF(txID, tx)
+	} else if s.CantGossipTx && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GossipTx")
+	}
+}
+
+// GetStateChunk calls GetStateChunkF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetStateChunk(vdr ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	if s.GetStateChunkF != nil {
+		s.GetStateChunkF(vdr, requestID, summaryID, index)
+	} else if s.CantGetStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// StateChunk calls StateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) StateChunk(vdr ids.ShortID, requestID uint32, chunk []byte) {
+	if s.StateChunkF != nil {
+		s.StateChunkF(vdr, requestID, chunk)
+	} else if s.CantStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateChunk")
+	}
+}
+
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetStateSummary(vdrs ids.ShortSet, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(vdrs, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) StateSummary(vdr ids.ShortID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(vdr, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+// StateSyncableVM is a ChainVM whose state can be transferred to a new node,
+// so that the node doesn't need to execute every block since genesis while
+// bootstrapping.
+//
+// Once blocks at certain heights are accepted, the VM summarizes its state.
+// The summarized state is split into chunks, numbered from 0, that other
+// validators fetch one at a time.
+//
+// The summary is the root of the summarized state. It must commit to every
+// chunk, so that each chunk can be verified against the summary alone, and it
+// must only depend on the accepted blocks, so that every validator that
+// accepted the same blocks sends identical summary bytes. The ID of a summary
+// is the hash of its bytes.
+type StateSyncableVM interface {
+	ChainVM
+
+	// StateSummary returns the summary of the most recent state this VM can
+	// send chunks of.
+	//
+	// If the VM hasn't summarized its state yet, an error should be returned.
+	StateSummary() ([]byte, error)
+
+	// ParseStateSummary returns the ID and height of the block that [summary]
+	// is a summary of the state after.
+	ParseStateSummary(summary []byte) (ids.ID, uint64, error)
+
+	// GetStateChunk returns chunk [index] of the state summarized by the
+	// summary with ID [summaryID].
+	//
+	// If the VM doesn't have that state, for example because it has since
+	// summarized its state after a later block, an error should be returned.
+	GetStateChunk(summaryID ids.ID, index uint32) ([]byte, error)
+
+	// PutStateChunk adds chunk [index] of the state summarized by [summary],
+	// which was fetched from another validator. [blk] is the block that
+	// [summary] is a summary of the state after. Chunks are added in order, and
+	// chunk 0 starts a new sync, discarding the chunks of any earlier sync.
+	//
+	// The VM must verify the chunk against [summary]. If the chunk is invalid,
+	// an error should be returned, and the chunk is fetched again from a
+	// different validator.
+	//
+	// Returns true once every chunk has been added. At that point the VM's
+	// state must be its state once [blk] was accepted, and [blk] must be its
+	// last accepted block. Until then, the VM's state must be unchanged.
+	PutStateChunk(summary []byte, blk snowman.Block, index uint32, chunk []byte) (bool, error)
+}
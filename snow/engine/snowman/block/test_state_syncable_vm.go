@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+var (
+	errStateSummary      = errors.New("unexpectedly called StateSummary")
+	errParseStateSummary = errors.New("unexpectedly called ParseStateSummary")
+	errGetStateChunk     = errors.New("unexpectedly called GetStateChunk")
+	errPutStateChunk     = errors.New("unexpectedly called PutStateChunk")
+)
+
+// TestStateSyncableVM ...
+type TestStateSyncableVM struct {
+	TestVM
+
+	CantStateSummary,
+	CantParseStateSummary,
+	CantGetStateChunk,
+	CantPutStateChunk bool
+
+	StateSummaryF      func() ([]byte, error)
+	ParseStateSummaryF func([]byte) (ids.ID, uint64, error)
+	GetStateChunkF     func(ids.ID, uint32) ([]byte, error)
+	PutStateChunkF     func([]byte, snowman.Block, uint32, []byte) (bool, error)
+}
+
+// Default ...
+func (vm *TestStateSyncableVM) Default(cant bool) {
+	vm.TestVM.Default(cant)
+
+	vm.CantStateSummary = cant
+	vm.CantParseStateSummary = cant
+	vm.CantGetStateChunk = cant
+	vm.CantPutStateChunk = cant
+}
+
+// StateSummary ...
+func (vm *TestStateSyncableVM) StateSummary() ([]byte, error) {
+	if vm.StateSummaryF != nil {
+		return vm.StateSummaryF()
+	}
+	if vm.CantStateSummary && vm.T != nil {
+		vm.T.Fatal(errStateSummary)
+	}
+	return nil, errStateSummary
+}
+
+// ParseStateSummary ...
+func (vm *TestStateSyncableVM) ParseStateSummary(summary []byte) (ids.ID, uint64, error) {
+	if vm.ParseStateSummaryF != nil {
+		return vm.ParseStateSummaryF(summary)
+	}
+	if vm.CantParseStateSummary && vm.T != nil {
+		vm.T.Fatal(errParseStateSummary)
+	}
+	return ids.ID{}, 0, errParseStateSummary
+}
+
+// GetStateChunk ...
+func (vm *TestStateSyncableVM) GetStateChunk(summaryID ids.ID, index uint32) ([]byte, error) {
+	if vm.GetStateChunkF != nil {
+		return vm.GetStateChunkF(summaryID, index)
+	}
+	if vm.CantGetStateChunk && vm.T != nil {
+		vm.T.Fatal(errGetStateChunk)
+	}
+	return nil, errGetStateChunk
+}
+
+// PutStateChunk ...
+func (vm *TestStateSyncableVM) PutStateChunk(summary []byte, blk snowman.Block, index uint32, chunk []byte) (bool, error) {
+	if vm.PutStateChunkF != nil {
+		return vm.PutStateChunkF(summary, blk, index, chunk)
+	}
+	if vm.CantPutStateChunk && vm.T != nil {
+		vm.T.Fatal(errPutStateChunk)
+	}
+	return false, errPutStateChunk
+}
@@ -0,0 +1,24 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// TxGossiper is an optional interface a ChainVM can implement to have its
+// unconfirmed transactions gossiped to the validators of the network.
+//
+// When the VM has new transactions that should be gossiped, it should send a
+// common.GossipTxs message to the consensus engine. The engine will then call
+// PendingGossipTxs and gossip each of the returned transactions.
+type TxGossiper interface {
+	// PendingGossipTxs returns, and clears, the transactions that should be
+	// gossiped to the network
+	PendingGossipTxs() [][]byte
+
+	// GossipedTx is called when the validator [validatorID] gossiped [tx] to
+	// this node. The VM may add the transaction to its mempool.
+	GossipedTx(validatorID ids.ShortID, tx []byte) error
+}
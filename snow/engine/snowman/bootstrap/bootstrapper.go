@@ -27,6 +27,9 @@ type Config struct {
 	VM block.ChainVM
 
 	Bootstrapped func()
+
+	// Configures syncing the state of the VM to a checkpoint
+	StateSync StateSyncConfig
 }
 
 // Bootstrapper ...
@@ -44,6 +47,25 @@ type Bootstrapper struct {
 
 	// true if all of the vertices in the original accepted frontier have been processed
 	processedStartingAcceptedFrontier bool
+
+	// Blocks that need to be fetched, but haven't been requested yet
+	needToFetch ids.Set
+
+	// Height of the last accepted block when fetching started, and the height
+	// of the highest block fetched. Only used to estimate the number of blocks
+	// to fetch.
+	startingHeight, tipHeight uint64
+
+	// Configures syncing the state of the VM to a checkpoint
+	StateSync StateSyncConfig
+
+	// Non-nil while the state of the VM is being synced to a checkpoint
+	stateSync *stateSync
+}
+
+// heightBlock is a block that reports its height
+type heightBlock interface {
+	Height() uint64
 }
 
 // Initialize this engine.
@@ -56,11 +78,17 @@ func (b *Bootstrapper) Initialize(
 	b.Blocked = config.Blocked
 	b.VM = config.VM
 	b.Bootstrapped = config.Bootstrapped
+	b.StateSync = config.StateSync
 	b.OnFinished = onFinished
 
 	if err := b.metrics.Initialize(namespace, registerer); err != nil {
 		return err
 	}
+	if err := b.Progress.Initialize(namespace, registerer); err != nil {
+		return err
+	}
+
+	b.Scheduler.Initialize(config.Fetch, config.Beacons)
 
 	b.Blocked.SetParser(&parser{
 		log:         config.Ctx.Log,
@@ -99,48 +127,88 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) error {
 			err)
 	}
 
+	if syncing, err := b.startStateSync(acceptedContainerIDs); err != nil || syncing {
+		return err
+	}
+	return b.fetchAccepted(acceptedContainerIDs)
+}
+
+// fetchAccepted fetches the blocks in [acceptedContainerIDs], and their
+// ancestors that haven't been accepted
+func (b *Bootstrapper) fetchAccepted(acceptedContainerIDs ids.Set) error {
+	b.Progress.SetPhase(common.PhaseFetching)
+	if lastAccepted, err := b.VM.GetBlock(b.VM.LastAccepted()); err == nil {
+		if blk, ok := lastAccepted.(heightBlock); ok {
+			b.startingHeight = blk.Height()
+			b.tipHeight = b.startingHeight
+		}
+	}
+
 	for _, blkID := range acceptedContainerIDs.List() {
 		if blk, err := b.VM.GetBlock(blkID); err == nil {
 			if err := b.process(blk); err != nil {
 				return err
 			}
-		} else if err := b.fetch(blkID); err != nil {
-			return err
+		} else {
+			b.needToFetch.Add(blkID)
 		}
 	}
 
 	b.processedStartingAcceptedFrontier = true
-	if numPending := b.OutstandingRequests.Len(); numPending == 0 {
-		return b.finish()
-	}
-	return nil
+	return b.fetch()
 }
 
-// Get block [blkID] and its ancestors from a validator
-func (b *Bootstrapper) fetch(blkID ids.ID) error {
-	// Make sure we haven't already requested this block
-	if b.OutstandingRequests.Contains(blkID) {
-		return nil
+// Add the blocks in [blkIDs] to the set of blocks that we need to fetch, and
+// then fetch blocks (and their ancestors) until either there are no more to
+// fetch or we are at the maximum number of outstanding requests. While there
+// are spare requests, the blocks that are being fetched are also requested
+// from other validators, so that a single slow validator doesn't stall
+// fetching the chain.
+func (b *Bootstrapper) fetch(blkIDs ...ids.ID) error {
+	b.needToFetch.Add(blkIDs...)
+	for b.needToFetch.Len() > 0 && b.Scheduler.CanSend() {
+		blkID := b.needToFetch.CappedList(1)[0]
+		b.needToFetch.Remove(blkID)
+
+		// Make sure we haven't already requested this block
+		if b.Scheduler.Contains(blkID) {
+			continue
+		}
+
+		// Make sure we don't already have this block
+		if _, err := b.VM.GetBlock(blkID); err == nil {
+			continue
+		}
+
+		validatorID, err := b.Scheduler.Sample(blkID) // validator to send request to
+		if err != nil {
+			return fmt.Errorf("dropping request for %s as there are no validators", blkID)
+		}
+		b.sendGetAncestors(validatorID, blkID)
 	}
 
-	// Make sure we don't already have this block
-	if _, err := b.VM.GetBlock(blkID); err == nil {
-		if numPending := b.OutstandingRequests.Len(); numPending == 0 && b.processedStartingAcceptedFrontier {
-			return b.finish()
+	for _, blkID := range b.Scheduler.Outstanding() {
+		for b.Scheduler.CanSend() && b.Scheduler.CanRequestAgain(blkID) {
+			validatorID, err := b.Scheduler.Sample(blkID)
+			if err != nil {
+				// Every validator is already being asked for this block
+				break
+			}
+			b.sendGetAncestors(validatorID, blkID)
 		}
-		return nil
 	}
 
-	validators, err := b.Beacons.Sample(1) // validator to send request to
-	if err != nil {
-		return fmt.Errorf("dropping request for %s as there are no validators", blkID)
+	if b.Scheduler.Len() == 0 && b.needToFetch.Len() == 0 && b.processedStartingAcceptedFrontier {
+		return b.finish()
 	}
-	validatorID := validators[0].ID()
-	b.RequestID++
+	return nil
+}
 
-	b.OutstandingRequests.Add(validatorID, b.RequestID, blkID)
+// Request block [blkID] and its ancestors from [validatorID]
+func (b *Bootstrapper) sendGetAncestors(validatorID ids.ShortID, blkID ids.ID) {
+	b.IncrementRequestID()
+	b.Scheduler.Sent(validatorID, b.RequestID, blkID)
 	b.Sender.GetAncestors(validatorID, b.RequestID, blkID) // request block and ancestors
-	return nil
 }
 
 // MultiPut handles the receipt of multiple containers. Should be received in response to a GetAncestors message to [vdr]
@@ -155,8 +223,17 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte
 		return b.GetAncestorsFailed(vdr, requestID)
 	}
 
+	if b.stateSync != nil {
+		if b.isCheckpointRequest(vdr, requestID) {
+			return b.checkpointReceived(vdr, requestID, blks[0])
+		}
+		b.Ctx.Log.Debug("received unexpected MultiPut from %s with ID %d while syncing state",
+			vdr, requestID)
+		return nil
+	}
+
 	// Make sure this is in response to a request we made
-	wantedBlkID, ok := b.OutstandingRequests.Remove(vdr, requestID)
+	wantedBlkID, ok := b.Scheduler.Requested(vdr, requestID)
 	if !ok { // this message isn't in response to a request we made
 		b.Ctx.Log.Debug("received unexpected MultiPut from %s with ID %d",
 			vdr, requestID)
@@ -166,32 +243,48 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte
 	wantedBlk, err := b.VM.ParseBlock(blks[0]) // the block we requested
 	if err != nil {
 		b.Ctx.Log.Debug("Failed to parse requested block %s: %s", wantedBlkID, err)
+		b.Misbehavior.Record(vdr, common.InvalidContainer)
+		b.Scheduler.Failed(vdr, requestID)
 		return b.fetch(wantedBlkID)
 	} else if actualID := wantedBlk.ID(); !actualID.Equals(wantedBlkID) {
 		b.Ctx.Log.Debug("expected the first block to be the requested block, %s, but is %s",
 			wantedBlk, actualID)
+		b.Scheduler.Failed(vdr, requestID)
 		return b.fetch(wantedBlkID)
 	}
 
+	numBytes := 0
 	for _, blkBytes := range blks {
+		numBytes += len(blkBytes)
 		if _, err := b.VM.ParseBlock(blkBytes); err != nil { // persists the block
 			b.Ctx.Log.Debug("Failed to parse block: %s", err)
 			b.Ctx.Log.Verbo("block: %s", formatting.DumpBytes{Bytes: blkBytes})
 		}
 	}
+	// The requests for this block sent to other validators are abandoned
+	b.Scheduler.Received(vdr, requestID, numBytes)
 
 	return b.process(wantedBlk)
 }
 
 // GetAncestorsFailed is called when a GetAncestors message we sent fails
 func (b *Bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) error {
-	blkID, ok := b.OutstandingRequests.Remove(vdr, requestID)
+	if b.stateSync != nil {
+		if b.isCheckpointRequest(vdr, requestID) {
+			return b.checkpointFailed(vdr, requestID)
+		}
+		b.Ctx.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID",
+			vdr, requestID)
+		return nil
+	}
+
+	blkID, ok := b.Scheduler.Failed(vdr, requestID)
 	if !ok {
 		b.Ctx.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID",
 			vdr, requestID)
 		return nil
 	}
-	// Send another request for this
+	// Send another request for this, which prefers a different validator
 	return b.fetch(blkID)
 }
 
@@ -200,6 +293,12 @@ func (b *Bootstrapper) process(blk snowman.Block) error {
 	status := blk.Status()
 	blkID := blk.ID()
 	for status == choices.Processing {
+		// A block at or below the last accepted block isn't an ancestor of a
+		// block that will be accepted. This happens after the state was synced
+		// past the accepted frontier.
+		if hBlk, ok := blk.(heightBlock); ok && hBlk.Height() <= b.startingHeight {
+			break
+		}
 		if err := b.Blocked.Push(&blockJob{
 			numAccepted: b.numAccepted,
 			numDropped:  b.numDropped,
@@ -210,11 +309,23 @@ func (b *Bootstrapper) process(blk snowman.Block) error {
 			if b.NumFetched%common.StatusUpdateFrequency == 0 { // Periodically print progress
 				b.Ctx.Log.Info("fetched %d blocks", b.NumFetched)
 			}
+			b.Progress.AddFetched(1)
+			if hBlk, ok := blk.(heightBlock); ok {
+				// All the blocks between the last accepted block and the
+				// highest block we've seen will be fetched
+				if height := hBlk.Height(); height > b.tipHeight {
+					b.tipHeight = height
+					b.Progress.SetEstimatedTotal(height - b.startingHeight)
+				}
+			}
 		}
 
 		if err := b.Blocked.Commit(); err != nil {
 			return err
 		}
+		if err := b.updateJobsRemaining(); err != nil {
+			return err
+		}
 
 		// Process this block's parent
 		blk = blk.Parent()
@@ -224,17 +335,20 @@ func (b *Bootstrapper) process(blk snowman.Block) error {
 
 	switch status := blk.Status(); status {
 	case choices.Unknown:
-		if err := b.fetch(blkID); err != nil {
-			return err
-		}
+		b.needToFetch.Add(blkID)
 	case choices.Rejected: // Should never happen
 		return fmt.Errorf("bootstrapping wants to accept %s, however it was previously rejected", blkID)
 	}
 
-	if numPending := b.OutstandingRequests.Len(); numPending == 0 && b.processedStartingAcceptedFrontier {
-		return b.finish()
+	// Blocks whose ancestors have all been accepted don't need to wait for the
+	// rest of the blocks to be fetched
+	if b.Scheduler.ExecuteWhileFetching && !b.IsBootstrapped() {
+		if _, err := b.execute(b.Blocked); err != nil {
+			return err
+		}
 	}
-	return nil
+
+	return b.fetch()
 }
 
 func (b *Bootstrapper) finish() error {
@@ -243,6 +357,7 @@ func (b *Bootstrapper) finish() error {
 	}
 	b.Ctx.Log.Info("bootstrapping fetched %d blocks. executing state transitions...",
 		b.NumFetched)
+	b.Progress.SetPhase(common.PhaseExecuting)
 
 	if err := b.executeAll(b.Blocked); err != nil {
 		return err
@@ -258,6 +373,7 @@ func (b *Bootstrapper) finish() error {
 		return err
 	}
 	b.Ctx.Bootstrapped()
+	b.Progress.SetPhase(common.PhaseFinished)
 
 	if b.Bootstrapped != nil {
 		b.Bootstrapped()
@@ -266,22 +382,46 @@ func (b *Bootstrapper) finish() error {
 }
 
 func (b *Bootstrapper) executeAll(jobs *queue.Jobs) error {
+	numExecuted, err := b.execute(jobs)
+	if err != nil {
+		return err
+	}
+	b.Ctx.Log.Info("executed %d blocks", numExecuted)
+	return nil
+}
+
+// execute the blocks in [jobs] that are ready to be executed. Returns the
+// number of blocks executed.
+func (b *Bootstrapper) execute(jobs *queue.Jobs) (int, error) {
 	numExecuted := 0
 	for job, err := jobs.Pop(); err == nil; job, err = jobs.Pop() {
 		if err := jobs.Execute(job); err != nil {
-			return err
+			return numExecuted, err
 		}
 		if err := jobs.Commit(); err != nil {
-			return err
+			return numExecuted, err
 		}
 		numExecuted++
 		if numExecuted%common.StatusUpdateFrequency == 0 { // Periodically print progress
 			b.Ctx.Log.Info("executed %d blocks", numExecuted)
 		}
+		b.Progress.AddExecuted(1)
+		if err := b.updateJobsRemaining(); err != nil {
+			return numExecuted, err
+		}
 
 		b.Ctx.ConsensusDispatcher.Accept(b.Ctx.ChainID, job.ID(), job.Bytes())
 		b.Ctx.DecisionDispatcher.Accept(b.Ctx.ChainID, job.ID(), job.Bytes())
 	}
-	b.Ctx.Log.Info("executed %d blocks", numExecuted)
+	return numExecuted, nil
+}
+
+// updateJobsRemaining reports the number of blocks waiting to be executed
+func (b *Bootstrapper) updateJobsRemaining() error {
+	numPending, err := b.Blocked.NumPending()
+	if err != nil {
+		return err
+	}
+	b.Progress.SetJobsRemaining(uint64(numPending))
 	return nil
 }
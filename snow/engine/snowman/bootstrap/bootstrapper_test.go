@@ -21,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
 var (
@@ -128,6 +129,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	}
 
 	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
 	vm.CantBootstrapped = false
 
 	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should finish
@@ -241,6 +243,7 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 		*requestID = reqID
 	}
 	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
 
 	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request blk1
 		t.Fatal(err)
@@ -280,6 +283,223 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	}
 }
 
+// A block that a peer failed to return is requested from a different peer
+func TestBootstrapperRetriesOnDifferentPeer(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	otherPeerID := ids.GenerateTestShortID()
+	beacons := validators.NewSet()
+	beacons.AddWeight(peerID, 1)
+	beacons.AddWeight(otherPeerID, 1)
+	config.Beacons = beacons
+	config.Fetch.MaxRequestsPerContainer = 1
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blkBytes1 := []byte{1}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Unknown,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+		BytesV:  blkBytes1,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID1)
+
+	parsedBlk1 := false
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID0):
+			return blk0, nil
+		case blkID.Equals(blkID1):
+			if parsedBlk1 {
+				return blk1, nil
+			}
+			return nil, errUnknownBlock
+		default:
+			t.Fatal(errUnknownBlock)
+			panic(errUnknownBlock)
+		}
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		if !bytes.Equal(blkBytes, blkBytes1) {
+			t.Fatal(errUnknownBlock)
+		}
+		blk1.StatusV = choices.Processing
+		parsedBlk1 = true
+		return blk1, nil
+	}
+
+	requestID := new(uint32)
+	requestedFrom := new(ids.ShortID)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		if !blkID.Equals(blkID1) {
+			t.Fatalf("should have requested blk1")
+		}
+		*requestID = reqID
+		*requestedFrom = vdr
+	}
+	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request blk1
+		t.Fatal(err)
+	}
+
+	failedPeerID := *requestedFrom
+	if err := bs.GetAncestorsFailed(failedPeerID, *requestID); err != nil {
+		t.Fatal(err)
+	} else if requestedFrom.Equals(failedPeerID) {
+		t.Fatalf("Should have requested the block from a different peer than %s", failedPeerID)
+	}
+
+	vm.CantBootstrapped = false
+
+	if err := bs.MultiPut(*requestedFrom, *requestID, [][]byte{blkBytes1}); err != nil {
+		t.Fatal(err)
+	} else if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	} else if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+}
+
+// A needed block is requested from several peers at once, and the first
+// response is used
+func TestBootstrapperFetchesFromSeveralPeers(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	otherPeerID := ids.GenerateTestShortID()
+	beacons := validators.NewSet()
+	beacons.AddWeight(peerID, 1)
+	beacons.AddWeight(otherPeerID, 1)
+	config.Beacons = beacons
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blkBytes1 := []byte{1}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Unknown,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+		BytesV:  blkBytes1,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID1)
+
+	parsedBlk1 := false
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID0):
+			return blk0, nil
+		case blkID.Equals(blkID1):
+			if parsedBlk1 {
+				return blk1, nil
+			}
+			return nil, errUnknownBlock
+		default:
+			t.Fatal(errUnknownBlock)
+			panic(errUnknownBlock)
+		}
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		if !bytes.Equal(blkBytes, blkBytes1) {
+			t.Fatal(errUnknownBlock)
+		}
+		blk1.StatusV = choices.Processing
+		parsedBlk1 = true
+		return blk1, nil
+	}
+
+	// Key: Peer ID
+	// Value: ID of the request sent to the peer
+	requests := make(map[[20]byte]uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		if !blkID.Equals(blkID1) {
+			t.Fatalf("should have requested blk1")
+		}
+		if _, ok := requests[vdr.Key()]; ok {
+			t.Fatalf("should have requested blk1 from %s once", vdr)
+		}
+		requests[vdr.Key()] = reqID
+	}
+	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request blk1 from both peers
+		t.Fatal(err)
+	} else if len(requests) != 2 {
+		t.Fatalf("should have requested blk1 from 2 peers but requested it from %d", len(requests))
+	}
+
+	vm.CantBootstrapped = false
+
+	if err := bs.MultiPut(otherPeerID, requests[otherPeerID.Key()], [][]byte{blkBytes1}); err != nil {
+		t.Fatal(err)
+	} else if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	} else if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+
+	// The request to the other peer was abandoned, so its response is ignored
+	if err := bs.MultiPut(peerID, requests[peerID.Key()], [][]byte{blkBytes1}); err != nil {
+		t.Fatal(err)
+	} else if bs.Scheduler.Len() != 0 {
+		t.Fatalf("should have no outstanding requests but has %d", bs.Scheduler.Len())
+	}
+}
+
 // There are multiple needed blocks and MultiPut returns one at a time
 func TestBootstrapperPartialFetch(t *testing.T) {
 	config, peerID, sender, vm := newConfig(t)
@@ -403,6 +623,7 @@ func TestBootstrapperPartialFetch(t *testing.T) {
 	}
 
 	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
 
 	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request blk2
 		t.Fatal(err)
@@ -484,6 +705,7 @@ func TestBootstrapperMultiPut(t *testing.T) {
 	}
 
 	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
 
 	finished := new(bool)
 	bs := Bootstrapper{}
@@ -561,6 +783,15 @@ func TestBootstrapperMultiPut(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// blk3 was fetched, and the blocks down to the last accepted block will be
+	if status := bs.BootstrapStatus(); status.NumFetched != 1 {
+		t.Fatalf("Should have fetched 1 block but fetched %d", status.NumFetched)
+	} else if status.EstimatedTotal != 3 {
+		t.Fatalf("Should have estimated 3 blocks but estimated %d", status.EstimatedTotal)
+	} else if status.JobsRemaining != 1 {
+		t.Fatalf("Should have 1 job remaining but have %d", status.JobsRemaining)
+	}
+
 	vm.CantBootstrapped = false
 
 	if err := bs.MultiPut(peerID, *requestID, [][]byte{blkBytes2, blkBytes1}); err != nil { // respond with blk2 and blk1
@@ -578,6 +809,14 @@ func TestBootstrapperMultiPut(t *testing.T) {
 	} else if blk2.Status() != choices.Accepted {
 		t.Fatalf("Block should be accepted")
 	}
+
+	if status := bs.BootstrapStatus(); status.Phase != common.PhaseFinished {
+		t.Fatalf("Bootstrapping should be %s but is %s", common.PhaseFinished, status.Phase)
+	} else if status.NumFetched != 3 {
+		t.Fatalf("Should have fetched 3 blocks but fetched %d", status.NumFetched)
+	} else if status.JobsRemaining != 0 {
+		t.Fatalf("Shouldn't have jobs remaining but have %d", status.JobsRemaining)
+	}
 }
 
 func TestBootstrapperAcceptedFrontier(t *testing.T) {
@@ -773,6 +1012,7 @@ func TestBootstrapperFinalized(t *testing.T) {
 	}
 
 	vm.CantBootstrapping = false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
 
 	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request blk0 and blk1
 		t.Fatal(err)
@@ -808,3 +1048,506 @@ func TestBootstrapperFinalized(t *testing.T) {
 		t.Fatalf("Block should be accepted")
 	}
 }
+
+// The state is synced to the accepted frontier, so no blocks need to be
+// executed
+func TestBootstrapperStateSync(t *testing.T) {
+	config, peerID, sender, _ := newConfig(t)
+
+	vm := &block.TestStateSyncableVM{}
+	vm.T = t
+	vm.Default(true)
+	config.VM = vm
+	config.StateSync = StateSyncConfig{Enabled: true}
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+
+	blkBytes2 := []byte{2}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Unknown,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+	}
+	blk2 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID2,
+			StatusV: choices.Unknown,
+		},
+		ParentV: blk1,
+		HeightV: 2,
+		BytesV:  blkBytes2,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID2)
+
+	lastAcceptedID := blkID0
+	parsedBlk2 := false
+	vm.LastAcceptedF = func() ids.ID { return lastAcceptedID }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID0):
+			return blk0, nil
+		case blkID.Equals(blkID2) && parsedBlk2:
+			return blk2, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		if bytes.Equal(blkBytes, blkBytes2) {
+			blk2.StatusV = choices.Processing
+			parsedBlk2 = true
+			return blk2, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	summary := []byte{2, 2}
+	vm.ParseStateSummaryF = func(summaryBytes []byte) (ids.ID, uint64, error) {
+		if !bytes.Equal(summaryBytes, summary) {
+			t.Fatalf("Wrong summary")
+		}
+		return blkID2, 2, nil
+	}
+	chunks := [][]byte{{0}, {1}}
+	vm.PutStateChunkF = func(summaryBytes []byte, blk snowman.Block, index uint32, chunk []byte) (bool, error) {
+		if !bytes.Equal(summaryBytes, summary) {
+			t.Fatalf("Should have verified the chunk against the summary")
+		}
+		if !blk.ID().Equals(blkID2) {
+			t.Fatalf("Should have synced the state to %s, synced to %s", blkID2, blk.ID())
+		}
+		if !bytes.Equal(chunk, chunks[index]) {
+			t.Fatalf("Wrong chunk %d", index)
+		}
+		if int(index) < len(chunks)-1 {
+			return false, nil
+		}
+		blk2.StatusV = choices.Accepted
+		lastAcceptedID = blkID2
+		return true, nil
+	}
+
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, reqID uint32) {
+		if vdrs.Len() != 1 || !vdrs.Contains(peerID) {
+			t.Fatalf("Should have requested the state summary from %s, requested from %s", peerID, vdrs)
+		}
+		*summaryRequestID = reqID
+	}
+	blkRequestID := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		if !vdr.Equals(peerID) {
+			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
+		}
+		if !blkID.Equals(blkID2) {
+			t.Fatalf("Should have requested blk2, requested %s", blkID)
+		}
+		*blkRequestID = reqID
+	}
+	chunkIndices := []uint32(nil)
+	chunkRequestID := new(uint32)
+	summaryID := ids.NewID(hashing.ComputeHash256Array(summary))
+	sender.GetStateChunkF = func(vdr ids.ShortID, reqID uint32, reqSummaryID ids.ID, index uint32) {
+		if !vdr.Equals(peerID) {
+			t.Fatalf("Should have requested chunk from %s, requested from %s", peerID, vdr)
+		}
+		if !reqSummaryID.Equals(summaryID) {
+			t.Fatalf("Should have requested the state summarized by %s, requested the state summarized by %s", summaryID, reqSummaryID)
+		}
+		chunkIndices = append(chunkIndices, index)
+		*chunkRequestID = reqID
+	}
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request the state summary
+		t.Fatal(err)
+	}
+
+	if err := bs.StateSummary(peerID, *summaryRequestID, summary); err != nil { // should request blk2
+		t.Fatal(err)
+	}
+
+	if err := bs.MultiPut(peerID, *blkRequestID, [][]byte{blkBytes2}); err != nil { // should request chunk 0
+		t.Fatal(err)
+	}
+
+	if err := bs.StateChunk(peerID, *chunkRequestID, chunks[0]); err != nil { // should request chunk 1
+		t.Fatal(err)
+	}
+
+	vm.CantBootstrapped = false
+
+	if err := bs.StateChunk(peerID, *chunkRequestID, chunks[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(chunkIndices) != 2 || chunkIndices[0] != 0 || chunkIndices[1] != 1 {
+		t.Fatalf("Should have requested chunks 0 and 1, requested %v", chunkIndices)
+	}
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	} else if blk1.Status() != choices.Unknown {
+		t.Fatalf("Block should not have been fetched")
+	} else if blk2.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+}
+
+// If a beacon fails to send a chunk, the chunk is fetched again from another
+// beacon that sent the same summary
+func TestBootstrapperStateSyncRetry(t *testing.T) {
+	config, peerID0, sender, _ := newConfig(t)
+
+	peerID1 := ids.GenerateTestShortID()
+	config.Beacons.AddWeight(peerID1, 1)
+	config.Alpha = 2
+
+	vm := &block.TestStateSyncableVM{}
+	vm.T = t
+	vm.Default(true)
+	config.VM = vm
+	config.StateSync = StateSyncConfig{Enabled: true}
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID1)
+
+	lastAcceptedID := blkID0
+	vm.LastAcceptedF = func() ids.ID { return lastAcceptedID }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID0):
+			return blk0, nil
+		case blkID.Equals(blkID1):
+			return blk1, nil
+		}
+		t.Fatal(errUnknownBlock)
+		panic(errUnknownBlock)
+	}
+
+	summary := []byte{1}
+	chunks := [][]byte{{0}, {1}}
+	vm.ParseStateSummaryF = func([]byte) (ids.ID, uint64, error) { return blkID1, 1, nil }
+	vm.PutStateChunkF = func(summaryBytes []byte, blk snowman.Block, index uint32, chunk []byte) (bool, error) {
+		if !bytes.Equal(summaryBytes, summary) || !bytes.Equal(chunk, chunks[index]) {
+			t.Fatalf("Should have verified chunk %d against the summary", index)
+		}
+		if int(index) < len(chunks)-1 {
+			return false, nil
+		}
+		blk1.StatusV = choices.Accepted
+		lastAcceptedID = blkID1
+		return true, nil
+	}
+
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, reqID uint32) {
+		if vdrs.Len() != 2 {
+			t.Fatalf("Should have requested the state summary from both beacons")
+		}
+		*summaryRequestID = reqID
+	}
+	summaryID := ids.NewID(hashing.ComputeHash256Array(summary))
+	chunkVdrs := []ids.ShortID(nil)
+	chunkIndices := []uint32(nil)
+	chunkRequestID := new(uint32)
+	sender.GetStateChunkF = func(vdr ids.ShortID, reqID uint32, reqSummaryID ids.ID, index uint32) {
+		if !reqSummaryID.Equals(summaryID) {
+			t.Fatalf("Should have requested the state summarized by %s", summaryID)
+		}
+		chunkVdrs = append(chunkVdrs, vdr)
+		chunkIndices = append(chunkIndices, index)
+		*chunkRequestID = reqID
+	}
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request the state summaries
+		t.Fatal(err)
+	}
+
+	if err := bs.StateSummary(peerID0, *summaryRequestID, summary); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.StateSummary(peerID1, *summaryRequestID, summary); err != nil { // should request chunk 0
+		t.Fatal(err)
+	}
+
+	if len(chunkVdrs) != 1 {
+		t.Fatalf("Should have requested chunk 0")
+	}
+	if err := bs.StateChunk(chunkVdrs[0], *chunkRequestID, chunks[0]); err != nil { // should request chunk 1
+		t.Fatal(err)
+	}
+	if err := bs.GetStateChunkFailed(chunkVdrs[1], *chunkRequestID); err != nil { // should request chunk 1 from the other beacon
+		t.Fatal(err)
+	}
+
+	if len(chunkVdrs) != 3 || !chunkVdrs[0].Equals(chunkVdrs[1]) || chunkVdrs[1].Equals(chunkVdrs[2]) {
+		t.Fatalf("Should have requested chunk 1 from the other beacon")
+	}
+	if chunkIndices[0] != 0 || chunkIndices[1] != 1 || chunkIndices[2] != 1 {
+		t.Fatalf("Should have requested chunks 0, 1 and 1 again, requested %v", chunkIndices)
+	}
+
+	vm.CantBootstrapped = false
+
+	if err := bs.StateChunk(chunkVdrs[2], *chunkRequestID, chunks[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	} else if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+}
+
+// If the beacons send different summaries of the state after the same block,
+// and not enough of them agree on one summary, the state isn't synced
+func TestBootstrapperStateSyncConflictingSummaries(t *testing.T) {
+	config, peerID0, sender, _ := newConfig(t)
+
+	peerID1 := ids.GenerateTestShortID()
+	config.Beacons.AddWeight(peerID1, 1)
+	config.Alpha = 2
+
+	vm := &block.TestStateSyncableVM{}
+	vm.T = t
+	vm.Default(true)
+	config.VM = vm
+	config.StateSync = StateSyncConfig{Enabled: true}
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blkBytes1 := []byte{1}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+		BytesV:  blkBytes1,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID1)
+
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID0):
+			return blk0, nil
+		case blkID.Equals(blkID1):
+			return blk1, nil
+		}
+		t.Fatal(errUnknownBlock)
+		panic(errUnknownBlock)
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		if bytes.Equal(blkBytes, blkBytes1) {
+			return blk1, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+	vm.ParseStateSummaryF = func([]byte) (ids.ID, uint64, error) { return blkID1, 1, nil }
+
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, reqID uint32) {
+		*summaryRequestID = reqID
+	}
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request the state summaries
+		t.Fatal(err)
+	}
+
+	vm.CantBootstrapped = false
+
+	// The beacons summarize the state after blk1 differently
+	if err := bs.StateSummary(peerID0, *summaryRequestID, []byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.StateSummary(peerID1, *summaryRequestID, []byte{1}); err != nil { // should execute blk1
+		t.Fatal(err)
+	}
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	} else if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+}
+
+// If not enough beacons send a summary of the state after the same block, the
+// blocks are fetched and executed as usual
+func TestBootstrapperStateSyncNoSummary(t *testing.T) {
+	config, peerID, sender, _ := newConfig(t)
+
+	vm := &block.TestStateSyncableVM{}
+	vm.T = t
+	vm.Default(true)
+	config.VM = vm
+	config.StateSync = StateSyncConfig{Enabled: true}
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blkBytes1 := []byte{1}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+		BytesV:  blkBytes1,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID1)
+
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID0):
+			return blk0, nil
+		case blkID.Equals(blkID1):
+			return blk1, nil
+		}
+		t.Fatal(errUnknownBlock)
+		panic(errUnknownBlock)
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		if bytes.Equal(blkBytes, blkBytes1) {
+			return blk1, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, reqID uint32) {
+		*summaryRequestID = reqID
+	}
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request the state summary
+		t.Fatal(err)
+	}
+
+	vm.CantBootstrapped = false
+
+	if err := bs.GetStateSummaryFailed(peerID, *summaryRequestID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	} else if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+}
@@ -0,0 +1,358 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	stdmath "math"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Checkpoint is an accepted block that the state of a VM can be synced to
+type Checkpoint struct {
+	BlockID ids.ID
+	Height  uint64
+}
+
+// StateSyncConfig configures syncing the state of the VM to a recent accepted
+// block, the checkpoint, rather than executing every block since genesis. The
+// blocks after the checkpoint are then fetched and executed as usual.
+type StateSyncConfig struct {
+	// If true, and the VM implements block.StateSyncableVM, the state of the VM
+	// is synced to the checkpoint while bootstrapping
+	Enabled bool
+
+	// The checkpoint is the highest block that a stake-weighted majority of the
+	// beacons sent identical summaries of the state after. If set, the
+	// checkpoint must also be this trusted block.
+	Trusted Checkpoint
+}
+
+// stateSync tracks the progress of syncing the state of the VM to a
+// checkpoint
+type stateSync struct {
+	vm block.StateSyncableVM
+
+	// The blocks that the beacons attest are accepted. Bootstrapping resumes
+	// from these once the state has been synced.
+	acceptedFrontier ids.Set
+
+	// Beacons that haven't responded to the request for their state summary
+	pendingSummaries ids.ShortSet
+	summaryRequestID uint32
+	// Key: ID of a summary
+	// Value: The beacons that sent the summary
+	summaries map[[32]byte]*summarySet
+
+	// The summary of the state after the checkpoint
+	chosen *summarySet
+	// Beacons that failed to return the checkpoint
+	blkFailures ids.ShortSet
+	// The block the state is synced to
+	checkpoint snowman.Block
+
+	// The beacon the state is being fetched from, the index of the chunk of
+	// state being fetched, and the request it's being fetched with
+	chunkVdr         ids.ShortID
+	chunkIndex       uint32
+	chunkRequestID   uint32
+	chunkOutstanding bool
+	// Beacons that failed to return a chunk
+	chunkFailures ids.ShortSet
+}
+
+// summarySet is a summary of the state after a block, and the beacons that sent
+// it. Because the summary commits to every chunk of the state, each chunk can
+// be fetched from any of these beacons and verified against the summary.
+type summarySet struct {
+	summary []byte
+	blkID   ids.ID
+	height  uint64
+	// Total weight of the beacons that sent the summary
+	weight uint64
+	vdrs   ids.ShortSet
+}
+
+// startStateSync starts syncing the state of the VM to a checkpoint, if state
+// sync is enabled and supported. [acceptedFrontier] is the set of blocks that
+// the beacons attest are accepted. Returns true if bootstrapping will resume
+// once the state is synced.
+func (b *Bootstrapper) startStateSync(acceptedFrontier ids.Set) (bool, error) {
+	vm, ok := b.VM.(block.StateSyncableVM)
+	if !b.StateSync.Enabled || !ok || b.Beacons.Len() == 0 {
+		return false, nil
+	}
+
+	// Blocks fetched by an earlier attempt to bootstrap would be executed on
+	// top of the synced state, so they must be executed first
+	numPending, err := b.Blocked.NumPending()
+	if err != nil {
+		return false, err
+	}
+	if numPending > 0 {
+		b.Ctx.Log.Info("not syncing state as %d blocks were fetched by a previous attempt to bootstrap", numPending)
+		return false, nil
+	}
+
+	b.Progress.SetPhase(common.PhaseStateSync)
+	b.IncrementRequestID()
+	b.stateSync = &stateSync{
+		vm:               vm,
+		acceptedFrontier: acceptedFrontier,
+		summaryRequestID: b.RequestID,
+		summaries:        make(map[[32]byte]*summarySet),
+	}
+	for _, vdr := range b.Beacons.List() {
+		b.stateSync.pendingSummaries.Add(vdr.ID())
+	}
+
+	// Ask each of the beacons to send a summary of its state
+	vdrs := ids.ShortSet{}
+	vdrs.Union(b.stateSync.pendingSummaries)
+	b.Sender.GetStateSummary(vdrs, b.RequestID)
+	return true, nil
+}
+
+// StateSummary handles the receipt of a summary of the state of [vdr]. Should
+// be received in response to a GetStateSummary message to [vdr] with request
+// ID [requestID].
+func (b *Bootstrapper) StateSummary(vdr ids.ShortID, requestID uint32, summary []byte) error {
+	s := b.stateSync
+	if s == nil || s.summaryRequestID != requestID || !s.pendingSummaries.Contains(vdr) {
+		b.Ctx.Log.Debug("received unexpected StateSummary from %s with ID %d", vdr, requestID)
+		return nil
+	}
+	s.pendingSummaries.Remove(vdr)
+
+	if blkID, height, err := s.vm.ParseStateSummary(summary); err != nil {
+		b.Ctx.Log.Debug("failed to parse the state summary from %s: %s", vdr, err)
+	} else {
+		summaryID := ids.NewID(hashing.ComputeHash256Array(summary))
+		set, ok := s.summaries[summaryID.Key()]
+		if !ok {
+			set = &summarySet{
+				summary: summary,
+				blkID:   blkID,
+				height:  height,
+			}
+			s.summaries[summaryID.Key()] = set
+		}
+		weight, _ := b.Beacons.GetWeight(vdr)
+		newWeight, err := math.Add64(set.weight, weight)
+		if err != nil {
+			newWeight = stdmath.MaxUint64
+		}
+		set.weight = newWeight
+		set.vdrs.Add(vdr)
+	}
+
+	if s.pendingSummaries.Len() == 0 {
+		return b.chooseCheckpoint()
+	}
+	return nil
+}
+
+// GetStateSummaryFailed is called when a GetStateSummary message we sent fails
+func (b *Bootstrapper) GetStateSummaryFailed(vdr ids.ShortID, requestID uint32) error {
+	s := b.stateSync
+	if s == nil || s.summaryRequestID != requestID || !s.pendingSummaries.Contains(vdr) {
+		b.Ctx.Log.Debug("GetStateSummaryFailed(%s, %d) called but there was no outstanding request to this validator with this ID",
+			vdr, requestID)
+		return nil
+	}
+	s.pendingSummaries.Remove(vdr)
+
+	if s.pendingSummaries.Len() == 0 {
+		return b.chooseCheckpoint()
+	}
+	return nil
+}
+
+// chooseCheckpoint syncs the state to the highest block that enough beacons
+// sent identical summaries of the state after, once every beacon has responded
+func (b *Bootstrapper) chooseCheckpoint() error {
+	s := b.stateSync
+	trusted := b.StateSync.Trusted
+	for _, set := range s.summaries {
+		if set.weight < b.Alpha {
+			continue
+		}
+		if !trusted.BlockID.IsZero() && (!trusted.BlockID.Equals(set.blkID) || trusted.Height != set.height) {
+			continue
+		}
+		if s.chosen == nil || set.height > s.chosen.height ||
+			(set.height == s.chosen.height && set.weight > s.chosen.weight) {
+			s.chosen = set
+		}
+	}
+	if s.chosen == nil {
+		b.Ctx.Log.Info("not syncing state as no beacons sent a summary of the state after the checkpoint")
+		return b.finishStateSync()
+	}
+
+	if lastAccepted, err := b.VM.GetBlock(b.VM.LastAccepted()); err == nil {
+		if blk, ok := lastAccepted.(heightBlock); ok && blk.Height() >= s.chosen.height {
+			b.Ctx.Log.Info("not syncing state as the last accepted block is at height %d, which isn't below the checkpoint",
+				blk.Height())
+			return b.finishStateSync()
+		}
+	}
+
+	b.Ctx.Log.Info("syncing state to checkpoint %s at height %d, which %d beacons sent a summary of",
+		s.chosen.blkID, s.chosen.height, s.chosen.vdrs.Len())
+	if blk, err := b.VM.GetBlock(s.chosen.blkID); err == nil {
+		return b.checkpointFetched(blk)
+	}
+	return b.fetchCheckpoint()
+}
+
+// Get the checkpoint from a beacon
+func (b *Bootstrapper) fetchCheckpoint() error {
+	blkID := b.stateSync.chosen.blkID
+	validatorID, err := b.Scheduler.Sample(blkID)
+	if err != nil {
+		return err
+	}
+	b.sendGetAncestors(validatorID, blkID)
+	return nil
+}
+
+// isCheckpointRequest returns true if request [requestID] sent to [vdr] is an
+// outstanding request for the checkpoint
+func (b *Bootstrapper) isCheckpointRequest(vdr ids.ShortID, requestID uint32) bool {
+	s := b.stateSync
+	if s.chosen == nil || s.checkpoint != nil {
+		return false
+	}
+	blkID, ok := b.Scheduler.Requested(vdr, requestID)
+	return ok && blkID.Equals(s.chosen.blkID)
+}
+
+// checkpointReceived is called when [vdr] responds to request [requestID] for
+// the checkpoint with [blkBytes]
+func (b *Bootstrapper) checkpointReceived(vdr ids.ShortID, requestID uint32, blkBytes []byte) error {
+	blkID := b.stateSync.chosen.blkID
+	blk, err := b.VM.ParseBlock(blkBytes)
+	if err != nil {
+		b.Ctx.Log.Debug("failed to parse checkpoint %s: %s", blkID, err)
+		return b.checkpointFailed(vdr, requestID)
+	}
+	if actualID := blk.ID(); !actualID.Equals(blkID) {
+		b.Ctx.Log.Debug("expected checkpoint %s but got %s", blkID, actualID)
+		return b.checkpointFailed(vdr, requestID)
+	}
+	b.Scheduler.Received(vdr, requestID, len(blkBytes))
+	return b.checkpointFetched(blk)
+}
+
+// checkpointFailed is called when [vdr] didn't return the checkpoint in
+// response to request [requestID]
+func (b *Bootstrapper) checkpointFailed(vdr ids.ShortID, requestID uint32) error {
+	s := b.stateSync
+	b.Scheduler.Failed(vdr, requestID)
+
+	s.blkFailures.Add(vdr)
+	if s.blkFailures.Len() < b.Beacons.Len() {
+		return b.fetchCheckpoint()
+	}
+	b.Ctx.Log.Warn("abandoning state sync as no beacon returned checkpoint %s", s.chosen.blkID)
+	return b.finishStateSync()
+}
+
+// checkpointFetched starts fetching the state after [blk], the checkpoint
+func (b *Bootstrapper) checkpointFetched(blk snowman.Block) error {
+	s := b.stateSync
+	if hBlk, ok := blk.(heightBlock); !ok || hBlk.Height() != s.chosen.height {
+		b.Ctx.Log.Warn("abandoning state sync as checkpoint %s isn't at height %d", s.chosen.blkID, s.chosen.height)
+		return b.finishStateSync()
+	}
+	s.checkpoint = blk
+	return b.fetchChunk()
+}
+
+// Get the next chunk of the state after the checkpoint. Chunks are fetched
+// from the same beacon until it fails to send a valid chunk, at which point
+// the chunk is fetched again from another beacon that sent the summary.
+func (b *Bootstrapper) fetchChunk() error {
+	s := b.stateSync
+	if s.chunkVdr.IsZero() || s.chunkFailures.Contains(s.chunkVdr) {
+		s.chunkVdr = ids.ShortID{}
+		for _, vdr := range s.chosen.vdrs.List() {
+			if !s.chunkFailures.Contains(vdr) {
+				s.chunkVdr = vdr
+				break
+			}
+		}
+		if s.chunkVdr.IsZero() {
+			b.Ctx.Log.Warn("abandoning state sync as no beacon returned chunk %d of the state after %s",
+				s.chunkIndex, s.chosen.blkID)
+			return b.finishStateSync()
+		}
+	}
+	b.IncrementRequestID()
+
+	s.chunkRequestID = b.RequestID
+	s.chunkOutstanding = true
+	summaryID := ids.NewID(hashing.ComputeHash256Array(s.chosen.summary))
+	b.Scheduler.Sent(s.chunkVdr, b.RequestID, s.chosen.blkID)
+	b.Sender.GetStateChunk(s.chunkVdr, b.RequestID, summaryID, s.chunkIndex)
+	return nil
+}
+
+// StateChunk handles the receipt of a chunk of state. Should be received in
+// response to a GetStateChunk message to [vdr] with request ID [requestID].
+func (b *Bootstrapper) StateChunk(vdr ids.ShortID, requestID uint32, chunk []byte) error {
+	s := b.stateSync
+	if s == nil || !s.chunkOutstanding || !s.chunkVdr.Equals(vdr) || s.chunkRequestID != requestID {
+		b.Ctx.Log.Debug("received unexpected StateChunk from %s with ID %d", vdr, requestID)
+		return nil
+	}
+	s.chunkOutstanding = false
+
+	done, err := s.vm.PutStateChunk(s.chosen.summary, s.checkpoint, s.chunkIndex, chunk)
+	if err != nil {
+		b.Ctx.Log.Debug("failed to add chunk %d of the state after %s from %s: %s",
+			s.chunkIndex, s.chosen.blkID, vdr, err)
+		b.Scheduler.Failed(vdr, requestID)
+		s.chunkFailures.Add(vdr)
+		return b.fetchChunk()
+	}
+	b.Scheduler.Received(vdr, requestID, len(chunk))
+
+	if !done {
+		s.chunkIndex++
+		return b.fetchChunk()
+	}
+	b.Ctx.Log.Info("synced state to checkpoint %s with %d chunks", s.chosen.blkID, s.chunkIndex+1)
+	return b.finishStateSync()
+}
+
+// GetStateChunkFailed is called when a GetStateChunk message we sent fails
+func (b *Bootstrapper) GetStateChunkFailed(vdr ids.ShortID, requestID uint32) error {
+	s := b.stateSync
+	if s == nil || !s.chunkOutstanding || !s.chunkVdr.Equals(vdr) || s.chunkRequestID != requestID {
+		b.Ctx.Log.Debug("GetStateChunkFailed(%s, %d) called but there was no outstanding request to this validator with this ID",
+			vdr, requestID)
+		return nil
+	}
+	s.chunkOutstanding = false
+
+	b.Scheduler.Failed(vdr, requestID)
+	s.chunkFailures.Add(vdr)
+	// Fetch the chunk from a different beacon
+	return b.fetchChunk()
+}
+
+// finishStateSync resumes bootstrapping from the accepted frontier, whether or
+// not the state was synced
+func (b *Bootstrapper) finishStateSync() error {
+	acceptedFrontier := b.stateSync.acceptedFrontier
+	b.stateSync = nil
+	return b.fetchAccepted(acceptedFrontier)
+}
@@ -14,10 +14,12 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
@@ -42,6 +44,10 @@ type Transitive struct {
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
 
+	// blocks in [blkReqs] that were requested from a validator that voted for
+	// them
+	votedReqs ids.Set
+
 	// blocks that are queued to be issued to consensus once missing dependencies are fetched
 	pending ids.Set
 
@@ -49,6 +55,13 @@ type Transitive struct {
 	// issuing another block, responding to a query, or applying votes to consensus
 	blocked events.Blocker
 
+	// tracer records the polls of the blocks being traced
+	tracer common.PollTracer
+
+	// rejections tells the votes for blocks that were already rejected when
+	// the query was sent apart from the votes that raced with the rejection
+	rejections common.Rejections
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -71,6 +84,11 @@ func (t *Transitive) Initialize(config Config) error {
 		return err
 	}
 
+	t.rejections.Initialize(&t.RequestID)
+	if err := config.Ctx.ConsensusDispatcher.RegisterChain(config.Ctx.ChainID, common.RejectionsHandlerID, &t.rejections); err != nil {
+		return err
+	}
+
 	return t.Bootstrapper.Initialize(
 		config.Config,
 		t.finishBootstrapping,
@@ -131,6 +149,9 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
+	if err := t.Ctx.ConsensusDispatcher.DeregisterChain(t.Ctx.ChainID, common.RejectionsHandlerID); err != nil {
+		t.Ctx.Log.Warn("failed to deregister the rejections tracker due to: %s", err)
+	}
 	return t.VM.Shutdown()
 }
 
@@ -183,11 +204,53 @@ func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, blkID ids.I
 	return nil
 }
 
+// GetStateChunk implements the Engine interface
+func (t *Transitive) GetStateChunk(vdr ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) error {
+	vm, ok := t.VM.(block.StateSyncableVM)
+	if !ok {
+		t.Ctx.Log.Debug("dropping GetStateChunk(%s, %d, %s, %d) as the VM doesn't support state sync", vdr, requestID, summaryID, index)
+		return nil
+	}
+	chunk, err := vm.GetStateChunk(summaryID, index)
+	if err != nil { // Don't have the state. Drop this request.
+		t.Ctx.Log.Verbo("couldn't get chunk %d of the state summarized by %s due to %s. dropping GetStateChunk(%s, %d)", index, summaryID, err, vdr, requestID)
+		return nil
+	}
+	if len(chunk) > maxContainersLen {
+		t.Ctx.Log.Warn("dropping GetStateChunk(%s, %d, %s, %d) as the chunk has %d bytes", vdr, requestID, summaryID, index, len(chunk))
+		return nil
+	}
+
+	t.Sender.StateChunk(vdr, requestID, chunk)
+	return nil
+}
+
+// GetStateSummary implements the Engine interface
+func (t *Transitive) GetStateSummary(vdr ids.ShortID, requestID uint32) error {
+	vm, ok := t.VM.(block.StateSyncableVM)
+	if !ok {
+		t.Ctx.Log.Debug("dropping GetStateSummary(%s, %d) as the VM doesn't support state sync", vdr, requestID)
+		return nil
+	}
+	summary, err := vm.StateSummary()
+	if err != nil { // Don't have a summary. Drop this request.
+		t.Ctx.Log.Verbo("couldn't get a state summary due to %s. dropping GetStateSummary(%s, %d)", err, vdr, requestID)
+		return nil
+	}
+	if len(summary) > maxContainersLen {
+		t.Ctx.Log.Warn("dropping GetStateSummary(%s, %d) as the summary has %d bytes", vdr, requestID, len(summary))
+		return nil
+	}
+
+	t.Sender.StateSummary(vdr, requestID, summary)
+	return nil
+}
+
 // Put implements the Engine interface
 func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkBytes []byte) error {
 	// bootstrapping isn't done --> we didn't send any gets --> this put is invalid
 	if !t.IsBootstrapped() {
-		if requestID == constants.GossipMsgRequestID {
+		if requestID == constants.GossipMsgRequestID || requestID == constants.GossipTxRequestID {
 			t.Ctx.Log.Verbo("dropping gossip Put(%s, %d, %s) due to bootstrapping",
 				vdr, requestID, blkID)
 		} else {
@@ -196,10 +259,25 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		return nil
 	}
 
+	// this put contains an unconfirmed transaction rather than a block
+	if requestID == constants.GossipTxRequestID {
+		gossiper, ok := t.VM.(block.TxGossiper)
+		if !ok {
+			t.Ctx.Log.Verbo("dropping gossiped tx %s from %s as the VM doesn't support tx gossip", blkID, vdr)
+			return nil
+		}
+		if err := gossiper.GossipedTx(vdr, blkBytes); err != nil {
+			t.Ctx.Log.Debug("dropping gossiped tx %s from %s: %s", blkID, vdr, err)
+			t.Ctx.Log.Verbo("tx:\n%s", formatting.DumpBytes{Bytes: blkBytes})
+		}
+		return nil
+	}
+
 	blk, err := t.VM.ParseBlock(blkBytes)
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse block %s: %s", blkID, err)
 		t.Ctx.Log.Verbo("block:\n%s", formatting.DumpBytes{Bytes: blkBytes})
+		t.Misbehavior.Record(vdr, common.InvalidContainer)
 		// because GetFailed doesn't utilize the assumption that we actually
 		// sent a Get message, we can safely call GetFailed here to potentially
 		// abandon the request.
@@ -231,6 +309,14 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 		return nil
 	}
 
+	// A validator that voted for a block should be able to provide it
+	if t.votedReqs.Contains(blkID) {
+		t.votedReqs.Remove(blkID)
+		if _, err := t.VM.GetBlock(blkID); err != nil {
+			t.Misbehavior.Record(vdr, common.UnknownVote)
+		}
+	}
+
 	// Because the get request was dropped, we no longer expect blkID to be issued.
 	t.blocked.Abandon(blkID)
 	return t.errs.Err
@@ -282,6 +368,7 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID,
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse block %s: %s", blkID, err)
 		t.Ctx.Log.Verbo("block:\n%s", formatting.DumpBytes{Bytes: blkBytes})
+		t.Misbehavior.Record(vdr, common.InvalidContainer)
 		return nil
 	}
 
@@ -309,6 +396,7 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 	// Since this is a linear chain, there should only be one ID in the vote set
 	if votes.Len() != 1 {
 		t.Ctx.Log.Debug("Chits(%s, %d) was called with %d votes (expected 1)", vdr, requestID, votes.Len())
+		t.Misbehavior.Record(vdr, common.MalformedChits)
 		// because QueryFailed doesn't utilize the assumption that we actually
 		// sent a Query message, we can safely call QueryFailed here to
 		// potentially abandon the request.
@@ -326,9 +414,23 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 		response:  blkID,
 	}
 
-	added, err := t.issueFromByID(vdr, blkID)
-	if err != nil {
-		return err
+	// Try to issue [blkID] to consensus.
+	// If we're missing an ancestor, request it from [vdr]
+	added := false
+	if blk, err := t.VM.GetBlock(blkID); err != nil {
+		if !t.blkReqs.Contains(blkID) {
+			t.sendRequest(vdr, blkID)
+			t.votedReqs.Add(blkID)
+		}
+	} else {
+		// a vote for a block that was already rejected when the query was
+		// sent conflicts with the accepted chain
+		if blk.Status() == choices.Rejected && t.rejectedBefore(blkID, requestID) {
+			t.Misbehavior.Record(vdr, common.ConflictingVote)
+		}
+		if added, err = t.issueFrom(vdr, blk); err != nil {
+			return err
+		}
 	}
 
 	// Wait until [blkID] has been issued to consensus before for applying this chit.
@@ -340,6 +442,19 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 	return t.errs.Err
 }
 
+// rejectedBefore returns true if [blkID], which is rejected, was already
+// rejected when the query [requestID] was sent
+func (t *Transitive) rejectedBefore(blkID ids.ID, requestID uint32) bool {
+	// Forget the rejections that happened before the oldest outstanding poll
+	// was issued, as the votes of the other polls are no longer applied
+	oldestRequestID := t.RequestID + 1
+	if polls := t.polls.Outstanding(1); len(polls) > 0 {
+		oldestRequestID = polls[0].RequestID
+	}
+	t.rejections.Prune(oldestRequestID)
+	return t.rejections.RejectedBefore(blkID, requestID)
+}
+
 // QueryFailed implements the Engine interface
 func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 	// If the engine hasn't been bootstrapped, we didn't issue a query
@@ -398,6 +513,17 @@ func (t *Transitive) Notify(msg common.Message) error {
 		} else {
 			t.Ctx.Log.Warn("VM.BuildBlock returned a block with unissued ancestors")
 		}
+	case common.GossipTxs:
+		// the gossip txs message means we should gossip the VM's new
+		// unconfirmed transactions to the validators
+		gossiper, ok := t.VM.(block.TxGossiper)
+		if !ok {
+			t.Ctx.Log.Warn("VM requested tx gossip but doesn't implement the TxGossiper interface")
+			return nil
+		}
+		for _, tx := range gossiper.PendingGossipTxs() {
+			t.Sender.GossipTx(ids.NewID(hashing.ComputeHash256Array(tx)), tx)
+		}
 	default:
 		t.Ctx.Log.Warn("unexpected message from the VM: %s", msg)
 	}
@@ -493,6 +619,7 @@ func (t *Transitive) issue(blk snowman.Block) error {
 
 	// Remove any outstanding requests for this block
 	t.blkReqs.RemoveAny(blkID)
+	t.votedReqs.Remove(blkID)
 
 	// Will add [blk] to consensus once its ancestors have been
 	i := &issuer{
@@ -522,7 +649,7 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, blkID ids.ID) {
 		return
 	}
 
-	t.RequestID++
+	t.IncrementRequestID()
 	t.blkReqs.Add(vdr, t.RequestID, blkID)
 	t.Ctx.Log.Verbo("sending Get(%s, %d, %s)", vdr, t.RequestID, blkID)
 	t.Sender.Get(vdr, t.RequestID, blkID)
@@ -541,11 +668,12 @@ func (t *Transitive) pullSample(blkID ids.ID) {
 		vdrBag.Add(vdr.ID())
 	}
 
-	t.RequestID++
+	t.IncrementRequestID()
 	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
 		vdrSet := ids.ShortSet{}
 		vdrSet.Add(vdrBag.List()...)
 
+		t.tracePoll(t.RequestID, blkID, vdrBag)
 		t.Sender.PullQuery(vdrSet, t.RequestID, blkID)
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blkID)
@@ -561,11 +689,12 @@ func (t *Transitive) pushSample(blk snowman.Block) {
 		vdrBag.Add(vdr.ID())
 	}
 
-	t.RequestID++
+	t.IncrementRequestID()
 	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
 		vdrSet := ids.ShortSet{}
 		vdrSet.Add(vdrBag.List()...)
 
+		t.tracePoll(t.RequestID, blk.ID(), vdrBag)
 		t.Sender.PushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blk.ID())
@@ -622,6 +751,7 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	}
 
 	t.VM.SetPreference(t.Consensus.Preference())
+	t.traceConsensus()
 
 	// Query the network for its preferences given this new block
 	t.pushSample(blk)
@@ -649,6 +779,65 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	return t.errs.Err
 }
 
+// tracePoll records the poll [requestID] of [blkID] in the timelines of the
+// traced blocks that are part of it. A block is part of the polls of itself
+// and of its processing descendants, as votes for a block are applied to its
+// ancestors.
+func (t *Transitive) tracePoll(requestID uint32, blkID ids.ID, vdrs ids.ShortBag) {
+	tracedIDs := t.tracer.Traced()
+	if len(tracedIDs) == 0 {
+		return
+	}
+	traced := ids.Set{}
+	traced.Add(tracedIDs...)
+
+	blk, err := t.VM.GetBlock(blkID)
+	if err != nil {
+		return
+	}
+	containerIDs := []ids.ID(nil)
+	for blk.Status() == choices.Processing {
+		if ancestorID := blk.ID(); traced.Contains(ancestorID) {
+			containerIDs = append(containerIDs, ancestorID)
+		}
+		blk = blk.Parent()
+	}
+	t.tracer.PollIssued(requestID, containerIDs, vdrs)
+}
+
+// traceConsensus records the state in consensus of the traced blocks
+func (t *Transitive) traceConsensus() {
+	for _, blkID := range t.tracer.Traced() {
+		blk, err := t.VM.GetBlock(blkID)
+		if err != nil {
+			continue
+		}
+		switch status := blk.Status(); {
+		case status.Decided():
+			t.tracer.ConsensusUpdated(blkID, status, false, 0)
+		case t.Consensus.Issued(blk):
+			confidence, _ := t.Consensus.Confidence(blkID)
+			t.tracer.ConsensusUpdated(blkID, status, t.Consensus.IsPreferred(blk), confidence)
+		}
+	}
+}
+
+// PollTracer implements the common.PollTraceable interface
+func (t *Transitive) PollTracer() *common.PollTracer { return &t.tracer }
+
+// ProcessingReport implements the common.ProcessingReporter interface
+func (t *Transitive) ProcessingReport(max int) (common.ProcessingReport, error) {
+	report := common.ProcessingReport{NumPolls: t.polls.Len()}
+	report.Blocks, report.NumBlocks = t.Consensus.Processing(max)
+	for _, poll := range t.polls.Outstanding(max) {
+		report.Polls = append(report.Polls, common.OutstandingPoll{
+			RequestID: poll.RequestID,
+			Start:     poll.Start,
+		})
+	}
+	return report, nil
+}
+
 // IsBootstrapped returns true iff this chain is done bootstrapping
 func (t *Transitive) IsBootstrapped() bool {
 	return t.Ctx.IsBootstrapped()
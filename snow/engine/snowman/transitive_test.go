@@ -17,6 +17,8 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
 var (
@@ -1723,3 +1725,355 @@ func TestEngineDoubleChit(t *testing.T) {
 		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
 	}
 }
+
+type txGossiperVM struct {
+	*block.TestVM
+
+	pendingTxs [][]byte
+	gossipedTx []byte
+}
+
+func (vm *txGossiperVM) PendingGossipTxs() [][]byte {
+	txs := vm.pendingTxs
+	vm.pendingTxs = nil
+	return txs
+}
+
+func (vm *txGossiperVM) GossipedTx(_ ids.ShortID, tx []byte) error {
+	vm.gossipedTx = tx
+	return nil
+}
+
+func TestEngineTxGossip(t *testing.T) {
+	vdr, _, sender, vm, te, _ := setup(t)
+
+	gossiper := &txGossiperVM{
+		TestVM:     vm,
+		pendingTxs: [][]byte{{1}, {2}},
+	}
+	te.VM = gossiper
+
+	gossiped := [][]byte(nil)
+	sender.GossipTxF = func(txID ids.ID, tx []byte) {
+		if !txID.Equals(ids.NewID(hashing.ComputeHash256Array(tx))) {
+			t.Fatalf("Wrong tx ID gossiped")
+		}
+		gossiped = append(gossiped, tx)
+	}
+
+	if err := te.Notify(common.GossipTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(gossiped) != 2 {
+		t.Fatalf("Should have gossiped 2 txs but gossiped %d", len(gossiped))
+	}
+
+	// A gossiped tx should be passed to the VM rather than parsed as a block
+	if err := te.Put(vdr, constants.GossipTxRequestID, ids.GenerateTestID(), []byte{3}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gossiper.gossipedTx, []byte{3}) {
+		t.Fatalf("Gossiped tx wasn't passed to the VM")
+	}
+}
+
+func TestEngineTracePoll(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(blk.ID()):
+			return blk, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	tracer := te.PollTracer()
+	if err := tracer.Trace(blk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	reqID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, _ ids.ID, _ []byte) { *reqID = requestID }
+
+	if err := te.issue(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	votes := ids.Set{}
+	votes.Add(blk.ID())
+	if err := te.Chits(vdr, *reqID, votes); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := blk.Status(); status != choices.Accepted {
+		t.Fatalf("Block should have been accepted, but is %s", status)
+	}
+
+	timeline, err := tracer.Timeline(blk.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTypes := []common.TraceEventType{
+		common.TraceConsensus,
+		common.TracePollIssued,
+		common.TraceChits,
+		common.TracePollFinished,
+		common.TraceConsensus,
+	}
+	if len(timeline) != len(expectedTypes) {
+		t.Fatalf("Recorded %d events, expected %d", len(timeline), len(expectedTypes))
+	}
+	for i, event := range timeline {
+		if event.Type != expectedTypes[i] {
+			t.Fatalf("Event %d is %s, expected %s", i, event.Type, expectedTypes[i])
+		}
+	}
+
+	if issued := timeline[0]; issued.Status != choices.Processing || !issued.Preferred || issued.Confidence != 0 {
+		t.Fatalf("Wrong state recorded when the block was issued")
+	} else if poll := timeline[1]; poll.RequestID != *reqID || len(poll.Validators) != 1 || !poll.Validators[0].Equals(vdr) {
+		t.Fatalf("Wrong poll recorded")
+	} else if chits := timeline[2]; !chits.Validator.Equals(vdr) || chits.Votes.Count(blk.ID()) != 1 {
+		t.Fatalf("Wrong chits recorded")
+	} else if finished := timeline[3]; finished.EarlyTermination || finished.Votes.Count(blk.ID()) != 1 {
+		t.Fatalf("Wrong poll result recorded")
+	} else if accepted := timeline[4]; accepted.Status != choices.Accepted {
+		t.Fatalf("Wrong status recorded, expected %s got %s", choices.Accepted, accepted.Status)
+	}
+}
+
+func TestEngineProcessingReport(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(blk.ID()):
+			return blk, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	reqID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, _ ids.ID, _ []byte) { *reqID = requestID }
+
+	if err := te.issue(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := te.ProcessingReport(10)
+	if err != nil {
+		t.Fatal(err)
+	} else if report.NumBlocks != 1 || len(report.Blocks) != 1 {
+		t.Fatalf("Should have reported one processing block")
+	} else if info := report.Blocks[0]; !info.ID.Equals(blk.ID()) || !info.ParentID.Equals(gBlk.ID()) || !info.Preferred {
+		t.Fatalf("Wrong block reported")
+	} else if report.NumPolls != 1 || len(report.Polls) != 1 {
+		t.Fatalf("Should have reported one outstanding poll")
+	} else if report.Polls[0].RequestID != *reqID {
+		t.Fatalf("Wrong poll reported")
+	}
+}
+
+func TestEngineRecordsMisbehavior(t *testing.T) {
+	vdr, vals, _, vm, te, _ := setup(t)
+
+	ledger := &common.MisbehaviorLedger{}
+	if err := ledger.Initialize(common.MisbehaviorConfig{}, vals, te.Ctx.Log, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	te.Misbehavior = ledger
+
+	votes := ids.Set{}
+	votes.Add(ids.GenerateTestID(), ids.GenerateTestID())
+	if err := te.Chits(vdr, 0, votes); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) { return nil, errUnknownBytes }
+	if err := te.PushQuery(vdr, 1, ids.GenerateTestID(), []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	offenders := ledger.Offenders()
+	if len(offenders) != 1 {
+		t.Fatalf("expected 1 offender, got %d", len(offenders))
+	}
+	if !offenders[0].NodeID.Equals(vdr) {
+		t.Fatalf("wrong offender")
+	}
+	if count := offenders[0].Count(common.MalformedChits); count != 1 {
+		t.Fatalf("expected 1 malformed chits, got %d", count)
+	}
+	if count := offenders[0].Count(common.InvalidContainer); count != 1 {
+		t.Fatalf("expected 1 invalid container, got %d", count)
+	}
+}
+
+func TestEngineConflictingVoteRace(t *testing.T) {
+	vdr, vals, _, vm, te, gBlk := setup(t)
+
+	ledger := &common.MisbehaviorLedger{}
+	if err := ledger.Initialize(common.MisbehaviorConfig{}, vals, te.Ctx.Log, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	te.Misbehavior = ledger
+
+	oldBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Rejected,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+	}
+	raceBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Rejected,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(oldBlk.ID()):
+			return oldBlk, nil
+		case blkID.Equals(raceBlk.ID()):
+			return raceBlk, nil
+		}
+		t.Fatalf("Wrong block requested")
+		panic("Should have failed")
+	}
+
+	// [oldBlk] was rejected before both queries were sent, [raceBlk] after
+	vdrs := ids.ShortBag{}
+	vdrs.Add(vdr)
+	for i := 0; i < 2; i++ {
+		te.RequestID++
+		te.polls.Add(te.RequestID, vdrs)
+	}
+	te.Ctx.ConsensusDispatcher.Reject(te.Ctx.ChainID, raceBlk.ID(), raceBlk.Bytes())
+
+	raceVotes := ids.Set{}
+	raceVotes.Add(raceBlk.ID())
+	if err := te.Chits(vdr, te.RequestID-1, raceVotes); err != nil {
+		t.Fatal(err)
+	}
+	if offenders := ledger.Offenders(); len(offenders) != 0 {
+		t.Fatalf("a vote that raced with the rejection shouldn't be recorded")
+	}
+
+	oldVotes := ids.Set{}
+	oldVotes.Add(oldBlk.ID())
+	if err := te.Chits(vdr, te.RequestID, oldVotes); err != nil {
+		t.Fatal(err)
+	}
+	offenders := ledger.Offenders()
+	if len(offenders) != 1 {
+		t.Fatalf("expected 1 offender, got %d", len(offenders))
+	}
+	if count := offenders[0].Count(common.ConflictingVote); count != 1 {
+		t.Fatalf("expected 1 conflicting vote, got %d", count)
+	}
+}
+
+func TestEngineRecordsUnknownVote(t *testing.T) {
+	vdr, vals, sender, vm, te, gBlk := setup(t)
+
+	otherVdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(otherVdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ledger := &common.MisbehaviorLedger{}
+	if err := ledger.Initialize(common.MisbehaviorConfig{}, vals, te.Ctx.Log, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	te.Misbehavior = ledger
+
+	unknownID := ids.GenerateTestID()
+	missingParent := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Unknown,
+	}}
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: missingParent,
+		HeightV: 2,
+		BytesV:  []byte{1},
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID.Equals(gBlk.ID()) {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) { return blk, nil }
+
+	reqIDs := map[[32]byte]uint32{}
+	sender.GetF = func(inVdr ids.ShortID, requestID uint32, blkID ids.ID) {
+		reqIDs[blkID.Key()] = requestID
+	}
+
+	// [vdr] votes for a block that it can't provide
+	votes := ids.Set{}
+	votes.Add(unknownID)
+	if err := te.Chits(vdr, 0, votes); err != nil {
+		t.Fatal(err)
+	}
+	// [otherVdr] sends a block whose parent it can't provide
+	if err := te.Put(otherVdr, 0, blk.ID(), blk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reqIDs) != 2 {
+		t.Fatalf("expected both blocks to be requested")
+	}
+
+	if err := te.GetFailed(vdr, reqIDs[unknownID.Key()]); err != nil {
+		t.Fatal(err)
+	} else if err := te.GetFailed(otherVdr, reqIDs[missingParent.ID().Key()]); err != nil {
+		t.Fatal(err)
+	}
+
+	offenders := ledger.Offenders()
+	if len(offenders) != 1 {
+		t.Fatalf("expected 1 offender, got %d", len(offenders))
+	}
+	if !offenders[0].NodeID.Equals(vdr) {
+		t.Fatalf("only the validator that voted for the block should be recorded")
+	}
+	if count := offenders[0].Count(common.UnknownVote); count != 1 {
+		t.Fatalf("expected 1 unknown vote, got %d", count)
+	}
+}
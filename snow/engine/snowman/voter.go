@@ -35,14 +35,17 @@ func (v *voter) Update() {
 	results := ids.Bag{}
 	finished := false
 	if v.response.IsZero() {
+		v.t.tracer.QueryFailed(v.requestID, v.vdr)
 		results, finished = v.t.polls.Drop(v.requestID, v.vdr)
 	} else {
+		v.t.tracer.Chits(v.requestID, v.vdr, []ids.ID{v.response})
 		results, finished = v.t.polls.Vote(v.requestID, v.vdr, v.response)
 	}
 
 	if !finished {
 		return
 	}
+	v.t.tracer.PollFinished(v.requestID, results)
 
 	// To prevent any potential deadlocks with un-disclosed dependencies, votes
 	// must be bubbled to the nearest valid block
@@ -55,6 +58,7 @@ func (v *voter) Update() {
 	}
 
 	v.t.VM.SetPreference(v.t.Consensus.Preference())
+	v.t.traceConsensus()
 
 	if v.t.Consensus.Finalized() {
 		v.t.Ctx.Log.Debug("Snowman engine can quiesce")
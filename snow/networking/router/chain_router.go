@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -116,8 +117,9 @@ func (sr *ChainRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID,
 	defer sr.lock.RUnlock()
 
 	if chain, exists := sr.chains[chainID.Key()]; exists {
-		if chain.AcceptedFrontier(validatorID, requestID, containerIDs) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		if chain.AcceptedFrontier(validatorID, requestID, containerIDs) && !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
 		}
 	} else {
 		sr.log.Debug("AcceptedFrontier(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerIDs)
@@ -161,8 +163,9 @@ func (sr *ChainRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, request
 	defer sr.lock.RUnlock()
 
 	if chain, exists := sr.chains[chainID.Key()]; exists {
-		if chain.Accepted(validatorID, requestID, containerIDs) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		if chain.Accepted(validatorID, requestID, containerIDs) && !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
 		}
 	} else {
 		sr.log.Debug("Accepted(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerIDs)
@@ -207,8 +210,9 @@ func (sr *ChainRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, request
 	// This message came in response to a GetAncestors message from this node, and when we sent that
 	// message we set a timeout. Since we got a response, cancel the timeout.
 	if chain, exists := sr.chains[chainID.Key()]; exists {
-		if chain.MultiPut(validatorID, requestID, containers) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		if chain.MultiPut(validatorID, requestID, containers) && !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
 		}
 	} else {
 		sr.log.Debug("MultiPut(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID, len(containers))
@@ -251,10 +255,14 @@ func (sr *ChainRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	// This message came in response to a Get message from this node, and when we sent that Get
 	// message we set a timeout. Since we got a response, cancel the timeout.
 	if chain, exists := sr.chains[chainID.Key()]; exists {
-		if chain.Put(validatorID, requestID, containerID, container) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		if chain.Put(validatorID, requestID, containerID, container) &&
+			!sr.timeouts.Cancel(validatorID, chainID, requestID) &&
+			requestID != constants.GossipMsgRequestID &&
+			requestID != constants.GossipTxRequestID {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
 		}
-	} else if requestID == constants.GossipMsgRequestID {
+	} else if requestID == constants.GossipMsgRequestID || requestID == constants.GossipTxRequestID {
 		sr.log.Verbo("Gossiped Put(%s, %s, %d, %s) dropped due to unknown chain. Container:",
 			validatorID, chainID, requestID, containerID, formatting.DumpBytes{Bytes: container},
 		)
@@ -270,8 +278,13 @@ func (sr *ChainRouter) GetFailed(validatorID ids.ShortID, chainID ids.ID, reques
 	sr.lock.RLock()
 	defer sr.lock.RUnlock()
 
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	// If the request is still outstanding, it failed to be sent rather than
+	// timing out
+	outstanding := sr.timeouts.Cancel(validatorID, chainID, requestID)
 	if chain, exists := sr.chains[chainID.Key()]; exists {
+		if !outstanding && !validatorID.Equals(chain.ctx.NodeID) {
+			chain.misbehavior.Record(validatorID, common.FailedGet)
+		}
 		chain.GetFailed(validatorID, requestID)
 	} else {
 		sr.log.Error("GetFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
@@ -313,8 +326,9 @@ func (sr *ChainRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID
 
 	// Cancel timeout we set when sent the message asking for these Chits
 	if chain, exists := sr.chains[chainID.Key()]; exists {
-		if chain.Chits(validatorID, requestID, votes) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		if chain.Chits(validatorID, requestID, votes) && !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
 		}
 	} else {
 		sr.log.Debug("Chits(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, votes)
@@ -335,6 +349,103 @@ func (sr *ChainRouter) QueryFailed(validatorID ids.ShortID, chainID ids.ID, requ
 	}
 }
 
+// GetStateChunk routes an incoming GetStateChunk request from the validator
+// with ID [validatorID] to the consensus engine working on the chain with ID
+// [chainID]
+func (sr *ChainRouter) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, index uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateChunk(validatorID, requestID, deadline, containerID, index)
+	} else {
+		sr.log.Debug("GetStateChunk(%s, %s, %d, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID, containerID, index)
+	}
+}
+
+// StateChunk routes an incoming StateChunk message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to a GetStateChunk message from this node,
+	// and when we sent that message we set a timeout. Since we got a response,
+	// cancel the timeout.
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		if chain.StateChunk(validatorID, requestID, chunk) && !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
+		}
+	} else {
+		sr.log.Debug("StateChunk(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID, len(chunk))
+	}
+}
+
+// GetStateChunkFailed routes an incoming GetStateChunkFailed message from the
+// validator with ID [validatorID] to the consensus engine working on the chain
+// with ID [chainID]
+func (sr *ChainRouter) GetStateChunkFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateChunkFailed(validatorID, requestID)
+	} else {
+		sr.log.Error("GetStateChunkFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// GetStateSummary routes an incoming GetStateSummary request from the
+// validator with ID [validatorID] to the consensus engine working on the chain
+// with ID [chainID]
+func (sr *ChainRouter) GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateSummary(validatorID, requestID, deadline)
+	} else {
+		sr.log.Debug("GetStateSummary(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// StateSummary routes an incoming StateSummary message from the validator with
+// ID [validatorID] to the consensus engine working on the chain with ID
+// [chainID]
+func (sr *ChainRouter) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to a GetStateSummary message from this
+	// node, and when we sent that message we set a timeout. Since we got a
+	// response, cancel the timeout.
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		if chain.StateSummary(validatorID, requestID, summary) && !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+			// there was no outstanding request for this response
+			chain.misbehavior.Record(validatorID, common.LateResponse)
+		}
+	} else {
+		sr.log.Debug("StateSummary(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID, len(summary))
+	}
+}
+
+// GetStateSummaryFailed routes an incoming GetStateSummaryFailed message from
+// the validator with ID [validatorID] to the consensus engine working on the
+// chain with ID [chainID]
+func (sr *ChainRouter) GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateSummaryFailed(validatorID, requestID)
+	} else {
+		sr.log.Error("GetStateSummaryFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
 // Shutdown shuts down this router
 func (sr *ChainRouter) Shutdown() {
 	sr.lock.Lock()
@@ -143,3 +143,68 @@ func TestShutdownTimesOut(t *testing.T) {
 	case <-shutdownFinished:
 	}
 }
+
+func TestGetFailedRecordsTimeouts(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout:    time.Hour,
+		MinimumTimeout:    time.Hour,
+		MaximumTimeout:    time.Hour,
+		TimeoutMultiplier: 1.1,
+		TimeoutReduction:  time.Millisecond,
+		Namespace:         "",
+		Registerer:        prometheus.NewRegistry(),
+	})
+
+	chainRouter := ChainRouter{}
+	chainRouter.Initialize(logging.NoLog{}, &tm, time.Hour, time.Second)
+
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	vdrs := validators.NewSet()
+	handler := &Handler{}
+	handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		1,
+		throttler.DefaultMaxNonStakerPendingMsgs,
+		throttler.DefaultStakerPortion,
+		throttler.DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	ledger := &common.MisbehaviorLedger{}
+	if err := ledger.Initialize(common.MisbehaviorConfig{}, vdrs, logging.NoLog{}, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	handler.SetMisbehaviorLedger(ledger)
+	chainRouter.AddChain(handler)
+
+	ctx := engine.Context()
+	vdr := ids.GenerateTestShortID()
+
+	// A request that is still outstanding failed to be sent
+	tm.Register(vdr, ctx.ChainID, 1, func() {})
+	chainRouter.GetFailed(vdr, ctx.ChainID, 1)
+	// Requests to this node always fail
+	chainRouter.GetFailed(ctx.NodeID, ctx.ChainID, 2)
+	if offenders := ledger.Offenders(); len(offenders) != 0 {
+		t.Fatalf("requests that didn't time out shouldn't be recorded")
+	}
+
+	// A request that is no longer outstanding timed out
+	chainRouter.GetFailed(vdr, ctx.ChainID, 3)
+	offenders := ledger.Offenders()
+	if len(offenders) != 1 {
+		t.Fatalf("expected 1 offender, got %d", len(offenders))
+	}
+	if !offenders[0].NodeID.Equals(vdr) {
+		t.Fatalf("wrong offender")
+	}
+	if count := offenders[0].Count(common.FailedGet); count != 1 {
+		t.Fatalf("expected 1 failed get, got %d", count)
+	}
+}
@@ -102,6 +102,9 @@ type Handler struct {
 	ctx    *snow.Context
 	engine common.Engine
 
+	// Records the misbehavior of validators noticed by the router. May be nil.
+	misbehavior *common.MisbehaviorLedger
+
 	toClose func()
 	closing bool
 }
@@ -171,6 +174,14 @@ func (h *Handler) Engine() common.Engine { return h.engine }
 // SetEngine sets the engine for this handler to dispatch to
 func (h *Handler) SetEngine(engine common.Engine) { h.engine = engine }
 
+// MisbehaviorLedger returns the ledger the misbehavior of validators is
+// recorded in. May be nil.
+func (h *Handler) MisbehaviorLedger() *common.MisbehaviorLedger { return h.misbehavior }
+
+// SetMisbehaviorLedger sets the ledger the misbehavior of validators noticed by
+// the router is recorded in
+func (h *Handler) SetMisbehaviorLedger(ledger *common.MisbehaviorLedger) { h.misbehavior = ledger }
+
 // Dispatch waits for incoming messages from the network
 // and, when they arrive, sends them to the consensus engine
 func (h *Handler) Dispatch() {
@@ -438,6 +449,76 @@ func (h *Handler) QueryFailed(validatorID ids.ShortID, requestID uint32) {
 	})
 }
 
+// GetStateChunk passes a GetStateChunk message received from the network to
+// the consensus engine.
+func (h *Handler) GetStateChunk(validatorID ids.ShortID, requestID uint32, deadline time.Time, containerID ids.ID, index uint32) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: getStateChunkMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		containerID: containerID,
+		index:       index,
+		received:    h.clock.Time(),
+	})
+}
+
+// StateChunk passes a StateChunk message received from the network to the
+// consensus engine.
+func (h *Handler) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: stateChunkMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   chunk,
+		received:    h.clock.Time(),
+	})
+}
+
+// GetStateChunkFailed passes a GetStateChunkFailed message to the consensus
+// engine.
+func (h *Handler) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: getStateChunkFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
+// GetStateSummary passes a GetStateSummary message received from the network
+// to the consensus engine.
+func (h *Handler) GetStateSummary(validatorID ids.ShortID, requestID uint32, deadline time.Time) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: getStateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		received:    h.clock.Time(),
+	})
+}
+
+// StateSummary passes a StateSummary message received from the network to the
+// consensus engine.
+func (h *Handler) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: stateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   summary,
+		received:    h.clock.Time(),
+	})
+}
+
+// GetStateSummaryFailed passes a GetStateSummaryFailed message to the consensus
+// engine.
+func (h *Handler) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: getStateSummaryFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
 // Gossip passes a gossip request to the consensus engine
 func (h *Handler) Gossip() {
 	h.sendReliableMsg(message{
@@ -547,6 +628,30 @@ func (h *Handler) handleValidatorMsg(msg message, startTime time.Time) error {
 		err = h.engine.Chits(msg.validatorID, msg.requestID, msg.containerIDs)
 		timeConsumed = h.clock.Time().Sub(startTime)
 		h.chits.Observe(float64(timeConsumed.Nanoseconds()))
+	case getStateChunkMsg:
+		err = h.engine.GetStateChunk(msg.validatorID, msg.requestID, msg.containerID, msg.index)
+		timeConsumed = h.clock.Time().Sub(startTime)
+		h.getStateChunk.Observe(float64(timeConsumed.Nanoseconds()))
+	case stateChunkMsg:
+		err = h.engine.StateChunk(msg.validatorID, msg.requestID, msg.container)
+		timeConsumed = h.clock.Time().Sub(startTime)
+		h.stateChunk.Observe(float64(timeConsumed.Nanoseconds()))
+	case getStateChunkFailedMsg:
+		err = h.engine.GetStateChunkFailed(msg.validatorID, msg.requestID)
+		timeConsumed = h.clock.Time().Sub(startTime)
+		h.getStateChunkFailed.Observe(float64(timeConsumed.Nanoseconds()))
+	case getStateSummaryMsg:
+		err = h.engine.GetStateSummary(msg.validatorID, msg.requestID)
+		timeConsumed = h.clock.Time().Sub(startTime)
+		h.getStateSummary.Observe(float64(timeConsumed.Nanoseconds()))
+	case stateSummaryMsg:
+		err = h.engine.StateSummary(msg.validatorID, msg.requestID, msg.container)
+		timeConsumed = h.clock.Time().Sub(startTime)
+		h.stateSummary.Observe(float64(timeConsumed.Nanoseconds()))
+	case getStateSummaryFailedMsg:
+		err = h.engine.GetStateSummaryFailed(msg.validatorID, msg.requestID)
+		timeConsumed = h.clock.Time().Sub(startTime)
+		h.getStateSummaryFailed.Observe(float64(timeConsumed.Nanoseconds()))
 	}
 
 	h.serviceQueue.UtilizeCPU(msg.validatorID, timeConsumed)
@@ -35,6 +35,12 @@ const (
 	getAncestorsMsg
 	multiPutMsg
 	getAncestorsFailedMsg
+	getStateChunkMsg
+	stateChunkMsg
+	getStateChunkFailedMsg
+	getStateSummaryMsg
+	stateSummaryMsg
+	getStateSummaryFailedMsg
 )
 
 type message struct {
@@ -45,6 +51,7 @@ type message struct {
 	container    []byte
 	containers   [][]byte
 	containerIDs ids.Set
+	index        uint32
 	notification common.Message
 	received     time.Time // Time this message was received
 	deadline     time.Time // Time this message must be responded to
@@ -52,6 +59,7 @@ type message struct {
 
 func (m message) IsPeriodic() bool {
 	return m.requestID == constants.GossipMsgRequestID ||
+		m.requestID == constants.GossipTxRequestID ||
 		m.messageType == gossipMsg
 }
 
@@ -111,6 +119,18 @@ func (t msgType) String() string {
 		return "Notify Message"
 	case gossipMsg:
 		return "Gossip Message"
+	case getStateChunkMsg:
+		return "Get State Chunk Message"
+	case stateChunkMsg:
+		return "State Chunk Message"
+	case getStateChunkFailedMsg:
+		return "Get State Chunk Failed Message"
+	case getStateSummaryMsg:
+		return "Get State Summary Message"
+	case stateSummaryMsg:
+		return "State Summary Message"
+	case getStateSummaryFailedMsg:
+		return "Get State Summary Failed Message"
 	default:
 		return fmt.Sprintf("Unknown Message Type: %d", t)
 	}
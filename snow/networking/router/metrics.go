@@ -41,6 +41,8 @@ type metrics struct {
 	getAncestors, multiPut, getAncestorsFailed,
 	get, put, getFailed,
 	pushQuery, pullQuery, chits, queryFailed,
+	getStateChunk, stateChunk, getStateChunkFailed,
+	getStateSummary, stateSummary, getStateSummaryFailed,
 	notify,
 	gossip,
 	cpu,
@@ -106,6 +108,12 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 	m.pullQuery = initHistogram(namespace, "pull_query", registerer, &errs)
 	m.chits = initHistogram(namespace, "chits", registerer, &errs)
 	m.queryFailed = initHistogram(namespace, "query_failed", registerer, &errs)
+	m.getStateChunk = initHistogram(namespace, "get_state_chunk", registerer, &errs)
+	m.stateChunk = initHistogram(namespace, "state_chunk", registerer, &errs)
+	m.getStateChunkFailed = initHistogram(namespace, "get_state_chunk_failed", registerer, &errs)
+	m.getStateSummary = initHistogram(namespace, "get_state_summary", registerer, &errs)
+	m.stateSummary = initHistogram(namespace, "state_summary", registerer, &errs)
+	m.getStateSummaryFailed = initHistogram(namespace, "get_state_summary_failed", registerer, &errs)
 	m.notify = initHistogram(namespace, "notify", registerer, &errs)
 	m.gossip = initHistogram(namespace, "gossip", registerer, &errs)
 
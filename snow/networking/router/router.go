@@ -42,6 +42,10 @@ type ExternalRouter interface {
 	PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte)
 	PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
+	GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, index uint32)
+	StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
+	GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time)
+	StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
 }
 
 // InternalRouter deals with messages internal to this node
@@ -51,4 +55,6 @@ type InternalRouter interface {
 	GetFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateChunkFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 }
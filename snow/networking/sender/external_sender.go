@@ -28,5 +28,11 @@ type ExternalSender interface {
 	PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
 
+	GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, index uint32)
+	StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
+	GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time)
+	StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+
 	Gossip(chainID ids.ID, containerID ids.ID, container []byte)
+	GossipTx(chainID ids.ID, txID ids.ID, tx []byte)
 }
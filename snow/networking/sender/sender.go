@@ -218,8 +218,67 @@ func (s *Sender) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set)
 	}
 }
 
+// GetStateChunk sends a GetStateChunk message
+func (s *Sender) GetStateChunk(validatorID ids.ShortID, requestID uint32, containerID ids.ID, index uint32) {
+	s.ctx.Log.Verbo("Sending GetStateChunk to validator %s. RequestID: %d. ContainerID: %s. Index: %d", validatorID, requestID, containerID, index)
+	// Sending a GetStateChunk to myself will always fail
+	if validatorID.Equals(s.ctx.NodeID) {
+		go s.router.GetStateChunkFailed(validatorID, s.ctx.ChainID, requestID)
+		return
+	}
+
+	deadline := s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.router.GetStateChunkFailed(validatorID, s.ctx.ChainID, requestID)
+	})
+	s.sender.GetStateChunk(validatorID, s.ctx.ChainID, requestID, deadline, containerID, index)
+}
+
+// StateChunk sends a StateChunk message to the consensus engine running on the
+// specified chain on the specified validator.
+func (s *Sender) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) {
+	s.ctx.Log.Verbo("Sending StateChunk to validator %s. RequestID: %d. Size: %d", validatorID, requestID, len(chunk))
+	s.sender.StateChunk(validatorID, s.ctx.ChainID, requestID, chunk)
+}
+
+// GetStateSummary sends a GetStateSummary message
+func (s *Sender) GetStateSummary(validatorIDs ids.ShortSet, requestID uint32) {
+	currentDeadline := time.Time{}
+	for _, validatorID := range validatorIDs.List() {
+		vID := validatorID
+		deadline := s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.router.GetStateSummaryFailed(vID, s.ctx.ChainID, requestID)
+		})
+		if deadline.After(currentDeadline) {
+			currentDeadline = deadline
+		}
+	}
+
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		go s.router.GetStateSummary(s.ctx.NodeID, s.ctx.ChainID, requestID, currentDeadline)
+	}
+
+	s.sender.GetStateSummary(validatorIDs, s.ctx.ChainID, requestID, currentDeadline)
+}
+
+// StateSummary sends a StateSummary message
+func (s *Sender) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	s.ctx.Log.Verbo("Sending StateSummary to validator %s. RequestID: %d. Size: %d", validatorID, requestID, len(summary))
+	if validatorID.Equals(s.ctx.NodeID) {
+		go s.router.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+	} else {
+		s.sender.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+	}
+}
+
 // Gossip the provided container
 func (s *Sender) Gossip(containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Gossiping %s", containerID)
 	s.sender.Gossip(s.ctx.ChainID, containerID, container)
 }
+
+// GossipTx gossips the provided unconfirmed transaction
+func (s *Sender) GossipTx(txID ids.ID, tx []byte) {
+	s.ctx.Log.Verbo("Gossiping tx %s", txID)
+	s.sender.GossipTx(s.ctx.ChainID, txID, tx)
+}
@@ -20,7 +20,9 @@ type ExternalSenderTest struct {
 	CantGetAncestors, CantMultiPut,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits,
-	CantGossip bool
+	CantGetStateChunk, CantStateChunk,
+	CantGetStateSummary, CantStateSummary,
+	CantGossip, CantGossipTx bool
 
 	GetAcceptedFrontierF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time)
 	AcceptedFrontierF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set)
@@ -38,7 +40,14 @@ type ExternalSenderTest struct {
 	PullQueryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	ChitsF     func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
 
-	GossipF func(chainID ids.ID, containerID ids.ID, container []byte)
+	GetStateChunkF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, index uint32)
+	StateChunkF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
+
+	GetStateSummaryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time)
+	StateSummaryF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+
+	GossipF   func(chainID ids.ID, containerID ids.ID, container []byte)
+	GossipTxF func(chainID ids.ID, txID ids.ID, tx []byte)
 }
 
 // Default set the default callable value to [cant]
@@ -59,7 +68,13 @@ func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantPushQuery = cant
 	s.CantChits = cant
 
+	s.CantGetStateChunk = cant
+	s.CantStateChunk = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+
 	s.CantGossip = cant
+	s.CantGossipTx = cant
 }
 
 // GetAcceptedFrontier calls GetAcceptedFrontierF if it was initialized. If it
@@ -205,6 +220,58 @@ func (s *ExternalSenderTest) Chits(vdr ids.ShortID, chainID ids.ID, requestID ui
 	}
 }
 
+// GetStateChunk calls GetStateChunkF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateChunk(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, blkID ids.ID, index uint32) {
+	if s.GetStateChunkF != nil {
+		s.GetStateChunkF(vdr, chainID, requestID, deadline, blkID, index)
+	} else if s.CantGetStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateChunk")
+	} else if s.CantGetStateChunk && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// StateChunk calls StateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) StateChunk(vdr ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	if s.StateChunkF != nil {
+		s.StateChunkF(vdr, chainID, requestID, chunk)
+	} else if s.CantStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateChunk")
+	} else if s.CantStateChunk && s.B != nil {
+		s.B.Fatalf("Unexpectedly called StateChunk")
+	}
+}
+
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateSummary(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(vdrs, chainID, requestID, deadline)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	} else if s.CantGetStateSummary && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) StateSummary(vdr ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(vdr, chainID, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	} else if s.CantStateSummary && s.B != nil {
+		s.B.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
 // Gossip calls GossipF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
@@ -217,3 +284,16 @@ func (s *ExternalSenderTest) Gossip(chainID ids.ID, containerID ids.ID, containe
 		s.B.Fatalf("Unexpectedly called Gossip")
 	}
 }
+
+// GossipTx calls GossipTxF if it was initialized. If it wasn't initialized and
+// this function shouldn't be called and testing was initialized, then testing
+// will fail.
+func (s *ExternalSenderTest) GossipTx(chainID ids.ID, txID ids.ID, tx []byte) {
+	if s.GossipTxF != nil {
+		s.GossipTxF(chainID, txID, tx)
+	} else if s.CantGossipTx && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GossipTx")
+	} else if s.CantGossipTx && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GossipTx")
+	}
+}
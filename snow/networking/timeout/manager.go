@@ -29,9 +29,10 @@ func (m *Manager) Register(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	return m.tm.Put(createRequestID(validatorID, chainID, requestID), timeout)
 }
 
-// Cancel request timeout with the specified parameters.
-func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
-	m.tm.Remove(createRequestID(validatorID, chainID, requestID))
+// Cancel request timeout with the specified parameters. Returns false if there
+// was no such outstanding request, because it timed out or was never registered.
+func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint32) bool {
+	return m.tm.Remove(createRequestID(validatorID, chainID, requestID))
 }
 
 func createRequestID(validatorID ids.ShortID, chainID ids.ID, requestID uint32) ids.ID {
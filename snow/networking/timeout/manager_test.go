@@ -55,7 +55,9 @@ func TestManagerCancel(t *testing.T) {
 
 	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0, func() { *fired = true })
 
-	manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0)
+	if !manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0) {
+		t.Fatalf("Should have cancelled an outstanding request")
+	}
 
 	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 1, wg.Done)
 
@@ -64,4 +66,7 @@ func TestManagerCancel(t *testing.T) {
 	if *fired {
 		t.Fatalf("Should have cancelled the function")
 	}
+	if manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 1) {
+		t.Fatalf("Shouldn't have cancelled a request that timed out")
+	}
 }
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// State allows the lookup of the validator set of a subnet at a given height
+// of the P-Chain
+type State interface {
+	// GetCurrentHeight returns the height of the last accepted P-Chain block
+	GetCurrentHeight() (uint64, error)
+
+	// GetValidatorSet returns the validators of subnet [subnetID], and their
+	// weights, once the P-Chain block at [height] was accepted
+	GetValidatorSet(height uint64, subnetID ids.ID) (Set, error)
+}
+
+// NewLockedState returns a State that holds [lock] during each call to [s]
+func NewLockedState(lock sync.Locker, s State) State {
+	return &lockedState{
+		lock: lock,
+		s:    s,
+	}
+}
+
+// lockedState implements State
+type lockedState struct {
+	lock sync.Locker
+	s    State
+}
+
+// GetCurrentHeight implements the State interface
+func (s *lockedState) GetCurrentHeight() (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetCurrentHeight()
+}
+
+// GetValidatorSet implements the State interface
+func (s *lockedState) GetValidatorSet(height uint64, subnetID ids.ID) (Set, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetValidatorSet(height, subnetID)
+}
@@ -12,4 +12,8 @@ const (
 	// Request ID used when sending a Put message to gossip an accepted container
 	// (ie not sent in response to a Get)
 	GossipMsgRequestID = math.MaxUint32
+
+	// Request ID used when sending a Put message to gossip an unconfirmed
+	// transaction that should be added to the recipient's mempool
+	GossipTxRequestID = math.MaxUint32 - 1
 )
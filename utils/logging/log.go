@@ -273,6 +273,21 @@ func (l *Log) stopAndExit(exit func()) {
 // RecoverAndExit ...
 func (l *Log) RecoverAndExit(f, exit func()) { defer l.stopAndExit(exit); f() }
 
+// GetLevel implements the Logger interface
+func (l *Log) GetLevel() Level {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	level := Off
+	if !l.config.DisableLogging && l.config.LogLevel > level {
+		level = l.config.LogLevel
+	}
+	if !l.config.DisableDisplaying && l.config.DisplayLevel > level {
+		level = l.config.DisplayLevel
+	}
+	return level
+}
+
 // SetLogLevel ...
 func (l *Log) SetLogLevel(lvl Level) {
 	l.configLock.Lock()
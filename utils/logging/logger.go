@@ -53,6 +53,10 @@ type Logger interface {
 	// executes the desired exit function
 	RecoverAndExit(f, exit func())
 
+	// Returns the most detailed level that is either logged or displayed. This
+	// allows skipping the formatting of arguments that are expensive to
+	// compute.
+	GetLevel() Level
 	SetLogLevel(Level)
 	SetDisplayLevel(Level)
 	SetPrefix(string)
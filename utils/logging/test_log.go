@@ -59,6 +59,9 @@ func (NoLog) RecoverAndExit(f, exit func()) { defer exit(); f() }
 // Stop ...
 func (NoLog) Stop() {}
 
+// GetLevel ...
+func (NoLog) GetLevel() Level { return Off }
+
 // SetLogLevel ...
 func (NoLog) SetLogLevel(Level) {}
 
@@ -108,14 +108,15 @@ func (tm *AdaptiveTimeoutManager) Put(id ids.ID, handler func()) time.Time {
 	return tm.put(id, handler)
 }
 
-// Remove the item that no longer needs to be there.
-func (tm *AdaptiveTimeoutManager) Remove(id ids.ID) {
+// Remove the item that no longer needs to be there. Returns true if the item
+// was outstanding.
+func (tm *AdaptiveTimeoutManager) Remove(id ids.ID) bool {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
 
 	currentTime := time.Now()
 
-	tm.remove(id, currentTime)
+	return tm.remove(id, currentTime)
 }
 
 // Timeout registers a timeout
@@ -160,11 +161,11 @@ func (tm *AdaptiveTimeoutManager) put(id ids.ID, handler func()) time.Time {
 	return timeout.deadline
 }
 
-func (tm *AdaptiveTimeoutManager) remove(id ids.ID, currentTime time.Time) {
+func (tm *AdaptiveTimeoutManager) remove(id ids.ID, currentTime time.Time) bool {
 	key := id.Key()
 	timeout, exists := tm.timeoutMap[key]
 	if !exists {
-		return
+		return false
 	}
 
 	if timeout.deadline.Before(currentTime) {
@@ -201,6 +202,7 @@ func (tm *AdaptiveTimeoutManager) remove(id ids.ID, currentTime time.Time) {
 
 	// Remove the timeout from the queue
 	heap.Remove(&tm.timeoutQueue, timeout.index)
+	return true
 }
 
 // Returns true if the head was removed, false otherwise
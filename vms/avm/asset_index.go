@@ -0,0 +1,636 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"errors"
+	"math"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	maxAssetsToFetch  = 1024
+	maxHoldersToFetch = 1024
+)
+
+var (
+	errHolderIndexDisabled = errors.New("the asset holder index is disabled on this node")
+
+	assetsKey           = ids.Empty.Prefix(assetsID)
+	assetIndexStatusKey = ids.Empty.Prefix(assetIndexStatusID)
+)
+
+// AssetIndexStatus describes how much of the history of this chain the asset,
+// NFT and property indices reflect
+type AssetIndexStatus struct {
+	// True if the indices were built from genesis. Otherwise, they were
+	// backfilled from the UTXO set when the node was upgraded. Then, the units
+	// on this chain at the time of the upgrade count as minted, and the NFT
+	// histories start at the upgrade.
+	FromGenesis bool `serialize:"true"`
+	// True if the holder balances are maintained
+	Holders bool `serialize:"true"`
+	// Number of accepted transactions that the indices reflect
+	IndexedTxs uint64 `serialize:"true"`
+}
+
+// AssetSupply tracks how many units of an asset have entered and left this
+// chain. NFTs and property outputs count as one unit each.
+type AssetSupply struct {
+	// Units created by the asset's initial state and by mint operations
+	Minted uint64 `serialize:"true"`
+	// Units destroyed by fees and by burn operations
+	Burned uint64 `serialize:"true"`
+	// Units imported from other chains
+	Imported uint64 `serialize:"true"`
+	// Units exported to other chains
+	Exported uint64 `serialize:"true"`
+	// Number of addresses with a non-zero balance of the asset. Only
+	// maintained when the holder index is enabled.
+	Holders uint64 `serialize:"true"`
+}
+
+// Circulating returns the number of units of the asset currently on this
+// chain.
+func (s *AssetSupply) Circulating() uint64 {
+	in := saturatingAdd(s.Minted, s.Imported)
+	out := saturatingAdd(s.Burned, s.Exported)
+	if out > in {
+		return 0
+	}
+	return in - out
+}
+
+// AssetHolder is an address along with its balance of an asset
+type AssetHolder struct {
+	Address ids.ShortID
+	Balance uint64
+}
+
+// Assets returns a list of asset IDs that have been created on this chain.
+// All returned asset IDs are greater than [start], where ids.Empty is the
+// "least" ID. Returns at most [limit] asset IDs.
+func (s *prefixedState) Assets(start ids.ID, limit int) ([]ids.ID, error) {
+	return s.state.IDs(assetsKey.Bytes(), start.Bytes(), limit)
+}
+
+// AddAsset adds the provided asset ID to the list of created assets
+func (s *prefixedState) AddAsset(assetID ids.ID) error {
+	return s.state.AddID(assetsKey.Bytes(), assetID)
+}
+
+// AssetIndexStatus returns the status of the asset indices. If the indices
+// were never built, database.ErrNotFound is returned.
+func (s *prefixedState) AssetIndexStatus() (*AssetIndexStatus, error) {
+	bytes, err := s.state.DB.Get(assetIndexStatusKey.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	status := &AssetIndexStatus{}
+	if err := s.state.Codec.Unmarshal(bytes, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// SetAssetIndexStatus saves the status of the asset indices
+func (s *prefixedState) SetAssetIndexStatus(status *AssetIndexStatus) error {
+	bytes, err := s.state.Codec.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return s.state.DB.Put(assetIndexStatusKey.Bytes(), bytes)
+}
+
+// AssetSupply returns the supply of the provided asset. If the asset has
+// never been indexed, an empty supply is returned.
+func (s *prefixedState) AssetSupply(assetID ids.ID) (*AssetSupply, error) {
+	supply := &AssetSupply{}
+	bytes, err := s.state.DB.Get(assetID.Prefix(assetSupplyID).Bytes())
+	if err == database.ErrNotFound {
+		return supply, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := s.state.Codec.Unmarshal(bytes, supply); err != nil {
+		return nil, err
+	}
+	return supply, nil
+}
+
+// SetAssetSupply saves the supply of the provided asset
+func (s *prefixedState) SetAssetSupply(assetID ids.ID, supply *AssetSupply) error {
+	bytes, err := s.state.Codec.Marshal(supply)
+	if err != nil {
+		return err
+	}
+	return s.state.DB.Put(assetID.Prefix(assetSupplyID).Bytes(), bytes)
+}
+
+func (s *prefixedState) holdersDB(assetID ids.ID) database.Database {
+	return prefixdb.NewNested(assetID.Prefix(assetHoldersID).Bytes(), s.state.DB)
+}
+
+// HolderBalance returns the indexed balance of [addr] of the provided asset
+func (s *prefixedState) HolderBalance(assetID ids.ID, addr ids.ShortID) (uint64, error) {
+	bytes, err := s.holdersDB(assetID).Get(addr.Bytes())
+	if err == database.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var balance uint64
+	err = s.state.Codec.Unmarshal(bytes, &balance)
+	return balance, err
+}
+
+// SetHolderBalance saves the indexed balance of [addr] of the provided asset.
+// A balance of zero removes [addr] from the holders of the asset.
+func (s *prefixedState) SetHolderBalance(assetID ids.ID, addr ids.ShortID, balance uint64) error {
+	oldBalance, err := s.HolderBalance(assetID, addr)
+	if err != nil {
+		return err
+	}
+
+	ranks := s.holderRanksDB(assetID)
+	if oldBalance != 0 {
+		if err := ranks.Delete(holderRankKey(oldBalance, addr)); err != nil {
+			return err
+		}
+	}
+	db := s.holdersDB(assetID)
+	if balance == 0 {
+		return db.Delete(addr.Bytes())
+	}
+	if err := ranks.Put(holderRankKey(balance, addr), nil); err != nil {
+		return err
+	}
+	bytes, err := s.state.Codec.Marshal(balance)
+	if err != nil {
+		return err
+	}
+	return db.Put(addr.Bytes(), bytes)
+}
+
+// holderRanksDB orders the holders of an asset by descending balance
+func (s *prefixedState) holderRanksDB(assetID ids.ID) database.Database {
+	return prefixdb.NewNested(assetID.Prefix(assetHolderRanksID).Bytes(), s.state.DB)
+}
+
+// holderRankKey returns the key of [addr] in the holder ranks of an asset.
+// Keys of larger balances sort first.
+func holderRankKey(balance uint64, addr ids.ShortID) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+hashing.AddrLen)}
+	p.PackLong(math.MaxUint64 - balance)
+	p.PackFixedBytes(addr.Bytes())
+	return p.Bytes
+}
+
+// TopHolders returns the [limit] addresses with the largest indexed balance of
+// the provided asset, sorted by descending balance.
+func (s *prefixedState) TopHolders(assetID ids.ID, limit int) ([]AssetHolder, error) {
+	iter := s.holderRanksDB(assetID).NewIterator()
+	defer iter.Release()
+
+	holders := []AssetHolder(nil)
+	for len(holders) < limit && iter.Next() {
+		p := wrappers.Packer{Bytes: iter.Key()}
+		balance := math.MaxUint64 - p.UnpackLong()
+		addrBytes := p.UnpackFixedBytes(hashing.AddrLen)
+		if p.Errored() {
+			return nil, p.Err
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, AssetHolder{
+			Address: addr,
+			Balance: balance,
+		})
+	}
+	return holders, iter.Error()
+}
+
+// ClearAssetIndex removes the supply and the holders of the provided asset
+// from the index
+func (s *prefixedState) ClearAssetIndex(assetID ids.ID) error {
+	if err := s.state.DB.Delete(assetID.Prefix(assetSupplyID).Bytes()); err != nil {
+		return err
+	}
+	if err := clearDB(s.holdersDB(assetID)); err != nil {
+		return err
+	}
+	return clearDB(s.holderRanksDB(assetID))
+}
+
+// clearDB deletes every key of [db]
+func clearDB(db database.Database) error {
+	iter := db.NewIterator()
+	keys := [][]byte(nil)
+	for iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assetFlow accumulates the units of a single asset moved by a transaction
+type assetFlow struct {
+	produced, consumed, imported, exported uint64
+
+	// Balance changes of each address referenced by the moved UTXOs
+	credits, debits map[[20]byte]uint64
+}
+
+// assetFlows accumulates the units of every asset moved by a transaction
+type assetFlows map[[32]byte]*assetFlow
+
+func (f assetFlows) get(assetID ids.ID) *assetFlow {
+	key := assetID.Key()
+	flow, ok := f[key]
+	if !ok {
+		flow = &assetFlow{
+			credits: make(map[[20]byte]uint64),
+			debits:  make(map[[20]byte]uint64),
+		}
+		f[key] = flow
+	}
+	return flow
+}
+
+// produce records that [utxo] was created on this chain
+func (f assetFlows) produce(utxo *djtx.UTXO) {
+	units := outputUnits(utxo.Out)
+	flow := f.get(utxo.AssetID())
+	flow.produced = saturatingAdd(flow.produced, units)
+	addBalances(flow.credits, utxo.Out, units)
+}
+
+// consume records that [utxo] was removed from this chain
+func (f assetFlows) consume(utxo *djtx.UTXO) {
+	units := outputUnits(utxo.Out)
+	flow := f.get(utxo.AssetID())
+	flow.consumed = saturatingAdd(flow.consumed, units)
+	addBalances(flow.debits, utxo.Out, units)
+}
+
+func addBalances(balances map[[20]byte]uint64, out verify.State, units uint64) {
+	addressable, ok := out.(djtx.Addressable)
+	if !ok || units == 0 {
+		return
+	}
+	for _, addr := range addressable.Addresses() {
+		addrID, err := ids.ToShortID(addr)
+		if err != nil {
+			continue
+		}
+		key := addrID.Key()
+		balances[key] = saturatingAdd(balances[key], units)
+	}
+}
+
+// outputUnits returns the number of units of its asset that [out] represents.
+// Outputs that only grant minting rights don't represent any units.
+func outputUnits(out verify.State) uint64 {
+	switch out := out.(type) {
+	case djtx.TransferableOut:
+		return out.Amount()
+	case *nftfx.TransferOutput, *propertyfx.OwnedOutput:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// indexTx updates the asset index with the effects of accepting [tx]. This
+// must be called before the UTXOs consumed by [tx] are removed from state.
+func (vm *VM) indexTx(tx *Tx) error {
+	flows := assetFlows{}
+
+	consumed := []*djtx.UTXO(nil)
+	for _, utxoID := range tx.InputUTXOs() {
+		if utxoID.Symbolic() {
+			continue
+		}
+		utxo, err := vm.state.UTXO(utxoID.InputID())
+		if err != nil {
+			return err
+		}
+		flows.consume(utxo)
+		consumed = append(consumed, utxo)
+	}
+	produced := tx.UTXOs()
+	for _, utxo := range produced {
+		flows.produce(utxo)
+	}
+
+	if err := vm.indexNFTs(tx.ID(), consumed, produced); err != nil {
+		return err
+	}
+	if err := vm.indexProperties(consumed, produced); err != nil {
+		return err
+	}
+
+	switch utx := tx.UnsignedTx.(type) {
+	case *CreateAssetTx:
+		if err := vm.state.AddAsset(tx.ID()); err != nil {
+			return err
+		}
+	case *ImportTx:
+		for _, in := range utx.ImportedIns {
+			flow := flows.get(in.AssetID())
+			flow.imported = saturatingAdd(flow.imported, in.In.Amount())
+		}
+	case *ExportTx:
+		for _, out := range utx.ExportedOuts {
+			flow := flows.get(out.AssetID())
+			flow.exported = saturatingAdd(flow.exported, out.Out.Amount())
+		}
+	}
+
+	for key, flow := range flows {
+		if err := vm.indexAssetFlow(ids.NewID(key), flow); err != nil {
+			return err
+		}
+	}
+
+	status, err := vm.state.AssetIndexStatus()
+	if err != nil {
+		return err
+	}
+	status.IndexedTxs++
+	return vm.state.SetAssetIndexStatus(status)
+}
+
+func (vm *VM) indexAssetFlow(assetID ids.ID, flow *assetFlow) error {
+	supply, err := vm.state.AssetSupply(assetID)
+	if err != nil {
+		return err
+	}
+
+	in := saturatingAdd(flow.produced, flow.exported)
+	out := saturatingAdd(flow.consumed, flow.imported)
+	if in > out {
+		supply.Minted = saturatingAdd(supply.Minted, in-out)
+	} else {
+		supply.Burned = saturatingAdd(supply.Burned, out-in)
+	}
+	supply.Imported = saturatingAdd(supply.Imported, flow.imported)
+	supply.Exported = saturatingAdd(supply.Exported, flow.exported)
+
+	if vm.indexHolders {
+		addrs := ids.ShortSet{}
+		for key := range flow.credits {
+			addrs.Add(ids.NewShortID(key))
+		}
+		for key := range flow.debits {
+			addrs.Add(ids.NewShortID(key))
+		}
+		for _, addr := range addrs.List() {
+			key := addr.Key()
+			oldBalance, err := vm.state.HolderBalance(assetID, addr)
+			if err != nil {
+				return err
+			}
+			newBalance := saturatingAdd(oldBalance, flow.credits[key])
+			if debit := flow.debits[key]; debit < newBalance {
+				newBalance -= debit
+			} else {
+				newBalance = 0
+			}
+			if err := vm.state.SetHolderBalance(assetID, addr, newBalance); err != nil {
+				return err
+			}
+			switch {
+			case oldBalance == 0 && newBalance != 0:
+				supply.Holders++
+			case oldBalance != 0 && newBalance == 0 && supply.Holders > 0:
+				supply.Holders--
+			}
+		}
+	}
+
+	return vm.state.SetAssetSupply(assetID, supply)
+}
+
+// initAssetIndex ensures that the asset indices reflect the state of this
+// chain. The indices are backfilled from the UTXO set if this chain was created
+// before they existed, and the holder balances are rebuilt if they weren't
+// maintained while the node was running.
+func (vm *VM) initAssetIndex() error {
+	status, err := vm.state.AssetIndexStatus()
+	switch {
+	case err == database.ErrNotFound:
+		vm.ctx.Log.Info("backfilling the asset index from the UTXO set")
+		return vm.backfillAssetIndex()
+	case err != nil:
+		return err
+	case status.Holders == vm.indexHolders:
+		return nil
+	case vm.indexHolders:
+		vm.ctx.Log.Info("rebuilding the asset holder index from the UTXO set")
+		if err := vm.rebuildHolderIndex(); err != nil {
+			return err
+		}
+	}
+	status.Holders = vm.indexHolders
+	return vm.state.SetAssetIndexStatus(status)
+}
+
+// backfillAssetIndex builds the asset indices from the accepted transactions
+// and the UTXOs of this chain
+func (vm *VM) backfillAssetIndex() error {
+	// Remove anything that was indexed by a previous version of the index
+	if err := vm.clearAssetIndex(); err != nil {
+		return err
+	}
+
+	status := &AssetIndexStatus{Holders: vm.indexHolders}
+	flows := assetFlows{}
+	onTx := func(tx *Tx) error {
+		status.IndexedTxs++
+		if _, ok := tx.UnsignedTx.(*CreateAssetTx); ok {
+			return vm.state.AddAsset(tx.ID())
+		}
+		return nil
+	}
+	onUTXO := func(utxo *djtx.UTXO) error {
+		flows.produce(utxo)
+		if err := vm.backfillNFT(utxo); err != nil {
+			return err
+		}
+		return vm.backfillProperty(utxo)
+	}
+	if err := vm.scanState(onTx, onUTXO); err != nil {
+		return err
+	}
+
+	for key, flow := range flows {
+		if err := vm.indexAssetFlow(ids.NewID(key), flow); err != nil {
+			return err
+		}
+	}
+	return vm.state.SetAssetIndexStatus(status)
+}
+
+// rebuildHolderIndex rebuilds the holder balances of every asset from the
+// UTXOs of this chain
+func (vm *VM) rebuildHolderIndex() error {
+	assetIDs, err := vm.allAssets()
+	if err != nil {
+		return err
+	}
+
+	flows := assetFlows{}
+	for _, assetID := range assetIDs {
+		if err := clearDB(vm.state.holdersDB(assetID)); err != nil {
+			return err
+		}
+		if err := clearDB(vm.state.holderRanksDB(assetID)); err != nil {
+			return err
+		}
+		// Make sure the number of holders is reset even if the asset has no
+		// UTXOs left
+		flows.get(assetID)
+	}
+
+	onUTXO := func(utxo *djtx.UTXO) error {
+		addBalances(flows.get(utxo.AssetID()).credits, utxo.Out, outputUnits(utxo.Out))
+		return nil
+	}
+	if err := vm.scanState(nil, onUTXO); err != nil {
+		return err
+	}
+
+	for key, flow := range flows {
+		assetID := ids.NewID(key)
+		supply, err := vm.state.AssetSupply(assetID)
+		if err != nil {
+			return err
+		}
+		supply.Holders = 0
+		if err := vm.state.SetAssetSupply(assetID, supply); err != nil {
+			return err
+		}
+		if err := vm.indexAssetFlow(assetID, &assetFlow{credits: flow.credits}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearAssetIndex removes every indexed asset from the asset indices
+func (vm *VM) clearAssetIndex() error {
+	assetIDs, err := vm.allAssets()
+	if err != nil {
+		return err
+	}
+	for _, assetID := range assetIDs {
+		if err := vm.state.ClearAssetIndex(assetID); err != nil {
+			return err
+		}
+		if err := vm.state.ClearNFTIndex(assetID); err != nil {
+			return err
+		}
+		if err := vm.state.ClearPropertyIndex(assetID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allAssets returns every asset in the asset index
+func (vm *VM) allAssets() ([]ids.ID, error) {
+	assetIDs := []ids.ID(nil)
+	start := ids.Empty
+	for {
+		page, err := vm.state.Assets(start, maxAssetsToFetch)
+		if err != nil {
+			return nil, err
+		}
+		assetIDs = append(assetIDs, page...)
+		if len(page) < maxAssetsToFetch {
+			return assetIDs, nil
+		}
+		start = page[len(page)-1]
+	}
+}
+
+// scanState calls [onTx] with every accepted transaction, and [onUTXO] with
+// every UTXO, of this chain. Either may be nil. Transactions and UTXOs are
+// stored under hashes of their IDs, so every entry of the database is checked
+// against the ID of the value it decodes to.
+func (vm *VM) scanState(onTx func(*Tx) error, onUTXO func(*djtx.UTXO) error) error {
+	iter := vm.db.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != hashing.HashLen {
+			continue
+		}
+		value := iter.Value()
+
+		if onUTXO != nil {
+			utxo := &djtx.UTXO{}
+			if err := vm.codec.Unmarshal(value, utxo); err == nil &&
+				bytes.Equal(key, utxo.InputID().Prefix(utxoID).Bytes()) {
+				if err := onUTXO(utxo); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if onTx != nil {
+			id := ids.NewID(hashing.ComputeHash256Array(value))
+			if !bytes.Equal(key, id.Prefix(txID).Bytes()) {
+				continue
+			}
+			if status, err := vm.state.Status(id); err != nil || status != choices.Accepted {
+				continue
+			}
+			tx, err := vm.state.Tx(id)
+			if err != nil {
+				return err
+			}
+			if err := onTx(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return iter.Error()
+}
+
+func saturatingAdd(a, b uint64) uint64 {
+	sum, err := safemath.Add64(a, b)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return sum
+}
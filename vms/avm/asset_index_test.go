@@ -0,0 +1,175 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// acceptTestSend sends [amount] of [assetID] from the address of keys[0] to
+// [to] and accepts the transaction
+func acceptTestSend(t *testing.T, s *Service, assetID ids.ID, amount uint64, to ids.ShortID) {
+	fromStr, err := s.vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	toStr, err := s.vm.FormatLocalAddress(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.vm.timer.Cancel()
+	reply := &api.JsonTxID{}
+	if err := s.Send(nil, &SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:  json.Uint64(amount),
+		AssetID: assetID.String(),
+		To:      toStr,
+		From:    []string{fromStr},
+	}, reply); err != nil {
+		t.Fatal(err)
+	}
+	tx := UniqueTx{
+		vm:   s.vm,
+		txID: reply.TxID,
+	}
+	if err := tx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAssetIndexTopHolders(t *testing.T) {
+	_, vm, _, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	assetID := ids.GenerateTestID()
+	addrs := []ids.ShortID{
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+	}
+	assert.NoError(t, vm.state.SetHolderBalance(assetID, addrs[0], 5))
+	assert.NoError(t, vm.state.SetHolderBalance(assetID, addrs[1], 10))
+	assert.NoError(t, vm.state.SetHolderBalance(assetID, addrs[2], 1))
+	assert.NoError(t, vm.state.SetHolderBalance(assetID, addrs[0], 20))
+
+	holders, err := vm.state.TopHolders(assetID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []AssetHolder{
+		{Address: addrs[0], Balance: 20},
+		{Address: addrs[1], Balance: 10},
+	}, holders)
+
+	assert.NoError(t, vm.state.SetHolderBalance(assetID, addrs[0], 0))
+	holders, err = vm.state.TopHolders(assetID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []AssetHolder{
+		{Address: addrs[1], Balance: 10},
+		{Address: addrs[2], Balance: 1},
+	}, holders)
+}
+
+func TestAssetIndexBackfill(t *testing.T) {
+	genesisBytes, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+	acceptTestSend(t, s, assetID, 1000, keys[1].PublicKey().Address())
+
+	status, err := vm.state.AssetIndexStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.FromGenesis)
+	indexedTxs := status.IndexedTxs
+
+	supply, err := vm.state.AssetSupply(assetID)
+	assert.NoError(t, err)
+	holders, err := vm.state.TopHolders(assetID, maxHoldersToFetch)
+	assert.NoError(t, err)
+	assets, err := vm.allAssets()
+	assert.NoError(t, err)
+
+	// Remove the indices, as if this chain was created before they existed
+	assert.NoError(t, vm.clearAssetIndex())
+	assert.NoError(t, clearDB(prefixdb.NewNested(assetsKey.Bytes(), vm.db)))
+	assert.NoError(t, vm.db.Delete(assetIndexStatusKey.Bytes()))
+
+	assert.NoError(t, vm.initAssetIndex())
+
+	status, err = vm.state.AssetIndexStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.FromGenesis)
+	assert.True(t, status.Holders)
+	assert.Equal(t, indexedTxs, status.IndexedTxs)
+
+	backfilledAssets, err := vm.allAssets()
+	assert.NoError(t, err)
+	assert.Equal(t, assets, backfilledAssets)
+
+	backfilledSupply, err := vm.state.AssetSupply(assetID)
+	assert.NoError(t, err)
+	assert.Equal(t, supply.Circulating(), backfilledSupply.Circulating())
+	assert.Equal(t, supply.Circulating(), backfilledSupply.Minted)
+	assert.Equal(t, supply.Holders, backfilledSupply.Holders)
+
+	backfilledHolders, err := vm.state.TopHolders(assetID, maxHoldersToFetch)
+	assert.NoError(t, err)
+	assert.Equal(t, holders, backfilledHolders)
+}
+
+func TestAssetIndexRebuildHolders(t *testing.T) {
+	genesisBytes, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+	addr0 := keys[0].PublicKey().Address()
+	addr1 := keys[1].PublicKey().Address()
+
+	// The holder balances aren't maintained while the holder index is disabled
+	vm.indexHolders = false
+	assert.NoError(t, vm.initAssetIndex())
+	acceptTestSend(t, s, assetID, 1000, addr1)
+
+	holders, err := vm.state.TopHolders(assetID, maxHoldersToFetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []AssetHolder{{Address: addr0, Balance: 300000}}, holders)
+
+	vm.indexHolders = true
+	assert.NoError(t, vm.initAssetIndex())
+
+	status, err := vm.state.AssetIndexStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.FromGenesis)
+	assert.True(t, status.Holders)
+
+	holders, err = vm.state.TopHolders(assetID, maxHoldersToFetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []AssetHolder{
+		{Address: addr0, Balance: 299000},
+		{Address: addr1, Balance: 1000},
+	}, holders)
+
+	supply, err := vm.state.AssetSupply(assetID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), supply.Holders)
+}
@@ -16,11 +16,15 @@ var (
 // Factory ...
 type Factory struct {
 	Fee uint64
+
+	// IndexHolders enables the per-asset index of holder balances
+	IndexHolders bool
 }
 
 // New ...
 func (f *Factory) New(*snow.Context) (interface{}, error) {
 	return &VM{
-		txFee: f.Fee,
+		txFee:        f.Fee,
+		indexHolders: f.IndexHolders,
 	}, nil
 }
@@ -0,0 +1,237 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+const (
+	maxNFTsToFetch = 1024
+)
+
+// NFTTransfer records an NFT being minted to, or transferred to, a set of
+// owners
+type NFTTransfer struct {
+	// ID of the transaction that created the NFT output
+	TxID ids.ID `serialize:"true"`
+	// ID of the created NFT output
+	UTXOID ids.ID `serialize:"true"`
+	// True if the NFT output was created by a mint operation. If the NFT
+	// indices were backfilled, the history of a group starts with the NFT
+	// output that existed at the time, which isn't marked as minted.
+	Minted bool `serialize:"true"`
+	// The owners of the created NFT output
+	Owners secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+func nftCollectionKey(assetID ids.ID) []byte {
+	return assetID.Prefix(nftCollectionID).Bytes()
+}
+
+func nftOwnerKey(addr ids.ShortID) []byte {
+	return ids.NewID(hashing.ComputeHash256Array(addr.Bytes())).Prefix(nftOwnerID).Bytes()
+}
+
+func nftHistoryKey(assetID ids.ID, groupID uint32) []byte {
+	return assetID.Prefix(nftHistoryID).Prefix(uint64(groupID)).Bytes()
+}
+
+// CollectionNFTs returns a list of IDs of the unspent NFT UTXOs of the
+// provided asset. All returned UTXO IDs are greater than [start], where
+// ids.Empty is the "least" ID. Returns at most [limit] UTXO IDs.
+func (s *prefixedState) CollectionNFTs(assetID ids.ID, start ids.ID, limit int) ([]ids.ID, error) {
+	return s.state.IDs(nftCollectionKey(assetID), start.Bytes(), limit)
+}
+
+// OwnedNFTs returns a list of IDs of the unspent NFT UTXOs that reference
+// [addr]. All returned UTXO IDs are greater than [start], where ids.Empty is
+// the "least" ID. Returns at most [limit] UTXO IDs.
+func (s *prefixedState) OwnedNFTs(addr ids.ShortID, start ids.ID, limit int) ([]ids.ID, error) {
+	return s.state.IDs(nftOwnerKey(addr), start.Bytes(), limit)
+}
+
+// AddNFT adds the provided NFT UTXO to the NFT indices
+func (s *prefixedState) AddNFT(utxo *djtx.UTXO, out *nftfx.TransferOutput) error {
+	utxoID := utxo.InputID()
+	if err := s.state.AddID(nftCollectionKey(utxo.AssetID()), utxoID); err != nil {
+		return err
+	}
+	for _, addr := range out.Addrs {
+		if err := s.state.AddID(nftOwnerKey(addr), utxoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveNFT removes the provided NFT UTXO from the NFT indices
+func (s *prefixedState) RemoveNFT(utxo *djtx.UTXO, out *nftfx.TransferOutput) error {
+	utxoID := utxo.InputID()
+	if err := s.state.RemoveID(nftCollectionKey(utxo.AssetID()), utxoID); err != nil {
+		return err
+	}
+	for _, addr := range out.Addrs {
+		if err := s.state.RemoveID(nftOwnerKey(addr), utxoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NFTHistory returns every transfer of the provided NFT group, oldest first
+func (s *prefixedState) NFTHistory(assetID ids.ID, groupID uint32) ([]*NFTTransfer, error) {
+	iter := prefixdb.NewNested(nftHistoryKey(assetID, groupID), s.state.DB).NewIterator()
+	defer iter.Release()
+
+	history := []*NFTTransfer(nil)
+	for iter.Next() {
+		transfer := &NFTTransfer{}
+		if err := s.state.Codec.Unmarshal(iter.Value(), transfer); err != nil {
+			return nil, err
+		}
+		history = append(history, transfer)
+	}
+	return history, iter.Error()
+}
+
+// AddNFTTransfer appends [transfer] to the history of the provided NFT group
+func (s *prefixedState) AddNFTTransfer(assetID ids.ID, groupID uint32, transfer *NFTTransfer) error {
+	historyKey := nftHistoryKey(assetID, groupID)
+
+	// The number of recorded transfers is stored under the history key itself.
+	// It is used as the key of the next transfer so that iteration returns the
+	// transfers in the order they were accepted.
+	numTransfers := uint64(0)
+	switch bytes, err := s.state.DB.Get(historyKey); err {
+	case nil:
+		if err := s.state.Codec.Unmarshal(bytes, &numTransfers); err != nil {
+			return err
+		}
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
+	transferBytes, err := s.state.Codec.Marshal(transfer)
+	if err != nil {
+		return err
+	}
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(numTransfers)
+	if err := prefixdb.NewNested(historyKey, s.state.DB).Put(p.Bytes, transferBytes); err != nil {
+		return err
+	}
+
+	numTransfersBytes, err := s.state.Codec.Marshal(numTransfers + 1)
+	if err != nil {
+		return err
+	}
+	return s.state.DB.Put(historyKey, numTransfersBytes)
+}
+
+// ClearNFTIndex removes the unspent NFT UTXOs of the provided asset, and the
+// histories of their groups, from the NFT indices
+func (s *prefixedState) ClearNFTIndex(assetID ids.ID) error {
+	utxoIDs := []ids.ID(nil)
+	start := ids.Empty
+	for {
+		page, err := s.CollectionNFTs(assetID, start, maxNFTsToFetch)
+		if err != nil {
+			return err
+		}
+		utxoIDs = append(utxoIDs, page...)
+		if len(page) < maxNFTsToFetch {
+			break
+		}
+		start = page[len(page)-1]
+	}
+
+	for _, utxoID := range utxoIDs {
+		utxo, err := s.UTXO(utxoID)
+		if err != nil {
+			return err
+		}
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok {
+			continue
+		}
+		if err := s.RemoveNFT(utxo, out); err != nil {
+			return err
+		}
+		historyKey := nftHistoryKey(assetID, out.GroupID)
+		if err := clearDB(prefixdb.NewNested(historyKey, s.state.DB)); err != nil {
+			return err
+		}
+		if err := s.state.DB.Delete(historyKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillNFT adds [utxo] to the NFT indices, as the first transfer of its
+// group, if it is an NFT
+func (vm *VM) backfillNFT(utxo *djtx.UTXO) error {
+	out, ok := utxo.Out.(*nftfx.TransferOutput)
+	if !ok {
+		return nil
+	}
+	if err := vm.state.AddNFT(utxo, out); err != nil {
+		return err
+	}
+	return vm.state.AddNFTTransfer(utxo.AssetID(), out.GroupID, &NFTTransfer{
+		TxID:   utxo.TxID,
+		UTXOID: utxo.InputID(),
+		Owners: out.OutputOwners,
+	})
+}
+
+// indexNFTs updates the NFT indices with the NFT UTXOs consumed and produced
+// by the transaction [txID].
+func (vm *VM) indexNFTs(txID ids.ID, consumed, produced []*djtx.UTXO) error {
+	// Groups that had an NFT transferred out of them by this transaction
+	transferred := map[[32]byte]map[uint32]bool{}
+	for _, utxo := range consumed {
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok {
+			continue
+		}
+		if err := vm.state.RemoveNFT(utxo, out); err != nil {
+			return err
+		}
+		assetKey := utxo.AssetID().Key()
+		if transferred[assetKey] == nil {
+			transferred[assetKey] = map[uint32]bool{}
+		}
+		transferred[assetKey][out.GroupID] = true
+	}
+
+	for _, utxo := range produced {
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok {
+			continue
+		}
+		if err := vm.state.AddNFT(utxo, out); err != nil {
+			return err
+		}
+		assetID := utxo.AssetID()
+		if err := vm.state.AddNFTTransfer(assetID, out.GroupID, &NFTTransfer{
+			TxID:   txID,
+			UTXOID: utxo.InputID(),
+			Minted: !transferred[assetID.Key()][out.GroupID],
+			Owners: out.OutputOwners,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
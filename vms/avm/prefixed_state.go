@@ -15,6 +15,15 @@ const (
 	utxoID
 	txStatusID
 	dbInitializedID
+	assetSupplyID
+	assetHoldersID
+	assetsID
+	nftCollectionID
+	nftOwnerID
+	nftHistoryID
+	propertyID
+	assetIndexStatusID
+	assetHolderRanksID
 )
 
 var (
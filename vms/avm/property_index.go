@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+)
+
+const (
+	maxPropertiesToFetch = 1024
+)
+
+func propertyKey(assetID ids.ID) []byte { return assetID.Prefix(propertyID).Bytes() }
+
+// Properties returns a list of IDs of the unburned property UTXOs of the
+// provided asset. All returned UTXO IDs are greater than [start], where
+// ids.Empty is the "least" ID. Returns at most [limit] UTXO IDs.
+func (s *prefixedState) Properties(assetID ids.ID, start ids.ID, limit int) ([]ids.ID, error) {
+	return s.state.IDs(propertyKey(assetID), start.Bytes(), limit)
+}
+
+// ClearPropertyIndex removes the unburned property UTXOs of the provided asset
+// from the property index
+func (s *prefixedState) ClearPropertyIndex(assetID ids.ID) error {
+	return clearDB(prefixdb.NewNested(propertyKey(assetID), s.state.DB))
+}
+
+// backfillProperty adds [utxo] to the property index if it is a property
+func (vm *VM) backfillProperty(utxo *djtx.UTXO) error {
+	if _, ok := utxo.Out.(*propertyfx.OwnedOutput); !ok {
+		return nil
+	}
+	return vm.state.state.AddID(propertyKey(utxo.AssetID()), utxo.InputID())
+}
+
+// indexProperties updates the property index with the property UTXOs consumed
+// and produced by a transaction.
+func (vm *VM) indexProperties(consumed, produced []*djtx.UTXO) error {
+	for _, utxo := range consumed {
+		if _, ok := utxo.Out.(*propertyfx.OwnedOutput); !ok {
+			continue
+		}
+		if err := vm.state.state.RemoveID(propertyKey(utxo.AssetID()), utxo.InputID()); err != nil {
+			return err
+		}
+	}
+	for _, utxo := range produced {
+		if _, ok := utxo.Out.(*propertyfx.OwnedOutput); !ok {
+			continue
+		}
+		if err := vm.state.state.AddID(propertyKey(utxo.AssetID()), utxo.InputID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
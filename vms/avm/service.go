@@ -20,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
@@ -28,6 +29,11 @@ import (
 const (
 	// Max number of addresses that can be passed in as argument to GetUTXOs
 	maxGetUTXOsAddrs = 1024
+
+	// Lock statuses that GetUTXOs can filter by
+	lockStatusAll      = "all"
+	lockStatusLocked   = "locked"
+	lockStatusUnlocked = "unlocked"
 )
 
 var (
@@ -42,6 +48,7 @@ var (
 	errInvalidUTXO            = errors.New("invalid utxo")
 	errNilTxID                = errors.New("nil transaction ID")
 	errNoAddresses            = errors.New("no addresses provided")
+	errInvalidLockStatus      = errors.New("lock status must be one of \"all\", \"locked\" or \"unlocked\"")
 )
 
 // Service defines the base service for the asset vm
@@ -131,11 +138,14 @@ type Index struct {
 // If [StartIndex] is omitted, gets all UTXOs.
 // If GetUTXOs is called multiple times, with our without [StartIndex], it is not guaranteed
 // that returned UTXOs are unique. That is, the same UTXO may appear in the response of multiple calls.
+// If [LockStatus] is "locked" or "unlocked", only UTXOs that are, or aren't,
+// currently timelocked are returned. If it is omitted or "all", every UTXO is returned.
 type GetUTXOsArgs struct {
 	Addresses   []string    `json:"addresses"`
 	SourceChain string      `json:"sourceChain"`
 	Limit       json.Uint32 `json:"limit"`
 	StartIndex  Index       `json:"startIndex"`
+	LockStatus  string      `json:"lockStatus"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
@@ -161,6 +171,17 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 		return fmt.Errorf("number of addresses given, %d, exceeds maximum, %d", len(args.Addresses), maxGetUTXOsAddrs)
 	}
 
+	var filter func(*djtx.UTXO) bool
+	switch args.LockStatus {
+	case "", lockStatusAll:
+	case lockStatusLocked:
+		filter = LockedFilter(service.vm.clock.Unix(), true)
+	case lockStatusUnlocked:
+		filter = LockedFilter(service.vm.clock.Unix(), false)
+	default:
+		return errInvalidLockStatus
+	}
+
 	sourceChain := ids.ID{}
 	if args.SourceChain == "" {
 		sourceChain = service.vm.ctx.ChainID
@@ -204,11 +225,12 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 		err       error
 	)
 	if sourceChain.Equals(service.vm.ctx.ChainID) {
-		utxos, endAddr, endUTXOID, err = service.vm.GetUTXOs(
+		utxos, endAddr, endUTXOID, err = service.vm.GetFilteredUTXOs(
 			addrSet,
 			startAddr,
 			startUTXO,
 			int(args.Limit),
+			filter,
 		)
 	} else {
 		utxos, endAddr, endUTXOID, err = service.vm.GetAtomicUTXOs(
@@ -218,6 +240,15 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 			startUTXO,
 			int(args.Limit),
 		)
+		if err == nil && filter != nil {
+			filteredUTXOs := make([]*djtx.UTXO, 0, len(utxos))
+			for _, utxo := range utxos {
+				if filter(utxo) {
+					filteredUTXOs = append(filteredUTXOs, utxo)
+				}
+			}
+			utxos = filteredUTXOs
+		}
 	}
 	if err != nil {
 		return fmt.Errorf("problem retrieving UTXOs: %w", err)
@@ -268,6 +299,11 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 		}
 	}
 
+	return service.describeAsset(assetID, reply)
+}
+
+// describeAsset populates [reply] with the description of the provided asset
+func (service *Service) describeAsset(assetID ids.ID, reply *GetAssetDescriptionReply) error {
 	tx := &UniqueTx{
 		vm:   service.vm,
 		txID: assetID,
@@ -288,6 +324,434 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	return nil
 }
 
+// ListAssetsArgs are arguments for passing into ListAssets requests
+type ListAssetsArgs struct {
+	// If provided, only assets with an ID greater than [StartAssetID] are
+	// returned
+	StartAssetID string `json:"startAssetID"`
+	// Maximum number of assets to return. If 0 or greater than 1024, returns
+	// at most 1024 assets.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// IndexStatusReply describes how much of the history of the chain a reply
+// built from the asset indices reflects
+type IndexStatusReply struct {
+	// False if the indices were backfilled from the UTXO set when the node was
+	// upgraded. Then, the units on the chain at the time of the upgrade count
+	// as minted, and NFT histories start at the upgrade.
+	IndexFromGenesis bool `json:"indexFromGenesis"`
+	// Number of accepted transactions that the indices reflect
+	IndexedTxs json.Uint64 `json:"indexedTxs"`
+}
+
+// indexStatus populates [reply] with the status of the asset indices
+func (service *Service) indexStatus(reply *IndexStatusReply) error {
+	status, err := service.vm.state.AssetIndexStatus()
+	if err != nil {
+		return fmt.Errorf("couldn't get the status of the asset index: %w", err)
+	}
+	reply.IndexFromGenesis = status.FromGenesis
+	reply.IndexedTxs = json.Uint64(status.IndexedTxs)
+	return nil
+}
+
+// ListAssetsReply defines the ListAssets replies returned from the API
+type ListAssetsReply struct {
+	IndexStatusReply
+	// Number of assets returned
+	NumFetched json.Uint64 `json:"numFetched"`
+	// The assets, ordered by asset ID
+	Assets []GetAssetDescriptionReply `json:"assets"`
+	// The ID of the last asset returned. Pass this as [StartAssetID] to get
+	// the next page.
+	EndAssetID string `json:"endAssetID"`
+}
+
+// ListAssets returns the description of every asset created on this chain,
+// paginated by asset ID
+func (service *Service) ListAssets(_ *http.Request, args *ListAssetsArgs, reply *ListAssetsReply) error {
+	service.vm.ctx.Log.Info("AVM: ListAssets called with start %s and limit %d", args.StartAssetID, args.Limit)
+
+	startAssetID := ids.Empty
+	if args.StartAssetID != "" {
+		assetID, err := ids.FromString(args.StartAssetID)
+		if err != nil {
+			return fmt.Errorf("couldn't parse start asset ID %q: %w", args.StartAssetID, err)
+		}
+		startAssetID = assetID
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxAssetsToFetch {
+		limit = maxAssetsToFetch
+	}
+
+	assetIDs, err := service.vm.state.Assets(startAssetID, limit)
+	if err != nil {
+		return fmt.Errorf("couldn't fetch assets: %w", err)
+	}
+
+	reply.Assets = make([]GetAssetDescriptionReply, len(assetIDs))
+	for i, assetID := range assetIDs {
+		if err := service.describeAsset(assetID, &reply.Assets[i]); err != nil {
+			return fmt.Errorf("couldn't describe asset %s: %w", assetID, err)
+		}
+	}
+
+	reply.EndAssetID = startAssetID.String()
+	if len(assetIDs) > 0 {
+		reply.EndAssetID = assetIDs[len(assetIDs)-1].String()
+	}
+	reply.NumFetched = json.Uint64(len(assetIDs))
+	return service.indexStatus(&reply.IndexStatusReply)
+}
+
+// GetAssetSupplyArgs are arguments for passing into GetAssetSupply requests
+type GetAssetSupplyArgs struct {
+	AssetID string `json:"assetID"`
+}
+
+// GetAssetSupplyReply defines the GetAssetSupply replies returned from the API.
+// NFTs and property outputs count as one unit each.
+type GetAssetSupplyReply struct {
+	FormattedAssetID
+	IndexStatusReply
+	// Units currently on this chain
+	Supply json.Uint64 `json:"supply"`
+	// Units created by the asset's initial state and by mint operations
+	Minted json.Uint64 `json:"minted"`
+	// Units destroyed by fees and by burn operations
+	Burned json.Uint64 `json:"burned"`
+	// Units imported from other chains
+	Imported json.Uint64 `json:"imported"`
+	// Units exported to other chains
+	Exported json.Uint64 `json:"exported"`
+}
+
+// GetAssetSupply returns the circulating supply of an asset
+func (service *Service) GetAssetSupply(_ *http.Request, args *GetAssetSupplyArgs, reply *GetAssetSupplyReply) error {
+	service.vm.ctx.Log.Info("AVM: GetAssetSupply called with %s", args.AssetID)
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	supply, err := service.vm.state.AssetSupply(assetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get supply of asset %s: %w", assetID, err)
+	}
+
+	reply.AssetID = assetID
+	reply.Supply = json.Uint64(supply.Circulating())
+	reply.Minted = json.Uint64(supply.Minted)
+	reply.Burned = json.Uint64(supply.Burned)
+	reply.Imported = json.Uint64(supply.Imported)
+	reply.Exported = json.Uint64(supply.Exported)
+	return service.indexStatus(&reply.IndexStatusReply)
+}
+
+// GetAssetHoldersArgs are arguments for passing into GetAssetHolders requests
+type GetAssetHoldersArgs struct {
+	AssetID string `json:"assetID"`
+	// Number of holders to return. If 0 or greater than 1024, returns at most
+	// 1024 holders.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// AddressBalance is an address along with its balance of an asset
+type AddressBalance struct {
+	Address string      `json:"address"`
+	Balance json.Uint64 `json:"balance"`
+}
+
+// GetAssetHoldersReply defines the GetAssetHolders replies returned from the
+// API
+type GetAssetHoldersReply struct {
+	FormattedAssetID
+	IndexStatusReply
+	// Number of addresses with a non-zero balance of the asset
+	NumHolders json.Uint64 `json:"numHolders"`
+	// The addresses with the largest balances, sorted by descending balance
+	Holders []AddressBalance `json:"holders"`
+}
+
+// GetAssetHolders returns the number of holders of an asset along with the
+// addresses holding the most of it. Balances include UTXOs that an address
+// only _partially_ owns (ie is one of several addresses specified in a
+// multi-sig). Requires the holder index to be enabled.
+func (service *Service) GetAssetHolders(_ *http.Request, args *GetAssetHoldersArgs, reply *GetAssetHoldersReply) error {
+	service.vm.ctx.Log.Info("AVM: GetAssetHolders called with %s and limit %d", args.AssetID, args.Limit)
+
+	if !service.vm.indexHolders {
+		return errHolderIndexDisabled
+	}
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	supply, err := service.vm.state.AssetSupply(assetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get holders of asset %s: %w", assetID, err)
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxHoldersToFetch {
+		limit = maxHoldersToFetch
+	}
+	holders, err := service.vm.state.TopHolders(assetID, limit)
+	if err != nil {
+		return fmt.Errorf("couldn't get holders of asset %s: %w", assetID, err)
+	}
+
+	reply.AssetID = assetID
+	reply.NumHolders = json.Uint64(supply.Holders)
+	reply.Holders = make([]AddressBalance, len(holders))
+	for i, holder := range holders {
+		addr, err := service.vm.FormatLocalAddress(holder.Address)
+		if err != nil {
+			return fmt.Errorf("couldn't format address %s: %w", holder.Address, err)
+		}
+		reply.Holders[i] = AddressBalance{
+			Address: addr,
+			Balance: json.Uint64(holder.Balance),
+		}
+	}
+	return service.indexStatus(&reply.IndexStatusReply)
+}
+
+// NFT describes an unspent NFT output
+type NFT struct {
+	UTXOID    string          `json:"utxoID"`
+	AssetID   ids.ID          `json:"assetID"`
+	GroupID   json.Uint32     `json:"groupID"`
+	Payload   formatting.CB58 `json:"payload"`
+	Locktime  json.Uint64     `json:"locktime"`
+	Threshold json.Uint32     `json:"threshold"`
+	Owners    []string        `json:"owners"`
+}
+
+// GetNFTsArgs are arguments for passing into GetNFTs requests
+type GetNFTsArgs struct {
+	// Address that owns the NFTs
+	Owner string `json:"owner"`
+	// If provided, only NFT outputs with an ID greater than [StartUTXOID] are
+	// returned
+	StartUTXOID string `json:"startUTXOID"`
+	// Maximum number of NFTs to return. If 0 or greater than 1024, returns at
+	// most 1024 NFTs.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// GetNFTsReply defines the GetNFTs and GetNFTCollection replies returned from
+// the API
+type GetNFTsReply struct {
+	IndexStatusReply
+	// Number of NFTs returned
+	NumFetched json.Uint64 `json:"numFetched"`
+	// The NFTs, ordered by UTXO ID
+	NFTs []NFT `json:"nfts"`
+	// The ID of the last NFT output returned. Pass this as [StartUTXOID] to
+	// get the next page.
+	EndUTXOID string `json:"endUTXOID"`
+}
+
+// GetNFTs returns the unspent NFT outputs that reference [args.Owner].
+// Note that this includes NFTs that the address only _partially_ owns
+// (ie is one of several addresses specified in a multi-sig)
+func (service *Service) GetNFTs(_ *http.Request, args *GetNFTsArgs, reply *GetNFTsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetNFTs called with owner %s", args.Owner)
+
+	owner, err := service.vm.ParseLocalAddress(args.Owner)
+	if err != nil {
+		return fmt.Errorf("couldn't parse owner %q: %w", args.Owner, err)
+	}
+	start, limit, err := parseNFTPage(args.StartUTXOID, args.Limit)
+	if err != nil {
+		return err
+	}
+	utxoIDs, err := service.vm.state.OwnedNFTs(owner, start, limit)
+	if err != nil {
+		return fmt.Errorf("couldn't get NFTs owned by %s: %w", args.Owner, err)
+	}
+	return service.formatNFTs(start, utxoIDs, reply)
+}
+
+// GetNFTCollectionArgs are arguments for passing into GetNFTCollection
+// requests
+type GetNFTCollectionArgs struct {
+	AssetID string `json:"assetID"`
+	// If provided, only NFT outputs with an ID greater than [StartUTXOID] are
+	// returned
+	StartUTXOID string `json:"startUTXOID"`
+	// Maximum number of NFTs to return. If 0 or greater than 1024, returns at
+	// most 1024 NFTs.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// GetNFTCollection returns the unspent NFT outputs of every group of an NFT
+// asset along with their current owners and payloads
+func (service *Service) GetNFTCollection(_ *http.Request, args *GetNFTCollectionArgs, reply *GetNFTsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetNFTCollection called with %s", args.AssetID)
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	start, limit, err := parseNFTPage(args.StartUTXOID, args.Limit)
+	if err != nil {
+		return err
+	}
+	utxoIDs, err := service.vm.state.CollectionNFTs(assetID, start, limit)
+	if err != nil {
+		return fmt.Errorf("couldn't get NFTs of asset %s: %w", assetID, err)
+	}
+	return service.formatNFTs(start, utxoIDs, reply)
+}
+
+// GetNFTHistoryArgs are arguments for passing into GetNFTHistory requests
+type GetNFTHistoryArgs struct {
+	AssetID string      `json:"assetID"`
+	GroupID json.Uint32 `json:"groupID"`
+}
+
+// NFTTransferReply describes an NFT being minted to, or transferred to, a set
+// of owners
+type NFTTransferReply struct {
+	TxID      ids.ID      `json:"txID"`
+	UTXOID    ids.ID      `json:"utxoID"`
+	Minted    bool        `json:"minted"`
+	Locktime  json.Uint64 `json:"locktime"`
+	Threshold json.Uint32 `json:"threshold"`
+	Owners    []string    `json:"owners"`
+}
+
+// GetNFTHistoryReply defines the GetNFTHistory replies returned from the API
+type GetNFTHistoryReply struct {
+	IndexStatusReply
+	// The transfers of the NFT group, oldest first
+	History []NFTTransferReply `json:"history"`
+}
+
+// GetNFTHistory returns every mint and transfer of an NFT group. If the NFT
+// indices were backfilled when the node was upgraded, [IndexFromGenesis] is
+// false and the history starts at the upgrade.
+func (service *Service) GetNFTHistory(_ *http.Request, args *GetNFTHistoryArgs, reply *GetNFTHistoryReply) error {
+	service.vm.ctx.Log.Info("AVM: GetNFTHistory called with %s and group %d", args.AssetID, args.GroupID)
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	history, err := service.vm.state.NFTHistory(assetID, uint32(args.GroupID))
+	if err != nil {
+		return fmt.Errorf("couldn't get history of asset %s: %w", assetID, err)
+	}
+
+	reply.History = make([]NFTTransferReply, len(history))
+	for i, transfer := range history {
+		owners, err := service.formatAddresses(transfer.Owners.Addrs)
+		if err != nil {
+			return err
+		}
+		reply.History[i] = NFTTransferReply{
+			TxID:      transfer.TxID,
+			UTXOID:    transfer.UTXOID,
+			Minted:    transfer.Minted,
+			Locktime:  json.Uint64(transfer.Owners.Locktime),
+			Threshold: json.Uint32(transfer.Owners.Threshold),
+			Owners:    owners,
+		}
+	}
+	return service.indexStatus(&reply.IndexStatusReply)
+}
+
+func parseNFTPage(startUTXOID string, limit json.Uint32) (ids.ID, int, error) {
+	start := ids.Empty
+	if startUTXOID != "" {
+		utxoID, err := ids.FromString(startUTXOID)
+		if err != nil {
+			return ids.ID{}, 0, fmt.Errorf("couldn't parse start UTXO ID %q: %w", startUTXOID, err)
+		}
+		start = utxoID
+	}
+	if limit == 0 || limit > maxNFTsToFetch {
+		limit = maxNFTsToFetch
+	}
+	return start, int(limit), nil
+}
+
+func (service *Service) formatNFTs(start ids.ID, utxoIDs []ids.ID, reply *GetNFTsReply) error {
+	reply.NFTs = make([]NFT, len(utxoIDs))
+	for i, utxoID := range utxoIDs {
+		utxo, err := service.vm.state.UTXO(utxoID)
+		if err != nil {
+			return fmt.Errorf("couldn't get NFT UTXO %s: %w", utxoID, err)
+		}
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok {
+			return fmt.Errorf("UTXO %s is not an NFT but %T", utxoID, utxo.Out)
+		}
+		owners, err := service.formatAddresses(out.Addrs)
+		if err != nil {
+			return err
+		}
+		reply.NFTs[i] = NFT{
+			UTXOID:    utxoID.String(),
+			AssetID:   utxo.AssetID(),
+			GroupID:   json.Uint32(out.GroupID),
+			Payload:   formatting.CB58{Bytes: out.Payload},
+			Locktime:  json.Uint64(out.Locktime),
+			Threshold: json.Uint32(out.Threshold),
+			Owners:    owners,
+		}
+	}
+
+	reply.EndUTXOID = start.String()
+	if len(utxoIDs) > 0 {
+		reply.EndUTXOID = utxoIDs[len(utxoIDs)-1].String()
+	}
+	reply.NumFetched = json.Uint64(len(utxoIDs))
+	return service.indexStatus(&reply.IndexStatusReply)
+}
+
+func (service *Service) formatAddresses(addrs []ids.ShortID) ([]string, error) {
+	addrStrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		addrStr, err := service.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't format address %s: %w", addr, err)
+		}
+		addrStrs[i] = addrStr
+	}
+	return addrStrs, nil
+}
+
+// lookupAssetID parses [asset] as either an asset alias or an asset ID and
+// ensures that it references an asset created on this chain
+func (service *Service) lookupAssetID(asset string) (ids.ID, error) {
+	assetID, err := service.vm.Lookup(asset)
+	if err != nil {
+		assetID, err = ids.FromString(asset)
+		if err != nil {
+			return ids.ID{}, fmt.Errorf("couldn't find asset with ID: %s", asset)
+		}
+	}
+
+	tx := &UniqueTx{
+		vm:   service.vm,
+		txID: assetID,
+	}
+	if status := tx.Status(); !status.Fetched() {
+		return ids.ID{}, errUnknownAssetID
+	}
+	if _, ok := tx.UnsignedTx.(*CreateAssetTx); !ok {
+		return ids.ID{}, errTxNotCreateAsset
+	}
+	return assetID, nil
+}
+
 // GetBalanceArgs are arguments for passing into GetBalance requests
 type GetBalanceArgs struct {
 	Address string `json:"address"`
@@ -296,7 +760,12 @@ type GetBalanceArgs struct {
 
 // GetBalanceReply defines the GetBalance replies returned from the API
 type GetBalanceReply struct {
-	Balance json.Uint64   `json:"balance"`
+	// Total balance, including timelocked funds
+	Balance json.Uint64 `json:"balance"`
+	// Balance that is currently spendable
+	Unlocked json.Uint64 `json:"unlocked"`
+	// Balance that is timelocked
+	Locked  json.Uint64   `json:"locked"`
 	UTXOIDs []djtx.UTXOID `json:"utxoIDs"`
 }
 
@@ -325,6 +794,7 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 		return fmt.Errorf("problem retrieving UTXOs: %w", err)
 	}
 
+	now := service.vm.clock.Unix()
 	reply.UTXOIDs = make([]djtx.UTXOID, 0, len(utxos))
 	for _, utxo := range utxos {
 		if !utxo.AssetID().Equals(assetID) {
@@ -339,6 +809,19 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 			return err
 		}
 		reply.Balance = json.Uint64(amt)
+		if outputLocktime(utxo.Out) > now {
+			locked, err := safemath.Add64(transferable.Amount(), uint64(reply.Locked))
+			if err != nil {
+				return err
+			}
+			reply.Locked = json.Uint64(locked)
+		} else {
+			unlocked, err := safemath.Add64(transferable.Amount(), uint64(reply.Unlocked))
+			if err != nil {
+				return err
+			}
+			reply.Unlocked = json.Uint64(unlocked)
+		}
 		reply.UTXOIDs = append(reply.UTXOIDs, utxo.UTXOID)
 	}
 
@@ -347,8 +830,13 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 
 // Balance ...
 type Balance struct {
-	AssetID string      `json:"asset"`
+	AssetID string `json:"asset"`
+	// Total balance, including timelocked funds
 	Balance json.Uint64 `json:"balance"`
+	// Balance that is currently spendable
+	Unlocked json.Uint64 `json:"unlocked"`
+	// Balance that is timelocked
+	Locked json.Uint64 `json:"locked"`
 }
 
 // GetAllBalancesReply is the response from a call to GetAllBalances
@@ -376,8 +864,10 @@ func (service *Service) GetAllBalances(r *http.Request, args *api.JsonAddress, r
 		return fmt.Errorf("couldn't get address's UTXOs: %s", err)
 	}
 
-	assetIDs := ids.Set{}                 // IDs of assets the address has a non-zero balance of
-	balances := make(map[[32]byte]uint64) // key: ID (as bytes). value: balance of that asset
+	now := service.vm.clock.Unix()
+	assetIDs := ids.Set{}                         // IDs of assets the address has a non-zero balance of
+	unlockedBalances := make(map[[32]byte]uint64) // key: ID (as bytes). value: unlocked balance of that asset
+	lockedBalances := make(map[[32]byte]uint64)   // key: ID (as bytes). value: timelocked balance of that asset
 	for _, utxo := range utxos {
 		transferable, ok := utxo.Out.(djtx.TransferableOut)
 		if !ok {
@@ -385,6 +875,11 @@ func (service *Service) GetAllBalances(r *http.Request, args *api.JsonAddress, r
 		}
 		assetID := utxo.AssetID()
 		assetIDs.Add(assetID)
+
+		balances := unlockedBalances
+		if outputLocktime(utxo.Out) > now {
+			balances = lockedBalances
+		}
 		balance := balances[assetID.Key()] // 0 if key doesn't exist
 		balance, err := safemath.Add64(transferable.Amount(), balance)
 		if err != nil {
@@ -396,16 +891,21 @@ func (service *Service) GetAllBalances(r *http.Request, args *api.JsonAddress, r
 
 	reply.Balances = make([]Balance, assetIDs.Len())
 	for i, assetID := range assetIDs.List() {
+		unlocked := unlockedBalances[assetID.Key()]
+		locked := lockedBalances[assetID.Key()]
+		balance, err := safemath.Add64(unlocked, locked)
+		if err != nil {
+			balance = math.MaxUint64
+		}
+
+		reply.Balances[i] = Balance{
+			AssetID:  assetID.String(),
+			Balance:  json.Uint64(balance),
+			Unlocked: json.Uint64(unlocked),
+			Locked:   json.Uint64(locked),
+		}
 		if alias, err := service.vm.PrimaryAlias(assetID); err == nil {
-			reply.Balances[i] = Balance{
-				AssetID: alias,
-				Balance: json.Uint64(balances[assetID.Key()]),
-			}
-		} else {
-			reply.Balances[i] = Balance{
-				AssetID: assetID.String(),
-				Balance: json.Uint64(balances[assetID.Key()]),
-			}
+			reply.Balances[i].AssetID = alias
 		}
 	}
 
@@ -918,6 +1418,18 @@ type SendArgs struct {
 	// Address of the recipient
 	To string `json:"to"`
 
+	// Additional recipients. If provided, the funds are sent to an output
+	// controlled by [To] and [ToAddresses], [Threshold] of which must sign to
+	// spend the output. [To] may be omitted if [ToAddresses] is provided.
+	ToAddresses []string `json:"toAddresses"`
+
+	// Number of recipient signatures required to spend the sent funds.
+	// Defaults to 1.
+	Threshold json.Uint32 `json:"threshold"`
+
+	// Unix time before which the sent funds can't be spent. Defaults to 0.
+	Locktime json.Uint64 `json:"locktime"`
+
 	// The addresses to send funds from
 	// If empty, will send from any addresses
 	// controlled by the given user
@@ -931,41 +1443,58 @@ type SendArgs struct {
 func (service *Service) Send(r *http.Request, args *SendArgs, reply *api.JsonTxID) error {
 	service.vm.ctx.Log.Info("AVM: Send called with username: %s", args.Username)
 
-	memoBytes := []byte(args.Memo)
-	if l := len(memoBytes); l > djtx.MaxMemoSize {
-		return fmt.Errorf("max memo length is %d but provided memo field is length %d", djtx.MaxMemoSize, l)
-	} else if args.Amount == 0 {
-		return errInvalidAmount
-	}
-
-	assetID, err := service.vm.Lookup(args.AssetID)
+	memoBytes, assetID, to, fromAddrs, err := service.parseSendArgs(args)
 	if err != nil {
-		assetID, err = ids.FromString(args.AssetID)
-		if err != nil {
-			return fmt.Errorf("asset '%s' not found", args.AssetID)
-		}
+		return err
 	}
-
-	to, err := service.vm.ParseLocalAddress(args.To)
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+		return err
 	}
 
-	fromAddrs := ids.ShortSet{}
-	for _, addrStr := range args.From {
-		addr, err := service.vm.ParseLocalAddress(addrStr)
-		if err != nil {
-			return fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
-		}
-		fromAddrs.Add(addr)
+	var keys [][]*crypto.PrivateKeySECP256K1R
+	spend := func(amountsWithFee map[[32]byte]uint64) (map[[32]byte]uint64, []*djtx.TransferableInput, error) {
+		amountsSpent, ins, signers, err := service.vm.Spend(utxos, kc, amountsWithFee)
+		keys = signers
+		return amountsSpent, ins, err
 	}
-	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	changeOwners := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{kc.Keys[0].PublicKey().Address()},
+	}
+	change := func(ids.ID) *secp256k1fx.OutputOwners { return changeOwners }
+
+	baseTx, err := service.buildSendTx(memoBytes, assetID, uint64(args.Amount), to, spend, change)
 	if err != nil {
 		return err
 	}
+	tx := Tx{UnsignedTx: baseTx}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
 
+// buildSendTx returns an unsigned transaction that sends [amount] of [assetID]
+// to [to]. The inputs, which must cover the amount and the fee, are selected by
+// [spend]. The change of each asset is sent to [change] of the asset.
+func (service *Service) buildSendTx(
+	memo []byte,
+	assetID ids.ID,
+	amount uint64,
+	to *secp256k1fx.OutputOwners,
+	spend func(amountsWithFee map[[32]byte]uint64) (map[[32]byte]uint64, []*djtx.TransferableInput, error),
+	change func(assetID ids.ID) *secp256k1fx.OutputOwners,
+) (*BaseTx, error) {
 	amounts := map[[32]byte]uint64{
-		assetID.Key(): uint64(args.Amount),
+		assetID.Key(): amount,
 	}
 	amountsWithFee := make(map[[32]byte]uint64, len(amounts)+1)
 	for k, v := range amounts {
@@ -975,17 +1504,13 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *api.JsonTxI
 	djtxKey := service.vm.ctx.DJTXAssetID.Key()
 	amountWithFee, err := safemath.Add64(amountsWithFee[djtxKey], service.vm.txFee)
 	if err != nil {
-		return fmt.Errorf("problem calculating required spend amount: %w", err)
+		return nil, fmt.Errorf("problem calculating required spend amount: %w", err)
 	}
 	amountsWithFee[djtxKey] = amountWithFee
 
-	amountsSpent, ins, keys, err := service.vm.Spend(
-		utxos,
-		kc,
-		amountsWithFee,
-	)
+	amountsSpent, ins, err := spend(amountsWithFee)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	outs := []*djtx.TransferableOutput{}
@@ -998,49 +1523,274 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *api.JsonTxI
 			outs = append(outs, &djtx.TransferableOutput{
 				Asset: djtx.Asset{ID: assetID},
 				Out: &secp256k1fx.TransferOutput{
-					Amt: amount,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Locktime:  0,
-						Threshold: 1,
-						Addrs:     []ids.ShortID{to},
-					},
+					Amt:          amount,
+					OutputOwners: *to,
 				},
 			})
 		}
 		if amountSpent > amountWithFee {
-			changeAddr := kc.Keys[0].PublicKey().Address()
 			outs = append(outs, &djtx.TransferableOutput{
 				Asset: djtx.Asset{ID: assetID},
 				Out: &secp256k1fx.TransferOutput{
-					Amt: amountSpent - amountWithFee,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Locktime:  0,
-						Threshold: 1,
-						Addrs:     []ids.ShortID{changeAddr},
-					},
+					Amt:          amountSpent - amountWithFee,
+					OutputOwners: *change(assetID),
 				},
 			})
 		}
 	}
 	djtx.SortTransferableOutputs(outs, service.vm.codec)
 
-	tx := Tx{UnsignedTx: &BaseTx{BaseTx: djtx.BaseTx{
+	return &BaseTx{BaseTx: djtx.BaseTx{
 		NetworkID:    service.vm.ctx.NetworkID,
 		BlockchainID: service.vm.ctx.ChainID,
 		Outs:         outs,
 		Ins:          ins,
-		Memo:         memoBytes,
-	}}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
-		return err
-	}
+		Memo:         memo,
+	}}, nil
+}
 
-	txID, err := service.vm.IssueTx(tx.Bytes())
-	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+// parseSendArgs returns the memo, the ID of the asset being sent, the owners of
+// the sent funds and the addresses the funds should be sent from.
+func (service *Service) parseSendArgs(args *SendArgs) ([]byte, ids.ID, *secp256k1fx.OutputOwners, ids.ShortSet, error) {
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > djtx.MaxMemoSize {
+		return nil, ids.ID{}, nil, nil, fmt.Errorf("max memo length is %d but provided memo field is length %d", djtx.MaxMemoSize, l)
+	} else if args.Amount == 0 {
+		return nil, ids.ID{}, nil, nil, errInvalidAmount
 	}
 
-	reply.TxID = txID
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return nil, ids.ID{}, nil, nil, fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	toAddrs := args.ToAddresses
+	if args.To != "" || len(toAddrs) == 0 {
+		toAddrs = append([]string{args.To}, toAddrs...)
+	}
+	threshold := uint32(args.Threshold)
+	if threshold == 0 {
+		threshold = 1
+	}
+	to, err := service.parseOwners(toAddrs, threshold, uint64(args.Locktime))
+	if err != nil {
+		return nil, ids.ID{}, nil, nil, err
+	}
+
+	fromAddrs := ids.ShortSet{}
+	for _, addrStr := range args.From {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return nil, ids.ID{}, nil, nil, fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+		}
+		fromAddrs.Add(addr)
+	}
+	return memoBytes, assetID, to, fromAddrs, nil
+}
+
+// parseOwners returns the owners of an output that is controlled by
+// [addrStrs], [threshold] of which must sign to spend the output after
+// [locktime].
+func (service *Service) parseOwners(addrStrs []string, threshold uint32, locktime uint64) (*secp256k1fx.OutputOwners, error) {
+	addrs := ids.ShortSet{}
+	for _, addrStr := range addrStrs {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing to address %q: %w", addrStr, err)
+		}
+		addrs.Add(addr)
+	}
+	owners := &secp256k1fx.OutputOwners{
+		Locktime:  locktime,
+		Threshold: threshold,
+		Addrs:     addrs.List(),
+	}
+	owners.Sort()
+	if err := owners.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid recipients: %w", err)
+	}
+	return owners, nil
+}
+
+// BuildSendArgs are arguments for passing into BuildSend requests
+type BuildSendArgs struct {
+	SendArgs
+
+	// Addresses, not controlled by the user, that are expected to sign the
+	// transaction before it is issued
+	Cosigners []string `json:"cosigners"`
+
+	// Address the change is sent to. If empty, the change of each asset is
+	// returned to the owners of the first UTXO consumed of the asset.
+	ChangeAddr string `json:"changeAddr"`
+}
+
+// PartiallySignedTxReply defines the replies of calls that return a
+// transaction that may still be missing signatures
+type PartiallySignedTxReply struct {
+	FormattedTx
+
+	// Addresses whose signatures must be added before the transaction can be
+	// issued
+	MissingSigners []string `json:"missingSigners"`
+}
+
+// BuildSend creates, but does not issue, a transaction that sends funds. Unlike
+// Send, the transaction may spend UTXOs that require signatures from
+// [Cosigners]. The transaction is signed with the keys held by the user and is
+// returned so that the cosigners can add their signatures with SignTx. Once
+// fully signed, the transaction can be issued with IssueTx.
+func (service *Service) BuildSend(r *http.Request, args *BuildSendArgs, reply *PartiallySignedTxReply) error {
+	service.vm.ctx.Log.Info("AVM: BuildSend called with username: %s", args.Username)
+
+	memoBytes, assetID, to, fromAddrs, err := service.parseSendArgs(&args.SendArgs)
+	if err != nil {
+		return err
+	}
+
+	cosigners := ids.ShortSet{}
+	for _, addrStr := range args.Cosigners {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse cosigner address %s: %w", addrStr, err)
+		}
+		cosigners.Add(addr)
+	}
+
+	var changeOwners *secp256k1fx.OutputOwners
+	if args.ChangeAddr != "" {
+		changeAddr, err := service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse change address %s: %w", args.ChangeAddr, err)
+		}
+		changeOwners = &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{changeAddr},
+		}
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Asset --> owners of the first UTXO consumed of the asset
+	utxoOwners := make(map[[32]byte]*secp256k1fx.OutputOwners)
+	spend := func(amountsWithFee map[[32]byte]uint64) (map[[32]byte]uint64, []*djtx.TransferableInput, error) {
+		amountsSpent, ins, err := service.vm.SpendPartial(utxos, kc, cosigners, amountsWithFee)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		spentUTXOs := make(map[[32]byte]*djtx.UTXO, len(utxos))
+		for _, utxo := range utxos {
+			spentUTXOs[utxo.InputID().Key()] = utxo
+		}
+		for _, in := range ins {
+			assetKey := in.AssetID().Key()
+			if _, exists := utxoOwners[assetKey]; exists {
+				continue
+			}
+			out, ok := spentUTXOs[in.InputID().Key()].Out.(*secp256k1fx.TransferOutput)
+			if !ok {
+				return nil, nil, errInvalidUTXO
+			}
+			utxoOwners[assetKey] = &secp256k1fx.OutputOwners{
+				Threshold: out.Threshold,
+				Addrs:     out.Addrs,
+			}
+		}
+		return amountsSpent, ins, nil
+	}
+	change := func(assetID ids.ID) *secp256k1fx.OutputOwners {
+		if changeOwners != nil {
+			return changeOwners
+		}
+		return utxoOwners[assetID.Key()]
+	}
+
+	baseTx, err := service.buildSendTx(memoBytes, assetID, uint64(args.Amount), to, spend, change)
+	if err != nil {
+		return err
+	}
+
+	creds := make([]verify.Verifiable, len(baseTx.Ins))
+	for i, in := range baseTx.Ins {
+		input, ok := in.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			return errInvalidUTXO
+		}
+		creds[i] = &secp256k1fx.Credential{
+			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(input.SigIndices)),
+		}
+	}
+
+	tx := &Tx{
+		UnsignedTx: baseTx,
+		Creds:      creds,
+	}
+	missing, err := service.vm.SignTx(tx, kc)
+	if err != nil {
+		return err
+	}
+	return service.formatPartiallySignedTx(tx, missing, reply)
+}
+
+// SignTxArgs are arguments for passing into SignTx requests
+type SignTxArgs struct {
+	api.UserPass
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// SignTx adds the signatures that the user is able to produce to a partially
+// signed transaction
+func (service *Service) SignTx(r *http.Request, args *SignTxArgs, reply *PartiallySignedTxReply) error {
+	service.vm.ctx.Log.Info("AVM: SignTx called with username: %s", args.Username)
+
+	tx := &Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	tx.Initialize(unsignedBytes, args.Tx.Bytes)
+	if err := tx.SyntacticVerify(
+		service.vm.ctx,
+		service.vm.codec,
+		service.vm.ctx.DJTXAssetID,
+		service.vm.txFee,
+		len(service.vm.fxs),
+	); err != nil {
+		return fmt.Errorf("invalid transaction: %w", err)
+	}
+
+	_, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	missing, err := service.vm.SignTx(tx, kc)
+	if err != nil {
+		return err
+	}
+	return service.formatPartiallySignedTx(tx, missing, reply)
+}
+
+func (service *Service) formatPartiallySignedTx(tx *Tx, missing []ids.ShortID, reply *PartiallySignedTxReply) error {
+	reply.Tx.Bytes = tx.Bytes()
+	reply.MissingSigners = make([]string, len(missing))
+	for i, addr := range missing {
+		addrStr, err := service.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+		reply.MissingSigners[i] = addrStr
+	}
 	return nil
 }
 
@@ -1050,6 +1800,9 @@ type MintArgs struct {
 	Amount  json.Uint64 `json:"amount"`
 	AssetID string      `json:"assetID"`
 	To      string      `json:"to"`
+
+	// Unix time before which the minted funds can't be spent. Defaults to 0.
+	Locktime json.Uint64 `json:"locktime"`
 }
 
 // Mint issues a transaction that mints more of the asset
@@ -1068,9 +1821,9 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JsonTxI
 		}
 	}
 
-	to, err := service.vm.ParseLocalAddress(args.To)
+	to, err := service.parseOwners([]string{args.To}, 1, uint64(args.Locktime))
 	if err != nil {
-		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+		return err
 	}
 
 	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
@@ -1329,6 +2082,343 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 	return nil
 }
 
+// CreatePropertyAssetArgs are arguments for passing into CreatePropertyAsset
+// requests
+type CreatePropertyAssetArgs struct {
+	api.UserPass
+	Name       string   `json:"name"`
+	Symbol     string   `json:"symbol"`
+	MinterSets []Owners `json:"minterSets"`
+}
+
+// CreatePropertyAsset returns ID of the newly created asset
+func (service *Service) CreatePropertyAsset(r *http.Request, args *CreatePropertyAssetArgs, reply *FormattedAssetID) error {
+	service.vm.ctx.Log.Info("AVM: CreatePropertyAsset called with name: %s symbol: %s number of minters: %d",
+		args.Name,
+		args.Symbol,
+		len(args.MinterSets),
+	)
+
+	if len(args.MinterSets) == 0 {
+		return errNoMinters
+	}
+
+	fxIndex, err := service.vm.getFxIndex(propertyfx.ID)
+	if err != nil {
+		return err
+	}
+
+	initialState := &InitialState{
+		FxID: fxIndex,
+		Outs: make([]verify.State, 0, len(args.MinterSets)),
+	}
+	for _, owner := range args.MinterSets {
+		minter := &propertyfx.MintOutput{
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: uint32(owner.Threshold),
+			},
+		}
+		for _, address := range owner.Minters {
+			addr, err := service.vm.ParseLocalAddress(address)
+			if err != nil {
+				return err
+			}
+			minter.Addrs = append(minter.Addrs, addr)
+		}
+		ids.SortShortIDs(minter.Addrs)
+		initialState.Outs = append(initialState.Outs, minter)
+	}
+	initialState.Sort(service.vm.codec)
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	ins, outs, keys, err := service.spendFee(utxos, kc)
+	if err != nil {
+		return err
+	}
+
+	tx := Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{BaseTx: djtx.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Name:         args.Name,
+		Symbol:       args.Symbol,
+		Denomination: 0, // Properties are non-fungible
+		States:       []*InitialState{initialState},
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	assetID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.AssetID = assetID
+	return nil
+}
+
+// MintPropertyArgs are arguments for passing into MintProperty requests
+type MintPropertyArgs struct {
+	api.UserPass
+	AssetID string `json:"assetID"`
+	// Addresses that will own the minted property
+	To []string `json:"to"`
+	// Number of [To] that must sign to burn the property. Defaults to 1.
+	Threshold json.Uint32 `json:"threshold"`
+	// Unix time before which the property can't be burned
+	Locktime json.Uint64 `json:"locktime"`
+}
+
+// MintProperty issues a transaction that mints a property and returns the ID
+// of the newly created transaction
+func (service *Service) MintProperty(r *http.Request, args *MintPropertyArgs, reply *api.JsonTxID) error {
+	service.vm.ctx.Log.Info("AVM: MintProperty called with username: %s", args.Username)
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	threshold := uint32(args.Threshold)
+	if threshold == 0 {
+		threshold = 1
+	}
+	to, err := service.parseOwners(args.To, threshold, uint64(args.Locktime))
+	if err != nil {
+		return err
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	ins, outs, secpKeys, err := service.spendFee(utxos, kc)
+	if err != nil {
+		return err
+	}
+
+	ops, propertyKeys, err := service.vm.MintProperty(utxos, kc, assetID, to)
+	if err != nil {
+		return err
+	}
+
+	return service.issuePropertyOperation(ins, outs, ops, secpKeys, propertyKeys, reply)
+}
+
+// BurnPropertyArgs are arguments for passing into BurnProperty requests
+type BurnPropertyArgs struct {
+	api.UserPass
+	AssetID string `json:"assetID"`
+	// If provided, the ID of the property UTXO to burn. Otherwise, any
+	// property of [AssetID] controlled by the user is burned.
+	UTXOID string `json:"utxoID"`
+}
+
+// BurnProperty issues a transaction that burns a property and returns the ID
+// of the newly created transaction
+func (service *Service) BurnProperty(r *http.Request, args *BurnPropertyArgs, reply *api.JsonTxID) error {
+	service.vm.ctx.Log.Info("AVM: BurnProperty called with username: %s", args.Username)
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	utxoID := ids.Empty
+	if args.UTXOID != "" {
+		utxoID, err = ids.FromString(args.UTXOID)
+		if err != nil {
+			return fmt.Errorf("couldn't parse UTXO ID %q: %w", args.UTXOID, err)
+		}
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	ins, outs, secpKeys, err := service.spendFee(utxos, kc)
+	if err != nil {
+		return err
+	}
+
+	ops, propertyKeys, err := service.vm.SpendProperty(utxos, kc, assetID, utxoID)
+	if err != nil {
+		return err
+	}
+
+	return service.issuePropertyOperation(ins, outs, ops, secpKeys, propertyKeys, reply)
+}
+
+// GetPropertyOwnersArgs are arguments for passing into GetPropertyOwners
+// requests
+type GetPropertyOwnersArgs struct {
+	AssetID string `json:"assetID"`
+	// If provided, only properties with a UTXO ID greater than [StartUTXOID]
+	// are returned
+	StartUTXOID string `json:"startUTXOID"`
+	// Maximum number of properties to return. If 0 or greater than 1024,
+	// returns at most 1024 properties.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// Property describes an unburned property output
+type Property struct {
+	UTXOID    string      `json:"utxoID"`
+	Locktime  json.Uint64 `json:"locktime"`
+	Threshold json.Uint32 `json:"threshold"`
+	Owners    []string    `json:"owners"`
+}
+
+// GetPropertyOwnersReply defines the GetPropertyOwners replies returned from
+// the API
+type GetPropertyOwnersReply struct {
+	IndexStatusReply
+	// Number of properties returned
+	NumFetched json.Uint64 `json:"numFetched"`
+	// The properties, ordered by UTXO ID
+	Properties []Property `json:"properties"`
+	// The ID of the last property UTXO returned. Pass this as [StartUTXOID] to
+	// get the next page.
+	EndUTXOID string `json:"endUTXOID"`
+}
+
+// GetPropertyOwners returns the owners of every unburned property of an asset
+func (service *Service) GetPropertyOwners(_ *http.Request, args *GetPropertyOwnersArgs, reply *GetPropertyOwnersReply) error {
+	service.vm.ctx.Log.Info("AVM: GetPropertyOwners called with %s", args.AssetID)
+
+	assetID, err := service.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	start := ids.Empty
+	if args.StartUTXOID != "" {
+		start, err = ids.FromString(args.StartUTXOID)
+		if err != nil {
+			return fmt.Errorf("couldn't parse start UTXO ID %q: %w", args.StartUTXOID, err)
+		}
+	}
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxPropertiesToFetch {
+		limit = maxPropertiesToFetch
+	}
+
+	utxoIDs, err := service.vm.state.Properties(assetID, start, limit)
+	if err != nil {
+		return fmt.Errorf("couldn't get properties of asset %s: %w", assetID, err)
+	}
+
+	reply.Properties = make([]Property, len(utxoIDs))
+	for i, utxoID := range utxoIDs {
+		utxo, err := service.vm.state.UTXO(utxoID)
+		if err != nil {
+			return fmt.Errorf("couldn't get property UTXO %s: %w", utxoID, err)
+		}
+		out, ok := utxo.Out.(*propertyfx.OwnedOutput)
+		if !ok {
+			return fmt.Errorf("UTXO %s is not a property but %T", utxoID, utxo.Out)
+		}
+		owners, err := service.formatAddresses(out.Addrs)
+		if err != nil {
+			return err
+		}
+		reply.Properties[i] = Property{
+			UTXOID:    utxoID.String(),
+			Locktime:  json.Uint64(out.Locktime),
+			Threshold: json.Uint32(out.Threshold),
+			Owners:    owners,
+		}
+	}
+
+	reply.EndUTXOID = start.String()
+	if len(utxoIDs) > 0 {
+		reply.EndUTXOID = utxoIDs[len(utxoIDs)-1].String()
+	}
+	reply.NumFetched = json.Uint64(len(utxoIDs))
+	return service.indexStatus(&reply.IndexStatusReply)
+}
+
+// spendFee returns the inputs, change outputs and signers needed to pay the
+// transaction fee with the funds in [kc]
+func (service *Service) spendFee(utxos []*djtx.UTXO, kc *secp256k1fx.Keychain) (
+	[]*djtx.TransferableInput,
+	[]*djtx.TransferableOutput,
+	[][]*crypto.PrivateKeySECP256K1R,
+	error,
+) {
+	djtxKey := service.vm.ctx.DJTXAssetID.Key()
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		utxos,
+		kc,
+		map[[32]byte]uint64{
+			djtxKey: service.vm.txFee,
+		},
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	outs := []*djtx.TransferableOutput{}
+	if amountSpent := amountsSpent[djtxKey]; amountSpent > service.vm.txFee {
+		changeAddr := kc.Keys[0].PublicKey().Address()
+		outs = append(outs, &djtx.TransferableOutput{
+			Asset: djtx.Asset{ID: service.vm.ctx.DJTXAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.txFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+	return ins, outs, keys, nil
+}
+
+func (service *Service) issuePropertyOperation(
+	ins []*djtx.TransferableInput,
+	outs []*djtx.TransferableOutput,
+	ops []*Operation,
+	secpKeys [][]*crypto.PrivateKeySECP256K1R,
+	propertyKeys [][]*crypto.PrivateKeySECP256K1R,
+	reply *api.JsonTxID,
+) error {
+	tx := Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{BaseTx: djtx.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Ops: ops,
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, secpKeys); err != nil {
+		return err
+	}
+	if err := tx.SignPropertyFx(service.vm.codec, propertyKeys); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
 // ImportDJTXArgs are arguments for passing into ImportDJTX requests
 type ImportDJTXArgs struct {
 	// User that controls To
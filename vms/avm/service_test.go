@@ -5,9 +5,11 @@ package avm
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -184,6 +186,563 @@ func TestServiceGetAllBalances(t *testing.T) {
 	assert.Equal(t, uint64(balance.Balance), uint64(300000))
 }
 
+func TestServiceTimelockedBalances(t *testing.T) {
+	genesisBytes, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+	addr := keys[0].PublicKey().Address()
+	addrStr, err := vm.FormatLocalAddress(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.clock.Set(time.Unix(1000, 0))
+	if err := vm.state.FundUTXO(&djtx.UTXO{
+		UTXOID: djtx.UTXOID{TxID: ids.Empty.Prefix(1)},
+		Asset:  djtx.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Locktime:  2000,
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	balanceReply := &GetBalanceReply{}
+	err = s.GetBalance(nil, &GetBalanceArgs{
+		Address: addrStr,
+		AssetID: assetID.String(),
+	}, balanceReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(301000), uint64(balanceReply.Balance))
+	assert.Equal(t, uint64(300000), uint64(balanceReply.Unlocked))
+	assert.Equal(t, uint64(1000), uint64(balanceReply.Locked))
+
+	allBalancesReply := &GetAllBalancesReply{}
+	err = s.GetAllBalances(nil, &api.JsonAddress{Address: addrStr}, allBalancesReply)
+	assert.NoError(t, err)
+	assert.Len(t, allBalancesReply.Balances, 1)
+	assert.Equal(t, uint64(301000), uint64(allBalancesReply.Balances[0].Balance))
+	assert.Equal(t, uint64(300000), uint64(allBalancesReply.Balances[0].Unlocked))
+	assert.Equal(t, uint64(1000), uint64(allBalancesReply.Balances[0].Locked))
+
+	for lockStatus, expected := range map[string]int{
+		"":         8,
+		"all":      8,
+		"locked":   1,
+		"unlocked": 7,
+	} {
+		utxosReply := &GetUTXOsReply{}
+		err := s.GetUTXOs(nil, &GetUTXOsArgs{
+			Addresses:  []string{addrStr},
+			LockStatus: lockStatus,
+		}, utxosReply)
+		assert.NoError(t, err)
+		assert.Len(t, utxosReply.UTXOs, expected, "wrong number of UTXOs with lock status %q", lockStatus)
+	}
+	err = s.GetUTXOs(nil, &GetUTXOsArgs{
+		Addresses:  []string{addrStr},
+		LockStatus: "bogus",
+	}, &GetUTXOsReply{})
+	assert.Error(t, err)
+
+	sendArgs := &SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:  300500,
+		AssetID: assetID.String(),
+		To:      addrStr,
+	}
+	err = s.Send(nil, sendArgs, &api.JsonTxID{})
+	assert.True(t, errors.Is(err, errInsufficientFunds), "should have reported insufficient funds")
+	assert.Contains(t, err.Error(), "timelocked")
+
+	vm.clock.Set(time.Unix(2000, 0))
+	balanceReply = &GetBalanceReply{}
+	err = s.GetBalance(nil, &GetBalanceArgs{
+		Address: addrStr,
+		AssetID: assetID.String(),
+	}, balanceReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(301000), uint64(balanceReply.Unlocked))
+	assert.Equal(t, uint64(0), uint64(balanceReply.Locked))
+}
+
+func TestSendTimelocked(t *testing.T) {
+	genesisBytes, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+	addrStr, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := &api.JsonTxID{}
+	vm.timer.Cancel()
+	if err := s.Send(nil, &SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:   500,
+		AssetID:  assetID.String(),
+		To:       addrStr,
+		Locktime: 12345,
+	}, reply); err != nil {
+		t.Fatalf("Failed to send transaction: %s", err)
+	}
+
+	tx := UniqueTx{vm: vm, txID: reply.TxID}
+	found := false
+	for _, utxo := range tx.UTXOs() {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || out.Amt != 500 {
+			continue
+		}
+		found = true
+		if out.Locktime != 12345 {
+			t.Fatalf("Expected locktime %d but got %d", 12345, out.Locktime)
+		}
+	}
+	if !found {
+		t.Fatal("Couldn't find the sent output")
+	}
+}
+
+func TestServiceAssetRegistry(t *testing.T) {
+	genesisBytes, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+	addr0Str, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1Str, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listReply := &ListAssetsReply{}
+	err = s.ListAssets(nil, &ListAssetsArgs{Limit: 2}, listReply)
+	assert.NoError(t, err)
+	assert.Len(t, listReply.Assets, 2)
+	firstPage := listReply.Assets
+
+	listReply = &ListAssetsReply{}
+	err = s.ListAssets(nil, &ListAssetsArgs{StartAssetID: firstPage[1].AssetID.String()}, listReply)
+	assert.NoError(t, err)
+	assert.Len(t, listReply.Assets, 1)
+	assert.NotEqual(t, firstPage[0].AssetID, listReply.Assets[0].AssetID)
+	assert.NotEqual(t, firstPage[1].AssetID, listReply.Assets[0].AssetID)
+
+	supplyReply := &GetAssetSupplyReply{}
+	err = s.GetAssetSupply(nil, &GetAssetSupplyArgs{AssetID: assetID.String()}, supplyReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300000), uint64(supplyReply.Supply))
+	assert.Equal(t, uint64(300000), uint64(supplyReply.Minted))
+	assert.Equal(t, uint64(0), uint64(supplyReply.Burned))
+
+	holdersReply := &GetAssetHoldersReply{}
+	err = s.GetAssetHolders(nil, &GetAssetHoldersArgs{AssetID: assetID.String()}, holdersReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), uint64(holdersReply.NumHolders))
+	assert.Equal(t, []AddressBalance{{Address: addr0Str, Balance: 300000}}, holdersReply.Holders)
+
+	vm.timer.Cancel()
+	sendReply := &api.JsonTxID{}
+	if err := s.Send(nil, &SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:  1000,
+		AssetID: assetID.String(),
+		To:      addr1Str,
+	}, sendReply); err != nil {
+		t.Fatal(err)
+	}
+	sendTx := UniqueTx{
+		vm:   vm,
+		txID: sendReply.TxID,
+	}
+	if err := sendTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	holdersReply = &GetAssetHoldersReply{}
+	err = s.GetAssetHolders(nil, &GetAssetHoldersArgs{AssetID: assetID.String()}, holdersReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), uint64(holdersReply.NumHolders))
+	assert.Equal(t, []AddressBalance{
+		{Address: addr0Str, Balance: 299000},
+		{Address: addr1Str, Balance: 1000},
+	}, holdersReply.Holders)
+
+	holdersReply = &GetAssetHoldersReply{}
+	err = s.GetAssetHolders(nil, &GetAssetHoldersArgs{AssetID: assetID.String(), Limit: 1}, holdersReply)
+	assert.NoError(t, err)
+	assert.Len(t, holdersReply.Holders, 1)
+
+	createReply := &FormattedAssetID{}
+	if err := s.CreateVariableCapAsset(nil, &CreateVariableCapAssetArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Name:   "test asset",
+		Symbol: "TEST",
+		MinterSets: []Owners{
+			{
+				Threshold: 1,
+				Minters:   []string{addr0Str},
+			},
+		},
+	}, createReply); err != nil {
+		t.Fatal(err)
+	}
+	createTx := UniqueTx{
+		vm:   vm,
+		txID: createReply.AssetID,
+	}
+	if err := createTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	mintReply := &api.JsonTxID{}
+	if err := s.Mint(nil, &MintArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:  200,
+		AssetID: createReply.AssetID.String(),
+		To:      addr1Str,
+	}, mintReply); err != nil {
+		t.Fatal(err)
+	}
+	mintTx := UniqueTx{
+		vm:   vm,
+		txID: mintReply.TxID,
+	}
+	if err := mintTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	listReply = &ListAssetsReply{}
+	err = s.ListAssets(nil, &ListAssetsArgs{}, listReply)
+	assert.NoError(t, err)
+	assert.Len(t, listReply.Assets, 4)
+
+	supplyReply = &GetAssetSupplyReply{}
+	err = s.GetAssetSupply(nil, &GetAssetSupplyArgs{AssetID: createReply.AssetID.String()}, supplyReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), uint64(supplyReply.Supply))
+
+	vm.indexHolders = false
+	err = s.GetAssetHolders(nil, &GetAssetHoldersArgs{AssetID: assetID.String()}, &GetAssetHoldersReply{})
+	assert.Equal(t, errHolderIndexDisabled, err)
+}
+
+func TestServiceNFTIndex(t *testing.T) {
+	_, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addr0Str, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1Str, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createReply := &FormattedAssetID{}
+	if err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Name:   "BIG COIN",
+		Symbol: "COIN",
+		MinterSets: []Owners{
+			{
+				Threshold: 1,
+				Minters:   []string{addr0Str},
+			},
+		},
+	}, createReply); err != nil {
+		t.Fatal(err)
+	}
+	assetID := createReply.AssetID
+	createTx := UniqueTx{
+		vm:   vm,
+		txID: assetID,
+	}
+	if err := createTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	mintReply := &api.JsonTxID{}
+	if err := s.MintNFT(nil, &MintNFTArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		AssetID: assetID.String(),
+		Payload: formatting.CB58{Bytes: []byte{1, 2, 3}},
+		To:      addr0Str,
+	}, mintReply); err != nil {
+		t.Fatal(err)
+	}
+	mintTx := UniqueTx{
+		vm:   vm,
+		txID: mintReply.TxID,
+	}
+	if err := mintTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	nftsReply := &GetNFTsReply{}
+	err = s.GetNFTs(nil, &GetNFTsArgs{Owner: addr0Str}, nftsReply)
+	assert.NoError(t, err)
+	assert.Len(t, nftsReply.NFTs, 1)
+	assert.Equal(t, assetID, nftsReply.NFTs[0].AssetID)
+	assert.Equal(t, []byte{1, 2, 3}, nftsReply.NFTs[0].Payload.Bytes)
+	assert.Equal(t, []string{addr0Str}, nftsReply.NFTs[0].Owners)
+
+	collectionReply := &GetNFTsReply{}
+	err = s.GetNFTCollection(nil, &GetNFTCollectionArgs{AssetID: assetID.String()}, collectionReply)
+	assert.NoError(t, err)
+	assert.Equal(t, nftsReply.NFTs, collectionReply.NFTs)
+
+	sendReply := &api.JsonTxID{}
+	if err := s.SendNFT(nil, &SendNFTArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		AssetID: assetID.String(),
+		GroupID: 0,
+		To:      addr1Str,
+	}, sendReply); err != nil {
+		t.Fatal(err)
+	}
+	sendTx := UniqueTx{
+		vm:   vm,
+		txID: sendReply.TxID,
+	}
+	if err := sendTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	nftsReply = &GetNFTsReply{}
+	err = s.GetNFTs(nil, &GetNFTsArgs{Owner: addr0Str}, nftsReply)
+	assert.NoError(t, err)
+	assert.Len(t, nftsReply.NFTs, 0)
+
+	nftsReply = &GetNFTsReply{}
+	err = s.GetNFTs(nil, &GetNFTsArgs{Owner: addr1Str}, nftsReply)
+	assert.NoError(t, err)
+	assert.Len(t, nftsReply.NFTs, 1)
+	assert.Equal(t, []byte{1, 2, 3}, nftsReply.NFTs[0].Payload.Bytes)
+
+	historyReply := &GetNFTHistoryReply{}
+	err = s.GetNFTHistory(nil, &GetNFTHistoryArgs{AssetID: assetID.String()}, historyReply)
+	assert.NoError(t, err)
+	if assert.Len(t, historyReply.History, 2) {
+		assert.True(t, historyReply.History[0].Minted)
+		assert.Equal(t, mintReply.TxID, historyReply.History[0].TxID)
+		assert.Equal(t, []string{addr0Str}, historyReply.History[0].Owners)
+		assert.False(t, historyReply.History[1].Minted)
+		assert.Equal(t, sendReply.TxID, historyReply.History[1].TxID)
+		assert.Equal(t, []string{addr1Str}, historyReply.History[1].Owners)
+	}
+	assert.True(t, historyReply.IndexFromGenesis)
+
+	// Backfill the indices, as if this chain was created before they existed.
+	// The history starts with the NFT output that exists at the time.
+	if err := vm.db.Delete(assetIndexStatusKey.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.initAssetIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	nftsReply = &GetNFTsReply{}
+	err = s.GetNFTs(nil, &GetNFTsArgs{Owner: addr1Str}, nftsReply)
+	assert.NoError(t, err)
+	assert.Len(t, nftsReply.NFTs, 1)
+	assert.False(t, nftsReply.IndexFromGenesis)
+
+	collectionReply = &GetNFTsReply{}
+	err = s.GetNFTCollection(nil, &GetNFTCollectionArgs{AssetID: assetID.String()}, collectionReply)
+	assert.NoError(t, err)
+	assert.Equal(t, nftsReply.NFTs, collectionReply.NFTs)
+
+	historyReply = &GetNFTHistoryReply{}
+	err = s.GetNFTHistory(nil, &GetNFTHistoryArgs{AssetID: assetID.String()}, historyReply)
+	assert.NoError(t, err)
+	if assert.Len(t, historyReply.History, 1) {
+		assert.False(t, historyReply.History[0].Minted)
+		assert.Equal(t, sendReply.TxID, historyReply.History[0].TxID)
+		assert.Equal(t, []string{addr1Str}, historyReply.History[0].Owners)
+	}
+	assert.False(t, historyReply.IndexFromGenesis)
+}
+
+func TestPropertyWorkflow(t *testing.T) {
+	_, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addr0Str, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1Str, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createReply := &FormattedAssetID{}
+	if err := s.CreatePropertyAsset(nil, &CreatePropertyAssetArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Name:   "Deed",
+		Symbol: "DEED",
+		MinterSets: []Owners{
+			{
+				Threshold: 1,
+				Minters:   []string{addr0Str},
+			},
+		},
+	}, createReply); err != nil {
+		t.Fatalf("Failed to create property asset: %s", err)
+	}
+	assetID := createReply.AssetID
+	createTx := UniqueTx{
+		vm:   vm,
+		txID: assetID,
+	}
+	if err := createTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	mintReply := &api.JsonTxID{}
+	if err := s.MintProperty(nil, &MintPropertyArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		AssetID:   assetID.String(),
+		To:        []string{addr0Str, addr1Str},
+		Threshold: 1,
+	}, mintReply); err != nil {
+		t.Fatalf("Failed to mint property: %s", err)
+	}
+	mintTx := UniqueTx{
+		vm:   vm,
+		txID: mintReply.TxID,
+	}
+	if err := mintTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	ownersReply := &GetPropertyOwnersReply{}
+	err = s.GetPropertyOwners(nil, &GetPropertyOwnersArgs{AssetID: assetID.String()}, ownersReply)
+	assert.NoError(t, err)
+	if assert.Len(t, ownersReply.Properties, 1) {
+		assert.ElementsMatch(t, []string{addr0Str, addr1Str}, ownersReply.Properties[0].Owners)
+		assert.Equal(t, uint32(1), uint32(ownersReply.Properties[0].Threshold))
+	}
+
+	// Backfilling the index, as if this chain was created before it existed,
+	// finds the unburned property
+	if err := vm.db.Delete(assetIndexStatusKey.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.initAssetIndex(); err != nil {
+		t.Fatal(err)
+	}
+	backfilledReply := &GetPropertyOwnersReply{}
+	err = s.GetPropertyOwners(nil, &GetPropertyOwnersArgs{AssetID: assetID.String()}, backfilledReply)
+	assert.NoError(t, err)
+	assert.Equal(t, ownersReply.Properties, backfilledReply.Properties)
+	assert.False(t, backfilledReply.IndexFromGenesis)
+
+	supplyReply := &GetAssetSupplyReply{}
+	err = s.GetAssetSupply(nil, &GetAssetSupplyArgs{AssetID: assetID.String()}, supplyReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), uint64(supplyReply.Supply))
+
+	burnReply := &api.JsonTxID{}
+	if err := s.BurnProperty(nil, &BurnPropertyArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		AssetID: assetID.String(),
+		UTXOID:  ownersReply.Properties[0].UTXOID,
+	}, burnReply); err != nil {
+		t.Fatalf("Failed to burn property: %s", err)
+	}
+	burnTx := UniqueTx{
+		vm:   vm,
+		txID: burnReply.TxID,
+	}
+	if err := burnTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	ownersReply = &GetPropertyOwnersReply{}
+	err = s.GetPropertyOwners(nil, &GetPropertyOwnersArgs{AssetID: assetID.String()}, ownersReply)
+	assert.NoError(t, err)
+	assert.Len(t, ownersReply.Properties, 0)
+
+	supplyReply = &GetAssetSupplyReply{}
+	err = s.GetAssetSupply(nil, &GetAssetSupplyArgs{AssetID: assetID.String()}, supplyReply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), uint64(supplyReply.Supply))
+	assert.Equal(t, uint64(1), uint64(supplyReply.Burned))
+
+	err = s.BurnProperty(nil, &BurnPropertyArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		AssetID: assetID.String(),
+	}, &api.JsonTxID{})
+	assert.Error(t, err, "should have failed to burn a property that no longer exists")
+}
+
 func TestServiceGetTx(t *testing.T) {
 	genesisBytes, vm, s, _ := setup(t)
 	defer func() {
@@ -918,6 +1477,263 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func TestSendMultisig(t *testing.T) {
+	genesisBytes, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+
+	addr1Str, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2Str, err := vm.FormatLocalAddress(keys[2].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:      500,
+		AssetID:     assetID.String(),
+		ToAddresses: []string{addr2Str, addr1Str},
+		Threshold:   2,
+	}
+	reply := &api.JsonTxID{}
+	vm.timer.Cancel()
+	if err := s.Send(nil, args, reply); err != nil {
+		t.Fatalf("Failed to send transaction: %s", err)
+	}
+
+	pendingTxs := vm.txs
+	if len(pendingTxs) != 1 {
+		t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
+	}
+	tx := pendingTxs[0].(*UniqueTx)
+	utxos := tx.UTXOs()
+	found := false
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || out.Amt != 500 {
+			continue
+		}
+		found = true
+		if out.Threshold != 2 || len(out.Addrs) != 2 {
+			t.Fatalf("Expected a 2 of 2 multisig output, but found %d of %d", out.Threshold, len(out.Addrs))
+		}
+	}
+	if !found {
+		t.Fatal("Couldn't find the sent output")
+	}
+
+	args.Threshold = 3
+	if err := s.Send(nil, args, reply); err == nil {
+		t.Fatal("Should have errored due to an unspendable threshold")
+	}
+}
+
+func TestBuildSendAndSignTx(t *testing.T) {
+	genesisBytes, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	cosignerUsername := "alice"
+	userKeystore := keystore.CreateTestKeystore()
+	if err := userKeystore.AddUser(username, password); err != nil {
+		t.Fatal(err)
+	}
+	if err := userKeystore.AddUser(cosignerUsername, password); err != nil {
+		t.Fatal(err)
+	}
+	vm.ctx.Keystore = userKeystore.NewBlockchainKeyStore(chainID)
+
+	user := userState{vm: vm}
+	for name, sk := range map[string]*crypto.PrivateKeySECP256K1R{
+		username:         keys[1],
+		cosignerUsername: keys[2],
+	} {
+		db, err := vm.ctx.Keystore.GetDatabase(name, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := user.SetKey(db, sk); err != nil {
+			t.Fatal(err)
+		}
+		if err := user.SetAddresses(db, []ids.ShortID{sk.PublicKey().Address()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	assetID := genesisTx.ID()
+	addr1 := keys[1].PublicKey().Address()
+	addr2 := keys[2].PublicKey().Address()
+	owners := secp256k1fx.OutputOwners{
+		Threshold: 2,
+		Addrs:     []ids.ShortID{addr1, addr2},
+	}
+	owners.Sort()
+	utxo := &djtx.UTXO{
+		UTXOID: djtx.UTXOID{TxID: ids.Empty.Prefix(1)},
+		Asset:  djtx.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          1000,
+			OutputOwners: owners,
+		},
+	}
+	if err := vm.state.FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	addr0Str, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2Str, err := vm.FormatLocalAddress(addr2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendArgs := SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:  600,
+		AssetID: assetID.String(),
+		To:      addr0Str,
+	}
+	if err := s.Send(nil, &sendArgs, &api.JsonTxID{}); err == nil {
+		t.Fatal("Send shouldn't be able to spend a UTXO that the user only partially controls")
+	}
+
+	buildArgs := &BuildSendArgs{
+		SendArgs:  sendArgs,
+		Cosigners: []string{addr2Str},
+	}
+	buildReply := &PartiallySignedTxReply{}
+	if err := s.BuildSend(nil, buildArgs, buildReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(buildReply.MissingSigners) != 1 || buildReply.MissingSigners[0] != addr2Str {
+		t.Fatalf("Expected %s to be the only missing signer but got %v", addr2Str, buildReply.MissingSigners)
+	}
+	if _, err := vm.IssueTx(buildReply.Tx.Bytes); err == nil {
+		t.Fatal("Shouldn't have been able to issue a partially signed transaction")
+	}
+
+	// A signature that wasn't produced by the expected signer must not be
+	// merged into the transaction
+	forgedTx := &Tx{}
+	if err := vm.codec.Unmarshal(buildReply.Tx.Bytes, forgedTx); err != nil {
+		t.Fatal(err)
+	}
+	forgedCred := forgedTx.Creds[0].(*secp256k1fx.Credential)
+	for i, sig := range forgedCred.Sigs {
+		if sig == emptySignature {
+			continue
+		}
+		unsignedBytes, err := vm.codec.Marshal(&forgedTx.UnsignedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		forgedSig, err := keys[0].Sign(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copy(forgedCred.Sigs[i][:], forgedSig)
+	}
+	forgedBytes, err := vm.codec.Marshal(forgedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedArgs := &SignTxArgs{
+		UserPass: api.UserPass{
+			Username: cosignerUsername,
+			Password: password,
+		},
+		Tx: formatting.CB58{Bytes: forgedBytes},
+	}
+	if err := s.SignTx(nil, forgedArgs, &PartiallySignedTxReply{}); err == nil {
+		t.Fatal("Should have errored due to a signature of an unexpected signer")
+	}
+
+	// The change can be sent to a provided address
+	changeArgs := &BuildSendArgs{
+		SendArgs:   sendArgs,
+		Cosigners:  []string{addr2Str},
+		ChangeAddr: addr0Str,
+	}
+	changeReply := &PartiallySignedTxReply{}
+	if err := s.BuildSend(nil, changeArgs, changeReply); err != nil {
+		t.Fatal(err)
+	}
+	changeTx := &Tx{}
+	if err := vm.codec.Unmarshal(changeReply.Tx.Bytes, changeTx); err != nil {
+		t.Fatal(err)
+	}
+	changeSent := false
+	for _, out := range changeTx.UnsignedTx.(*BaseTx).Outs {
+		if out.Out.Amount() != 400 {
+			continue
+		}
+		changeSent = true
+		addrs := out.Out.(*secp256k1fx.TransferOutput).Addrs
+		if len(addrs) != 1 || !addrs[0].Equals(keys[0].PublicKey().Address()) {
+			t.Fatalf("Change should have been sent to %s", addr0Str)
+		}
+	}
+	if !changeSent {
+		t.Fatal("Couldn't find the change output")
+	}
+
+	signArgs := &SignTxArgs{
+		UserPass: api.UserPass{
+			Username: cosignerUsername,
+			Password: password,
+		},
+		Tx: buildReply.Tx,
+	}
+	signReply := &PartiallySignedTxReply{}
+	if err := s.SignTx(nil, signArgs, signReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(signReply.MissingSigners) != 0 {
+		t.Fatalf("Expected the transaction to be fully signed but missing %v", signReply.MissingSigners)
+	}
+
+	txID, err := vm.IssueTx(signReply.Tx.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to issue the fully signed transaction: %s", err)
+	}
+
+	tx := UniqueTx{vm: vm, txID: txID}
+	changeFound := false
+	for _, utxo := range tx.UTXOs() {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			t.Fatalf("Unexpected output type %T", utxo.Out)
+		}
+		if out.Amt == 400 {
+			changeFound = true
+			if !out.OutputOwners.Equals(&owners) {
+				t.Fatal("Change should have been returned to the multisig owners")
+			}
+		}
+	}
+	if !changeFound {
+		t.Fatal("Couldn't find the change output")
+	}
+}
+
 func TestCreateAndListAddresses(t *testing.T) {
 	_, vm, s, _ := setup(t)
 	defer func() {
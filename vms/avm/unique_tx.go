@@ -117,6 +117,11 @@ func (tx *UniqueTx) Accept() error {
 
 	defer tx.vm.db.Abort()
 
+	if err := tx.vm.indexTx(tx.Tx); err != nil {
+		tx.vm.ctx.Log.Error("Failed to index tx %s due to %s", tx.txID, err)
+		return err
+	}
+
 	// Remove spent utxos
 	for _, utxo := range tx.InputUTXOs() {
 		if utxo.Symbolic() {
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
@@ -24,12 +25,14 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
@@ -52,6 +55,12 @@ var (
 	errWrongBlockchainID         = errors.New("wrong blockchain ID")
 	errBootstrapping             = errors.New("chain is currently bootstrapping")
 	errInsufficientFunds         = errors.New("insufficient funds")
+	errWrongNumberOfUTXOs        = errors.New("operation should consume exactly one utxo")
+	errWrongNumberOfSignatures   = errors.New("credential has a different number of signatures than the input")
+	errInvalidSigIndex           = errors.New("signature index is out of range of the output's addresses")
+	errWrongSigner               = errors.New("signature was not produced by the expected signer")
+
+	emptySignature [crypto.SECP256K1RSigLen]byte
 )
 
 // VM implements the avalanche.DAGVM interface
@@ -78,6 +87,9 @@ type VM struct {
 	// fee that must be burned by every transaction
 	txFee uint64
 
+	// Set to true if the balances of every asset holder should be indexed
+	indexHolders bool
+
 	// Transaction issuing
 	timer        *timer.Timer
 	batchTimeout time.Duration
@@ -193,6 +205,9 @@ func (vm *VM) Initialize(
 			return err
 		}
 	}
+	if err := vm.initAssetIndex(); err != nil {
+		return err
+	}
 
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
@@ -403,6 +418,20 @@ func (vm *VM) GetUTXOs(
 	startAddr ids.ShortID,
 	startUTXOID ids.ID,
 	limit int,
+) ([]*djtx.UTXO, ids.ShortID, ids.ID, error) {
+	return vm.GetFilteredUTXOs(addrs, startAddr, startUTXOID, limit, nil)
+}
+
+// GetFilteredUTXOs is like GetUTXOs, but only returns UTXOs for which [filter]
+// returns true. UTXOs that are filtered out still advance the returned address
+// and UTXO ID, so they can be used for pagination. If [filter] is nil, no UTXOs
+// are filtered out.
+func (vm *VM) GetFilteredUTXOs(
+	addrs ids.ShortSet,
+	startAddr ids.ShortID,
+	startUTXOID ids.ID,
+	limit int,
+	filter func(*djtx.UTXO) bool,
 ) ([]*djtx.UTXO, ids.ShortID, ids.ID, error) {
 	if limit <= 0 || limit > maxUTXOsToFetch {
 		limit = maxUTXOsToFetch
@@ -415,37 +444,80 @@ func (vm *VM) GetUTXOs(
 	addrsList := addrs.List()
 	ids.SortShortIDs(addrsList)
 	for _, addr := range addrsList {
+		if limit <= 0 {
+			break // Found [limit] utxos; stop.
+		}
+
 		start := ids.Empty
 		if comp := bytes.Compare(addr.Bytes(), startAddr.Bytes()); comp == -1 { // Skip addresses before [startAddr]
 			continue
 		} else if comp == 0 {
 			start = startUTXOID
 		}
-		utxoIDs, err := vm.state.Funds(addr.Bytes(), start, limit) // Get UTXOs associated with [addr]
-		if err != nil {
-			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXOs for address %s", addr)
-		}
-		for _, utxoID := range utxoIDs {
-			if seen.Contains(utxoID) { // Already have this UTXO in the list
-				continue
-			}
-			utxo, err := vm.state.UTXO(utxoID)
+		for limit > 0 {
+			numToFetch := limit
+			utxoIDs, err := vm.state.Funds(addr.Bytes(), start, numToFetch) // Get UTXOs associated with [addr]
 			if err != nil {
-				return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+				return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXOs for address %s", addr)
 			}
-			utxos = append(utxos, utxo)
-			seen.Add(utxoID)
-			lastAddr = addr
-			lastIndex = utxoID
-			limit--
-			if limit <= 0 {
-				break // Found [limit] utxos; stop.
+			for _, utxoID := range utxoIDs {
+				start = utxoID
+				if seen.Contains(utxoID) { // Already have this UTXO in the list
+					continue
+				}
+				seen.Add(utxoID)
+
+				utxo, err := vm.state.UTXO(utxoID)
+				if err != nil {
+					return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+				}
+				lastAddr = addr
+				lastIndex = utxoID
+				if filter != nil && !filter(utxo) {
+					continue
+				}
+				utxos = append(utxos, utxo)
+				limit--
+				if limit <= 0 {
+					break // Found [limit] utxos; stop.
+				}
+			}
+			if len(utxoIDs) < numToFetch {
+				break // There are no more UTXOs for [addr]
 			}
 		}
 	}
 	return utxos, lastAddr, lastIndex, nil
 }
 
+// LockedFilter returns a UTXO filter that only accepts UTXOs whose timelocked
+// status at [time] is [locked]
+func LockedFilter(time uint64, locked bool) func(*djtx.UTXO) bool {
+	return func(utxo *djtx.UTXO) bool {
+		return (outputLocktime(utxo.Out) > time) == locked
+	}
+}
+
+// outputLocktime returns the time before which [out] can't be spent
+func outputLocktime(out verify.State) uint64 {
+	switch out := out.(type) {
+	case *secp256k1fx.TransferOutput:
+		return out.Locktime
+	case *secp256k1fx.MintOutput:
+		return out.Locktime
+	case *nftfx.TransferOutput:
+		return out.Locktime
+	case *nftfx.MintOutput:
+		return out.Locktime
+	case *propertyfx.OwnedOutput:
+		return out.Locktime
+	case *propertyfx.MintOutput:
+		return out.Locktime
+	default:
+		return 0
+	}
+}
+
 /*
  ******************************************************************************
  *********************************** Fx API ***********************************
@@ -519,6 +591,14 @@ func (vm *VM) initState(genesisBytes []byte) error {
 		return err
 	}
 
+	// The asset indices are built from genesis
+	if err := vm.state.SetAssetIndexStatus(&AssetIndexStatus{
+		FromGenesis: true,
+		Holders:     vm.indexHolders,
+	}); err != nil {
+		return err
+	}
+
 	for _, genesisTx := range genesis.Txs {
 		if len(genesisTx.Outs) != 0 {
 			return errGenesisAssetMustHaveState
@@ -539,6 +619,9 @@ func (vm *VM) initState(genesisBytes []byte) error {
 		if err := vm.state.SetStatus(txID, choices.Accepted); err != nil {
 			return err
 		}
+		if err := vm.indexTx(&tx); err != nil {
+			return err
+		}
 		for _, utxo := range tx.UTXOs() {
 			if err := vm.state.FundUTXO(utxo); err != nil {
 				return err
@@ -776,6 +859,7 @@ func (vm *VM) Spend(
 	error,
 ) {
 	amountsSpent := make(map[[32]byte]uint64, len(amounts))
+	amountsLocked := make(map[[32]byte]uint64, len(amounts))
 	time := vm.clock.Unix()
 
 	ins := []*djtx.TransferableInput{}
@@ -793,7 +877,20 @@ func (vm *VM) Spend(
 
 		inputIntf, signers, err := kc.Spend(utxo.Out, time)
 		if err != nil {
-			// this utxo can't be spent with the current keys right now
+			// this utxo can't be spent with the current keys right now. If it
+			// will become spendable once its timelock expires, keep track of
+			// it to report why the funds are insufficient.
+			out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+			if !ok || out.Locktime <= time {
+				continue
+			}
+			if _, _, able := kc.Match(&out.OutputOwners, out.Locktime); able {
+				amountLocked, err := safemath.Add64(amountsLocked[assetKey], out.Amt)
+				if err != nil {
+					amountLocked = math.MaxUint64
+				}
+				amountsLocked[assetKey] = amountLocked
+			}
 			continue
 		}
 		input, ok := inputIntf.(djtx.TransferableIn)
@@ -819,9 +916,15 @@ func (vm *VM) Spend(
 	}
 
 	for asset, amount := range amounts {
-		if amountsSpent[asset] < amount {
-			return nil, nil, nil, errInsufficientFunds
+		amountSpent := amountsSpent[asset]
+		if amountSpent >= amount {
+			continue
 		}
+		if amountLocked := amountsLocked[asset]; amountLocked > 0 {
+			return nil, nil, nil, fmt.Errorf("%w: %d of asset %s is spendable and %d is timelocked but %d is needed",
+				errInsufficientFunds, amountSpent, ids.NewID(asset), amountLocked, amount)
+		}
+		return nil, nil, nil, errInsufficientFunds
 	}
 
 	djtx.SortTransferableInputsWithSigners(ins, keys)
@@ -954,12 +1057,277 @@ func (vm *VM) SpendAll(
 	return amountsSpent, ins, keys, nil
 }
 
+// SpendPartial is like Spend, but also selects UTXOs that [kc] is only able to
+// sign when combined with [cosigners]. The returned inputs are not signed, the
+// signatures can be added with SignTx.
+func (vm *VM) SpendPartial(
+	utxos []*djtx.UTXO,
+	kc *secp256k1fx.Keychain,
+	cosigners ids.ShortSet,
+	amounts map[[32]byte]uint64,
+) (
+	map[[32]byte]uint64,
+	[]*djtx.TransferableInput,
+	error,
+) {
+	amountsSpent := make(map[[32]byte]uint64, len(amounts))
+	time := vm.clock.Unix()
+
+	ins := []*djtx.TransferableInput{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		assetKey := assetID.Key()
+		amount := amounts[assetKey]
+		amountSpent := amountsSpent[assetKey]
+
+		if amountSpent >= amount {
+			// we already have enough inputs allocated to this asset
+			continue
+		}
+
+		inputIntf, err := kc.SpendPartial(utxo.Out, time, cosigners)
+		if err != nil {
+			// this utxo can't be spent with the provided signers right now
+			continue
+		}
+		input, ok := inputIntf.(djtx.TransferableIn)
+		if !ok {
+			// this input doesn't have an amount, so I don't care about it here
+			continue
+		}
+		newAmountSpent, err := safemath.Add64(amountSpent, input.Amount())
+		if err != nil {
+			// there was an error calculating the consumed amount, just error
+			return nil, nil, errSpendOverflow
+		}
+		amountsSpent[assetKey] = newAmountSpent
+
+		// add the new input to the array
+		ins = append(ins, &djtx.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  djtx.Asset{ID: assetID},
+			In:     input,
+		})
+	}
+
+	for asset, amount := range amounts {
+		if amountsSpent[asset] < amount {
+			return nil, nil, errInsufficientFunds
+		}
+	}
+
+	djtx.SortTransferableInputs(ins)
+	return amountsSpent, ins, nil
+}
+
+// SignTx adds to [tx] every missing signature that can be produced by [kc].
+// Every credential of [tx] must already contain a signature slot for each of
+// the signature indices of the corresponding input. Unfilled slots are
+// represented by empty signatures. Signatures that were already provided must
+// have been produced by the expected signer. Returns the addresses whose
+// signatures are still missing after signing.
+func (vm *VM) SignTx(tx *Tx, kc *secp256k1fx.Keychain) ([]ids.ShortID, error) {
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return nil, fmt.Errorf("problem marshalling transaction: %w", err)
+	}
+
+	signers, err := vm.expectedSigners(tx.UnsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) != len(tx.Creds) {
+		return nil, errWrongNumberOfCredentials
+	}
+
+	hash := hashing.ComputeHash256(unsignedBytes)
+	factory := crypto.FactorySECP256K1R{}
+	missing := ids.ShortSet{}
+	for i, addrs := range signers {
+		cred, err := secpCredential(tx.Creds[i])
+		if err != nil {
+			return nil, err
+		}
+		if len(cred.Sigs) != len(addrs) {
+			return nil, errWrongNumberOfSignatures
+		}
+		for j, addr := range addrs {
+			if sig := cred.Sigs[j]; sig != emptySignature {
+				// this signature was already provided, make sure it wasn't
+				// forged or produced for a different transaction
+				pk, err := factory.RecoverHashPublicKey(hash, sig[:])
+				if err != nil {
+					return nil, fmt.Errorf("problem verifying signature: %w", err)
+				}
+				if !addr.Equals(pk.Address()) {
+					return nil, fmt.Errorf("%w: expected signature of %s", errWrongSigner, addr)
+				}
+				continue
+			}
+			key, exists := kc.Get(addr)
+			if !exists {
+				missing.Add(addr)
+				continue
+			}
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return nil, fmt.Errorf("problem signing transaction: %w", err)
+			}
+			copy(cred.Sigs[j][:], sig)
+		}
+	}
+
+	signedBytes, err := vm.codec.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("problem marshalling transaction: %w", err)
+	}
+	tx.Initialize(unsignedBytes, signedBytes)
+
+	missingList := missing.List()
+	ids.SortShortIDs(missingList)
+	return missingList, nil
+}
+
+// expectedSigners returns, for each credential of [tx], the addresses that are
+// expected to produce each of the credential's signatures.
+func (vm *VM) expectedSigners(tx UnsignedTx) ([][]ids.ShortID, error) {
+	var (
+		ins         []*djtx.TransferableInput
+		ops         []*Operation
+		sourceChain ids.ID
+		importedIns []*djtx.TransferableInput
+	)
+	switch tx := tx.(type) {
+	case *BaseTx:
+		ins = tx.Ins
+	case *CreateAssetTx:
+		ins = tx.Ins
+	case *OperationTx:
+		ins = tx.Ins
+		ops = tx.Ops
+	case *ImportTx:
+		ins = tx.Ins
+		sourceChain = tx.SourceChain
+		importedIns = tx.ImportedIns
+	case *ExportTx:
+		ins = tx.Ins
+	default:
+		return nil, fmt.Errorf("can't sign unexpected transaction type %T", tx)
+	}
+
+	signers := make([][]ids.ShortID, 0, tx.NumCredentials())
+	for _, in := range ins {
+		utxo, err := vm.getUTXO(&in.UTXOID)
+		if err != nil {
+			return nil, fmt.Errorf("problem fetching UTXO %s: %w", in.InputID(), err)
+		}
+		addrs, err := inputSigners(in.In, utxo.Out)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, addrs)
+	}
+	for _, op := range ops {
+		if len(op.UTXOIDs) != 1 {
+			return nil, errWrongNumberOfUTXOs
+		}
+		utxo, err := vm.getUTXO(op.UTXOIDs[0])
+		if err != nil {
+			return nil, fmt.Errorf("problem fetching UTXO %s: %w", op.UTXOIDs[0].InputID(), err)
+		}
+		addrs, err := inputSigners(op.Op, utxo.Out)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, addrs)
+	}
+	if len(importedIns) == 0 {
+		return signers, nil
+	}
+
+	utxoIDs := make([][]byte, len(importedIns))
+	for i, in := range importedIns {
+		utxoIDs[i] = in.UTXOID.InputID().Bytes()
+	}
+	allUTXOBytes, err := vm.ctx.SharedMemory.Get(sourceChain, utxoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("problem fetching atomic UTXOs: %w", err)
+	}
+	for i, in := range importedIns {
+		utxo := djtx.UTXO{}
+		if err := vm.codec.Unmarshal(allUTXOBytes[i], &utxo); err != nil {
+			return nil, fmt.Errorf("problem parsing atomic UTXO: %w", err)
+		}
+		addrs, err := inputSigners(in.In, utxo.Out)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, addrs)
+	}
+	return signers, nil
+}
+
+// inputSigners returns the addresses of [out] that are referenced by the
+// signature indices of [in].
+func inputSigners(in interface{}, out interface{}) ([]ids.ShortID, error) {
+	var sigIndices []uint32
+	switch in := in.(type) {
+	case *secp256k1fx.TransferInput:
+		sigIndices = in.SigIndices
+	case *secp256k1fx.MintOperation:
+		sigIndices = in.MintInput.SigIndices
+	case *nftfx.MintOperation:
+		sigIndices = in.MintInput.SigIndices
+	case *nftfx.TransferOperation:
+		sigIndices = in.Input.SigIndices
+	case *propertyfx.MintOperation:
+		sigIndices = in.MintInput.SigIndices
+	case *propertyfx.BurnOperation:
+		sigIndices = in.Input.SigIndices
+	default:
+		return nil, fmt.Errorf("can't sign unexpected input type %T", in)
+	}
+
+	addressable, ok := out.(djtx.Addressable)
+	if !ok {
+		return nil, fmt.Errorf("can't sign for unexpected output type %T", out)
+	}
+	outAddrs := addressable.Addresses()
+
+	addrs := make([]ids.ShortID, len(sigIndices))
+	for i, index := range sigIndices {
+		if index >= uint32(len(outAddrs)) {
+			return nil, errInvalidSigIndex
+		}
+		addr, err := ids.ToShortID(outAddrs[index])
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
+// secpCredential returns the secp256k1 credential that backs [cred]
+func secpCredential(cred verify.Verifiable) (*secp256k1fx.Credential, error) {
+	switch cred := cred.(type) {
+	case *secp256k1fx.Credential:
+		return cred, nil
+	case *nftfx.Credential:
+		return &cred.Credential, nil
+	case *propertyfx.Credential:
+		return &cred.Credential, nil
+	default:
+		return nil, fmt.Errorf("can't sign unexpected credential type %T", cred)
+	}
+}
+
 // Mint ...
 func (vm *VM) Mint(
 	utxos []*djtx.UTXO,
 	kc *secp256k1fx.Keychain,
 	amounts map[[32]byte]uint64,
-	to ids.ShortID,
+	to *secp256k1fx.OutputOwners,
 ) (
 	[]*Operation,
 	[][]*crypto.PrivateKeySECP256K1R,
@@ -1004,11 +1372,8 @@ func (vm *VM) Mint(
 				MintInput:  *in,
 				MintOutput: *out,
 				TransferOutput: secp256k1fx.TransferOutput{
-					Amt: amount,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Threshold: 1,
-						Addrs:     []ids.ShortID{to},
-					},
+					Amt:          amount,
+					OutputOwners: *to,
 				},
 			},
 		})
@@ -1101,6 +1466,114 @@ func (vm *VM) MintNFT(
 	return ops, keys, nil
 }
 
+// MintProperty creates an operation that mints a property of [assetID] owned
+// by [to]. The mint output is spent using keys in [kc].
+func (vm *VM) MintProperty(
+	utxos []*djtx.UTXO,
+	kc *secp256k1fx.Keychain,
+	assetID ids.ID,
+	to *secp256k1fx.OutputOwners,
+) (
+	[]*Operation,
+	[][]*crypto.PrivateKeySECP256K1R,
+	error,
+) {
+	time := vm.clock.Unix()
+
+	for _, utxo := range utxos {
+		if !utxo.AssetID().Equals(assetID) {
+			// wrong asset id
+			continue
+		}
+		out, ok := utxo.Out.(*propertyfx.MintOutput)
+		if !ok {
+			// wrong output type
+			continue
+		}
+
+		indices, signers, ok := kc.Match(&out.OutputOwners, time)
+		if !ok {
+			// unable to spend the output
+			continue
+		}
+
+		op := &Operation{
+			Asset:   djtx.Asset{ID: assetID},
+			UTXOIDs: []*djtx.UTXOID{&utxo.UTXOID},
+			Op: &propertyfx.MintOperation{
+				MintInput: secp256k1fx.Input{
+					SigIndices: indices,
+				},
+				MintOutput: *out,
+				OwnedOutput: propertyfx.OwnedOutput{
+					OutputOwners: *to,
+				},
+			},
+		}
+		return []*Operation{op}, [][]*crypto.PrivateKeySECP256K1R{signers}, nil
+	}
+	return nil, nil, errAddressesCantMintAsset
+}
+
+// SpendProperty creates an operation that burns a property of [assetID] owned
+// by keys in [kc]. If [utxoID] isn't ids.Empty, only that property is burned.
+func (vm *VM) SpendProperty(
+	utxos []*djtx.UTXO,
+	kc *secp256k1fx.Keychain,
+	assetID ids.ID,
+	utxoID ids.ID,
+) (
+	[]*Operation,
+	[][]*crypto.PrivateKeySECP256K1R,
+	error,
+) {
+	time := vm.clock.Unix()
+
+	for _, utxo := range utxos {
+		if !utxo.AssetID().Equals(assetID) {
+			// wrong asset id
+			continue
+		}
+		if !utxoID.IsZero() && !utxo.InputID().Equals(utxoID) {
+			// wrong property
+			continue
+		}
+		out, ok := utxo.Out.(*propertyfx.OwnedOutput)
+		if !ok {
+			// wrong output type
+			continue
+		}
+
+		indices, signers, ok := kc.Match(&out.OutputOwners, time)
+		if !ok {
+			// unable to spend the output
+			continue
+		}
+
+		op := &Operation{
+			Asset:   djtx.Asset{ID: assetID},
+			UTXOIDs: []*djtx.UTXOID{&utxo.UTXOID},
+			Op: &propertyfx.BurnOperation{
+				Input: secp256k1fx.Input{
+					SigIndices: indices,
+				},
+			},
+		}
+		return []*Operation{op}, [][]*crypto.PrivateKeySECP256K1R{signers}, nil
+	}
+	return nil, nil, errInsufficientFunds
+}
+
+// getFxIndex returns the index of the fx with ID [fxID] in this VM's fxs
+func (vm *VM) getFxIndex(fxID ids.ID) (uint32, error) {
+	for i, fx := range vm.fxs {
+		if fx.ID.Equals(fxID) {
+			return uint32(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", errUnknownFx, fxID)
+}
+
 // ParseLocalAddress takes in an address for this chain and produces the ID
 func (vm *VM) ParseLocalAddress(addrStr string) (ids.ShortID, error) {
 	chainID, addr, err := vm.ParseAddress(addrStr)
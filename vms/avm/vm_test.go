@@ -214,7 +214,7 @@ func GenesisVM(t *testing.T) ([]byte, chan common.Message, *VM, *atomic.Memory)
 	ctx.Keystore = userKeystore.NewBlockchainKeyStore(ctx.ChainID)
 
 	issuer := make(chan common.Message, 1)
-	vm := &VM{}
+	vm := &VM{indexHolders: true}
 	err := vm.Initialize(
 		ctx,
 		prefixdb.New([]byte{1}, baseDB),
@@ -229,6 +229,10 @@ func GenesisVM(t *testing.T) ([]byte, chan common.Message, *VM, *atomic.Memory)
 				ID: nftfx.ID,
 				Fx: &nftfx.Fx{},
 			},
+			{
+				ID: propertyfx.ID,
+				Fx: &propertyfx.Fx{},
+			},
 		},
 	)
 	if err != nil {
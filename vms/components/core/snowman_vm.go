@@ -129,6 +129,16 @@ func (svm *SnowmanVM) NotifyBlockReady() {
 	}
 }
 
+// NotifyGossipTxs tells the consensus engine that this VM has new unconfirmed
+// transactions that should be gossiped
+func (svm *SnowmanVM) NotifyGossipTxs() {
+	select {
+	case svm.ToEngine <- common.GossipTxs:
+	default:
+		svm.Ctx.Log.Debug("dropping message to consensus engine")
+	}
+}
+
 // NewHandler returns a new Handler for a service where:
 //   * The handler's functionality is defined by [service]
 //     [service] should be a gorilla RPC service (see https://www.gorillatoolkit.org/pkg/rpc/v2)
@@ -6,6 +6,7 @@ package platformvm
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -23,9 +24,11 @@ import (
 )
 
 var (
-	errDelegatorSubset = errors.New("delegator's time range must be a subset of the validator's time range")
-	errInvalidState    = errors.New("generated output isn't valid state")
-	errInvalidAmount   = errors.New("invalid amount")
+	errDelegatorSubset         = errors.New("delegator's time range must be a subset of the validator's time range")
+	errInvalidState            = errors.New("generated output isn't valid state")
+	errInvalidAmount           = errors.New("invalid amount")
+	errOverDelegated           = errors.New("validator would be over delegated")
+	errValidatorWeightTooLarge = errors.New("validator's weight would be too large")
 
 	_ UnsignedProposalTx = &UnsignedAddDelegatorTx{}
 	_ TimedTx            = &UnsignedAddDelegatorTx{}
@@ -59,7 +62,7 @@ func (tx *UnsignedAddDelegatorTx) Verify(
 	c codec.Codec,
 	feeAmount uint64,
 	feeAssetID ids.ID,
-	minStake uint64,
+	params *StakingParameters,
 ) error {
 	switch {
 	case tx == nil:
@@ -74,6 +77,9 @@ func (tx *UnsignedAddDelegatorTx) Verify(
 	if err := verify.All(&tx.Validator, tx.RewardsOwner); err != nil {
 		return err
 	}
+	if err := params.verifyStakeDuration(&tx.Validator); err != nil {
+		return err
+	}
 
 	totalStakeWeight := uint64(0)
 	for _, out := range tx.Stake {
@@ -92,7 +98,7 @@ func (tx *UnsignedAddDelegatorTx) Verify(
 		return errOutputsNotSorted
 	case totalStakeWeight != tx.Validator.Wght:
 		return errInvalidAmount
-	case tx.Validator.Wght < minStake:
+	case tx.Validator.Wght < params.MinDelegatorStake:
 		// Ensure validator is staking at least the minimum amount
 		return errWeightTooSmall
 	}
@@ -115,7 +121,7 @@ func (tx *UnsignedAddDelegatorTx) SemanticVerify(
 	TxError,
 ) {
 	// Verify the tx is well-formed
-	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err != nil {
+	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err != nil {
 		return nil, nil, nil, nil, permError{err}
 	}
 
@@ -134,20 +140,40 @@ func (tx *UnsignedAddDelegatorTx) SemanticVerify(
 	if err != nil {
 		return nil, nil, nil, nil, tempError{err}
 	}
-	if isValidator && !tx.Validator.BoundedBy(vdr.StartTime(), vdr.EndTime()) {
-		return nil, nil, nil, nil, permError{errDelegatorSubset}
-	}
 	if !isValidator {
 		// Ensure that the period this delegator delegates is a subset of the
 		// time the validator will validates.
-		vdr, willBeValidator, err := vm.willBeValidator(db, constants.PrimaryNetworkID, tx.Validator.NodeID)
+		var willBeValidator bool
+		vdr, willBeValidator, err = vm.willBeValidator(db, constants.PrimaryNetworkID, tx.Validator.NodeID)
 		if err != nil {
 			return nil, nil, nil, nil, tempError{err}
 		}
-		if !willBeValidator || !tx.Validator.BoundedBy(vdr.StartTime(), vdr.EndTime()) {
+		if !willBeValidator {
 			return nil, nil, nil, nil, permError{errDelegatorSubset}
 		}
 	}
+	if !tx.Validator.BoundedBy(vdr.StartTime(), vdr.EndTime()) {
+		return nil, nil, nil, nil, permError{errDelegatorSubset}
+	}
+	vdrTx, ok := vdr.(*UnsignedAddValidatorTx)
+	if !ok {
+		return nil, nil, nil, nil, permError{fmt.Errorf("expected validator to be *UnsignedAddValidatorTx but got %T", vdr)}
+	}
+
+	// Ensure that the validator doesn't exceed its delegation limits at any
+	// point during the delegation period
+	delegators, err := vm.getDelegators(db, tx.Validator.NodeID)
+	if err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
+	delegatedWeight := maxConcurrentWeight(delegators, tx.StartTime(), tx.EndTime())
+	newDelegatedWeight, err := safemath.Add64(delegatedWeight, tx.Validator.Wght)
+	if err != nil {
+		return nil, nil, nil, nil, permError{err}
+	}
+	if err := vm.stakingParams.verifyDelegation(vdrTx.Validator.Wght, newDelegatedWeight); err != nil {
+		return nil, nil, nil, nil, permError{err}
+	}
 
 	outs := make([]*djtx.TransferableOutput, len(tx.Outs)+len(tx.Stake))
 	copy(outs, tx.Outs)
@@ -190,6 +216,50 @@ func (tx *UnsignedAddDelegatorTx) SemanticVerify(
 	return onCommitDB, onAbortDB, nil, nil, nil
 }
 
+// maxConcurrentWeight returns the largest total weight of [delegators] that
+// delegate at the same time during the period [startTime, endTime). A
+// delegator that stops delegating at the same time another starts does not
+// overlap with it.
+func maxConcurrentWeight(delegators []*UnsignedAddDelegatorTx, startTime, endTime time.Time) uint64 {
+	type event struct {
+		time   time.Time
+		weight uint64
+		start  bool
+	}
+	events := make([]event, 0, 2*len(delegators))
+	for _, delegator := range delegators {
+		delegatorStart, delegatorEnd := delegator.StartTime(), delegator.EndTime()
+		if !delegatorStart.Before(endTime) || !delegatorEnd.After(startTime) {
+			continue // doesn't overlap with the period
+		}
+		events = append(events,
+			event{time: delegatorStart, weight: delegator.Validator.Wght, start: true},
+			event{time: delegatorEnd, weight: delegator.Validator.Wght},
+		)
+	}
+	// Process the delegators that stop before the ones that start at the same
+	// time
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].time.Equal(events[j].time) {
+			return events[i].time.Before(events[j].time)
+		}
+		return !events[i].start && events[j].start
+	})
+
+	current, max := uint64(0), uint64(0)
+	for _, e := range events {
+		if !e.start {
+			current -= e.weight
+			continue
+		}
+		current += e.weight
+		if current > max {
+			max = current
+		}
+	}
+	return max
+}
+
 // InitiallyPrefersCommit returns true if the proposed validators start time is
 // after the current wall clock time,
 func (tx *UnsignedAddDelegatorTx) InitiallyPrefersCommit(vm *VM) bool {
@@ -205,7 +275,21 @@ func (vm *VM) newAddDelegatorTx(
 	rewardAddress ids.ShortID, // Address to returned staked tokens (and maybe reward) to
 	keys []*crypto.PrivateKeySECP256K1R, // Keys providing the staked tokens + fee
 ) (*Tx, error) {
-	ins, unlockedOuts, lockedOuts, signers, err := vm.stake(vm.DB, keys, stakeAmt, vm.txFee)
+	return vm.buildAddDelegatorTx(vm.DB, stakeAmt, startTime, endTime, nodeID, rewardAddress, keys)
+}
+
+// buildAddDelegatorTx is newAddDelegatorTx, except that the staked tokens
+// and the fee are spent from the UTXOs in [db]
+func (vm *VM) buildAddDelegatorTx(
+	db database.Database, // Database holding the UTXOs to spend
+	stakeAmt, // Amount the delegator stakes
+	startTime, // Unix time they start delegating
+	endTime uint64, // Unix time they stop delegating
+	nodeID ids.ShortID, // ID of the node we are delegating to
+	rewardAddress ids.ShortID, // Address to returned staked tokens (and maybe reward) to
+	keys []*crypto.PrivateKeySECP256K1R, // Keys providing the staked tokens + fee
+) (*Tx, error) {
+	ins, unlockedOuts, lockedOuts, signers, err := vm.stake(db, keys, stakeAmt, vm.txFee)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -234,5 +318,5 @@ func (vm *VM) newAddDelegatorTx(
 	if err := tx.Sign(vm.codec, signers); err != nil {
 		return nil, err
 	}
-	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake)
+	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams)
 }
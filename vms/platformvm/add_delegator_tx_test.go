@@ -4,11 +4,13 @@
 package platformvm
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -28,13 +30,13 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case : tx is nil
 	var unsignedTx *UnsignedAddDelegatorTx
-	if err := unsignedTx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err == nil {
+	if err := unsignedTx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err == nil {
 		t.Fatal("should have errored because tx is nil")
 	}
 
 	// Case: Wrong network ID
 	tx, err := vm.newAddDelegatorTx(
-		vm.minStake,
+		vm.stakingParams.MinDelegatorStake,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
@@ -47,13 +49,13 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).NetworkID++
 	// This tx was syntactically verified when it was created...pretend it wasn't so we don't use cache
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).syntacticallyVerified = false
-	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err == nil {
+	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err == nil {
 		t.Fatal("should have errored because the wrong network ID was used")
 	}
 
 	// Case: Missing Node ID
 	tx, err = vm.newAddDelegatorTx(
-		vm.minStake,
+		vm.stakingParams.MinDelegatorStake,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
@@ -66,13 +68,13 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).Validator.NodeID = ids.ShortID{}
 	// This tx was syntactically verified when it was created...pretend it wasn't so we don't use cache
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).syntacticallyVerified = false
-	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err == nil {
+	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err == nil {
 		t.Fatal("should have errored because NodeID is nil")
 	}
 
 	// Case: Not enough weight
 	tx, err = vm.newAddDelegatorTx(
-		vm.minStake,
+		vm.stakingParams.MinDelegatorStake,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
@@ -82,16 +84,16 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	tx.UnsignedTx.(*UnsignedAddDelegatorTx).Validator.Wght = vm.minStake - 1
+	tx.UnsignedTx.(*UnsignedAddDelegatorTx).Validator.Wght = vm.stakingParams.MinDelegatorStake - 1
 	// This tx was syntactically verified when it was created...pretend it wasn't so we don't use cache
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).syntacticallyVerified = false
-	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err == nil {
+	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err == nil {
 		t.Fatal("should have errored because of not enough weight")
 	}
 
 	// Case: Validation length is too short
 	tx, err = vm.newAddDelegatorTx(
-		vm.minStake,
+		vm.stakingParams.MinDelegatorStake,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix()),
 		nodeID,
@@ -104,13 +106,13 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).Validator.End-- // 1 shorter than minimum stake time
 	// This tx was syntactically verified when it was created...pretend it wasn't so we don't use cache
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).syntacticallyVerified = false
-	if err = tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err == nil {
+	if err = tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err == nil {
 		t.Fatal("should have errored because validation length too short")
 	}
 
 	// Case: Validation length is too long
 	if tx, err = vm.newAddDelegatorTx(
-		vm.minStake,
+		vm.stakingParams.MinDelegatorStake,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix()),
 		nodeID,
@@ -122,13 +124,13 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).Validator.End++ // 1 longer than maximum stake time
 	// This tx was syntactically verified when it was created...pretend it wasn't so we don't use cache
 	tx.UnsignedTx.(*UnsignedAddDelegatorTx).syntacticallyVerified = false
-	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err == nil {
+	if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err == nil {
 		t.Fatal("should have errored because validation length too long")
 	}
 
 	// Case: Valid
 	if tx, err = vm.newAddDelegatorTx(
-		vm.minStake,
+		vm.stakingParams.MinDelegatorStake,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		nodeID,
@@ -136,7 +138,7 @@ func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 		[]*crypto.PrivateKeySECP256K1R{keys[0]},
 	); err != nil {
 		t.Fatal(err)
-	} else if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err != nil {
+	} else if err := tx.UnsignedTx.(*UnsignedAddDelegatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -167,7 +169,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 	// [addValidator] adds a new validator to the primary network's pending validator set
 	addValidator := func(db database.Database) {
 		if tx, err := vm.newAddValidatorTx(
-			vm.minStake,                             // stake amount
+			vm.stakingParams.MinValidatorStake,      // stake amount
 			newValidatorStartTime,                   // start time
 			newValidatorEndTime,                     // end time
 			newValidatorID,                          // node ID
@@ -198,7 +200,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 
 	tests := []test{
 		{
-			vm.minStake,
+			vm.stakingParams.MinDelegatorStake,
 			uint64(defaultValidateStartTime.Unix()),
 			uint64(defaultValidateEndTime.Unix()) + 1,
 			nodeID,
@@ -209,7 +211,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"validator stops validating primary network earlier than subnet",
 		},
 		{
-			vm.minStake,
+			vm.stakingParams.MinDelegatorStake,
 			uint64(defaultValidateStartTime.Unix()),
 			uint64(defaultValidateEndTime.Unix()) + 1,
 			nodeID,
@@ -220,7 +222,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"end time is after the primary network end time",
 		},
 		{
-			vm.minStake,
+			vm.stakingParams.MinDelegatorStake,
 			uint64(defaultValidateStartTime.Add(5 * time.Second).Unix()),
 			uint64(defaultValidateEndTime.Add(-5 * time.Second).Unix()),
 			newValidatorID,
@@ -231,7 +233,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"validator not in the current or pending validator sets of the subnet",
 		},
 		{
-			vm.minStake,
+			vm.stakingParams.MinDelegatorStake,
 			newValidatorStartTime - 1, // start validating subnet before primary network
 			newValidatorEndTime,
 			newValidatorID,
@@ -242,7 +244,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"validator starts validating subnet before primary network",
 		},
 		{
-			vm.minStake,
+			vm.stakingParams.MinDelegatorStake,
 			newValidatorStartTime,
 			newValidatorEndTime + 1, // stop validating subnet after stopping validating primary network
 			newValidatorID,
@@ -253,7 +255,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"validator stops validating primary network before subnet",
 		},
 		{
-			vm.minStake,
+			vm.stakingParams.MinDelegatorStake,
 			newValidatorStartTime, // same start time as for primary network
 			newValidatorEndTime,   // same end time as for primary network
 			newValidatorID,
@@ -264,7 +266,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"valid",
 		},
 		{
-			vm.minStake, // weight
+			vm.stakingParams.MinDelegatorStake, // weight
 			uint64(currentTimestamp.Unix()),
 			uint64(defaultValidateEndTime.Unix()),
 			nodeID,                                  // node ID
@@ -275,7 +277,7 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 			"starts validating at current timestamp",
 		},
 		{
-			vm.minStake,                             // weight
+			vm.stakingParams.MinDelegatorStake,      // weight
 			uint64(defaultValidateStartTime.Unix()), // start time
 			uint64(defaultValidateEndTime.Unix()),   // end time
 			nodeID,                                  // node ID
@@ -322,3 +324,192 @@ func TestAddDelegatorTxSemanticVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestAddDelegatorTxDelegationLimits(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vdb := versiondb.New(vm.DB) // so tests don't interfere with one another
+
+	vdrStake := vm.stakingParams.MinValidatorStake
+	maxDelegated := vdrStake * vm.stakingParams.MaxDelegationFactor
+	vdrID := ids.GenerateTestShortID()
+	vdrStartTime := defaultValidateStartTime.Add(5 * time.Second)
+	vdrEndTime := vdrStartTime.Add(2 * MinimumStakingDuration)
+	midTime := vdrStartTime.Add(MinimumStakingDuration)
+
+	vdrTx, err := vm.newAddValidatorTx(
+		vdrStake,
+		uint64(vdrStartTime.Unix()),
+		uint64(vdrEndTime.Unix()),
+		vdrID,
+		vdrID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The existing delegator delegates during the first half of the
+	// validation period
+	delegatorTx, err := vm.newAddDelegatorTx(
+		maxDelegated/2,
+		uint64(vdrStartTime.Unix()),
+		uint64(midTime.Unix()),
+		vdrID,
+		vdrID,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup := func(db database.Database) {
+		if err := vm.enqueueStaker(db, constants.PrimaryNetworkID, vdrTx); err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.enqueueStaker(db, constants.PrimaryNetworkID, delegatorTx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		description string
+		stakeAmount uint64
+		startTime   time.Time
+		endTime     time.Time
+		expectedErr error
+	}{
+		{
+			description: "overlaps the existing delegator within the limit",
+			stakeAmount: maxDelegated / 2,
+			startTime:   vdrStartTime,
+			endTime:     vdrEndTime,
+		},
+		{
+			description: "overlaps the existing delegator beyond the limit",
+			stakeAmount: maxDelegated/2 + 1,
+			startTime:   vdrStartTime,
+			endTime:     vdrEndTime,
+			expectedErr: errOverDelegated,
+		},
+		{
+			description: "starts when the existing delegator stops",
+			stakeAmount: maxDelegated,
+			startTime:   midTime,
+			endTime:     vdrEndTime,
+		},
+		{
+			description: "exceeds the limit on its own",
+			stakeAmount: maxDelegated + 1,
+			startTime:   midTime,
+			endTime:     vdrEndTime,
+			expectedErr: errOverDelegated,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			vdb.Abort()
+			setup(vdb)
+			tx, err := vm.newAddDelegatorTx(
+				test.stakeAmount,
+				uint64(test.startTime.Unix()),
+				uint64(test.endTime.Unix()),
+				vdrID,
+				vdrID,
+				[]*crypto.PrivateKeySECP256K1R{keys[0]},
+			)
+			if err != nil {
+				t.Fatalf("couldn't build tx: %s", err)
+			}
+			_, _, _, _, err = tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vdb, tx)
+			switch {
+			case test.expectedErr == nil && err != nil:
+				t.Fatalf("shouldn't have errored but got %s", err)
+			case test.expectedErr != nil && !errors.Is(err, test.expectedErr):
+				t.Fatalf("expected %q but got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDelegatorIndex(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vdb := versiondb.New(vm.DB)
+
+	vdrID := ids.GenerateTestShortID()
+	startTime := defaultValidateStartTime.Add(5 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	delegatorTx, err := vm.newAddDelegatorTx(
+		vm.stakingParams.MinDelegatorStake,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		vdrID,
+		vdrID,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.enqueueStaker(vdb, constants.PrimaryNetworkID, delegatorTx); err != nil {
+		t.Fatal(err)
+	}
+	delegators, err := vm.getDelegators(vdb, vdrID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delegators) != 1 || !delegators[0].ID().Equals(delegatorTx.ID()) {
+		t.Fatalf("expected the pending delegator to be indexed but got %v", delegators)
+	}
+	if delegators, err := vm.getDelegators(vdb, ids.GenerateTestShortID()); err != nil {
+		t.Fatal(err)
+	} else if len(delegators) != 0 {
+		t.Fatalf("expected no delegators but got %d", len(delegators))
+	}
+
+	// Delegators added before delegators were indexed are indexed on startup
+	if err := vm.removeDelegator(vdb, vdrID, delegatorTx.ID()); err != nil {
+		t.Fatal(err)
+	}
+	indexedDB := prefixdb.NewNested([]byte(delegatorIndexedDBPrefix), vdb)
+	if err := indexedDB.Delete([]byte(delegatorIndexedDBPrefix)); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.indexDelegators(vdb); err != nil {
+		t.Fatal(err)
+	}
+	if delegators, err := vm.getDelegators(vdb, vdrID); err != nil {
+		t.Fatal(err)
+	} else if len(delegators) != 1 {
+		t.Fatalf("expected the pending delegator to be indexed but got %d delegators", len(delegators))
+	}
+
+	// Once removed from the current stakers, the delegator isn't indexed
+	if err := vm.dequeueStaker(vdb, constants.PrimaryNetworkID, delegatorTx); err != nil {
+		t.Fatal(err)
+	}
+	stakerTx := &rewardTx{Tx: *delegatorTx}
+	if err := vm.addStaker(vdb, constants.PrimaryNetworkID, stakerTx); err != nil {
+		t.Fatal(err)
+	}
+	if delegators, err := vm.getDelegators(vdb, vdrID); err != nil {
+		t.Fatal(err)
+	} else if len(delegators) != 1 {
+		t.Fatalf("expected the current delegator to be indexed but got %d delegators", len(delegators))
+	}
+	if err := vm.removeStaker(vdb, constants.PrimaryNetworkID, stakerTx); err != nil {
+		t.Fatal(err)
+	}
+	if delegators, err := vm.getDelegators(vdb, vdrID); err != nil {
+		t.Fatal(err)
+	} else if len(delegators) != 0 {
+		t.Fatalf("expected no delegators but got %d", len(delegators))
+	}
+}
@@ -52,6 +52,7 @@ func (tx *UnsignedAddSubnetValidatorTx) Verify(
 	c codec.Codec,
 	feeAmount uint64,
 	feeAssetID ids.ID,
+	params *StakingParameters,
 ) error {
 	switch {
 	case tx == nil:
@@ -66,6 +67,9 @@ func (tx *UnsignedAddSubnetValidatorTx) Verify(
 	if err := verify.All(&tx.Validator, tx.SubnetAuth); err != nil {
 		return err
 	}
+	if err := params.verifyStakeDuration(&tx.Validator.Validator); err != nil {
+		return err
+	}
 
 	// cache that this is valid
 	tx.syntacticallyVerified = true
@@ -88,7 +92,7 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 	if len(stx.Creds) == 0 {
 		return nil, nil, nil, nil, permError{errWrongNumberOfCredentials}
 	}
-	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err != nil {
 		return nil, nil, nil, nil, permError{err}
 	}
 
@@ -146,12 +150,11 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 	baseTxCreds := stx.Creds[:baseTxCredsLen]
 	subnetCred := stx.Creds[baseTxCredsLen]
 
-	subnet, timedErr := vm.getSubnet(db, tx.Validator.Subnet)
-	if err != nil {
+	owner, timedErr := vm.getSubnetOwner(db, tx.Validator.Subnet)
+	if timedErr != nil {
 		return nil, nil, nil, nil, timedErr
 	}
-	unsignedSubnet := subnet.UnsignedTx.(*UnsignedCreateSubnetTx)
-	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, unsignedSubnet.Owner); err != nil {
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, owner); err != nil {
 		return nil, nil, nil, nil, permError{err}
 	}
 
@@ -205,12 +208,26 @@ func (vm *VM) newAddSubnetValidatorTx(
 	subnetID ids.ID, // ID of the subnet the validator will validate
 	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for adding the validator
 ) (*Tx, error) {
-	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.txFee)
+	return vm.buildAddSubnetValidatorTx(vm.DB, weight, startTime, endTime, nodeID, subnetID, keys)
+}
+
+// buildAddSubnetValidatorTx is newAddSubnetValidatorTx, except that the fee is
+// spent from the UTXOs in [db]
+func (vm *VM) buildAddSubnetValidatorTx(
+	db database.Database, // Database holding the UTXOs to spend
+	weight, // Sampling weight of the new validator
+	startTime, // Unix time they start delegating
+	endTime uint64, // Unix time they top delegating
+	nodeID ids.ShortID, // ID of the node validating
+	subnetID ids.ID, // ID of the subnet the validator will validate
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for adding the validator
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(db, keys, 0, vm.txFee)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
 
-	subnetAuth, subnetSigners, err := vm.authorize(vm.DB, subnetID, keys)
+	subnetAuth, subnetSigners, err := vm.authorize(db, subnetID, keys)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
 	}
@@ -239,5 +256,5 @@ func (vm *VM) newAddSubnetValidatorTx(
 	if err := tx.Sign(vm.codec, signers); err != nil {
 		return nil, err
 	}
-	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID)
+	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams)
 }
@@ -25,6 +25,7 @@ import (
 var (
 	errNilTx          = errors.New("tx is nil")
 	errWeightTooSmall = errors.New("weight of this validator is too low")
+	errWeightTooLarge = errors.New("weight of this validator is too large")
 	errStakeTooShort  = errors.New("staking period is too short")
 	errStakeTooLong   = errors.New("staking period is too long")
 	errTooManyShares  = fmt.Errorf("a staker can only require at most %d shares from delegators", PercentDenominator)
@@ -64,7 +65,7 @@ func (tx *UnsignedAddValidatorTx) Verify(
 	c codec.Codec,
 	feeAmount uint64,
 	feeAssetID ids.ID,
-	minStake uint64,
+	params *StakingParameters,
 ) error {
 	switch {
 	case tx == nil:
@@ -79,6 +80,9 @@ func (tx *UnsignedAddValidatorTx) Verify(
 	if err := verify.All(&tx.Validator, tx.RewardsOwner); err != nil {
 		return err
 	}
+	if err := params.verifyStakeDuration(&tx.Validator); err != nil {
+		return err
+	}
 
 	totalStakeWeight := uint64(0)
 	for _, out := range tx.Stake {
@@ -97,8 +101,10 @@ func (tx *UnsignedAddValidatorTx) Verify(
 		return errOutputsNotSorted
 	case totalStakeWeight != tx.Validator.Wght:
 		return errInvalidAmount
-	case tx.Validator.Wght < minStake: // Ensure validator is staking at least the minimum amount
+	case tx.Validator.Wght < params.MinValidatorStake: // Ensure validator is staking at least the minimum amount
 		return errWeightTooSmall
+	case tx.Validator.Wght > params.MaxValidatorStake: // Ensure validator isn't staking more than the maximum amount
+		return errWeightTooLarge
 	case tx.Shares > PercentDenominator: // Ensure delegators shares are in the allowed amount
 		return errTooManyShares
 	}
@@ -121,7 +127,7 @@ func (tx *UnsignedAddValidatorTx) SemanticVerify(
 	TxError,
 ) {
 	// Verify the tx is well-formed
-	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake); err != nil {
+	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams); err != nil {
 		return nil, nil, nil, nil, permError{err}
 	}
 
@@ -240,5 +246,5 @@ func (vm *VM) newAddValidatorTx(
 	if err := tx.Sign(vm.codec, signers); err != nil {
 		return nil, err
 	}
-	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, vm.minStake)
+	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID, &vm.stakingParams)
 }
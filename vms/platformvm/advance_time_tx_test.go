@@ -39,7 +39,7 @@ func TestAdvanceTimeTxTimestampTooLate(t *testing.T) {
 	nodeIDKey, _ := vm.factory.NewPrivateKey()
 	nodeID := nodeIDKey.PublicKey().Address()
 	addPendingValidatorTx, err := vm.newAddValidatorTx(
-		vm.minStake,
+		vm.stakingParams.MinValidatorStake,
 		uint64(pendingValidatorStartTime.Unix()),
 		uint64(pendingValidatorEndTime.Unix()),
 		nodeID,
@@ -99,7 +99,7 @@ func TestAdvanceTimeTxUpdateValidators(t *testing.T) {
 	nodeIDKey, _ := vm.factory.NewPrivateKey()
 	nodeID := nodeIDKey.PublicKey().Address()
 	addPendingValidatorTx, err := vm.newAddValidatorTx(
-		vm.minStake,
+		vm.stakingParams.MinValidatorStake,
 		uint64(pendingValidatorStartTime.Unix()),
 		uint64(pendingValidatorEndTime.Unix()),
 		nodeID,
@@ -82,7 +82,7 @@ func (ab *AtomicBlock) Verify() error {
 
 	ab.onAcceptDB = versiondb.New(pdb)
 	if err := tx.SemanticVerify(ab.vm, ab.onAcceptDB, &ab.Tx); err != nil {
-		ab.vm.droppedTxCache.Put(ab.Tx.ID(), nil) // cache tx as dropped
+		ab.vm.mempool.Evict(ab.Tx.ID()) // drop tx and the txs spending its outputs
 		return err
 	}
 	txBytes := ab.Tx.Bytes()
@@ -90,6 +90,10 @@ func (ab *AtomicBlock) Verify() error {
 		return err
 	} else if err := ab.vm.putStatus(ab.onAcceptDB, ab.Tx.ID(), Committed); err != nil {
 		return err
+	} else if err := ab.vm.putTxBlockID(ab.onAcceptDB, ab.Tx.ID(), ab.ID()); err != nil {
+		return err
+	} else if err := ab.vm.putAtomicTx(ab.onAcceptDB, ab.Height(), ab.Tx.ID()); err != nil {
+		return err
 	}
 
 	ab.vm.currentBlocks[ab.ID().Key()] = ab
@@ -115,6 +119,9 @@ func (ab *AtomicBlock) Accept() error {
 		ab.vm.Ctx.Log.Error("unable to commit onAcceptDB")
 		return err
 	}
+	if err := ab.vm.indexValidatorChanges(ab.vm.DB, ab.Height()); err != nil {
+		return err
+	}
 
 	batch, err := ab.vm.DB.CommitBatch()
 	if err != nil {
@@ -127,6 +134,7 @@ func (ab *AtomicBlock) Accept() error {
 		ab.vm.Ctx.Log.Error("unable to atomically commit block")
 		return err
 	}
+	ab.vm.summarizeState(ab.ID(), ab.Height())
 
 	for _, child := range ab.children {
 		child.setBaseDatabase(ab.vm.DB)
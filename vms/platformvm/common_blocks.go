@@ -129,6 +129,26 @@ type CommonBlock struct {
 	children []Block
 }
 
+// Accept implements the snowman.Block interface. It also indexes this block by
+// its height, along with the chain's timestamp when the block was executed.
+// Must be called before the block's state changes are committed.
+// Recall that cb.vm.DB.Commit() must be called to persist to the DB
+func (cb *CommonBlock) Accept() error {
+	if err := cb.Block.Accept(); err != nil {
+		return err
+	}
+
+	blkID := cb.ID()
+	if err := cb.vm.putAcceptedBlockID(cb.vm.DB, cb.Height(), blkID); err != nil {
+		return err
+	}
+	timestamp, err := cb.vm.getTimestamp(cb.vm.DB)
+	if err != nil {
+		return err
+	}
+	return cb.vm.putBlockTime(cb.vm.DB, blkID, timestamp)
+}
+
 // Reject implements the snowman.Block interface
 func (cb *CommonBlock) Reject() error {
 	defer cb.free() // remove this block from memory
@@ -229,10 +249,14 @@ func (sdb *SingleDecisionBlock) Accept() error {
 		sdb.vm.Ctx.Log.Warn("unable to commit onAcceptDB")
 		return err
 	}
+	if err := sdb.vm.indexValidatorChanges(sdb.vm.DB, sdb.Height()); err != nil {
+		return err
+	}
 	if err := sdb.vm.DB.Commit(); err != nil {
 		sdb.vm.Ctx.Log.Warn("unable to commit vm's DB")
 		return err
 	}
+	sdb.vm.summarizeState(sdb.ID(), sdb.Height())
 
 	for _, child := range sdb.children {
 		child.setBaseDatabase(sdb.vm.DB)
@@ -275,10 +299,14 @@ func (ddb *DoubleDecisionBlock) Accept() error {
 		ddb.vm.Ctx.Log.Warn("unable to commit onAcceptDB: %s", err)
 		return err
 	}
+	if err := ddb.vm.indexValidatorChanges(ddb.vm.DB, ddb.Height()); err != nil {
+		return err
+	}
 	if err := ddb.vm.DB.Commit(); err != nil {
 		ddb.vm.Ctx.Log.Warn("unable to commit vm's DB: %s", err)
 		return err
 	}
+	ddb.vm.summarizeState(ddb.ID(), ddb.Height())
 
 	for _, child := range ddb.children {
 		child.setBaseDatabase(ddb.vm.DB)
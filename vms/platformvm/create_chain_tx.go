@@ -133,12 +133,11 @@ func (tx *UnsignedCreateChainTx) SemanticVerify(
 	}
 
 	// Verify that this chain is authorized by the subnet
-	subnet, err := vm.getSubnet(db, tx.SubnetID)
+	owner, err := vm.getSubnetOwner(db, tx.SubnetID)
 	if err != nil {
 		return nil, err
 	}
-	unsignedSubnet := subnet.UnsignedTx.(*UnsignedCreateSubnetTx)
-	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, unsignedSubnet.Owner); err != nil {
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, owner); err != nil {
 		return nil, permError{err}
 	}
 
@@ -21,8 +21,8 @@ func TestTxHeapStart(t *testing.T) {
 	txHeap := EventHeap{SortByStartTime: true}
 
 	validator0, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+1), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -35,8 +35,8 @@ func TestTxHeapStart(t *testing.T) {
 	vdr0Tx := validator0.UnsignedTx.(*UnsignedAddValidatorTx)
 
 	validator1, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+2), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+2),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+2), // endTime
 		ids.NewShortID([20]byte{1}),                                     // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -49,8 +49,8 @@ func TestTxHeapStart(t *testing.T) {
 	vdr1Tx := validator1.UnsignedTx.(*UnsignedAddValidatorTx)
 
 	validator2, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+3), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+3),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+3), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -91,8 +91,8 @@ func TestTxHeapStop(t *testing.T) {
 	txHeap := EventHeap{}
 
 	validator0, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+1), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -105,8 +105,8 @@ func TestTxHeapStop(t *testing.T) {
 	vdr0Tx := validator0.UnsignedTx.(*UnsignedAddValidatorTx)
 
 	validator1, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+2), // endTime
 		ids.NewShortID([20]byte{1}),                                     // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -119,8 +119,8 @@ func TestTxHeapStop(t *testing.T) {
 	vdr1Tx := validator1.UnsignedTx.(*UnsignedAddValidatorTx)
 
 	validator2, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+3), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -161,8 +161,8 @@ func TestTxHeapStartValidatorVsDelegatorOrdering(t *testing.T) {
 	txHeap := EventHeap{SortByStartTime: true}
 
 	validator, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+1), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -174,8 +174,8 @@ func TestTxHeapStartValidatorVsDelegatorOrdering(t *testing.T) {
 	}
 
 	delegator, err := vm.newAddDelegatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+1), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -204,8 +204,8 @@ func TestTxHeapStopValidatorVsDelegatorOrdering(t *testing.T) {
 	txHeap := EventHeap{}
 
 	validator, err := vm.newAddValidatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+1), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -217,8 +217,8 @@ func TestTxHeapStopValidatorVsDelegatorOrdering(t *testing.T) {
 	}
 
 	delegator, err := vm.newAddDelegatorTx(
-		vm.minStake,                         // stake amount
-		uint64(defaultGenesisTime.Unix()+1), // startTime
+		vm.stakingParams.MinValidatorStake,                              // stake amount
+		uint64(defaultGenesisTime.Unix()+1),                             // startTime
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()+1), // endTime
 		ids.NewShortID([20]byte{}),                                      // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}),                   // reward address
@@ -21,7 +21,6 @@ type Factory struct {
 	Validators       validators.Manager
 	StakingEnabled   bool
 	Fee              uint64
-	MinStake         uint64
 	UptimePercentage float64
 }
 
@@ -32,7 +31,6 @@ func (f *Factory) New(*snow.Context) (interface{}, error) {
 		vdrMgr:           f.Validators,
 		stakingEnabled:   f.StakingEnabled,
 		txFee:            f.Fee,
-		minStake:         f.MinStake,
 		uptimePercentage: f.UptimePercentage,
 	}, nil
 }
@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// gossipedTxsPerSecond is the rate at which each peer may gossip new
+	// transactions to this node
+	gossipedTxsPerSecond = 10
+
+	// maxGossipedTxBurst is the number of new transactions that a peer may
+	// gossip to this node at once
+	maxGossipedTxBurst = 100
+
+	// gossipLimiterSize is the number of peers whose gossip rate is tracked.
+	// When exceeded, the peer that gossiped least recently is forgotten.
+	gossipLimiterSize = 1024
+)
+
+// gossipLimiter limits the rate at which each peer may gossip transactions to
+// this node, so that a peer can't make this node spend all of its time parsing
+// and verifying gossiped transactions
+type gossipLimiter struct {
+	clock *timer.Clock
+
+	// Key: peer ID
+	// Value: *gossipBucket
+	buckets cache.LRU
+}
+
+// gossipBucket holds the number of transactions that a peer may still gossip
+type gossipBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// Initialize this limiter
+func (l *gossipLimiter) Initialize(clock *timer.Clock) {
+	l.clock = clock
+	l.buckets = cache.LRU{Size: gossipLimiterSize}
+}
+
+// Allow returns true, and consumes one of the transactions that [peerID] may
+// gossip, if [peerID] hasn't exceeded its gossip rate
+func (l *gossipLimiter) Allow(peerID ids.ShortID) bool {
+	now := l.clock.Time()
+	key := peerID.LongID()
+
+	bucket := &gossipBucket{
+		tokens:     maxGossipedTxBurst,
+		lastRefill: now,
+	}
+	if bucketIntf, ok := l.buckets.Get(key); ok {
+		bucket = bucketIntf.(*gossipBucket)
+		if elapsed := now.Sub(bucket.lastRefill); elapsed > 0 {
+			bucket.tokens += elapsed.Seconds() * gossipedTxsPerSecond
+			if bucket.tokens > maxGossipedTxBurst {
+				bucket.tokens = maxGossipedTxBurst
+			}
+			bucket.lastRefill = now
+		}
+	} else {
+		l.buckets.Put(key, bucket)
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
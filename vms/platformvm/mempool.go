@@ -0,0 +1,572 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+)
+
+const (
+	// maxMempoolBytes is the maximum total size, in bytes, of the transactions
+	// in the mempool
+	maxMempoolBytes = 64 * 1024 * 1024
+
+	// maxGossipQueueLen is the maximum number of transactions waiting to be
+	// gossiped. When exceeded, the oldest transactions are not gossiped.
+	maxGossipQueueLen = 1024
+)
+
+var (
+	errDuplicateTx  = errors.New("transaction is already in the mempool")
+	errMempoolFull  = errors.New("mempool is full")
+	errStartTooLate = errors.New("staker's start time is too soon to be added to the chain")
+)
+
+// Mempool holds the transactions that have been verified against the
+// preferred state but not yet put into a block.
+//
+// A transaction may spend the outputs of transactions in the mempool. Such a
+// transaction is verified against the preferred state with the transactions
+// whose outputs it spends applied, and isn't put into a block before them.
+//
+// Transactions are deduplicated by ID, and the total size of the mempool is
+// capped at [maxMempoolBytes].
+type Mempool struct {
+	vm *VM
+
+	// Total size, in bytes, of the transactions in the mempool
+	bytes int
+
+	// Key: tx ID
+	// Value: the tx
+	txs map[[32]byte]*Tx
+
+	// Key: ID of a tx in the mempool
+	// Value: IDs of the txs whose outputs it spends
+	spends map[[32]byte][]ids.ID
+
+	// Key: tx ID
+	// Value: IDs of the txs in the mempool that spend its outputs
+	spenders map[[32]byte]ids.Set
+
+	// Transactions that have not been put into blocks yet
+	unissuedProposalTxs *proposalTxHeap
+	unissuedDecisionTxs []*Tx
+	unissuedAtomicTxs   []*Tx
+
+	// IDs of the transactions that were added to the mempool, but haven't
+	// been gossiped yet
+	gossipQueue []ids.ID
+}
+
+// Initialize this mempool
+func (m *Mempool) Initialize(vm *VM) {
+	m.vm = vm
+	m.txs = make(map[[32]byte]*Tx)
+	m.spends = make(map[[32]byte][]ids.ID)
+	m.spenders = make(map[[32]byte]ids.Set)
+	m.unissuedProposalTxs = &proposalTxHeap{
+		EventHeap: EventHeap{SortByStartTime: true},
+		indices:   make(map[[32]byte]int),
+	}
+}
+
+// Len returns the number of transactions in the mempool
+func (m *Mempool) Len() int { return len(m.txs) }
+
+// Bytes returns the total size, in bytes, of the transactions in the mempool
+func (m *Mempool) Bytes() int { return m.bytes }
+
+// Has returns true if the transaction [txID] is in the mempool
+func (m *Mempool) Has(txID ids.ID) bool {
+	_, ok := m.txs[txID.Key()]
+	return ok
+}
+
+// Txs returns the transactions in the mempool. Proposal transactions are
+// returned in the order of their start time.
+func (m *Mempool) Txs() []*Tx {
+	txs := make([]*Tx, 0, len(m.txs))
+	txs = append(txs, m.unissuedDecisionTxs...)
+	txs = append(txs, m.unissuedAtomicTxs...)
+	proposalTxs := &EventHeap{
+		SortByStartTime: true,
+		Txs:             append([]*Tx(nil), m.unissuedProposalTxs.Txs...),
+	}
+	for proposalTxs.Len() > 0 {
+		txs = append(txs, proposalTxs.Remove())
+	}
+	return txs
+}
+
+// Add [tx] to the mempool if it is valid with respect to the preferred state.
+// [tx] must be initialized.
+func (m *Mempool) Add(tx *Tx) error {
+	txID := tx.ID()
+	if m.Has(txID) {
+		return errDuplicateTx
+	}
+	txSize := len(tx.Bytes())
+	if m.bytes+txSize > maxMempoolBytes {
+		return errMempoolFull
+	}
+	if err := m.verify(tx); err != nil {
+		return err
+	}
+
+	switch tx.UnsignedTx.(type) {
+	case TimedTx:
+		m.unissuedProposalTxs.Add(tx)
+	case UnsignedDecisionTx:
+		m.unissuedDecisionTxs = append(m.unissuedDecisionTxs, tx)
+	case UnsignedAtomicTx:
+		m.unissuedAtomicTxs = append(m.unissuedAtomicTxs, tx)
+	default:
+		return errUnknownTxType
+	}
+	m.txs[txID.Key()] = tx
+	m.bytes += txSize
+
+	spends := spentTxIDs(tx)
+	m.spends[txID.Key()] = spends
+	for _, spentTxID := range spends {
+		spenders := m.spenders[spentTxID.Key()]
+		if spenders == nil {
+			spenders = ids.Set{}
+			m.spenders[spentTxID.Key()] = spenders
+		}
+		spenders.Add(txID)
+	}
+
+	m.gossipQueue = append(m.gossipQueue, txID)
+	if len(m.gossipQueue) > maxGossipQueueLen {
+		m.gossipQueue = m.gossipQueue[len(m.gossipQueue)-maxGossipQueueLen:]
+	}
+	return nil
+}
+
+// Remove the transaction [txID] from the mempool, if it is in the mempool
+func (m *Mempool) Remove(txID ids.ID) {
+	key := txID.Key()
+	tx, ok := m.txs[key]
+	if !ok {
+		return
+	}
+	delete(m.txs, key)
+	m.bytes -= len(tx.Bytes())
+
+	for _, spentTxID := range m.spends[key] {
+		spenders := m.spenders[spentTxID.Key()]
+		spenders.Remove(txID)
+		if spenders.Len() == 0 {
+			delete(m.spenders, spentTxID.Key())
+		}
+	}
+	delete(m.spends, key)
+
+	switch tx.UnsignedTx.(type) {
+	case TimedTx:
+		m.unissuedProposalTxs.Remove(txID)
+	case UnsignedDecisionTx:
+		m.unissuedDecisionTxs = removeTx(m.unissuedDecisionTxs, tx)
+	case UnsignedAtomicTx:
+		m.unissuedAtomicTxs = removeTx(m.unissuedAtomicTxs, tx)
+	}
+}
+
+// Evict the transaction [txID], and the transactions in the mempool that spend
+// its outputs, directly or through other transactions in the mempool, from the
+// mempool and mark them as dropped. [txID] doesn't need to be in the mempool.
+func (m *Mempool) Evict(txID ids.ID) {
+	m.Remove(txID)
+	m.vm.droppedTxCache.Put(txID, nil) // cache tx as dropped
+	for _, childID := range m.children(txID) {
+		// [childID] may have been evicted as the descendant of a previous child
+		if m.Has(childID) {
+			m.vm.Ctx.Log.Debug("evicting tx %s from the mempool as it spends the outputs of dropped tx %s", childID, txID)
+			m.Evict(childID)
+		}
+	}
+}
+
+// HasDecisionTxs returns true if there are decision transactions in the
+// mempool
+func (m *Mempool) HasDecisionTxs() bool { return len(m.unissuedDecisionTxs) > 0 }
+
+// PopDecisionTxs removes, and returns, up to [numTxs] decision transactions
+// from the mempool
+func (m *Mempool) PopDecisionTxs(numTxs int) []*Tx {
+	if numTxs > len(m.unissuedDecisionTxs) {
+		numTxs = len(m.unissuedDecisionTxs)
+	}
+	txs := append([]*Tx(nil), m.unissuedDecisionTxs[:numTxs]...)
+	for _, tx := range txs {
+		m.Remove(tx.ID())
+	}
+	return txs
+}
+
+// HasAtomicTxs returns true if there are atomic transactions in the mempool
+func (m *Mempool) HasAtomicTxs() bool { return len(m.unissuedAtomicTxs) > 0 }
+
+// PopAtomicTx removes, and returns, the oldest atomic transaction in the
+// mempool. There must be an atomic transaction in the mempool.
+func (m *Mempool) PopAtomicTx() *Tx {
+	tx := m.unissuedAtomicTxs[0]
+	m.Remove(tx.ID())
+	return tx
+}
+
+// HasProposalTxs returns true if there are proposal transactions in the
+// mempool
+func (m *Mempool) HasProposalTxs() bool { return m.unissuedProposalTxs.Len() > 0 }
+
+// PeekProposalTx returns the proposal transaction in the mempool with the
+// earliest start time. There must be a proposal transaction in the mempool.
+func (m *Mempool) PeekProposalTx() *Tx { return m.unissuedProposalTxs.Peek() }
+
+// PopProposalTx removes, and returns, the proposal transaction in the mempool
+// with the earliest start time. If that transaction spends the outputs of a
+// proposal transaction in the mempool, the earliest such proposal transaction
+// is returned instead. There must be a proposal transaction in the mempool.
+func (m *Mempool) PopProposalTx() *Tx {
+	tx := m.unissuedProposalTxs.Peek()
+	for _, ancestor := range m.ancestors(tx) {
+		if _, ok := ancestor.UnsignedTx.(TimedTx); ok {
+			tx = ancestor
+			break
+		}
+	}
+	m.Remove(tx.ID())
+	return tx
+}
+
+// DropExpiredProposalTxs evicts the staker transactions whose start time is
+// before [syncTime], along with the transactions that spend their outputs. Such
+// transactions can never be put into a block.
+func (m *Mempool) DropExpiredProposalTxs(syncTime time.Time) {
+	for m.HasProposalTxs() {
+		tx := m.PeekProposalTx()
+		if !syncTime.After(tx.UnsignedTx.(TimedTx).StartTime()) {
+			return
+		}
+		m.vm.Ctx.Log.Debug("dropping tx %s to add staker because its start time has passed", tx.ID())
+		m.Evict(tx.ID())
+	}
+}
+
+// Revalidate re-verifies the transactions in the mempool that may have been
+// invalidated by the preference moving from [oldPreferredID] to the preferred
+// block. Transactions that are no longer valid are evicted.
+//
+// If the new preference extends the old one, the transactions that conflict
+// with the newly preferred blocks are evicted and, if those blocks change the
+// stakers, subnets or chain time, the transactions that depend on them are
+// re-verified. Otherwise, every transaction is re-verified.
+func (m *Mempool) Revalidate(oldPreferredID ids.ID) {
+	txs := m.Txs()
+	added, err := m.vm.preferredBlocksSince(oldPreferredID)
+	if err != nil {
+		m.vm.Ctx.Log.Debug("re-verifying every tx in the mempool: %s", err)
+		m.revalidate(txs)
+		return
+	}
+
+	addedTxIDs := ids.Set{}
+	consumed := ids.Set{}
+	stateChanged := false
+	for _, blk := range added {
+		blkTxs, changesState := blockTxs(blk)
+		stateChanged = stateChanged || changesState
+		for _, tx := range blkTxs {
+			addedTxIDs.Add(tx.ID())
+			consumed.Union(consumedInputs(tx))
+			stateChanged = stateChanged || !changesOnlyUTXOs(tx)
+		}
+	}
+
+	affected := []*Tx(nil)
+	for _, tx := range txs {
+		txID := tx.ID()
+		switch {
+		case !m.Has(txID):
+			// The transaction was evicted along with a transaction whose
+			// outputs it spends
+		case addedTxIDs.Contains(txID):
+			// The transaction is already in a preferred block
+			m.Remove(txID)
+		case consumed.Overlaps(consumedInputs(tx)):
+			// The transaction conflicts with a preferred block. Verifying it
+			// would only report its inputs as missing, which is temporary.
+			m.vm.Ctx.Log.Debug("evicting tx %s from the mempool as it conflicts with a preferred block", txID)
+			m.Evict(txID)
+		case stateChanged && !dependsOnlyOnUTXOs(tx):
+			affected = append(affected, tx)
+		}
+	}
+	m.revalidate(affected)
+}
+
+// revalidate re-verifies [txs] against the preferred state. Transactions that
+// are no longer valid are evicted, along with the transactions that spend their
+// outputs.
+func (m *Mempool) revalidate(txs []*Tx) {
+	for _, tx := range txs {
+		if !m.Has(tx.ID()) {
+			continue
+		}
+		if err := m.verify(tx); err != nil {
+			if txErr, ok := err.(TxError); ok && txErr.Temporary() {
+				continue
+			}
+			m.vm.Ctx.Log.Debug("evicting tx %s from the mempool: %s", tx.ID(), err)
+			m.Evict(tx.ID())
+		}
+	}
+}
+
+// PopGossipTxs removes, and returns, the transactions that should be gossiped
+func (m *Mempool) PopGossipTxs() []*Tx {
+	txs := make([]*Tx, 0, len(m.gossipQueue))
+	for _, txID := range m.gossipQueue {
+		if tx, ok := m.txs[txID.Key()]; ok {
+			txs = append(txs, tx)
+		}
+	}
+	m.gossipQueue = nil
+	return txs
+}
+
+// verify returns nil if [tx] would be valid if it were put into a block on
+// top of the preferred block
+func (m *Mempool) verify(tx *Tx) error {
+	preferred, err := m.vm.getBlock(m.vm.Preferred())
+	if err != nil {
+		return fmt.Errorf("couldn't get preferred block: %w", err)
+	}
+	// The preferred block should always be a decision block
+	preferredDecision, ok := preferred.(decision)
+	if !ok {
+		return errInvalidBlockType
+	}
+	db := versiondb.New(preferredDecision.onAccept())
+	defer db.Abort()
+
+	// Apply the transactions in the mempool whose outputs [tx] spends
+	for _, ancestor := range m.ancestors(tx) {
+		consumed, produced := chainUTXOs(ancestor)
+		for _, utxoID := range consumed {
+			if err := m.vm.removeUTXO(db, utxoID.InputID()); err != nil {
+				return tempError{err}
+			}
+		}
+		for _, utxo := range produced {
+			if err := m.vm.putUTXO(db, utxo); err != nil {
+				return tempError{err}
+			}
+		}
+	}
+
+	switch utx := tx.UnsignedTx.(type) {
+	case UnsignedProposalTx:
+		if timedTx, ok := utx.(TimedTx); ok {
+			syncTime := m.vm.clock.Time().Add(Delta)
+			if syncTime.After(timedTx.StartTime()) {
+				return errStartTooLate
+			}
+		}
+		if _, _, _, _, err := utx.SemanticVerify(m.vm, db, tx); err != nil {
+			return err
+		}
+	case UnsignedDecisionTx:
+		if _, err := utx.SemanticVerify(m.vm, db, tx); err != nil {
+			return err
+		}
+	case UnsignedAtomicTx:
+		if err := utx.SemanticVerify(m.vm, db, tx); err != nil {
+			return err
+		}
+	default:
+		return errUnknownTxType
+	}
+	return nil
+}
+
+// ancestors returns the transactions in the mempool whose outputs [tx] spends,
+// directly or through other transactions in the mempool. A transaction is
+// returned after the transactions whose outputs it spends.
+func (m *Mempool) ancestors(tx *Tx) []*Tx {
+	ancestors := []*Tx(nil)
+	visited := ids.Set{}
+	var visit func(spends []ids.ID)
+	visit = func(spends []ids.ID) {
+		for _, parentID := range spends {
+			parent, ok := m.txs[parentID.Key()]
+			if !ok || visited.Contains(parentID) {
+				continue
+			}
+			visited.Add(parentID)
+			visit(m.spends[parentID.Key()])
+			ancestors = append(ancestors, parent)
+		}
+	}
+	// [tx] may not be in the mempool yet
+	spends, ok := m.spends[tx.ID().Key()]
+	if !ok {
+		spends = spentTxIDs(tx)
+	}
+	visit(spends)
+	return ancestors
+}
+
+// children returns the IDs of the transactions in the mempool that spend the
+// outputs of the transaction [txID]
+func (m *Mempool) children(txID ids.ID) []ids.ID {
+	return m.spenders[txID.Key()].List()
+}
+
+// spentTxIDs returns the IDs of the transactions whose outputs [tx] spends, in
+// the order they are first spent
+func spentTxIDs(tx *Tx) []ids.ID {
+	spent := []ids.ID(nil)
+	spentSet := ids.Set{}
+	consumed, _ := chainUTXOs(tx)
+	for _, utxoID := range consumed {
+		if !spentSet.Contains(utxoID.TxID) {
+			spentSet.Add(utxoID.TxID)
+			spent = append(spent, utxoID.TxID)
+		}
+	}
+	return spent
+}
+
+// chainUTXOs returns the UTXOs of this chain that [tx] consumes and produces
+func chainUTXOs(tx *Tx) ([]*djtx.UTXOID, []*djtx.UTXO) {
+	switch utx := tx.UnsignedTx.(type) {
+	case *UnsignedImportTx:
+		// The imported UTXOs are in shared memory
+		return utx.BaseTx.InputUTXOs(), utx.UTXOs()
+	case interface {
+		InputUTXOs() []*djtx.UTXOID
+		UTXOs() []*djtx.UTXO
+	}:
+		return utx.InputUTXOs(), utx.UTXOs()
+	default:
+		return nil, nil
+	}
+}
+
+// blockTxs returns the transactions of [blk], and true if accepting [blk]
+// changes the stakers or the chain time even though it contains no
+// transactions
+func blockTxs(blk Block) ([]*Tx, bool) {
+	switch blk := blk.(type) {
+	case *StandardBlock:
+		return blk.Txs, false
+	case *AtomicBlock:
+		return []*Tx{&blk.Tx}, false
+	case *ProposalBlock:
+		return []*Tx{&blk.Tx}, false
+	default:
+		// Commit and abort blocks decide the proposal of their parent
+		return nil, true
+	}
+}
+
+// consumedInputs returns the IDs of the UTXOs consumed by [tx]
+func consumedInputs(tx *Tx) ids.Set {
+	inputs := ids.Set{}
+	if importTx, ok := tx.UnsignedTx.(*UnsignedImportTx); ok {
+		inputs.Union(importTx.InputUTXOs())
+		for _, utxoID := range importTx.BaseTx.InputUTXOs() {
+			inputs.Add(utxoID.InputID())
+		}
+		return inputs
+	}
+	if spender, ok := tx.UnsignedTx.(interface{ InputUTXOs() []*djtx.UTXOID }); ok {
+		for _, utxoID := range spender.InputUTXOs() {
+			inputs.Add(utxoID.InputID())
+		}
+	}
+	return inputs
+}
+
+// changesOnlyUTXOs returns true if accepting [tx] doesn't change anything, other
+// than UTXOs, that other transactions may depend on
+func changesOnlyUTXOs(tx *Tx) bool {
+	switch tx.UnsignedTx.(type) {
+	case *UnsignedImportTx, *UnsignedExportTx, *UnsignedCreateSubnetTx, *UnsignedCreateChainTx:
+		return true
+	default:
+		return false
+	}
+}
+
+// dependsOnlyOnUTXOs returns true if the validity of [tx] depends on nothing but
+// the UTXOs that it consumes
+func dependsOnlyOnUTXOs(tx *Tx) bool {
+	switch tx.UnsignedTx.(type) {
+	case *UnsignedImportTx, *UnsignedExportTx, *UnsignedCreateSubnetTx:
+		return true
+	default:
+		return false
+	}
+}
+
+// removeTx returns [txs] without [tx]
+func removeTx(txs []*Tx, tx *Tx) []*Tx {
+	for i, otherTx := range txs {
+		if otherTx == tx {
+			return append(txs[:i], txs[i+1:]...)
+		}
+	}
+	return txs
+}
+
+// proposalTxHeap is an EventHeap that tracks the index of each of its
+// transactions so that any of them can be removed without a linear scan
+type proposalTxHeap struct {
+	EventHeap
+
+	// Key: tx ID
+	// Value: index of the tx in [Txs]
+	indices map[[32]byte]int
+}
+
+func (h *proposalTxHeap) Swap(i, j int) {
+	h.EventHeap.Swap(i, j)
+	h.indices[h.Txs[i].ID().Key()] = i
+	h.indices[h.Txs[j].ID().Key()] = j
+}
+
+// Add [tx] to the heap
+func (h *proposalTxHeap) Add(tx *Tx) { heap.Push(h, tx) }
+
+// Remove the transaction [txID] from the heap, if it is in the heap
+func (h *proposalTxHeap) Remove(txID ids.ID) {
+	if i, ok := h.indices[txID.Key()]; ok {
+		heap.Remove(h, i)
+	}
+}
+
+// Push implements the heap interface
+func (h *proposalTxHeap) Push(x interface{}) {
+	tx := x.(*Tx)
+	h.indices[tx.ID().Key()] = len(h.Txs)
+	h.EventHeap.Push(tx)
+}
+
+// Pop implements the heap interface
+func (h *proposalTxHeap) Pop() interface{} {
+	tx := h.EventHeap.Pop().(*Tx)
+	delete(h.indices, tx.ID().Key())
+	return tx
+}
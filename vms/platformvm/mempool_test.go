@@ -0,0 +1,379 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+func TestMempoolDeduplicatesAndVerifies(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	tx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.issueTx(tx))
+	assert.Equal(t, errDuplicateTx, vm.issueTx(tx))
+	assert.True(t, vm.mempool.Has(tx.ID()))
+	assert.Equal(t, 1, vm.mempool.Len())
+	assert.Equal(t, len(tx.Bytes()), vm.mempool.Bytes())
+
+	service := &Service{vm: vm}
+	status := Unknown
+	assert.NoError(t, service.GetTxStatus(nil, &GetTxStatusArgs{TxID: tx.ID()}, &status))
+	assert.Equal(t, Processing, status)
+
+	reply := GetMempoolReply{}
+	assert.NoError(t, service.GetMempool(nil, nil, &reply))
+	assert.EqualValues(t, 1, reply.NumTxs)
+	assert.EqualValues(t, len(tx.Bytes()), reply.Bytes)
+	if assert.Len(t, reply.Txs, 1) {
+		assert.Equal(t, tx.ID(), reply.Txs[0].TxID)
+		assert.Equal(t, "decision", reply.Txs[0].Type)
+	}
+
+	// A staker that would start before the chain could add it is invalid
+	startTime := defaultGenesisTime.Add(Delta).Add(-1 * time.Second)
+	nodeID := ids.GenerateTestShortID()
+	invalidTx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	assert.Error(t, vm.issueTx(invalidTx))
+	assert.False(t, vm.mempool.Has(invalidTx.ID()))
+
+	blk, err := vm.BuildBlock()
+	assert.NoError(t, err)
+	assert.NoError(t, blk.Verify())
+	assert.NoError(t, blk.Accept())
+	assert.Equal(t, 0, vm.mempool.Len())
+	assert.Equal(t, 0, vm.mempool.Bytes())
+
+	// Now that the tx has been accepted, it can't be added again
+	assert.Error(t, vm.issueTx(tx))
+}
+
+func TestMempoolEvictsExpiredStakers(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	nodeID := ids.GenerateTestShortID()
+	tx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.issueTx(tx))
+	assert.True(t, vm.mempool.Has(tx.ID()))
+
+	// Once the staker's start time is within the synchrony bound, the tx can
+	// no longer be put into a block
+	vm.clock.Set(defaultGenesisTime.Add(2 * time.Second))
+	vm.resetTimer()
+	assert.False(t, vm.mempool.Has(tx.ID()))
+	_, dropped := vm.droppedTxCache.Get(tx.ID())
+	assert.True(t, dropped)
+}
+
+func TestMempoolGossip(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	tx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.issueTx(tx))
+
+	gossipTxs := vm.PendingGossipTxs()
+	if assert.Len(t, gossipTxs, 1) {
+		assert.Equal(t, tx.Bytes(), gossipTxs[0])
+	}
+	assert.Empty(t, vm.PendingGossipTxs())
+
+	// Re-gossiping a tx that is already in the mempool is a no-op
+	assert.NoError(t, vm.GossipedTx(ids.GenerateTestShortID(), tx.Bytes()))
+	assert.Empty(t, vm.PendingGossipTxs())
+
+	otherTx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[1].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[1]},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.GossipedTx(ids.GenerateTestShortID(), otherTx.Bytes()))
+	assert.True(t, vm.mempool.Has(otherTx.ID()))
+	gossipTxs = vm.PendingGossipTxs()
+	if assert.Len(t, gossipTxs, 1) {
+		assert.Equal(t, otherTx.Bytes(), gossipTxs[0])
+	}
+
+	assert.Error(t, vm.GossipedTx(ids.GenerateTestShortID(), []byte{1, 2, 3}))
+}
+
+func TestMempoolIgnoresGossipWhileBootstrapping(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	tx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+
+	// The state may be stale while bootstrapping, so gossiped txs are ignored
+	peerID := ids.GenerateTestShortID()
+	assert.NoError(t, vm.Bootstrapping())
+	assert.NoError(t, vm.GossipedTx(peerID, tx.Bytes()))
+	assert.False(t, vm.mempool.Has(tx.ID()))
+
+	// Ignoring the tx doesn't prevent it from being added once bootstrapped
+	assert.NoError(t, vm.Bootstrapped())
+	assert.NoError(t, vm.GossipedTx(peerID, tx.Bytes()))
+	assert.True(t, vm.mempool.Has(tx.ID()))
+}
+
+func TestMempoolRevalidatesConflictingTxs(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// [tx] and [conflictingTx] spend the same UTXO
+	tx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	conflictingTx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[1].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	otherTx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[1].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[1]},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.issueTx(tx))
+	assert.NoError(t, vm.issueTx(conflictingTx))
+	assert.NoError(t, vm.issueTx(otherTx))
+
+	preferredHeight, err := vm.preferredHeight()
+	assert.NoError(t, err)
+	blk, err := vm.newStandardBlock(vm.Preferred(), preferredHeight+1, []*Tx{tx})
+	assert.NoError(t, err)
+	assert.NoError(t, blk.Verify())
+	vm.SetPreference(blk.ID())
+
+	// The tx in the preferred block is no longer pending, the tx that conflicts
+	// with it is evicted and the unrelated tx is kept
+	assert.False(t, vm.mempool.Has(tx.ID()))
+	assert.False(t, vm.mempool.Has(conflictingTx.ID()))
+	_, dropped := vm.droppedTxCache.Get(conflictingTx.ID())
+	assert.True(t, dropped)
+	assert.True(t, vm.mempool.Has(otherTx.ID()))
+}
+
+func TestMempoolGossipRateLimit(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.clock.Set(defaultGenesisTime)
+
+	peerID := ids.GenerateTestShortID()
+	gossip := func(i int) error {
+		return vm.GossipedTx(peerID, []byte{byte(i), byte(i >> 8)})
+	}
+	for i := 0; i < maxGossipedTxBurst; i++ {
+		assert.NotEqual(t, errGossipRateExceeded, gossip(i))
+	}
+	assert.Equal(t, errGossipRateExceeded, gossip(maxGossipedTxBurst))
+
+	// Recently gossiped txs are dropped before the rate is checked
+	assert.NoError(t, gossip(0))
+
+	// Other peers may still gossip
+	assert.NotEqual(t, errGossipRateExceeded, vm.GossipedTx(ids.GenerateTestShortID(), []byte{1}))
+
+	// The peer may gossip again once some time has passed
+	vm.clock.Set(defaultGenesisTime.Add(time.Second))
+	for i := 0; i < gossipedTxsPerSecond; i++ {
+		assert.NotEqual(t, errGossipRateExceeded, gossip(maxGossipedTxBurst+1+i))
+	}
+	assert.Equal(t, errGossipRateExceeded, gossip(maxGossipedTxBurst+1+gossipedTxsPerSecond))
+}
+
+// issueChainedDelegators issues a delegator tx starting at [parentStart] and a
+// delegator tx, starting later, that spends the change of the first one
+func issueChainedDelegators(t *testing.T, vm *VM, parentStart time.Time) (*Tx, *Tx) {
+	spendDB := versiondb.New(vm.DB)
+	defer spendDB.Abort()
+
+	// The genesis validators can only be delegated a few times their weight
+	vm.stakingParams.MinDelegatorStake = defaultWeight
+
+	nodeID := keys[0].PublicKey().Address()
+	txs := []*Tx(nil)
+	for _, startTime := range []time.Time{parentStart, parentStart.Add(time.Minute)} {
+		tx, err := vm.buildAddDelegatorTx(
+			spendDB,
+			vm.stakingParams.MinDelegatorStake,
+			uint64(startTime.Unix()),
+			uint64(startTime.Add(MinimumStakingDuration).Unix()),
+			nodeID,
+			nodeID,
+			[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, vm.issueTx(tx))
+		utx := tx.UnsignedTx.(*UnsignedAddDelegatorTx)
+		assert.NoError(t, vm.consumeInputs(spendDB, utx.Ins))
+		assert.NoError(t, vm.produceOutputs(spendDB, tx.ID(), utx.Outs))
+		txs = append(txs, tx)
+	}
+	parent, child := txs[0], txs[1]
+
+	spendsParent := false
+	for _, in := range child.UnsignedTx.(*UnsignedAddDelegatorTx).Ins {
+		spendsParent = spendsParent || in.TxID.Equals(parent.ID())
+	}
+	assert.True(t, spendsParent)
+	assert.True(t, vm.mempool.Has(child.ID()))
+	return parent, child
+}
+
+func TestMempoolEvictsChildrenOfExpiredStakers(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	parentStart := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	parent, child := issueChainedDelegators(t, vm, parentStart)
+
+	// Only the parent's start time passes the synchrony bound, but the child
+	// can't be put into a block without it
+	vm.clock.Set(defaultGenesisTime.Add(2 * time.Second))
+	vm.resetTimer()
+	assert.False(t, vm.mempool.Has(parent.ID()))
+	assert.False(t, vm.mempool.Has(child.ID()))
+	_, dropped := vm.droppedTxCache.Get(child.ID())
+	assert.True(t, dropped)
+	assert.Equal(t, 0, vm.mempool.Len())
+	assert.Empty(t, vm.PendingGossipTxs())
+}
+
+func TestMempoolEvictsChildrenOfConflictingTxs(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	parentStart := defaultGenesisTime.Add(Delta).Add(time.Minute)
+	parent, child := issueChainedDelegators(t, vm, parentStart)
+
+	// [conflictingTx] spends the same UTXO as [parent]
+	conflictingTx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	preferredHeight, err := vm.preferredHeight()
+	assert.NoError(t, err)
+	blk, err := vm.newStandardBlock(vm.Preferred(), preferredHeight+1, []*Tx{conflictingTx})
+	assert.NoError(t, err)
+	assert.NoError(t, blk.Verify())
+	vm.SetPreference(blk.ID())
+
+	assert.False(t, vm.mempool.Has(parent.ID()))
+	assert.False(t, vm.mempool.Has(child.ID()))
+	_, dropped := vm.droppedTxCache.Get(child.ID())
+	assert.True(t, dropped)
+	assert.Equal(t, 0, vm.mempool.Len())
+}
+
+func TestMempoolIndicesTrackRemovedTxs(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	parentStart := defaultGenesisTime.Add(Delta).Add(time.Minute)
+	parent, child := issueChainedDelegators(t, vm, parentStart)
+	assert.Equal(t, []ids.ID{child.ID()}, vm.mempool.children(parent.ID()))
+	assert.Equal(t, []*Tx{parent}, vm.mempool.ancestors(child))
+
+	// Removing the parent, as when it's put into a block, keeps the child
+	// indexed as spending its outputs
+	vm.mempool.Remove(parent.ID())
+	assert.Equal(t, []ids.ID{child.ID()}, vm.mempool.children(parent.ID()))
+	assert.Empty(t, vm.mempool.ancestors(child))
+	assert.Equal(t, []*Tx{child}, vm.mempool.unissuedProposalTxs.Txs)
+	assert.Equal(t, map[[32]byte]int{child.ID().Key(): 0}, vm.mempool.unissuedProposalTxs.indices)
+
+	vm.mempool.Remove(child.ID())
+	assert.Empty(t, vm.mempool.children(parent.ID()))
+	assert.Empty(t, vm.mempool.spends)
+	assert.Empty(t, vm.mempool.spenders)
+	assert.Empty(t, vm.mempool.unissuedProposalTxs.Txs)
+	assert.Empty(t, vm.mempool.unissuedProposalTxs.indices)
+}
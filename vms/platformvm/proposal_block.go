@@ -123,7 +123,7 @@ func (pb *ProposalBlock) Verify() error {
 	var err TxError
 	pb.onCommitDB, pb.onAbortDB, pb.onCommitFunc, pb.onAbortFunc, err = tx.SemanticVerify(pb.vm, pdb, &pb.Tx)
 	if err != nil {
-		pb.vm.droppedTxCache.Put(txID, nil) // cache tx as dropped
+		pb.vm.mempool.Evict(txID) // drop tx and the txs spending its outputs
 		// If this block's transaction proposes to advance the timestamp, the transaction may fail
 		// verification now but be valid in the future, so don't (permanently) mark the block as rejected.
 		if !err.Temporary() {
@@ -145,6 +145,9 @@ func (pb *ProposalBlock) Verify() error {
 	if err := pb.vm.putStatus(pb.onCommitDB, txID, Committed); err != nil {
 		return err
 	}
+	if err := pb.vm.putTxBlockID(pb.onCommitDB, txID, pb.ID()); err != nil {
+		return err
+	}
 
 	if err := pb.vm.putTx(pb.onAbortDB, txID, txBytes); err != nil {
 		return err
@@ -152,6 +155,9 @@ func (pb *ProposalBlock) Verify() error {
 	if err := pb.vm.putStatus(pb.onAbortDB, txID, Aborted); err != nil {
 		return err
 	}
+	if err := pb.vm.putTxBlockID(pb.onAbortDB, txID, pb.ID()); err != nil {
+		return err
+	}
 
 	pb.vm.currentBlocks[pb.ID().Key()] = pb
 	parentIntf.addChild(pb)
@@ -171,10 +177,10 @@ func (pb *ProposalBlock) Options() ([2]snowman.Block, error) {
 		return [2]snowman.Block{}, err
 	}
 
-	if err := pb.vm.State.PutBlock(pb.vm.DB, commit); err != nil {
+	if err := pb.vm.putBlock(pb.vm.DB, commit); err != nil {
 		return [2]snowman.Block{}, err
 	}
-	if err := pb.vm.State.PutBlock(pb.vm.DB, abort); err != nil {
+	if err := pb.vm.putBlock(pb.vm.DB, abort); err != nil {
 		return [2]snowman.Block{}, err
 	}
 	if err := pb.vm.DB.Commit(); err != nil {
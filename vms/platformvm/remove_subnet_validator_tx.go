@@ -0,0 +1,218 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+var (
+	errRemovePrimaryNetworkValidator = errors.New("can't remove a validator of the primary network")
+	errNotSubnetValidator            = errors.New("node isn't a current or pending validator of the subnet")
+
+	_ UnsignedDecisionTx = &UnsignedRemoveSubnetValidatorTx{}
+)
+
+// UnsignedRemoveSubnetValidatorTx is an unsigned removeSubnetValidatorTx. It
+// removes a validator from the current or pending validator set of a subnet
+// before its end time, or changes the validator's weight.
+type UnsignedRemoveSubnetValidatorTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the node to remove
+	NodeID ids.ShortID `serialize:"true" json:"nodeID"`
+	// ID of the subnet the node is removed from
+	Subnet ids.ID `serialize:"true" json:"subnet"`
+	// If 0, the node is removed from the subnet. Otherwise, the node's weight
+	// is set to [Weight] and it keeps validating the subnet.
+	Weight uint64 `serialize:"true" json:"weight"`
+	// Auth that will be allowing this validator out of the network
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+}
+
+// Verify return nil iff [tx] is valid
+func (tx *UnsignedRemoveSubnetValidatorTx) Verify(
+	ctx *snow.Context,
+	c codec.Codec,
+	feeAmount uint64,
+	feeAssetID ids.ID,
+) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.NodeID.IsZero():
+		return errInvalidID
+	case tx.Subnet.Equals(constants.PrimaryNetworkID):
+		return errRemovePrimaryNetworkValidator
+	}
+
+	if err := tx.BaseTx.Verify(ctx, c); err != nil {
+		return err
+	}
+	if err := tx.SubnetAuth.Verify(); err != nil {
+		return err
+	}
+
+	// cache that this is valid
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// SemanticVerify returns nil if [tx] is valid given the state in [db]
+func (tx *UnsignedRemoveSubnetValidatorTx) SemanticVerify(
+	vm *VM,
+	db database.Database,
+	stx *Tx,
+) (
+	func() error,
+	TxError,
+) {
+	// Verify the tx is well-formed
+	if len(stx.Creds) == 0 {
+		return nil, permError{errWrongNumberOfCredentials}
+	}
+	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		return nil, permError{err}
+	}
+
+	baseTxCredsLen := len(stx.Creds) - 1
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	subnetCred := stx.Creds[baseTxCredsLen]
+
+	owner, txErr := vm.getSubnetOwner(db, tx.Subnet)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, owner); err != nil {
+		return nil, permError{err}
+	}
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		return nil, err
+	}
+
+	txID := tx.ID()
+
+	// Find the validator in the current validator set, or, if it hasn't
+	// started validating yet, in the pending validator set
+	vdrTx, isValidator, err := vm.getCurrentSubnetValidator(db, tx.Subnet, tx.NodeID)
+	if err != nil {
+		return nil, tempError{err}
+	}
+	var stakerTx *Tx
+	if isValidator {
+		stakerTx = &vdrTx.Tx
+	} else {
+		pendingTx, willBeValidator, err := vm.getPendingSubnetValidator(db, tx.Subnet, tx.NodeID)
+		if err != nil {
+			return nil, tempError{err}
+		}
+		if !willBeValidator {
+			return nil, permError{fmt.Errorf("%w: node %s, subnet %s",
+				errNotSubnetValidator,
+				tx.NodeID.PrefixedString(constants.NodeIDPrefix),
+				tx.Subnet)}
+		}
+		stakerTx = pendingTx
+	}
+
+	switch {
+	case tx.Weight != 0:
+		// Update the validator's weight
+		stakerUTx, ok := stakerTx.UnsignedTx.(*UnsignedAddSubnetValidatorTx)
+		if !ok {
+			return nil, tempError{fmt.Errorf("expected validator to be *UnsignedAddSubnetValidatorTx but got %T", stakerTx.UnsignedTx)}
+		}
+		oldWeight, err := vm.getSubnetValidatorWeight(db, stakerUTx)
+		if err != nil {
+			return nil, tempError{err}
+		}
+		if err := vm.putSubnetValidatorWeight(db, stakerTx.ID(), tx.Weight); err != nil {
+			return nil, tempError{err}
+		}
+		if isValidator {
+			if err := vm.putValidatorWeightChange(db, txID, tx.Subnet, tx.NodeID, oldWeight, tx.Weight); err != nil {
+				return nil, tempError{err}
+			}
+		}
+	case isValidator:
+		if err := vm.removeStaker(db, tx.Subnet, vdrTx); err != nil {
+			return nil, tempError{err}
+		}
+	default:
+		if err := vm.dequeueStaker(db, tx.Subnet, stakerTx); err != nil {
+			return nil, tempError{err}
+		}
+		if err := vm.deleteSubnetValidatorWeight(db, stakerTx.ID()); err != nil {
+			return nil, tempError{err}
+		}
+	}
+
+	// Consume the UTXOS
+	if err := vm.consumeInputs(db, tx.Ins); err != nil {
+		return nil, tempError{err}
+	}
+	// Produce the UTXOS
+	if err := vm.produceOutputs(db, txID, tx.Outs); err != nil {
+		return nil, tempError{err}
+	}
+
+	if !isValidator {
+		return nil, nil
+	}
+	// Update the subnet's validator set
+	onAccept := func() error { return vm.updateVdrMgr(false) }
+	return onAccept, nil
+}
+
+// Create a new transaction
+func (vm *VM) newRemoveSubnetValidatorTx(
+	nodeID ids.ShortID, // ID of the node to remove
+	subnetID ids.ID, // ID of the subnet the node is removed from
+	weight uint64, // If non-zero, the node's new weight rather than removing it
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for removing the validator
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.txFee)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	subnetAuth, subnetSigners, err := vm.authorize(vm.DB, subnetID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
+	}
+	signers = append(signers, subnetSigners)
+
+	// Create the tx
+	utx := &UnsignedRemoveSubnetValidatorTx{
+		BaseTx: BaseTx{BaseTx: djtx.BaseTx{
+			NetworkID:    vm.Ctx.NetworkID,
+			BlockchainID: vm.Ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		NodeID:     nodeID,
+		Subnet:     subnetID,
+		Weight:     weight,
+		SubnetAuth: subnetAuth,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(vm.codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID)
+}
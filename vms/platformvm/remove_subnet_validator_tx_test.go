@@ -0,0 +1,329 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+func TestRemoveSubnetValidatorTxSyntacticVerify(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// Case: tx is nil
+	var unsignedTx *UnsignedRemoveSubnetValidatorTx
+	if err := unsignedTx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err == nil {
+		t.Fatal("should have errored because tx is nil")
+	}
+
+	// Case: valid tx
+	tx, err := vm.newRemoveSubnetValidatorTx(
+		keys[0].PublicKey().Address(),
+		testSubnet1.ID(),
+		0,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Case: node ID is empty
+	tx.UnsignedTx.(*UnsignedRemoveSubnetValidatorTx).syntacticallyVerified = false
+	tx.UnsignedTx.(*UnsignedRemoveSubnetValidatorTx).NodeID = ids.ShortID{}
+	if err := tx.UnsignedTx.(*UnsignedRemoveSubnetValidatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err == nil {
+		t.Fatal("should have errored because node ID is empty")
+	}
+
+	// Case: removing a validator of the primary network
+	tx.UnsignedTx.(*UnsignedRemoveSubnetValidatorTx).NodeID = keys[0].PublicKey().Address()
+	tx.UnsignedTx.(*UnsignedRemoveSubnetValidatorTx).Subnet = constants.PrimaryNetworkID
+	if err := tx.UnsignedTx.(*UnsignedRemoveSubnetValidatorTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err != errRemovePrimaryNetworkValidator {
+		t.Fatalf("expected %q but got %v", errRemovePrimaryNetworkValidator, err)
+	}
+}
+
+func TestRemoveSubnetValidatorTxSemanticVerify(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	nodeID := keys[0].PublicKey().Address()
+
+	// Case: node isn't a validator of the subnet
+	tx, err := vm.newRemoveSubnetValidatorTx(
+		nodeID,
+		testSubnet1.ID(),
+		0,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vdb := versiondb.New(vm.DB)
+	if _, err := tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, vdb, tx); !errors.Is(err, errNotSubnetValidator) {
+		t.Fatalf("expected %q but got %v", errNotSubnetValidator, err)
+	}
+	vdb.Abort()
+
+	// Case: not enough control keys signed the tx
+	tx, err = vm.newRemoveSubnetValidatorTx(
+		nodeID,
+		testSubnet1.ID(),
+		0,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Creds = tx.Creds[:len(tx.Creds)-1]
+	vdb = versiondb.New(vm.DB)
+	if _, err := tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, vdb, tx); err == nil {
+		t.Fatal("should have errored because the subnet's credential is missing")
+	}
+	vdb.Abort()
+}
+
+func TestRemoveSubnetValidatorAccept(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	currentNodeID := keys[0].PublicKey().Address()
+	pendingNodeID := keys[1].PublicKey().Address()
+	controlKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+
+	// Add a current and a pending validator to the subnet
+	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
+	currentTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		currentNodeID,
+		testSubnet1.ID(),
+		controlKeys,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.addStaker(vm.DB, testSubnet1.ID(), &rewardTx{Tx: *currentTx}); err != nil {
+		t.Fatal(err)
+	}
+	pendingTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		pendingNodeID,
+		testSubnet1.ID(),
+		controlKeys,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.enqueueStaker(vm.DB, testSubnet1.ID(), pendingTx); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.updateVdrMgr(false); err != nil {
+		t.Fatal(err)
+	}
+	if vdrs, ok := vm.vdrMgr.GetValidators(testSubnet1.ID()); !ok || !vdrs.Contains(currentNodeID) {
+		t.Fatal("should have added the validator to the subnet's validator set")
+	}
+
+	for _, nodeID := range []ids.ShortID{currentNodeID, pendingNodeID} {
+		tx, err := vm.newRemoveSubnetValidatorTx(nodeID, testSubnet1.ID(), 0, controlKeys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.issueTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		blk, err := vm.BuildBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Accept(); err != nil {
+			t.Fatal(err)
+		}
+		if status, err := vm.getStatus(vm.DB, tx.ID()); err != nil {
+			t.Fatal(err)
+		} else if status != Committed {
+			t.Fatalf("status should be Committed but is %s", status)
+		}
+	}
+
+	if _, isValidator, err := vm.isValidator(vm.DB, testSubnet1.ID(), currentNodeID); err != nil {
+		t.Fatal(err)
+	} else if isValidator {
+		t.Fatal("should have removed the validator from the current validator set")
+	}
+	if _, willBeValidator, err := vm.willBeValidator(vm.DB, testSubnet1.ID(), pendingNodeID); err != nil {
+		t.Fatal(err)
+	} else if willBeValidator {
+		t.Fatal("should have removed the validator from the pending validator set")
+	}
+	if vdrs, ok := vm.vdrMgr.GetValidators(testSubnet1.ID()); !ok || vdrs.Contains(currentNodeID) {
+		t.Fatal("should have removed the validator from the subnet's validator set")
+	}
+}
+
+func TestRemoveSubnetValidatorUpdateWeight(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.SetPreference(vm.LastAccepted())
+
+	currentNodeID := keys[0].PublicKey().Address()
+	pendingNodeID := keys[1].PublicKey().Address()
+	controlKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+
+	// Add a current and a pending validator to the subnet
+	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
+	currentTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		currentNodeID,
+		testSubnet1.ID(),
+		controlKeys,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.addStaker(vm.DB, testSubnet1.ID(), &rewardTx{Tx: *currentTx}); err != nil {
+		t.Fatal(err)
+	}
+	pendingTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		pendingNodeID,
+		testSubnet1.ID(),
+		controlKeys,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.enqueueStaker(vm.DB, testSubnet1.ID(), pendingTx); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.updateVdrMgr(false); err != nil {
+		t.Fatal(err)
+	}
+	heightBefore, err := vm.GetCurrentHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Record the added validator in the validator set history
+	if err := vm.indexValidatorChanges(vm.DB, heightBefore); err != nil {
+		t.Fatal(err)
+	}
+
+	newWeight := uint64(2 * defaultWeight)
+	for _, nodeID := range []ids.ShortID{currentNodeID, pendingNodeID} {
+		tx, err := vm.newRemoveSubnetValidatorTx(nodeID, testSubnet1.ID(), newWeight, controlKeys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.issueTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		blk, err := vm.BuildBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Accept(); err != nil {
+			t.Fatal(err)
+		}
+		vm.SetPreference(vm.LastAccepted())
+	}
+
+	// The validators keep validating the subnet with their new weight
+	if _, isValidator, err := vm.isValidator(vm.DB, testSubnet1.ID(), currentNodeID); err != nil {
+		t.Fatal(err)
+	} else if !isValidator {
+		t.Fatal("should still be a current validator of the subnet")
+	}
+	if _, willBeValidator, err := vm.willBeValidator(vm.DB, testSubnet1.ID(), pendingNodeID); err != nil {
+		t.Fatal(err)
+	} else if !willBeValidator {
+		t.Fatal("should still be a pending validator of the subnet")
+	}
+	if weight, err := vm.getSubnetValidatorWeight(vm.DB, pendingTx.UnsignedTx.(*UnsignedAddSubnetValidatorTx)); err != nil {
+		t.Fatal(err)
+	} else if weight != newWeight {
+		t.Fatalf("pending validator's weight should be %d but is %d", newWeight, weight)
+	}
+	vdrs, ok := vm.vdrMgr.GetValidators(testSubnet1.ID())
+	if !ok {
+		t.Fatal("subnet should have validators")
+	}
+	if weight, ok := vdrs.GetWeight(currentNodeID); !ok || weight != newWeight {
+		t.Fatalf("validator's weight should be %d but is %d", newWeight, weight)
+	}
+
+	// The validator set history reflects the update
+	vdrsBefore, err := vm.GetValidatorSet(heightBefore, testSubnet1.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight, ok := vdrsBefore.GetWeight(currentNodeID); !ok || weight != defaultWeight {
+		t.Fatalf("validator's weight should have been %d but was %d", defaultWeight, weight)
+	}
+
+	// Removing the validator removes its updated weight
+	tx, err := vm.newRemoveSubnetValidatorTx(currentNodeID, testSubnet1.ID(), 0, controlKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	vm.SetPreference(vm.LastAccepted())
+	if vdrs, ok := vm.vdrMgr.GetValidators(testSubnet1.ID()); ok && vdrs.Contains(currentNodeID) {
+		t.Fatal("should have removed the validator from the subnet's validator set")
+	}
+	vdrsBefore, err = vm.GetValidatorSet(heightBefore, testSubnet1.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight, ok := vdrsBefore.GetWeight(currentNodeID); !ok || weight != defaultWeight {
+		t.Fatalf("validator's weight should have been %d but was %d", defaultWeight, weight)
+	}
+}
@@ -0,0 +1,324 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errDelegationOutlivesValidator = errors.New("renewed delegation would end after its validator")
+)
+
+// A restaking validator or delegator doesn't leave the staker set at the end
+// of its staking period. If it's rewarded, it continues staking for the same
+// duration with its stake plus its reward. Otherwise, it leaves the staker set
+// as usual.
+//
+// A delegator is renewed only if its renewed staking period fits in the
+// current staking period of its validator, or if both end at the same time and
+// the validator is also restaking. In the latter case, if the validator isn't
+// renewed, the delegations that were carried over are returned to their
+// owners.
+//
+// The tx that renews a staker isn't issued: it has no inputs and isn't
+// signed. So it's only stored in the staker set, not with the txs that were
+// accepted.
+
+// Persist that the staker added by [txID] is restaking
+func (vm *VM) putRestake(db database.Database, txID ids.ID) error {
+	restakeDB := prefixdb.NewNested([]byte(restakeDBPrefix), db)
+	defer restakeDB.Close()
+
+	return restakeDB.Put(txID.Bytes(), nil)
+}
+
+// Returns true if the staker added by [txID] is restaking
+func (vm *VM) isRestaking(db database.Database, txID ids.ID) (bool, error) {
+	restakeDB := prefixdb.NewNested([]byte(restakeDBPrefix), db)
+	defer restakeDB.Close()
+
+	return restakeDB.Has(txID.Bytes())
+}
+
+// Persist that the staker added by [txID] isn't restaking
+func (vm *VM) deleteRestake(db database.Database, txID ids.ID) error {
+	restakeDB := prefixdb.NewNested([]byte(restakeDBPrefix), db)
+	defer restakeDB.Close()
+
+	return restakeDB.Delete(txID.Bytes())
+}
+
+// newRenewedStakerTx returns the tx that continues the staking period of
+// [stakerTx], which must add a primary network validator or delegator, for the
+// same duration. [reward] is added to the stake of the renewed staker, and is
+// owned by the staker's rewards owner.
+func (vm *VM) newRenewedStakerTx(stakerTx *Tx, reward uint64) (*Tx, error) {
+	var (
+		vdr          Validator
+		stake        []*djtx.TransferableOutput
+		rewardsOwner verify.Verifiable
+	)
+	switch staker := stakerTx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		vdr = staker.Validator
+		stake = staker.Stake
+		rewardsOwner = staker.RewardsOwner
+	case *UnsignedAddDelegatorTx:
+		vdr = staker.Validator
+		stake = staker.Stake
+		rewardsOwner = staker.RewardsOwner
+	default:
+		return nil, errWrongTxType
+	}
+
+	renewedStake := make([]*djtx.TransferableOutput, len(stake), len(stake)+1)
+	copy(renewedStake, stake)
+	if reward > 0 {
+		outIntf, err := vm.fx.CreateOutput(reward, rewardsOwner)
+		if err != nil {
+			return nil, err
+		}
+		out, ok := outIntf.(djtx.TransferableOut)
+		if !ok {
+			return nil, errInvalidState
+		}
+		renewedStake = append(renewedStake, &djtx.TransferableOutput{
+			Asset: djtx.Asset{ID: vm.Ctx.DJTXAssetID},
+			Out:   out,
+		})
+		djtx.SortTransferableOutputs(renewedStake, vm.codec)
+	}
+
+	weight, err := safemath.Add64(vdr.Wght, reward)
+	if err != nil {
+		return nil, err
+	}
+	end, err := safemath.Add64(vdr.End, vdr.End-vdr.Start)
+	if err != nil {
+		return nil, err
+	}
+	renewedVdr := Validator{
+		NodeID: vdr.NodeID,
+		Start:  vdr.End,
+		End:    end,
+		Wght:   weight,
+	}
+	baseTx := BaseTx{BaseTx: djtx.BaseTx{
+		NetworkID:    vm.Ctx.NetworkID,
+		BlockchainID: vm.Ctx.ChainID,
+		Ins:          []*djtx.TransferableInput{},
+		Outs:         []*djtx.TransferableOutput{},
+	}}
+
+	var utx UnsignedTx
+	switch staker := stakerTx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		utx = &UnsignedAddValidatorTx{
+			BaseTx:       baseTx,
+			Validator:    renewedVdr,
+			Stake:        renewedStake,
+			RewardsOwner: rewardsOwner,
+			Shares:       staker.Shares,
+		}
+	default:
+		utx = &UnsignedAddDelegatorTx{
+			BaseTx:       baseTx,
+			Validator:    renewedVdr,
+			Stake:        renewedStake,
+			RewardsOwner: rewardsOwner,
+		}
+	}
+	tx := &Tx{UnsignedTx: utx}
+	return tx, tx.Sign(vm.codec, nil)
+}
+
+// getRenewedStakerTx returns the tx that renews the staker added by
+// [stakerTx], with [reward] added to its stake, if the staker is restaking
+// and may be renewed given the state in [db]. Otherwise, returns nil.
+// The staker must already have been removed from the current staker set in
+// [db]. [currentTime] is the end of the staker's period.
+func (vm *VM) getRenewedStakerTx(db database.Database, stakerTx *Tx, reward uint64, currentTime time.Time) (*Tx, error) {
+	restaking, err := vm.isRestaking(db, stakerTx.ID())
+	if err != nil || !restaking {
+		return nil, err
+	}
+	renewedTx, err := vm.newRenewedStakerTx(stakerTx, reward)
+	if err != nil {
+		return nil, err
+	}
+
+	switch renewed := renewedTx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		err = vm.canRenewValidator(db, renewed)
+	case *UnsignedAddDelegatorTx:
+		vdrTx, ok, vdrErr := vm.isValidator(db, constants.PrimaryNetworkID, renewed.Validator.NodeID)
+		if vdrErr != nil {
+			return nil, vdrErr
+		}
+		vdr, isVdr := vdrTx.(*UnsignedAddValidatorTx)
+		if !ok || !isVdr {
+			return nil, nil
+		}
+		err = vm.canRenewDelegator(db, vdr, renewed, currentTime)
+	}
+	if err != nil {
+		// The staker leaves the staker set as if it wasn't restaking
+		vm.Ctx.Log.Debug("not renewing staker %s: %s", stakerTx.ID(), err)
+		return nil, nil
+	}
+	return renewedTx, nil
+}
+
+// renewStaker adds [renewedTx] to the current primary network staker set,
+// and carries the restaking preference of [txID] over to it
+func (vm *VM) renewStaker(db database.Database, txID ids.ID, renewedTx *Tx) error {
+	vdr := Validator{}
+	switch utx := renewedTx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		vdr = utx.Validator
+	case *UnsignedAddDelegatorTx:
+		vdr = utx.Validator
+	default:
+		return errWrongTxType
+	}
+
+	reward, err := vm.calculateReward(db, vdr.Duration(), vdr.Wght)
+	if err != nil {
+		return err
+	}
+	if err := vm.addStaker(db, constants.PrimaryNetworkID, &rewardTx{
+		Reward: reward,
+		Tx:     *renewedTx,
+	}); err != nil {
+		return err
+	}
+
+	if err := vm.deleteRestake(db, txID); err != nil {
+		return err
+	}
+	return vm.putRestake(db, renewedTx.ID())
+}
+
+// returnCarriedOverDelegations removes the delegations to [nodeID] that were
+// carried over to a renewal of its validator that didn't happen. Their stake
+// is returned to its owners, and their potential reward is removed from the
+// current supply. [nodeID] must no longer be a current validator.
+func (vm *VM) returnCarriedOverDelegations(db database.Database, nodeID ids.ShortID) error {
+	delegators, err := vm.getDelegators(db, nodeID)
+	if err != nil {
+		return err
+	}
+
+	for _, delegator := range delegators {
+		delegation, isCurrent, err := vm.getCurrentDelegator(db, delegator)
+		if err != nil {
+			return err
+		}
+		if !isCurrent {
+			continue
+		}
+		txID := delegation.Tx.ID()
+		if err := vm.removeStaker(db, constants.PrimaryNetworkID, delegation); err != nil {
+			return err
+		}
+		for i, out := range delegator.Stake {
+			if err := vm.putUTXO(db, &djtx.UTXO{
+				UTXOID: djtx.UTXOID{
+					TxID:        txID,
+					OutputIndex: uint32(len(delegator.Outs) + i),
+				},
+				Asset: djtx.Asset{ID: vm.Ctx.DJTXAssetID},
+				Out:   out.Output(),
+			}); err != nil {
+				return err
+			}
+		}
+		currentSupply, err := vm.getCurrentSupply(db)
+		if err != nil {
+			return err
+		}
+		newSupply, err := safemath.Sub64(currentSupply, delegation.Reward)
+		if err != nil {
+			return err
+		}
+		if err := vm.putCurrentSupply(db, newSupply); err != nil {
+			return err
+		}
+		if err := vm.deleteRestake(db, txID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canRenewValidator returns nil if [renewedTx], which renews a validator, is
+// within the staking limits given the delegations to it in [db]
+func (vm *VM) canRenewValidator(db database.Database, renewedTx *UnsignedAddValidatorTx) error {
+	if renewedTx.Validator.Wght > vm.stakingParams.MaxValidatorStake {
+		return errWeightTooLarge
+	}
+	delegators, err := vm.getDelegators(db, renewedTx.Validator.NodeID)
+	if err != nil {
+		return err
+	}
+	delegatedWeight := maxConcurrentWeight(
+		delegators,
+		renewedTx.StartTime(),
+		renewedTx.EndTime(),
+	)
+	return vm.stakingParams.verifyDelegation(renewedTx.Validator.Wght, delegatedWeight)
+}
+
+// canRenewDelegator returns nil if [renewedTx], which renews a delegator to
+// [vdrTx], may be added to the staker set given the state in [db].
+// [currentTime] is the chain's timestamp, which is when the renewed period
+// starts.
+func (vm *VM) canRenewDelegator(
+	db database.Database,
+	vdrTx *UnsignedAddValidatorTx,
+	renewedTx *UnsignedAddDelegatorTx,
+	currentTime time.Time,
+) error {
+	vdrEndTime := vdrTx.EndTime()
+	if !renewedTx.Validator.BoundedBy(vdrTx.StartTime(), vdrEndTime) {
+		// The delegation can only be carried over to the validator's renewal
+		if !vdrEndTime.Equal(currentTime) {
+			return errDelegationOutlivesValidator
+		}
+		vdrRestaking, err := vm.isRestaking(db, vdrTx.ID())
+		if err != nil {
+			return err
+		}
+		renewedVdrEndTime := vdrEndTime.Add(vdrTx.Validator.Duration())
+		if !vdrRestaking || renewedTx.EndTime().After(renewedVdrEndTime) {
+			return errDelegationOutlivesValidator
+		}
+	}
+
+	delegators, err := vm.getDelegators(db, renewedTx.Validator.NodeID)
+	if err != nil {
+		return err
+	}
+	delegatedWeight := maxConcurrentWeight(
+		delegators,
+		renewedTx.StartTime(),
+		renewedTx.EndTime(),
+	)
+	delegatedWeight, err = safemath.Add64(delegatedWeight, renewedTx.Validator.Wght)
+	if err != nil {
+		return err
+	}
+	return vm.stakingParams.verifyDelegation(vdrTx.Validator.Wght, delegatedWeight)
+}
@@ -0,0 +1,359 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+func TestSetRestakeTx(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	startTime := uint64(defaultValidateStartTime.Unix()) + 1
+	endTime := uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix()) + 1
+	vdrTx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		startTime,
+		endTime,
+		ids.GenerateTestShortID(),
+		keys[1].PublicKey().Address(),
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+
+	// Case: the staker doesn't exist
+	_, err = vm.newSetRestakeTx(vdrTx.ID(), true, []*crypto.PrivateKeySECP256K1R{keys[0], keys[1]})
+	assert.Error(t, err)
+
+	assert.NoError(t, vm.addStaker(vm.DB, constants.PrimaryNetworkID, &rewardTx{Tx: *vdrTx}))
+
+	// Case: only the owner of the stake may opt in to restaking, as it keeps
+	// the stake locked
+	_, err = vm.newSetRestakeTx(vdrTx.ID(), true, []*crypto.PrivateKeySECP256K1R{keys[1]})
+	assert.Error(t, err)
+
+	// Case: the rewards owner authorizes opting in to restaking
+	tx := newRewardsOwnerSetRestakeTx(t, vm, vdrTx.ID(), true)
+	db := versiondb.New(vm.DB)
+	_, err = tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, db, tx)
+	assert.Error(t, err)
+	restaking, err := vm.isRestaking(db, vdrTx.ID())
+	assert.NoError(t, err)
+	assert.False(t, restaking)
+
+	// Case: the owner of the stake opts in to restaking
+	tx, err = vm.newSetRestakeTx(vdrTx.ID(), true, []*crypto.PrivateKeySECP256K1R{keys[0]})
+	assert.NoError(t, err)
+	_, err = tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, db, tx)
+	assert.NoError(t, err)
+	restaking, err = vm.isRestaking(db, vdrTx.ID())
+	assert.NoError(t, err)
+	assert.True(t, restaking)
+	assert.NoError(t, db.Commit())
+
+	// Case: only the rewards owner may cancel restaking
+	_, err = vm.newSetRestakeTx(vdrTx.ID(), false, []*crypto.PrivateKeySECP256K1R{keys[0]})
+	assert.Error(t, err)
+
+	// Case: the rewards owner cancels restaking
+	tx, err = vm.newSetRestakeTx(vdrTx.ID(), false, []*crypto.PrivateKeySECP256K1R{keys[1]})
+	assert.NoError(t, err)
+	_, err = tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, db, tx)
+	assert.NoError(t, err)
+	restaking, err = vm.isRestaking(db, vdrTx.ID())
+	assert.NoError(t, err)
+	assert.False(t, restaking)
+
+	// Case: the staking period has ended
+	assert.NoError(t, vm.putTimestamp(db, time.Unix(int64(endTime), 0)))
+	_, err = tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, db, tx)
+	assert.Equal(t, permError{errStakingPeriodFinished}, err)
+}
+
+// newRewardsOwnerSetRestakeTx returns a tx setting the restaking preference of
+// [stakerTxID] to [restake] that is authorized by the staker's rewards owner,
+// keys[1], whatever the preference
+func newRewardsOwnerSetRestakeTx(t *testing.T, vm *VM, stakerTxID ids.ID, restake bool) *Tx {
+	stakerTx, _, err := vm.getPrimaryNetworkStaker(vm.DB, stakerTxID)
+	assert.NoError(t, err)
+	rewardsOwner := stakerTx.UnsignedTx.(*UnsignedAddValidatorTx).RewardsOwner
+
+	keys := []*crypto.PrivateKeySECP256K1R{keys[1]}
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.txFee)
+	assert.NoError(t, err)
+	ownerAuth, ownerSigners, err := vm.authorizeOwner(rewardsOwner, keys)
+	assert.NoError(t, err)
+	signers = append(signers, ownerSigners)
+
+	tx := &Tx{UnsignedTx: &UnsignedSetRestakeTx{
+		BaseTx: BaseTx{BaseTx: djtx.BaseTx{
+			NetworkID:    vm.Ctx.NetworkID,
+			BlockchainID: vm.Ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		TxID:       stakerTxID,
+		Restake:    restake,
+		OwnerAuths: []verify.Verifiable{ownerAuth},
+	}}
+	assert.NoError(t, tx.Sign(vm.codec, signers))
+	return tx
+}
+
+func TestRestakeValidator(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	startTime := uint64(defaultValidateStartTime.Unix()) + 1
+	endTime := uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix()) + 1
+	nodeID := ids.GenerateTestShortID()
+	vdrTx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		startTime,
+		endTime,
+		nodeID,
+		keys[1].PublicKey().Address(),
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	reward := uint64(1000000)
+	assert.NoError(t, vm.addStaker(vm.DB, constants.PrimaryNetworkID, &rewardTx{
+		Reward: reward,
+		Tx:     *vdrTx,
+	}))
+	assert.NoError(t, vm.putRestake(vm.DB, vdrTx.ID()))
+	assert.NoError(t, vm.putTimestamp(vm.DB, time.Unix(int64(endTime), 0)))
+	supply, err := vm.getCurrentSupply(vm.DB)
+	assert.NoError(t, err)
+
+	tx, err := vm.newRewardValidatorTx(vdrTx.ID())
+	assert.NoError(t, err)
+	onCommitDB, onAbortDB, _, _, err := tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	assert.NoError(t, err)
+
+	// If the tx is committed, the validator continues validating for the same
+	// duration with its stake and reward
+	renewedTx, err := vm.nextStakerStop(onCommitDB, constants.PrimaryNetworkID)
+	assert.NoError(t, err)
+	renewed, ok := renewedTx.Tx.UnsignedTx.(*UnsignedAddValidatorTx)
+	if assert.True(t, ok) {
+		assert.Equal(t, nodeID, renewed.Validator.NodeID)
+		assert.Equal(t, endTime, renewed.Validator.Start)
+		assert.Equal(t, 2*endTime-startTime, renewed.Validator.End)
+		assert.Equal(t, vm.stakingParams.MinValidatorStake+reward, renewed.Validator.Wght)
+		assert.Equal(t, vm.stakingParams.MinValidatorStake+reward, renewed.Validator.Weight())
+	}
+	restaking, err := vm.isRestaking(onCommitDB, renewedTx.Tx.ID())
+	assert.NoError(t, err)
+	assert.True(t, restaking)
+	restaking, err = vm.isRestaking(onCommitDB, vdrTx.ID())
+	assert.NoError(t, err)
+	assert.False(t, restaking)
+	newSupply, err := vm.getCurrentSupply(onCommitDB)
+	assert.NoError(t, err)
+	assert.Equal(t, supply+renewedTx.Reward, newSupply)
+
+	// The stake isn't returned
+	stakeUTXOID := djtx.UTXOID{
+		TxID:        vdrTx.ID(),
+		OutputIndex: uint32(len(vdrTx.UnsignedTx.(*UnsignedAddValidatorTx).Outs)),
+	}
+	_, err = vm.getUTXO(onCommitDB, stakeUTXOID.InputID())
+	assert.Error(t, err)
+
+	// If the tx is aborted, the validator leaves with its stake
+	_, ok, err = vm.isValidator(onAbortDB, constants.PrimaryNetworkID, nodeID)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = vm.getUTXO(onAbortDB, stakeUTXOID.InputID())
+	assert.NoError(t, err)
+	restaking, err = vm.isRestaking(onAbortDB, vdrTx.ID())
+	assert.NoError(t, err)
+	assert.False(t, restaking)
+}
+
+func TestRestakeDelegatorCarriedOver(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	startTime := uint64(defaultValidateStartTime.Unix()) + 1
+	endTime := uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix()) + 1
+	nodeID := ids.GenerateTestShortID()
+	vdrTx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		startTime,
+		endTime,
+		nodeID,
+		keys[1].PublicKey().Address(),
+		PercentDenominator/2,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	delTx, err := vm.newAddDelegatorTx(
+		vm.stakingParams.MinDelegatorStake,
+		startTime,
+		endTime,
+		nodeID,
+		keys[2].PublicKey().Address(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.addStaker(vm.DB, constants.PrimaryNetworkID, &rewardTx{Tx: *vdrTx}))
+	assert.NoError(t, vm.addStaker(vm.DB, constants.PrimaryNetworkID, &rewardTx{
+		Reward: 1000000,
+		Tx:     *delTx,
+	}))
+	assert.NoError(t, vm.putRestake(vm.DB, delTx.ID()))
+	assert.NoError(t, vm.putTimestamp(vm.DB, time.Unix(int64(endTime), 0)))
+
+	// The delegation can't outlive its validator unless the validator is
+	// restaking
+	tx, err := vm.newRewardValidatorTx(delTx.ID())
+	assert.NoError(t, err)
+	onCommitDB, _, _, _, err := tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	assert.NoError(t, err)
+	_, ok, err := vm.isValidator(onCommitDB, constants.PrimaryNetworkID, nodeID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	nextStaker, err := vm.nextStakerStop(onCommitDB, constants.PrimaryNetworkID)
+	assert.NoError(t, err)
+	assert.Equal(t, vdrTx.ID(), nextStaker.Tx.ID())
+
+	// If the validator is restaking, the delegation is carried over, with half
+	// the reward compounded
+	assert.NoError(t, vm.putRestake(vm.DB, vdrTx.ID()))
+	onCommitDB, _, _, _, err = tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	assert.NoError(t, err)
+	renewedDelegators, err := vm.getDelegators(onCommitDB, nodeID)
+	assert.NoError(t, err)
+	if !assert.Len(t, renewedDelegators, 1) {
+		return
+	}
+	renewedDel := renewedDelegators[0]
+	assert.Equal(t, endTime, renewedDel.Validator.Start)
+	assert.Equal(t, vm.stakingParams.MinDelegatorStake+500000, renewedDel.Validator.Wght)
+
+	// The renewed tx isn't stored with the accepted txs, only in the staker set
+	_, err = vm.getTx(onCommitDB, renewedDel.ID())
+	assert.Equal(t, database.ErrNotFound, err)
+	_, isStaker, err := vm.getPrimaryNetworkStaker(onCommitDB, renewedDel.ID())
+	assert.NoError(t, err)
+	assert.True(t, isStaker)
+	assert.NoError(t, onCommitDB.Commit())
+
+	supply, err := vm.getCurrentSupply(vm.DB)
+	assert.NoError(t, err)
+
+	// If the validator isn't renewed, the carried over delegation is returned
+	tx, err = vm.newRewardValidatorTx(vdrTx.ID())
+	assert.NoError(t, err)
+	onCommitDB, onAbortDB, _, _, err := tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	assert.NoError(t, err)
+	delegators, err := vm.getDelegators(onAbortDB, nodeID)
+	assert.NoError(t, err)
+	assert.Empty(t, delegators)
+	for i := range renewedDel.Stake {
+		utxoID := djtx.UTXOID{
+			TxID:        renewedDel.ID(),
+			OutputIndex: uint32(i),
+		}
+		_, err := vm.getUTXO(onAbortDB, utxoID.InputID())
+		assert.NoError(t, err)
+	}
+	newSupply, err := vm.getCurrentSupply(onAbortDB)
+	assert.NoError(t, err)
+	assert.Less(t, newSupply, supply)
+	restaking, err := vm.isRestaking(onAbortDB, renewedDel.ID())
+	assert.NoError(t, err)
+	assert.False(t, restaking)
+
+	// If the validator is renewed, so is the delegation
+	delegators, err = vm.getDelegators(onCommitDB, nodeID)
+	assert.NoError(t, err)
+	assert.Len(t, delegators, 1)
+}
+
+func TestPrimaryNetworkStakerIndex(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	startTime := uint64(defaultValidateStartTime.Add(Delta).Unix()) + 1
+	endTime := startTime + uint64(MinimumStakingDuration/time.Second)
+	nodeID := ids.GenerateTestShortID()
+	vdrTx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		startTime,
+		endTime,
+		nodeID,
+		keys[1].PublicKey().Address(),
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+	vdrTxID := vdrTx.ID()
+
+	vdb := versiondb.New(vm.DB)
+	_, isStaker, err := vm.getPrimaryNetworkStaker(vdb, vdrTxID)
+	assert.NoError(t, err)
+	assert.False(t, isStaker)
+
+	// Pending stakers are indexed
+	assert.NoError(t, vm.enqueueStaker(vdb, constants.PrimaryNetworkID, vdrTx))
+	stakerTx, isStaker, err := vm.getPrimaryNetworkStaker(vdb, vdrTxID)
+	assert.NoError(t, err)
+	assert.True(t, isStaker)
+	assert.Equal(t, vdrTxID, stakerTx.ID())
+
+	// Stakers added before stakers were indexed are indexed on startup
+	assert.NoError(t, vm.deletePrimaryNetworkStaker(vdb, vdrTxID))
+	indexedDB := prefixdb.NewNested([]byte(stakersIndexedDBPrefix), vdb)
+	assert.NoError(t, indexedDB.Delete([]byte(stakersIndexedDBPrefix)))
+	assert.NoError(t, vm.indexPrimaryNetworkStakers(vdb))
+	_, isStaker, err = vm.getPrimaryNetworkStaker(vdb, vdrTxID)
+	assert.NoError(t, err)
+	assert.True(t, isStaker)
+
+	// Current stakers stay indexed until they are removed
+	assert.NoError(t, vm.dequeueStaker(vdb, constants.PrimaryNetworkID, vdrTx))
+	currentTx := &rewardTx{Tx: *vdrTx}
+	assert.NoError(t, vm.addStaker(vdb, constants.PrimaryNetworkID, currentTx))
+	_, isStaker, err = vm.getPrimaryNetworkStaker(vdb, vdrTxID)
+	assert.NoError(t, err)
+	assert.True(t, isStaker)
+	assert.NoError(t, vm.removeStaker(vdb, constants.PrimaryNetworkID, currentTx))
+	_, isStaker, err = vm.getPrimaryNetworkStaker(vdb, vdrTxID)
+	assert.NoError(t, err)
+	assert.False(t, isStaker)
+}
@@ -9,21 +9,9 @@ import (
 )
 
 var (
-	// maxSubMinConsumptionRate is the difference between the maximum
-	// consumption rate of the remaining tokens and the minimum.
-	maxSubMinConsumptionRate = new(big.Int).SetUint64(MaxSubMinConsumptionRate)
-
-	// minConsumptionRate is the consumption rate to use when calculating a
-	// validator period with duration 0.
-	minConsumptionRate = new(big.Int).SetUint64(MinConsumptionRate)
-
 	// consumptionRateDenominator is the magnitude offset used to emulate
 	// floating point fractions.
 	consumptionRateDenominator = new(big.Int).SetUint64(PercentDenominator)
-
-	// consumptionInterval is the period that should be used to calculate the
-	// consumption rate given a duration.
-	consumptionInterval = new(big.Int).SetUint64(uint64(MaximumStakingDuration))
 )
 
 type rewardTx struct {
@@ -35,14 +23,30 @@ type rewardTx struct {
 //
 // RemainingSupply = SupplyCap - ExistingSupply
 // PortionOfExistingSupply = StakedAmount / ExistingSupply
-// PortionOfStakingDuration = StakingDuration / MaximumStakingDuration
+// PortionOfStakingDuration = StakingDuration / ConsumptionInterval
 // MintingRate = MinMintingRate + MaxSubMinMintingRate * PortionOfStakingDuration
 // Reward = RemainingSupply * PortionOfExistingSupply * MintingRate * PortionOfStakingDuration
-func Reward(
+func (p *StakingParameters) Reward(
 	rawDuration time.Duration,
 	rawStakedAmount,
 	rawMaxExistingAmount uint64,
 ) uint64 {
+	if rawMaxExistingAmount == 0 || rawMaxExistingAmount >= p.SupplyCap {
+		return 0
+	}
+
+	// maxSubMinConsumptionRate is the difference between the maximum
+	// consumption rate of the remaining tokens and the minimum.
+	maxSubMinConsumptionRate := new(big.Int).SetUint64(p.MaxSubMinConsumptionRate)
+
+	// minConsumptionRate is the consumption rate to use when calculating a
+	// validator period with duration 0.
+	minConsumptionRate := new(big.Int).SetUint64(p.MinConsumptionRate)
+
+	// consumptionInterval is the period that should be used to calculate the
+	// consumption rate given a duration.
+	consumptionInterval := new(big.Int).SetUint64(uint64(time.Duration(p.ConsumptionInterval) * time.Second))
+
 	duration := new(big.Int).SetUint64(uint64(rawDuration))
 	stakedAmount := new(big.Int).SetUint64(rawStakedAmount)
 	maxExistingAmount := new(big.Int).SetUint64(rawMaxExistingAmount)
@@ -52,7 +56,7 @@ func Reward(
 	adjustedConsumptionRateNumerator.Add(adjustedConsumptionRateNumerator, adjustedMinConsumptionRateNumerator)
 	adjustedConsumptionRateDenominator := new(big.Int).Mul(consumptionInterval, consumptionRateDenominator)
 
-	reward := new(big.Int).SetUint64(SupplyCap - rawMaxExistingAmount)
+	reward := new(big.Int).SetUint64(p.SupplyCap - rawMaxExistingAmount)
 	reward.Mul(reward, adjustedConsumptionRateNumerator)
 	reward.Mul(reward, stakedAmount)
 	reward.Mul(reward, duration)
@@ -0,0 +1,227 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+const (
+	stakingPeriodDBPrefix       = "stakingPeriod"
+	stakingPeriodUptimeDBPrefix = "stakingPeriodUptime"
+	nodeRewardHistoryDBPrefix   = "nodeRewardHistory"
+	addrRewardHistoryDBPrefix   = "addrRewardHistory"
+
+	// Max number of staking periods that can be returned by GetRewardHistory
+	maxStakingPeriodsToFetch = 1024
+)
+
+var (
+	errDBCorrupted = errors.New("database is corrupted")
+)
+
+// stakingPeriod is a completed validation or delegation period of the primary
+// network. It's written when the staker's RewardValidatorTx is decided.
+type stakingPeriod struct {
+	// ID of the tx that added the validator/delegator
+	TxID ids.ID `serialize:"true"`
+	// ID of the node that was validated (or delegated to)
+	NodeID ids.ShortID `serialize:"true"`
+	// True if this staker was a delegator
+	IsDelegator bool `serialize:"true"`
+	// Unix time the staking period started
+	StartTime uint64 `serialize:"true"`
+	// Unix time the staking period ended
+	EndTime uint64 `serialize:"true"`
+	// Amount of DJTX that was staked
+	StakeAmount uint64 `serialize:"true"`
+	// The reward that would be issued if the staker was rewarded
+	PotentialReward uint64 `serialize:"true"`
+	// True if the RewardValidatorTx was committed
+	Rewarded bool `serialize:"true"`
+	// Amount of DJTX that was issued to the staker's rewards owner
+	Reward uint64 `serialize:"true"`
+	// The UTXOs holding the reward
+	RewardUTXOs []djtx.UTXOID `serialize:"true"`
+	// Amount of DJTX issued to the validator as a delegation fee. Only
+	// non-zero for delegators.
+	DelegationFee uint64 `serialize:"true"`
+	// The UTXOs holding the delegation fee
+	DelegationFeeUTXOs []djtx.UTXOID `serialize:"true"`
+	// The addresses of the staker's rewards owner
+	RewardAddresses []ids.ShortID `serialize:"true"`
+}
+
+// rewardAddresses returns the addresses of [owner], if any
+func rewardAddresses(owner verify.Verifiable) []ids.ShortID {
+	if owners, ok := owner.(*secp256k1fx.OutputOwners); ok {
+		return owners.Addrs
+	}
+	return nil
+}
+
+// historyKey returns the key under which [period] is indexed. Periods are
+// ordered by their end time, then by their tx ID.
+func historyKey(period *stakingPeriod) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+hashing.HashLen)}
+	p.PackLong(period.EndTime)
+	p.PackFixedBytes(period.TxID.Bytes())
+	return p.Bytes
+}
+
+// Persist [period] and index it by its node ID and reward addresses
+func (vm *VM) putStakingPeriod(db database.Database, period *stakingPeriod) error {
+	periodBytes, err := Codec.Marshal(period)
+	if err != nil {
+		return err
+	}
+
+	periodDB := prefixdb.NewNested([]byte(stakingPeriodDBPrefix), db)
+	defer periodDB.Close()
+	if err := periodDB.Put(period.TxID.Bytes(), periodBytes); err != nil {
+		return err
+	}
+
+	if err := vm.indexStakingPeriod(db, nodeRewardHistoryDBPrefix, period.NodeID, period); err != nil {
+		return err
+	}
+	for _, addr := range period.RewardAddresses {
+		if err := vm.indexStakingPeriod(db, addrRewardHistoryDBPrefix, addr, period); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Index [period] under [key] in the index [indexPrefix]
+func (vm *VM) indexStakingPeriod(db database.Database, indexPrefix string, key ids.ShortID, period *stakingPeriod) error {
+	indexDB := prefixdb.NewNested([]byte(indexPrefix), db)
+	defer indexDB.Close()
+	keyDB := prefixdb.NewNested(key.Bytes(), indexDB)
+	defer keyDB.Close()
+
+	return keyDB.Put(historyKey(period), nil)
+}
+
+// Persist the uptime of the validator during the staking period of the staker
+// added by [txID], as observed by this node, in units of [PercentDenominator].
+// Uptimes differ between nodes, so they must only be written to [vm.DB] once
+// the staking period is decided, never to the chain state.
+func (vm *VM) putStakingPeriodUptime(db database.Database, txID ids.ID, uptime uint64) error {
+	uptimeDB := prefixdb.NewNested([]byte(stakingPeriodUptimeDBPrefix), db)
+	defer uptimeDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(uptime)
+	return uptimeDB.Put(txID.Bytes(), p.Bytes)
+}
+
+// Returns the uptime this node observed during the staking period of the
+// staker added by [txID], and true, if this node decided the period
+func (vm *VM) getStakingPeriodUptime(db database.Database, txID ids.ID) (uint64, bool, error) {
+	uptimeDB := prefixdb.NewNested([]byte(stakingPeriodUptimeDBPrefix), db)
+	defer uptimeDB.Close()
+
+	uptimeBytes, err := uptimeDB.Get(txID.Bytes())
+	if err == database.ErrNotFound {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	p := wrappers.Packer{Bytes: uptimeBytes}
+	uptime := p.UnpackLong()
+	return uptime, true, p.Err
+}
+
+// Returns the staking period of the staker added by [txID]
+func (vm *VM) getStakingPeriod(db database.Database, txID ids.ID) (*stakingPeriod, error) {
+	periodDB := prefixdb.NewNested([]byte(stakingPeriodDBPrefix), db)
+	defer periodDB.Close()
+
+	periodBytes, err := periodDB.Get(txID.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	period := &stakingPeriod{}
+	if err := Codec.Unmarshal(periodBytes, period); err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// Returns the staking periods of node [nodeID], ordered by end time
+// See getRewardHistory
+func (vm *VM) getNodeRewardHistory(db database.Database, nodeID ids.ShortID, startTxID ids.ID, limit int) ([]*stakingPeriod, error) {
+	return vm.getRewardHistory(db, nodeRewardHistoryDBPrefix, nodeID, startTxID, limit)
+}
+
+// Returns the staking periods rewarding [addr], ordered by end time
+// See getRewardHistory
+func (vm *VM) getAddrRewardHistory(db database.Database, addr ids.ShortID, startTxID ids.ID, limit int) ([]*stakingPeriod, error) {
+	return vm.getRewardHistory(db, addrRewardHistoryDBPrefix, addr, startTxID, limit)
+}
+
+// Returns the staking periods indexed under [key] in the index [indexPrefix].
+// If [startTxID] is non-empty, only returns the periods after the period of
+// [startTxID].
+// Returns at most [limit] periods.
+// If [limit] <= 0 or [limit] > maxStakingPeriodsToFetch, it is set to
+// [maxStakingPeriodsToFetch].
+func (vm *VM) getRewardHistory(
+	db database.Database,
+	indexPrefix string,
+	key ids.ShortID,
+	startTxID ids.ID,
+	limit int,
+) ([]*stakingPeriod, error) {
+	if limit <= 0 || limit > maxStakingPeriodsToFetch {
+		limit = maxStakingPeriodsToFetch
+	}
+
+	var start []byte
+	if !startTxID.IsZero() && !startTxID.Equals(ids.Empty) {
+		startPeriod, err := vm.getStakingPeriod(db, startTxID)
+		if err != nil {
+			return nil, err
+		}
+		start = historyKey(startPeriod)
+	}
+
+	indexDB := prefixdb.NewNested([]byte(indexPrefix), db)
+	defer indexDB.Close()
+	keyDB := prefixdb.NewNested(key.Bytes(), indexDB)
+	defer keyDB.Close()
+	iter := keyDB.NewIteratorWithStart(start)
+	defer iter.Release()
+
+	periods := []*stakingPeriod(nil)
+	for len(periods) < limit && iter.Next() {
+		indexKey := iter.Key()
+		if len(indexKey) != wrappers.LongLen+hashing.HashLen {
+			return nil, errDBCorrupted
+		}
+		txID, err := ids.ToID(indexKey[wrappers.LongLen:])
+		if err != nil {
+			return nil, err
+		}
+		if start != nil && txID.Equals(startTxID) {
+			continue
+		}
+		period, err := vm.getStakingPeriod(db, txID)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	return periods, iter.Error()
+}
@@ -0,0 +1,229 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+func TestRewardDelegatorTxRewardHistory(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	vdrRewardAddress := ids.GenerateTestShortID()
+	delRewardAddress := ids.GenerateTestShortID()
+
+	vdrStartTime := uint64(defaultValidateStartTime.Unix()) + 1
+	vdrEndTime := uint64(defaultValidateStartTime.Add(2 * MinimumStakingDuration).Unix())
+	vdrNodeID := ids.GenerateTestShortID()
+	vdrTx, err := vm.newAddValidatorTx(
+		vm.stakingParams.MinValidatorStake,
+		vdrStartTime,
+		vdrEndTime,
+		vdrNodeID,
+		vdrRewardAddress,
+		PercentDenominator/4,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+
+	delTx, err := vm.newAddDelegatorTx(
+		vm.stakingParams.MinDelegatorStake,
+		vdrStartTime,
+		vdrEndTime,
+		vdrNodeID,
+		delRewardAddress,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+
+	assert.NoError(t, vm.addStaker(vm.DB, constants.PrimaryNetworkID, &rewardTx{Tx: *vdrTx}))
+	assert.NoError(t, vm.addStaker(vm.DB, constants.PrimaryNetworkID, &rewardTx{
+		Reward: 1000000,
+		Tx:     *delTx,
+	}))
+	assert.NoError(t, vm.putTimestamp(vm.DB, time.Unix(int64(vdrEndTime), 0)))
+
+	tx, err := vm.newRewardValidatorTx(delTx.ID())
+	assert.NoError(t, err)
+	onCommitDB, onAbortDB, onCommitFunc, _, err := tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	assert.NoError(t, err)
+
+	// The uptime this node observed isn't part of the chain state
+	for _, db := range []database.Database{onCommitDB, onAbortDB} {
+		_, observed, err := vm.getStakingPeriodUptime(db, delTx.ID())
+		assert.NoError(t, err)
+		assert.False(t, observed)
+	}
+
+	// If the tx is committed, the period is recorded with its rewards
+	periods, err := vm.getAddrRewardHistory(onCommitDB, delRewardAddress, ids.Empty, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, periods, 1) {
+		period := periods[0]
+		assert.Equal(t, delTx.ID(), period.TxID)
+		assert.Equal(t, vdrNodeID, period.NodeID)
+		assert.True(t, period.IsDelegator)
+		assert.Equal(t, vdrStartTime, period.StartTime)
+		assert.Equal(t, vdrEndTime, period.EndTime)
+		assert.Equal(t, vm.stakingParams.MinDelegatorStake, period.StakeAmount)
+		assert.EqualValues(t, 1000000, period.PotentialReward)
+		assert.True(t, period.Rewarded)
+		assert.EqualValues(t, 1000000, period.Reward+period.DelegationFee)
+		assert.Len(t, period.RewardUTXOs, 1)
+		assert.Len(t, period.DelegationFeeUTXOs, 1)
+		assert.Equal(t, []ids.ShortID{delRewardAddress}, period.RewardAddresses)
+
+		for _, utxoID := range append(period.RewardUTXOs, period.DelegationFeeUTXOs...) {
+			_, err := vm.getUTXO(onCommitDB, utxoID.InputID())
+			assert.NoError(t, err)
+		}
+	}
+	periods, err = vm.getNodeRewardHistory(onCommitDB, vdrNodeID, ids.Empty, 0)
+	assert.NoError(t, err)
+	assert.Len(t, periods, 1)
+
+	// If the tx is aborted, the period is recorded without rewards
+	periods, err = vm.getNodeRewardHistory(onAbortDB, vdrNodeID, ids.Empty, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, periods, 1) {
+		period := periods[0]
+		assert.Equal(t, delTx.ID(), period.TxID)
+		assert.EqualValues(t, 1000000, period.PotentialReward)
+		assert.False(t, period.Rewarded)
+		assert.Zero(t, period.Reward)
+		assert.Zero(t, period.DelegationFee)
+		assert.Empty(t, period.RewardUTXOs)
+		assert.Empty(t, period.DelegationFeeUTXOs)
+	}
+
+	// The validator's reward address isn't credited with the delegation
+	periods, err = vm.getAddrRewardHistory(onCommitDB, vdrRewardAddress, ids.Empty, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, periods)
+
+	// Nothing is recorded until the tx is decided
+	periods, err = vm.getNodeRewardHistory(vm.DB, vdrNodeID, ids.Empty, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, periods)
+
+	// Once the tx is decided, the observed uptime is recorded locally
+	assert.NoError(t, onCommitDB.Commit())
+	assert.NoError(t, onCommitFunc())
+	_, observed, err := vm.getStakingPeriodUptime(vm.DB, delTx.ID())
+	assert.NoError(t, err)
+	assert.True(t, observed)
+}
+
+func TestGetRewardHistory(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	vm.uptimePercentage = .6
+
+	nodeID := ids.GenerateTestShortID()
+	rewardAddress := keys[0].PublicKey().Address()
+
+	// Periods are returned ordered by end time, regardless of the order they
+	// were written in
+	endTimes := []uint64{30, 10, 20}
+	txIDs := make([]ids.ID, len(endTimes))
+	for i, endTime := range endTimes {
+		txIDs[i] = ids.GenerateTestID()
+		assert.NoError(t, vm.putStakingPeriod(vm.DB, &stakingPeriod{
+			TxID:            txIDs[i],
+			NodeID:          nodeID,
+			EndTime:         endTime,
+			StakeAmount:     defaultWeight,
+			PotentialReward: 1,
+			RewardAddresses: []ids.ShortID{rewardAddress},
+		}))
+		assert.NoError(t, vm.putStakingPeriodUptime(vm.DB, txIDs[i], PercentDenominator/2))
+	}
+	expectedTxIDs := []ids.ID{txIDs[1], txIDs[2], txIDs[0]}
+
+	service := &Service{vm: vm}
+	nodeIDStr := nodeID.PrefixedString(constants.NodeIDPrefix)
+	rewardAddressStr, err := vm.FormatLocalAddress(rewardAddress)
+	assert.NoError(t, err)
+
+	// Exactly one of nodeID and rewardAddress must be provided
+	assert.Equal(t, errNoNodeIDOrRewardAddress, service.GetRewardHistory(nil, &GetRewardHistoryArgs{}, &GetRewardHistoryReply{}))
+	assert.Equal(t, errNodeIDAndRewardAddress, service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+		NodeID:        nodeIDStr,
+		RewardAddress: rewardAddressStr,
+	}, &GetRewardHistoryReply{}))
+
+	reply := GetRewardHistoryReply{}
+	assert.NoError(t, service.GetRewardHistory(nil, &GetRewardHistoryArgs{RewardAddress: rewardAddressStr}, &reply))
+	assert.EqualValues(t, len(expectedTxIDs), reply.NumFetched)
+	for i, period := range reply.Periods {
+		assert.Equal(t, expectedTxIDs[i], period.TxID)
+		assert.Equal(t, nodeIDStr, period.NodeID)
+		if assert.NotNil(t, period.Uptime) {
+			assert.EqualValues(t, 0.5, *period.Uptime)
+		}
+		assert.False(t, period.Rewarded)
+		assert.Equal(t, rewardWithheldInsufficientUptime, period.Reason)
+		assert.Equal(t, []string{rewardAddressStr}, period.RewardAddresses)
+	}
+
+	// Page through the node's history one period at a time
+	startIndex := ""
+	for _, expectedTxID := range expectedTxIDs {
+		reply := GetRewardHistoryReply{}
+		assert.NoError(t, service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+			NodeID:     nodeIDStr,
+			Limit:      1,
+			StartIndex: startIndex,
+		}, &reply))
+		if assert.Len(t, reply.Periods, 1) {
+			assert.Equal(t, expectedTxID, reply.Periods[0].TxID)
+		}
+		assert.Equal(t, expectedTxID.String(), reply.EndIndex)
+		startIndex = reply.EndIndex
+	}
+	reply = GetRewardHistoryReply{}
+	assert.NoError(t, service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+		NodeID:     nodeIDStr,
+		StartIndex: startIndex,
+	}, &reply))
+	assert.Empty(t, reply.Periods)
+	assert.Equal(t, startIndex, reply.EndIndex)
+
+	// The uptime of a period this node didn't observe is omitted, and the
+	// reward isn't blamed on it
+	otherTxID := ids.GenerateTestID()
+	assert.NoError(t, vm.putStakingPeriod(vm.DB, &stakingPeriod{
+		TxID:    otherTxID,
+		NodeID:  nodeID,
+		EndTime: 40,
+	}))
+	reply = GetRewardHistoryReply{}
+	assert.NoError(t, service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+		NodeID:     nodeIDStr,
+		StartIndex: expectedTxIDs[len(expectedTxIDs)-1].String(),
+	}, &reply))
+	if assert.Len(t, reply.Periods, 1) {
+		assert.Equal(t, otherTxID, reply.Periods[0].TxID)
+		assert.Nil(t, reply.Periods[0].Uptime)
+		assert.Equal(t, rewardWithheldByNetwork, reply.Periods[0].Reason)
+	}
+}
@@ -12,18 +12,19 @@ import (
 )
 
 func TestRewardLongerDurationBonus(t *testing.T) {
+	params := DefaultStakingParameters()
 	shortDuration := 14 * 24 * time.Hour
 	totalDuration := 365 * 24 * time.Hour
 	shortBalance := units.KiloDjtx
 	for i := 0; i < int(totalDuration/shortDuration); i++ {
-		reward := Reward(shortDuration, shortBalance, 359*units.MegaDjtx+shortBalance)
+		reward := params.Reward(shortDuration, shortBalance, 359*units.MegaDjtx+shortBalance)
 		shortBalance += reward
 	}
-	reward := Reward(totalDuration%shortDuration, shortBalance, 359*units.MegaDjtx+shortBalance)
+	reward := params.Reward(totalDuration%shortDuration, shortBalance, 359*units.MegaDjtx+shortBalance)
 	shortBalance += reward
 
 	longBalance := units.KiloDjtx
-	longBalance += Reward(totalDuration, longBalance, 359*units.MegaDjtx+longBalance)
+	longBalance += params.Reward(totalDuration, longBalance, 359*units.MegaDjtx+longBalance)
 
 	if shortBalance >= longBalance {
 		t.Fatalf("should promote stakers to stake longer")
@@ -31,6 +32,7 @@ func TestRewardLongerDurationBonus(t *testing.T) {
 }
 
 func TestRewards(t *testing.T) {
+	params := DefaultStakingParameters()
 	tests := []struct {
 		duration       time.Duration
 		stakeAmount    uint64
@@ -107,7 +109,7 @@ func TestRewards(t *testing.T) {
 			test.expectedReward,
 		)
 		t.Run(name, func(t *testing.T) {
-			reward := Reward(
+			reward := params.Reward(
 				test.duration,
 				test.stakeAmount,
 				test.existingAmount,
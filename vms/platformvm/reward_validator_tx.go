@@ -35,6 +35,9 @@ var (
 // If this transaction is accepted and the next block accepted is an Abort
 // block, the validator is removed and the address that the validator specified
 // receives the staked DJTX but no reward.
+//
+// If the staker is restaking and this transaction's proposal is committed,
+// the staker is renewed rather than removed. See restake.go.
 type UnsignedRewardValidatorTx struct {
 	djtx.Metadata
 
@@ -109,10 +112,29 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 		return nil, nil, nil, nil, tempError{err}
 	}
 
+	// Record the staking period. The reward fields are only populated if this
+	// tx's proposal is committed.
+	period := stakingPeriod{
+		TxID:            tx.TxID,
+		StartTime:       uint64(staker.StartTime().Unix()),
+		EndTime:         uint64(currentTime.Unix()),
+		PotentialReward: stakerTx.Reward,
+	}
+
 	nodeID := ids.ShortID{}
 	startTime := time.Time{}
 	switch uStakerTx := stakerTx.Tx.UnsignedTx.(type) {
 	case *UnsignedAddValidatorTx:
+		period.StakeAmount = uStakerTx.Validator.Wght
+		period.RewardAddresses = rewardAddresses(uStakerTx.RewardsOwner)
+
+		// If the validator is restaking, it keeps validating with its stake
+		// and reward if this tx's proposal is committed
+		renewedTx, err := vm.getRenewedStakerTx(onCommitDB, &stakerTx.Tx, stakerTx.Reward, currentTime)
+		if err != nil {
+			return nil, nil, nil, nil, tempError{err}
+		}
+
 		// Refund the stake here
 		for i, out := range uStakerTx.Stake {
 			utxo := &djtx.UTXO{
@@ -124,8 +146,10 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 				Out:   out.Output(),
 			}
 
-			if err := vm.putUTXO(onCommitDB, utxo); err != nil {
-				return nil, nil, nil, nil, tempError{err}
+			if renewedTx == nil {
+				if err := vm.putUTXO(onCommitDB, utxo); err != nil {
+					return nil, nil, nil, nil, tempError{err}
+				}
 			}
 			if err := vm.putUTXO(onAbortDB, utxo); err != nil {
 				return nil, nil, nil, nil, tempError{err}
@@ -133,7 +157,9 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 		}
 
 		// Provide the reward here
-		if stakerTx.Reward > 0 {
+		if stakerTx.Reward > 0 && renewedTx != nil {
+			period.Reward = stakerTx.Reward
+		} else if stakerTx.Reward > 0 {
 			outIntf, err := vm.fx.CreateOutput(stakerTx.Reward, uStakerTx.RewardsOwner)
 			if err != nil {
 				return nil, nil, nil, nil, permError{err}
@@ -142,17 +168,21 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 			if !ok {
 				return nil, nil, nil, nil, permError{errInvalidState}
 			}
+			utxoID := djtx.UTXOID{
+				TxID:        tx.TxID,
+				OutputIndex: uint32(len(uStakerTx.Outs) + len(uStakerTx.Stake)),
+			}
 			if err := vm.putUTXO(onCommitDB, &djtx.UTXO{
-				UTXOID: djtx.UTXOID{
-					TxID:        tx.TxID,
-					OutputIndex: uint32(len(uStakerTx.Outs) + len(uStakerTx.Stake)),
-				},
-				Asset: djtx.Asset{ID: vm.Ctx.DJTXAssetID},
-				Out:   out,
+				UTXOID: utxoID,
+				Asset:  djtx.Asset{ID: vm.Ctx.DJTXAssetID},
+				Out:    out,
 			}); err != nil {
 				return nil, nil, nil, nil, tempError{err}
 			}
-
+			period.Reward = stakerTx.Reward
+			period.RewardUTXOs = []djtx.UTXOID{utxoID}
+		}
+		if stakerTx.Reward > 0 {
 			currentSupply, err := vm.getCurrentSupply(onAbortDB)
 			if err != nil {
 				return nil, nil, nil, nil, tempError{err}
@@ -175,7 +205,24 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 		if err := vm.deleteUptime(onAbortDB, nodeID); err != nil {
 			return nil, nil, nil, nil, tempError{err}
 		}
+
+		// Delegations carried over to a renewal that doesn't happen are
+		// returned to their owners
+		if renewedTx != nil {
+			if err := vm.renewStaker(onCommitDB, tx.TxID, renewedTx); err != nil {
+				return nil, nil, nil, nil, tempError{err}
+			}
+		} else if err := vm.returnCarriedOverDelegations(onCommitDB, nodeID); err != nil {
+			return nil, nil, nil, nil, tempError{err}
+		}
+		if err := vm.returnCarriedOverDelegations(onAbortDB, nodeID); err != nil {
+			return nil, nil, nil, nil, tempError{err}
+		}
 	case *UnsignedAddDelegatorTx:
+		period.IsDelegator = true
+		period.StakeAmount = uStakerTx.Validator.Wght
+		period.RewardAddresses = rewardAddresses(uStakerTx.RewardsOwner)
+
 		// We're removing a delegator
 		vdrTx, ok, err := vm.isValidator(db, constants.PrimaryNetworkID, uStakerTx.Validator.NodeID)
 		if err != nil {
@@ -191,6 +238,23 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 				fmt.Errorf("expected vdr to be *UnsignedAddValidatorTx but is %T", vdrTx)}
 		}
 
+		// Calculate split of reward between delegator/delegatee
+		// The delegator gives stake to the validatee
+		delegatorShares := PercentDenominator - uint64(vdr.Shares)                  // parentTx.Shares <= NumberOfShares so no underflow
+		delegatorReward := delegatorShares * (stakerTx.Reward / PercentDenominator) // delegatorShares <= NumberOfShares so no overflow
+		// Delay rounding as long as possible for small numbers
+		if optimisticReward, err := safemath.Mul64(delegatorShares, stakerTx.Reward); err == nil {
+			delegatorReward = optimisticReward / PercentDenominator
+		}
+		delegateeReward := stakerTx.Reward - delegatorReward // delegatorReward <= reward so no underflow
+
+		// If the delegator is restaking, it keeps delegating with its stake
+		// and reward if this tx's proposal is committed
+		renewedTx, err := vm.getRenewedStakerTx(onCommitDB, &stakerTx.Tx, delegatorReward, currentTime)
+		if err != nil {
+			return nil, nil, nil, nil, tempError{err}
+		}
+
 		// Refund the stake here
 		for i, out := range uStakerTx.Stake {
 			utxo := &djtx.UTXO{
@@ -202,8 +266,10 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 				Out:   out.Output(),
 			}
 
-			if err := vm.putUTXO(onCommitDB, utxo); err != nil {
-				return nil, nil, nil, nil, tempError{err}
+			if renewedTx == nil {
+				if err := vm.putUTXO(onCommitDB, utxo); err != nil {
+					return nil, nil, nil, nil, tempError{err}
+				}
 			}
 			if err := vm.putUTXO(onAbortDB, utxo); err != nil {
 				return nil, nil, nil, nil, tempError{err}
@@ -222,20 +288,12 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 			return nil, nil, nil, nil, tempError{err}
 		}
 
-		// Calculate split of reward between delegator/delegatee
-		// The delegator gives stake to the validatee
-		delegatorShares := PercentDenominator - uint64(vdr.Shares)                  // parentTx.Shares <= NumberOfShares so no underflow
-		delegatorReward := delegatorShares * (stakerTx.Reward / PercentDenominator) // delegatorShares <= NumberOfShares so no overflow
-		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := safemath.Mul64(delegatorShares, stakerTx.Reward); err == nil {
-			delegatorReward = optimisticReward / PercentDenominator
-		}
-		delegateeReward := stakerTx.Reward - delegatorReward // delegatorReward <= reward so no underflow
-
 		offset := 0
 
 		// Reward the delegator here
-		if delegatorReward > 0 {
+		if delegatorReward > 0 && renewedTx != nil {
+			period.Reward = delegatorReward
+		} else if delegatorReward > 0 {
 			outIntf, err := vm.fx.CreateOutput(delegatorReward, uStakerTx.RewardsOwner)
 			if err != nil {
 				return nil, nil, nil, nil, permError{err}
@@ -244,16 +302,19 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 			if !ok {
 				return nil, nil, nil, nil, permError{errInvalidState}
 			}
+			utxoID := djtx.UTXOID{
+				TxID:        tx.TxID,
+				OutputIndex: uint32(len(uStakerTx.Outs) + len(uStakerTx.Stake)),
+			}
 			if err := vm.putUTXO(onCommitDB, &djtx.UTXO{
-				UTXOID: djtx.UTXOID{
-					TxID:        tx.TxID,
-					OutputIndex: uint32(len(uStakerTx.Outs) + len(uStakerTx.Stake)),
-				},
-				Asset: djtx.Asset{ID: vm.Ctx.DJTXAssetID},
-				Out:   out,
+				UTXOID: utxoID,
+				Asset:  djtx.Asset{ID: vm.Ctx.DJTXAssetID},
+				Out:    out,
 			}); err != nil {
 				return nil, nil, nil, nil, tempError{err}
 			}
+			period.Reward = delegatorReward
+			period.RewardUTXOs = []djtx.UTXOID{utxoID}
 
 			offset++
 		}
@@ -268,27 +329,39 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 			if !ok {
 				return nil, nil, nil, nil, permError{errInvalidState}
 			}
+			utxoID := djtx.UTXOID{
+				TxID:        tx.TxID,
+				OutputIndex: uint32(len(uStakerTx.Outs) + len(uStakerTx.Stake) + offset),
+			}
 			if err := vm.putUTXO(onCommitDB, &djtx.UTXO{
-				UTXOID: djtx.UTXOID{
-					TxID:        tx.TxID,
-					OutputIndex: uint32(len(uStakerTx.Outs) + len(uStakerTx.Stake) + offset),
-				},
-				Asset: djtx.Asset{ID: vm.Ctx.DJTXAssetID},
-				Out:   out,
+				UTXOID: utxoID,
+				Asset:  djtx.Asset{ID: vm.Ctx.DJTXAssetID},
+				Out:    out,
 			}); err != nil {
 				return nil, nil, nil, nil, tempError{err}
 			}
+			period.DelegationFee = delegateeReward
+			period.DelegationFeeUTXOs = []djtx.UTXOID{utxoID}
 		}
 		nodeID = uStakerTx.Validator.ID()
 		startTime = vdrTx.StartTime()
+
+		if renewedTx != nil {
+			if err := vm.renewStaker(onCommitDB, tx.TxID, renewedTx); err != nil {
+				return nil, nil, nil, nil, tempError{err}
+			}
+		}
 	default:
 		return nil, nil, nil, nil, permError{errShouldBeDSValidator}
 	}
 
-	// Regardless of whether this tx is committed or aborted, update the
-	// validator set to remove the staker. onAbortDB or onCommitDB should commit
-	// (flush to vm.DB) before this is called
-	updateValidators := func() error { return vm.updateVdrMgr(false) }
+	// The staker's restaking preference is cleared unless it was renewed
+	if err := vm.deleteRestake(onCommitDB, tx.TxID); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
+	if err := vm.deleteRestake(onAbortDB, tx.TxID); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
 
 	uptime, err := vm.calculateUptime(vm.DB, nodeID, startTime)
 	if err != nil {
@@ -296,6 +369,45 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 	}
 
 	tx.shouldPreferCommit = uptime >= vm.uptimePercentage
+
+	// The uptime this node observed isn't part of the chain state, so it's
+	// only recorded in this node's database once the tx is decided
+	periodUptime := uint64(0)
+	switch {
+	case uptime >= 1:
+		periodUptime = PercentDenominator
+	case uptime > 0:
+		periodUptime = uint64(uptime * PercentDenominator)
+	}
+
+	// Regardless of whether this tx is committed or aborted, update the
+	// validator set to remove the staker. onAbortDB or onCommitDB should commit
+	// (flush to vm.DB) before this is called
+	updateValidators := func() error {
+		if err := vm.putStakingPeriodUptime(vm.DB, tx.TxID, periodUptime); err != nil {
+			return err
+		}
+		if err := vm.DB.Commit(); err != nil {
+			return err
+		}
+		return vm.updateVdrMgr(false)
+	}
+
+	// Index the staking period, marking whether the staker was rewarded
+	period.NodeID = nodeID
+	period.Rewarded = true
+	if err := vm.putStakingPeriod(onCommitDB, &period); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
+	abortedPeriod := period
+	abortedPeriod.Rewarded = false
+	abortedPeriod.Reward = 0
+	abortedPeriod.RewardUTXOs = nil
+	abortedPeriod.DelegationFee = 0
+	abortedPeriod.DelegationFeeUTXOs = nil
+	if err := vm.putStakingPeriod(onAbortDB, &abortedPeriod); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
 	return onCommitDB, onAbortDB, updateValidators, updateValidators, nil
 }
 
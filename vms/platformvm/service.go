@@ -11,8 +11,11 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -30,15 +33,30 @@ const (
 
 	// Max number of addresses that can be passed in as argument to GetStake
 	maxGetStakeAddrs = 256
+
+	// Max number of stakers that can be added by AddDelegators or
+	// AddSubnetValidators
+	maxBulkStakers = 256
+
+	// Reasons a staker wasn't rewarded, returned by GetRewardHistory
+	rewardWithheldInsufficientUptime = "insufficient uptime"
+	rewardWithheldByNetwork          = "the network voted not to reward the staker"
 )
 
 var (
-	errMissingDecisionBlock  = errors.New("should have a decision block within the past two blocks")
-	errNoFunds               = errors.New("no spendable funds were found")
-	errNoSubnetID            = errors.New("argument 'subnetID' not provided")
-	errNoRewardAddress       = errors.New("argument 'rewardAddress' not provided")
-	errInvalidDelegationRate = errors.New("argument 'delegationFeeRate' must be between 0 and 100, inclusive")
-	errNoAddresses           = errors.New("no addresses provided")
+	errMissingDecisionBlock    = errors.New("should have a decision block within the past two blocks")
+	errNoFunds                 = errors.New("no spendable funds were found")
+	errNoSubnetID              = errors.New("argument 'subnetID' not provided")
+	errNoRewardAddress         = errors.New("argument 'rewardAddress' not provided")
+	errInvalidDelegationRate   = errors.New("argument 'delegationFeeRate' must be between 0 and 100, inclusive")
+	errNoAddresses             = errors.New("no addresses provided")
+	errNoNodeIDOrRewardAddress = errors.New("one of 'nodeID' and 'rewardAddress' must be provided")
+	errNodeIDAndRewardAddress  = errors.New("only one of 'nodeID' and 'rewardAddress' can be provided")
+	errNoBlockIDOrHeight       = errors.New("one of 'blockID' and 'height' must be provided")
+	errBlockIDAndHeight        = errors.New("only one of 'blockID' and 'height' can be provided")
+	errNoTxID                  = errors.New("argument 'txID' not provided")
+	errNoStakers               = errors.New("no stakers provided")
+	errTooManyStakers          = fmt.Errorf("at most %d stakers can be added at once", maxBulkStakers)
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -442,8 +460,14 @@ func (service *Service) GetSubnets(_ *http.Request, args *GetSubnetsArgs, respon
 	if getAll {
 		response.Subnets = make([]APISubnet, len(subnets)+1)
 		for i, subnet := range subnets {
-			unsignedTx := subnet.UnsignedTx.(*UnsignedCreateSubnetTx)
-			owner := unsignedTx.Owner.(*secp256k1fx.OutputOwners)
+			subnetOwner, err := service.vm.getSubnetOwner(service.vm.DB, subnet.ID())
+			if err != nil {
+				return fmt.Errorf("couldn't get owner of subnet %s: %w", subnet.ID(), err)
+			}
+			owner, ok := subnetOwner.(*secp256k1fx.OutputOwners)
+			if !ok {
+				return errUnknownOwners
+			}
 			controlAddrs := []string{}
 			for _, controlKeyID := range owner.Addrs {
 				addr, err := service.vm.FormatLocalAddress(controlKeyID)
@@ -471,8 +495,14 @@ func (service *Service) GetSubnets(_ *http.Request, args *GetSubnetsArgs, respon
 	idsSet.Add(args.IDs...)
 	for _, subnet := range subnets {
 		if idsSet.Contains(subnet.ID()) {
-			unsignedTx := subnet.UnsignedTx.(*UnsignedCreateSubnetTx)
-			owner := unsignedTx.Owner.(*secp256k1fx.OutputOwners)
+			subnetOwner, err := service.vm.getSubnetOwner(service.vm.DB, subnet.ID())
+			if err != nil {
+				return fmt.Errorf("couldn't get owner of subnet %s: %w", subnet.ID(), err)
+			}
+			owner, ok := subnetOwner.(*secp256k1fx.OutputOwners)
+			if !ok {
+				return errUnknownOwners
+			}
 			controlAddrs := []string{}
 			for _, controlKeyID := range owner.Addrs {
 				addr, err := service.vm.FormatLocalAddress(controlKeyID)
@@ -559,6 +589,11 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	reply.Validators = []interface{}{}
 	reply.Delegators = []interface{}{}
 
+	currentTime, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get chain timestamp: %w", err)
+	}
+
 	stopPrefix := []byte(fmt.Sprintf("%s%s", args.SubnetID, stopDBPrefix))
 	stopDB := prefixdb.NewNested(stopPrefix, service.vm.DB)
 	defer stopDB.Close()
@@ -620,6 +655,17 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 			}
 			uptime := json.Float32(rawUptime)
 
+			maxDelegated := service.vm.stakingParams.maxDelegatedWeight(staker.Validator.Wght)
+			delegators, err := service.vm.getDelegators(service.vm.DB, nodeID)
+			if err != nil {
+				return fmt.Errorf("couldn't get delegators: %w", err)
+			}
+			delegated := maxConcurrentWeight(delegators, currentTime, staker.EndTime())
+			delegationCapacity := json.Uint64(0)
+			if delegated < maxDelegated {
+				delegationCapacity = json.Uint64(maxDelegated - delegated)
+			}
+
 			service.vm.uptimeLock.Lock()
 			_, connected := service.vm.connections[nodeID.Key()]
 			service.vm.uptimeLock.Unlock()
@@ -647,14 +693,19 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 					EndTime:     json.Uint64(staker.EndTime().Unix()),
 					StakeAmount: &weight,
 				},
-				Uptime:          &uptime,
-				Connected:       &connected,
-				PotentialReward: &potentialReward,
-				RewardOwner:     rewardOwner,
-				DelegationFee:   delegationFee,
+				Uptime:             &uptime,
+				Connected:          &connected,
+				PotentialReward:    &potentialReward,
+				RewardOwner:        rewardOwner,
+				DelegationFee:      delegationFee,
+				DelegationCapacity: &delegationCapacity,
 			})
 		case *UnsignedAddSubnetValidatorTx:
-			weight := json.Uint64(staker.Validator.Weight())
+			rawWeight, err := service.vm.getSubnetValidatorWeight(service.vm.DB, staker)
+			if err != nil {
+				return err
+			}
+			weight := json.Uint64(rawWeight)
 			reply.Validators = append(reply.Validators, APIStaker{
 				NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
 				StartTime: json.Uint64(staker.StartTime().Unix()),
@@ -737,7 +788,11 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 				Connected:     &connected,
 			})
 		case *UnsignedAddSubnetValidatorTx:
-			weight := json.Uint64(staker.Validator.Weight())
+			rawWeight, err := service.vm.getSubnetValidatorWeight(service.vm.DB, staker)
+			if err != nil {
+				return err
+			}
+			weight := json.Uint64(rawWeight)
 			reply.Validators = append(reply.Validators, APIStaker{
 				NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
 				StartTime: json.Uint64(staker.StartTime().Unix()),
@@ -808,6 +863,42 @@ func (service *Service) SampleValidators(_ *http.Request, args *SampleValidators
 	return nil
 }
 
+// GetValidatorsAtArgs are the arguments for calling GetValidatorsAt
+type GetValidatorsAtArgs struct {
+	// Height of the P-Chain at which to get the validator set
+	Height json.Uint64 `json:"height"`
+
+	// ID of the subnet whose validators are returned
+	// If omitted, defaults to the primary network
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// GetValidatorsAtReply are the results from calling GetValidatorsAt
+type GetValidatorsAtReply struct {
+	// Node ID -> The node's weight
+	Validators map[string]json.Uint64 `json:"validators"`
+}
+
+// GetValidatorsAt returns the weights of the validators of a subnet once the
+// P-Chain block at the given height was accepted
+func (service *Service) GetValidatorsAt(_ *http.Request, args *GetValidatorsAtArgs, reply *GetValidatorsAtReply) error {
+	service.vm.Ctx.Log.Info("Platform: GetValidatorsAt called with Height = %d", args.Height)
+	if args.SubnetID.IsZero() {
+		args.SubnetID = constants.PrimaryNetworkID
+	}
+
+	vdrs, err := service.vm.GetValidatorSet(uint64(args.Height), args.SubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validator set at height %d: %w", args.Height, err)
+	}
+
+	reply.Validators = make(map[string]json.Uint64, vdrs.Len())
+	for _, vdr := range vdrs.List() {
+		reply.Validators[vdr.ID().PrefixedString(constants.NodeIDPrefix)] = json.Uint64(vdr.Weight())
+	}
+	return nil
+}
+
 /*
  ******************************************************
  ************ Add Validators to Subnets ***************
@@ -1035,6 +1126,337 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	return errs.Err
 }
 
+// RemoveSubnetValidatorArgs are the arguments to RemoveSubnetValidator
+type RemoveSubnetValidatorArgs struct {
+	api.UserPass
+	// ID of the node to remove
+	NodeID string `json:"nodeID"`
+	// ID of the subnet the node is removed from
+	SubnetID string `json:"subnetID"`
+	// If non-zero, the node's weight is set to [Weight] instead of the node
+	// being removed from the subnet
+	Weight json.Uint64 `json:"weight"`
+}
+
+// RemoveSubnetValidator creates and signs and issues a transaction to remove a
+// validator from a subnet other than the primary network
+func (service *Service) RemoveSubnetValidator(_ *http.Request, args *RemoveSubnetValidatorArgs, response *api.JsonTxID) error {
+	service.vm.Ctx.Log.Info("Platform: RemoveSubnetValidator called")
+	switch {
+	case args.SubnetID == "":
+		return errNoSubnetID
+	}
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("error parsing nodeID: '%s': %w", args.NodeID, err)
+	}
+
+	subnetID, err := ids.FromString(args.SubnetID)
+	if err != nil {
+		return fmt.Errorf("problem parsing subnetID '%s': %w", args.SubnetID, err)
+	}
+	if subnetID.Equals(constants.PrimaryNetworkID) {
+		return errRemovePrimaryNetworkValidator
+	}
+
+	// Get the keys controlled by the user
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user '%s': %w", args.Username, err)
+	}
+
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := user{db: db}
+	keys, err := user.getKeys()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newRemoveSubnetValidatorTx(nodeID, subnetID, uint64(args.Weight), keys)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	response.TxID = tx.ID()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		service.vm.issueTx(tx),
+		db.Close(),
+	)
+	return errs.Err
+}
+
+// BulkStakingResult is the result of adding one staker in a bulk staking
+// call. Exactly one of TxID and Error is set.
+type BulkStakingResult struct {
+	TxID  ids.ID `json:"txID"`
+	Error string `json:"error,omitempty"`
+	// IDs of the transactions issued earlier in the call whose outputs this
+	// transaction spends, directly or through other transactions in the call.
+	// If any of them is dropped, this transaction is dropped too.
+	DependsOn []ids.ID `json:"dependsOn,omitempty"`
+}
+
+// bulkStakingDependencies returns the IDs of the transactions in [results]
+// whose outputs [tx] spends, directly or through other transactions in
+// [results], in the order they were issued
+func bulkStakingDependencies(results []BulkStakingResult, tx *Tx) []ids.ID {
+	parents := ids.Set{}
+	consumed, _ := chainUTXOs(tx)
+	for _, utxoID := range consumed {
+		parents.Add(utxoID.TxID)
+	}
+
+	dependencies := ids.Set{}
+	for _, result := range results {
+		if result.Error != "" || !parents.Contains(result.TxID) {
+			continue
+		}
+		dependencies.Add(result.TxID)
+		dependencies.Add(result.DependsOn...)
+	}
+
+	dependsOn := []ids.ID(nil)
+	for _, result := range results {
+		if result.Error == "" && dependencies.Contains(result.TxID) {
+			dependsOn = append(dependsOn, result.TxID)
+		}
+	}
+	return dependsOn
+}
+
+// BulkStakingReply is the reply from AddDelegators and AddSubnetValidators.
+// Results[i] is the result of adding the i'th staker.
+type BulkStakingReply struct {
+	Results []BulkStakingResult `json:"results"`
+}
+
+// AddDelegatorsArgs are the arguments to AddDelegators
+type AddDelegatorsArgs struct {
+	api.UserPass
+	Delegators []AddDelegatorsEntry `json:"delegators"`
+}
+
+// AddDelegatorsEntry is a delegator to add in a call to AddDelegators
+type AddDelegatorsEntry struct {
+	APIStaker
+	RewardAddress string `json:"rewardAddress"`
+}
+
+// AddDelegators creates and signs and issues a transaction per delegator to
+// add to the primary network. Each transaction may spend the change of the
+// transactions issued before it, so each one only burns the transaction fee. A
+// delegator that can't be added doesn't prevent the others from being added,
+// but a transaction that is dropped takes the transactions that depend on it
+// with it.
+func (service *Service) AddDelegators(_ *http.Request, args *AddDelegatorsArgs, reply *BulkStakingReply) error {
+	service.vm.Ctx.Log.Info("Platform: AddDelegators called with %d delegators", len(args.Delegators))
+	switch {
+	case len(args.Delegators) == 0:
+		return errNoStakers
+	case len(args.Delegators) > maxBulkStakers:
+		return errTooManyStakers
+	}
+
+	// Get the keys controlled by the user
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user '%s': %w", args.Username, err)
+	}
+
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := user{db: db}
+	privKeys, err := user.getKeys()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// The UTXOs with the transactions issued so far applied
+	spendDB := versiondb.New(service.vm.DB)
+	defer spendDB.Abort()
+
+	reply.Results = make([]BulkStakingResult, len(args.Delegators))
+	for i, delegator := range args.Delegators {
+		tx, err := service.addDelegator(spendDB, &delegator, privKeys)
+		if err != nil {
+			reply.Results[i].Error = err.Error()
+			continue
+		}
+		reply.Results[i].TxID = tx.ID()
+		reply.Results[i].DependsOn = bulkStakingDependencies(reply.Results[:i], tx)
+	}
+	return db.Close()
+}
+
+// addDelegator issues a transaction adding [delegator], which spends the
+// UTXOs of [keys] in [spendDB]. The spent UTXOs are removed from [spendDB] and
+// the change is added to it.
+func (service *Service) addDelegator(
+	spendDB database.Database,
+	delegator *AddDelegatorsEntry,
+	keys []*crypto.PrivateKeySECP256K1R,
+) (*Tx, error) {
+	switch {
+	case uint64(delegator.StartTime) < service.vm.clock.Unix():
+		return nil, fmt.Errorf("start time must be in the future")
+	case delegator.RewardAddress == "":
+		return nil, errNoRewardAddress
+	}
+
+	nodeID := service.vm.Ctx.NodeID // If ID unspecified, use this node's ID as validator ID
+	if delegator.NodeID != "" {
+		nID, err := ids.ShortFromPrefixedString(delegator.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		nodeID = nID
+	}
+
+	rewardAddress, err := service.vm.ParseLocalAddress(delegator.RewardAddress)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing 'rewardAddress': %w", err)
+	}
+
+	tx, err := service.vm.buildAddDelegatorTx(
+		spendDB,                     // UTXOs to spend
+		delegator.weight(),          // Stake amount
+		uint64(delegator.StartTime), // Start time
+		uint64(delegator.EndTime),   // End time
+		nodeID,                      // Node ID
+		rewardAddress,               // Reward Address
+		keys,                        // Private keys
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create tx: %w", err)
+	}
+	if err := service.vm.issueTx(tx); err != nil {
+		return nil, fmt.Errorf("couldn't issue tx: %w", err)
+	}
+	utx := tx.UnsignedTx.(*UnsignedAddDelegatorTx)
+	if err := service.vm.consumeInputs(spendDB, utx.Ins); err != nil {
+		return nil, err
+	}
+	return tx, service.vm.produceOutputs(spendDB, tx.ID(), utx.Outs)
+}
+
+// AddSubnetValidatorsArgs are the arguments to AddSubnetValidators
+type AddSubnetValidatorsArgs struct {
+	api.UserPass
+	Validators []AddSubnetValidatorsEntry `json:"validators"`
+}
+
+// AddSubnetValidatorsEntry is a validator to add in a call to
+// AddSubnetValidators
+type AddSubnetValidatorsEntry struct {
+	APIStaker
+	// ID of subnet to validate
+	SubnetID string `json:"subnetID"`
+}
+
+// AddSubnetValidators creates and signs and issues a transaction per validator
+// to add to a subnet other than the primary network. Each transaction may spend
+// the change of the transactions issued before it, so each one only burns the
+// transaction fee. A validator that can't be added doesn't prevent the others
+// from being added, but a transaction that is dropped takes the transactions
+// that depend on it with it.
+func (service *Service) AddSubnetValidators(_ *http.Request, args *AddSubnetValidatorsArgs, reply *BulkStakingReply) error {
+	service.vm.Ctx.Log.Info("Platform: AddSubnetValidators called with %d validators", len(args.Validators))
+	switch {
+	case len(args.Validators) == 0:
+		return errNoStakers
+	case len(args.Validators) > maxBulkStakers:
+		return errTooManyStakers
+	}
+
+	// Get the keys controlled by the user
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user '%s': %w", args.Username, err)
+	}
+
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := user{db: db}
+	keys, err := user.getKeys()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// The UTXOs with the transactions issued so far applied
+	spendDB := versiondb.New(service.vm.DB)
+	defer spendDB.Abort()
+
+	reply.Results = make([]BulkStakingResult, len(args.Validators))
+	for i, validator := range args.Validators {
+		tx, err := service.addSubnetValidator(spendDB, &validator, keys)
+		if err != nil {
+			reply.Results[i].Error = err.Error()
+			continue
+		}
+		reply.Results[i].TxID = tx.ID()
+		reply.Results[i].DependsOn = bulkStakingDependencies(reply.Results[:i], tx)
+	}
+	return db.Close()
+}
+
+// addSubnetValidator issues a transaction adding [validator], which spends the
+// UTXOs of [keys] in [spendDB]. The spent UTXOs are removed from [spendDB] and
+// the change is added to it.
+func (service *Service) addSubnetValidator(
+	spendDB database.Database,
+	validator *AddSubnetValidatorsEntry,
+	keys []*crypto.PrivateKeySECP256K1R,
+) (*Tx, error) {
+	if validator.SubnetID == "" {
+		return nil, errNoSubnetID
+	}
+
+	nodeID, err := ids.ShortFromPrefixedString(validator.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nodeID: '%s': %w", validator.NodeID, err)
+	}
+
+	subnetID, err := ids.FromString(validator.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing subnetID '%s': %w", validator.SubnetID, err)
+	}
+	if subnetID.Equals(constants.PrimaryNetworkID) {
+		return nil, errors.New("subnet validator attempts to validate primary network")
+	}
+
+	tx, err := service.vm.buildAddSubnetValidatorTx(
+		spendDB,                     // UTXOs to spend
+		validator.weight(),          // Stake amount
+		uint64(validator.StartTime), // Start time
+		uint64(validator.EndTime),   // End time
+		nodeID,                      // Node ID
+		subnetID,                    // Subnet ID
+		keys,                        // Keys
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create tx: %w", err)
+	}
+	if err := service.vm.issueTx(tx); err != nil {
+		return nil, fmt.Errorf("couldn't issue tx: %w", err)
+	}
+	utx := tx.UnsignedTx.(*UnsignedAddSubnetValidatorTx)
+	if err := service.vm.consumeInputs(spendDB, utx.Ins); err != nil {
+		return nil, err
+	}
+	return tx, service.vm.produceOutputs(spendDB, tx.ID(), utx.Outs)
+}
+
 // CreateSubnetArgs are the arguments to CreateSubnet
 type CreateSubnetArgs struct {
 	// The ID member of APISubnet is ignored
@@ -1092,6 +1514,128 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	return errs.Err
 }
 
+// TransferSubnetOwnershipArgs are the arguments to TransferSubnetOwnership
+type TransferSubnetOwnershipArgs struct {
+	// The ID member of APISubnet is the subnet whose owner is replaced.
+	// ControlKeys and Threshold define the new owner.
+	APISubnet
+	api.UserPass
+}
+
+// TransferSubnetOwnership creates and signs and issues a transaction to
+// replace the owner of a subnet
+func (service *Service) TransferSubnetOwnership(_ *http.Request, args *TransferSubnetOwnershipArgs, response *api.JsonTxID) error {
+	service.vm.Ctx.Log.Info("Platform: TransferSubnetOwnership called")
+	switch {
+	case args.ID.IsZero():
+		return errNoSubnetID
+	case args.ID.Equals(constants.PrimaryNetworkID):
+		return errTransferPrimaryNetwork
+	}
+
+	controlKeys := []ids.ShortID{}
+	for _, controlKey := range args.ControlKeys {
+		controlKeyID, err := service.vm.ParseLocalAddress(controlKey)
+		if err != nil {
+			return fmt.Errorf("problem parsing control key '%s': %w", controlKey, err)
+		}
+		controlKeys = append(controlKeys, controlKeyID)
+	}
+
+	// Get the keys controlled by the user
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user '%s': %w", args.Username, err)
+	}
+
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := user{db: db}
+	privKeys, err := user.getKeys()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newTransferSubnetOwnershipTx(
+		args.ID,                // Subnet ID
+		uint32(args.Threshold), // Threshold
+		controlKeys,            // Control Addresses
+		privKeys,               // Private keys
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	response.TxID = tx.ID()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		service.vm.issueTx(tx),
+		db.Close(),
+	)
+	return errs.Err
+}
+
+// SetRestakeArgs are the arguments to SetRestake
+type SetRestakeArgs struct {
+	api.UserPass
+
+	// ID of the tx that added the validator or delegator
+	TxID ids.ID `json:"txID"`
+
+	// True if the staker should be renewed at the end of its staking period
+	Restake bool `json:"restake"`
+}
+
+// SetRestake creates and signs and issues a transaction to set whether a
+// validator or delegator of the primary network is renewed at the end of its
+// staking period. Opting in requires control of the owner of the staked
+// outputs; opting out requires control of the staker's rewards owner.
+func (service *Service) SetRestake(_ *http.Request, args *SetRestakeArgs, response *api.JsonTxID) error {
+	service.vm.Ctx.Log.Info("Platform: SetRestake called")
+	if args.TxID.IsZero() {
+		return errNoTxID
+	}
+
+	// Get the keys controlled by the user
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user '%s': %w", args.Username, err)
+	}
+
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := user{db: db}
+	privKeys, err := user.getKeys()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newSetRestakeTx(
+		args.TxID,    // Staker tx ID
+		args.Restake, // Restaking preference
+		privKeys,     // Private keys
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	response.TxID = tx.ID()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		service.vm.issueTx(tx),
+		db.Close(),
+	)
+	return errs.Err
+}
+
 // ExportDJTXArgs are the arguments to ExportDJTX
 type ExportDJTXArgs struct {
 	api.UserPass
@@ -1506,6 +2050,11 @@ type GetTxArgs struct {
 type GetTxResponse struct {
 	// Raw byte representation of the transaction
 	Tx formatting.CB58 `json:"tx"`
+	// ID of the block containing the transaction
+	BlockID *ids.ID `json:"blockID,omitempty"`
+	// Unix time of the chain when the block containing the transaction was
+	// accepted
+	AcceptTime *json.Uint64 `json:"acceptTime,omitempty"`
 }
 
 // GetTx gets a tx
@@ -1516,6 +2065,132 @@ func (service *Service) GetTx(_ *http.Request, args *GetTxArgs, response *GetTxR
 		return fmt.Errorf("couldn't get tx: %w", err)
 	}
 	response.Tx.Bytes = txBytes
+
+	// Transactions in the genesis aren't included in a block
+	blkID, err := service.vm.getTxBlockID(service.vm.DB, args.TxID)
+	if err == database.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("couldn't get block of tx: %w", err)
+	}
+	response.BlockID = &blkID
+	if acceptTime, err := service.vm.getBlockTime(service.vm.DB, blkID); err == nil {
+		unixTime := json.Uint64(acceptTime.Unix())
+		response.AcceptTime = &unixTime
+	} else if err != database.ErrNotFound {
+		return fmt.Errorf("couldn't get accept time of block: %w", err)
+	}
+	return nil
+}
+
+// GetBlockArgs are the arguments to GetBlock.
+// Exactly one of [BlockID] and [Height] must be provided.
+type GetBlockArgs struct {
+	BlockID ids.ID       `json:"blockID"`
+	Height  *json.Uint64 `json:"height"`
+}
+
+// GetBlockByHeightArgs are the arguments to GetBlockByHeight
+type GetBlockByHeightArgs struct {
+	Height json.Uint64 `json:"height"`
+}
+
+// APIBlock is the representation of a block used in API calls
+type APIBlock struct {
+	ID       ids.ID         `json:"id"`
+	ParentID ids.ID         `json:"parentID"`
+	Height   json.Uint64    `json:"height"`
+	Status   choices.Status `json:"status"`
+	// One of "proposal", "commit", "abort", "standard" or "atomic"
+	Type string `json:"type"`
+	// Transactions in the block
+	Txs []*Tx `json:"txs"`
+	// Raw byte representation of the block
+	Bytes formatting.CB58 `json:"bytes"`
+	// Unix time of the chain when the block was accepted, if it was accepted
+	AcceptTime *json.Uint64 `json:"acceptTime,omitempty"`
+}
+
+// GetBlockResponse is the response from calling GetBlock
+type GetBlockResponse struct {
+	Block APIBlock `json:"block"`
+}
+
+// GetBlock returns the block with the given ID, or the accepted block at the
+// given height
+func (service *Service) GetBlock(_ *http.Request, args *GetBlockArgs, response *GetBlockResponse) error {
+	service.vm.Ctx.Log.Info("Platform: GetBlock called")
+
+	blkID := args.BlockID
+	switch {
+	case !blkID.IsZero() && args.Height != nil:
+		return errBlockIDAndHeight
+	case args.Height != nil:
+		id, err := service.vm.getAcceptedBlockID(service.vm.DB, uint64(*args.Height))
+		if err != nil {
+			return fmt.Errorf("couldn't get block at height %d: %w", *args.Height, err)
+		}
+		blkID = id
+	case blkID.IsZero():
+		return errNoBlockIDOrHeight
+	}
+	return service.getBlock(blkID, &response.Block)
+}
+
+// GetBlockByHeight returns the accepted block at the given height
+func (service *Service) GetBlockByHeight(_ *http.Request, args *GetBlockByHeightArgs, response *GetBlockResponse) error {
+	service.vm.Ctx.Log.Info("Platform: GetBlockByHeight called")
+
+	blkID, err := service.vm.getAcceptedBlockID(service.vm.DB, uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("couldn't get block at height %d: %w", args.Height, err)
+	}
+	return service.getBlock(blkID, &response.Block)
+}
+
+// getBlock populates [reply] with the block [blkID]
+func (service *Service) getBlock(blkID ids.ID, reply *APIBlock) error {
+	blk, err := service.vm.getBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+
+	reply.ID = blk.ID()
+	reply.ParentID = blk.Parent().ID()
+	reply.Height = json.Uint64(blk.Height())
+	reply.Status = blk.Status()
+	reply.Bytes.Bytes = blk.Bytes()
+	switch blk := blk.(type) {
+	case *ProposalBlock:
+		reply.Type = "proposal"
+		reply.Txs = []*Tx{&blk.Tx}
+	case *Commit:
+		reply.Type = "commit"
+		reply.Txs = []*Tx{}
+	case *Abort:
+		reply.Type = "abort"
+		reply.Txs = []*Tx{}
+	case *StandardBlock:
+		reply.Type = "standard"
+		reply.Txs = blk.Txs
+	case *AtomicBlock:
+		reply.Type = "atomic"
+		reply.Txs = []*Tx{&blk.Tx}
+	default:
+		return fmt.Errorf("unexpected block type %T", blk)
+	}
+
+	if reply.Status != choices.Accepted {
+		return nil
+	}
+	acceptTime, err := service.vm.getBlockTime(service.vm.DB, blkID)
+	switch {
+	case err == nil:
+		unixTime := json.Uint64(acceptTime.Unix())
+		reply.AcceptTime = &unixTime
+	case err != database.ErrNotFound:
+		return fmt.Errorf("couldn't get accept time of block %s: %w", blkID, err)
+	}
 	return nil
 }
 
@@ -1546,7 +2221,9 @@ func (service *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, resp
 			return nil
 		}
 	}
-	if _, ok := service.vm.droppedTxCache.Get(args.TxID); ok {
+	if service.vm.mempool.Has(args.TxID) {
+		*response = Processing // The tx is waiting in the mempool to be put into a block
+	} else if _, ok := service.vm.droppedTxCache.Get(args.TxID); ok {
 		*response = Dropped
 	} else {
 		*response = Unknown
@@ -1554,6 +2231,59 @@ func (service *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, resp
 	return nil
 }
 
+// APIMempoolTx is the representation of a mempool transaction used in API
+// calls
+type APIMempoolTx struct {
+	// ID of the transaction
+	TxID ids.ID `json:"txID"`
+
+	// Kind of block the transaction will be put into.
+	// One of "proposal", "decision" or "atomic"
+	Type string `json:"type"`
+
+	// Size of the transaction, in bytes
+	Size json.Uint64 `json:"size"`
+}
+
+// GetMempoolReply is the response from calling GetMempool
+type GetMempoolReply struct {
+	// Number of transactions in the mempool
+	NumTxs json.Uint64 `json:"numTxs"`
+
+	// Total size, in bytes, of the transactions in the mempool
+	Bytes json.Uint64 `json:"bytes"`
+
+	// Transactions in the mempool
+	Txs []APIMempoolTx `json:"txs"`
+}
+
+// GetMempool returns the transactions that are waiting to be put into a block
+func (service *Service) GetMempool(_ *http.Request, _ *struct{}, reply *GetMempoolReply) error {
+	service.vm.Ctx.Log.Info("Platform: GetMempool called")
+
+	txs := service.vm.mempool.Txs()
+	reply.NumTxs = json.Uint64(len(txs))
+	reply.Bytes = json.Uint64(service.vm.mempool.Bytes())
+	reply.Txs = make([]APIMempoolTx, len(txs))
+	for i, tx := range txs {
+		txType := ""
+		switch tx.UnsignedTx.(type) {
+		case UnsignedProposalTx:
+			txType = "proposal"
+		case UnsignedDecisionTx:
+			txType = "decision"
+		case UnsignedAtomicTx:
+			txType = "atomic"
+		}
+		reply.Txs[i] = APIMempoolTx{
+			TxID: tx.ID(),
+			Type: txType,
+			Size: json.Uint64(len(tx.Bytes())),
+		}
+	}
+	return nil
+}
+
 // GetStakeReply is the response from calling GetStake.
 type GetStakeReply struct {
 	Staked json.Uint64 `json:"staked"`
@@ -1698,6 +2428,160 @@ type GetMinStakeReply struct {
 
 // GetMinStake returns the minimum staking amount in nDJTX.
 func (service *Service) GetMinStake(_ *http.Request, _ *struct{}, reply *GetMinStakeReply) error {
-	reply.MinStake = json.Uint64(service.vm.minStake)
+	reply.MinStake = json.Uint64(service.vm.stakingParams.MinValidatorStake)
+	return nil
+}
+
+// GetStakingParameters returns the economic parameters of staking on the
+// primary network, as defined in the genesis
+func (service *Service) GetStakingParameters(_ *http.Request, _ *struct{}, reply *APIStakingParameters) error {
+	service.vm.Ctx.Log.Info("Platform: GetStakingParameters called")
+
+	*reply = NewAPIStakingParameters(service.vm.stakingParams)
+	return nil
+}
+
+// GetRewardHistoryArgs are the arguments for calling GetRewardHistory.
+// Exactly one of [NodeID] and [RewardAddress] must be provided.
+type GetRewardHistoryArgs struct {
+	// Node whose validation and delegation periods are fetched
+	NodeID string `json:"nodeID"`
+	// Address whose validation and delegation periods are fetched
+	RewardAddress string `json:"rewardAddress"`
+	// Max number of periods to fetch
+	Limit json.Uint32 `json:"limit"`
+	// If provided, only fetches the periods that ended after this one
+	StartIndex string `json:"startIndex"`
+}
+
+// APIStakingPeriod is a completed validation or delegation period of the
+// primary network
+type APIStakingPeriod struct {
+	APIStaker
+	TxID        ids.ID `json:"txID"`
+	IsDelegator bool   `json:"isDelegator"`
+	// Uptime of the validator, as observed by this node. Omitted if this node
+	// didn't observe the staking period.
+	Uptime          *json.Float32 `json:"uptime,omitempty"`
+	PotentialReward json.Uint64   `json:"potentialReward"`
+	Rewarded        bool          `json:"rewarded"`
+	// Why the staker wasn't rewarded, if it wasn't
+	Reason             string      `json:"reason,omitempty"`
+	Reward             json.Uint64 `json:"reward"`
+	RewardUTXOs        []string    `json:"rewardUTXOs"`
+	DelegationFee      json.Uint64 `json:"delegationFee"`
+	DelegationFeeUTXOs []string    `json:"delegationFeeUTXOs"`
+	RewardAddresses    []string    `json:"rewardAddresses"`
+}
+
+// GetRewardHistoryReply is the response from calling GetRewardHistory
+type GetRewardHistoryReply struct {
+	// Number of periods returned
+	NumFetched json.Uint64 `json:"numFetched"`
+	// The periods, ordered by their end time
+	Periods []APIStakingPeriod `json:"periods"`
+	// The ID of the last period returned. Used for pagination. To get the
+	// rest of the periods, call GetRewardHistory again and set [StartIndex]
+	// to this value.
+	EndIndex string `json:"endIndex"`
+}
+
+// GetRewardHistory returns the completed staking periods of a node, or of the
+// stakers rewarding an address, along with the rewards they were issued
+func (service *Service) GetRewardHistory(_ *http.Request, args *GetRewardHistoryArgs, reply *GetRewardHistoryReply) error {
+	service.vm.Ctx.Log.Info("Platform: GetRewardHistory called")
+
+	startTxID := ids.Empty
+	if args.StartIndex != "" {
+		txID, err := ids.FromString(args.StartIndex)
+		if err != nil {
+			return fmt.Errorf("couldn't parse start index %q: %w", args.StartIndex, err)
+		}
+		startTxID = txID
+	}
+
+	var periods []*stakingPeriod
+	switch {
+	case args.NodeID != "" && args.RewardAddress != "":
+		return errNodeIDAndRewardAddress
+	case args.NodeID != "":
+		nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return fmt.Errorf("couldn't parse nodeID %q: %w", args.NodeID, err)
+		}
+		periods, err = service.vm.getNodeRewardHistory(service.vm.DB, nodeID, startTxID, int(args.Limit))
+		if err != nil {
+			return fmt.Errorf("problem retrieving reward history: %w", err)
+		}
+	case args.RewardAddress != "":
+		addr, err := service.vm.ParseLocalAddress(args.RewardAddress)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", args.RewardAddress, err)
+		}
+		periods, err = service.vm.getAddrRewardHistory(service.vm.DB, addr, startTxID, int(args.Limit))
+		if err != nil {
+			return fmt.Errorf("problem retrieving reward history: %w", err)
+		}
+	default:
+		return errNoNodeIDOrRewardAddress
+	}
+
+	reply.Periods = make([]APIStakingPeriod, len(periods))
+	for i, period := range periods {
+		stakeAmount := json.Uint64(period.StakeAmount)
+		apiPeriod := APIStakingPeriod{
+			APIStaker: APIStaker{
+				StartTime:   json.Uint64(period.StartTime),
+				EndTime:     json.Uint64(period.EndTime),
+				StakeAmount: &stakeAmount,
+				NodeID:      period.NodeID.PrefixedString(constants.NodeIDPrefix),
+			},
+			TxID:               period.TxID,
+			IsDelegator:        period.IsDelegator,
+			PotentialReward:    json.Uint64(period.PotentialReward),
+			Rewarded:           period.Rewarded,
+			Reward:             json.Uint64(period.Reward),
+			RewardUTXOs:        make([]string, len(period.RewardUTXOs)),
+			DelegationFee:      json.Uint64(period.DelegationFee),
+			DelegationFeeUTXOs: make([]string, len(period.DelegationFeeUTXOs)),
+			RewardAddresses:    make([]string, len(period.RewardAddresses)),
+		}
+		uptime, observed, err := service.vm.getStakingPeriodUptime(service.vm.DB, period.TxID)
+		if err != nil {
+			return fmt.Errorf("problem retrieving uptime: %w", err)
+		}
+		if observed {
+			apiUptime := json.Float32(float32(uptime) / float32(PercentDenominator))
+			apiPeriod.Uptime = &apiUptime
+		}
+		if !period.Rewarded {
+			if observed && float64(uptime) < service.vm.uptimePercentage*PercentDenominator {
+				apiPeriod.Reason = rewardWithheldInsufficientUptime
+			} else {
+				apiPeriod.Reason = rewardWithheldByNetwork
+			}
+		}
+		for j, utxoID := range period.RewardUTXOs {
+			apiPeriod.RewardUTXOs[j] = utxoID.InputID().String()
+		}
+		for j, utxoID := range period.DelegationFeeUTXOs {
+			apiPeriod.DelegationFeeUTXOs[j] = utxoID.InputID().String()
+		}
+		for j, addr := range period.RewardAddresses {
+			addrStr, err := service.vm.FormatLocalAddress(addr)
+			if err != nil {
+				return fmt.Errorf("problem formatting address: %w", err)
+			}
+			apiPeriod.RewardAddresses[j] = addrStr
+		}
+		reply.Periods[i] = apiPeriod
+	}
+
+	reply.NumFetched = json.Uint64(len(periods))
+	if len(periods) > 0 {
+		reply.EndIndex = periods[len(periods)-1].TxID.String()
+	} else {
+		reply.EndIndex = args.StartIndex
+	}
 	return nil
 }
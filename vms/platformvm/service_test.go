@@ -10,13 +10,18 @@ import (
 
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -241,7 +246,7 @@ func TestGetTx(t *testing.T) {
 			"proposal block",
 			func() (*Tx, error) {
 				return service.vm.newAddValidatorTx( // Test GetTx works for proposal blocks
-					service.vm.minStake,
+					service.vm.stakingParams.MinValidatorStake,
 					uint64(service.vm.clock.Time().Add(Delta).Unix()),
 					uint64(service.vm.clock.Time().Add(Delta).Add(MinimumStakingDuration).Unix()),
 					ids.GenerateTestShortID(),
@@ -295,10 +300,369 @@ func TestGetTx(t *testing.T) {
 			t.Fatalf("failed test '%s': %s", test.description, err)
 		} else if !bytes.Equal(response.Tx.Bytes, tx.Bytes()) {
 			t.Fatalf("failed test '%s': byte representation of tx in response is incorrect", test.description)
+		} else if response.BlockID == nil || !response.BlockID.Equals(block.ID()) {
+			t.Fatalf("failed test '%s': block of tx in response should be %s but is %v", test.description, block.ID(), response.BlockID)
+		} else if response.AcceptTime == nil {
+			t.Fatalf("failed test '%s': accept time of tx should be in response", test.description)
 		}
 	}
 }
 
+// Test fetching blocks by ID and by height
+func TestGetBlock(t *testing.T) {
+	service := defaultService(t)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	parentID := service.vm.LastAccepted()
+	service.vm.SetPreference(parentID)
+	parent, err := service.vm.getBlock(parentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	height := cjson.Uint64(parent.Height() + 1)
+
+	tx, err := service.vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := service.vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := service.vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The block isn't indexed by height until it's accepted
+	response := GetBlockResponse{}
+	if err := service.GetBlock(nil, &GetBlockArgs{Height: &height}, &response); err == nil {
+		t.Fatalf("should have errored because no block has been accepted at height %d", height)
+	} else if err := service.GetBlock(nil, &GetBlockArgs{BlockID: blk.ID()}, &response); err != nil {
+		t.Fatal(err)
+	} else if response.Block.Status != choices.Processing {
+		t.Fatalf("block should be processing but is %s", response.Block.Status)
+	} else if response.Block.AcceptTime != nil {
+		t.Fatal("processing block shouldn't have an accept time")
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range []*GetBlockArgs{{BlockID: blk.ID()}, {Height: &height}} {
+		response := GetBlockResponse{}
+		if err := service.GetBlock(nil, args, &response); err != nil {
+			t.Fatal(err)
+		} else if !response.Block.ID.Equals(blk.ID()) {
+			t.Fatalf("block should be %s but is %s", blk.ID(), response.Block.ID)
+		} else if !response.Block.ParentID.Equals(parentID) {
+			t.Fatalf("parent should be %s but is %s", parentID, response.Block.ParentID)
+		} else if response.Block.Height != height {
+			t.Fatalf("height should be %d but is %d", height, response.Block.Height)
+		} else if response.Block.Type != "standard" {
+			t.Fatalf("type should be standard but is %s", response.Block.Type)
+		} else if response.Block.Status != choices.Accepted {
+			t.Fatalf("block should be accepted but is %s", response.Block.Status)
+		} else if response.Block.AcceptTime == nil || *response.Block.AcceptTime != cjson.Uint64(defaultGenesisTime.Unix()) {
+			t.Fatalf("accept time should be the chain's timestamp %d but is %v", defaultGenesisTime.Unix(), response.Block.AcceptTime)
+		} else if len(response.Block.Txs) != 1 || !response.Block.Txs[0].ID().Equals(tx.ID()) {
+			t.Fatalf("block should contain tx %s", tx.ID())
+		} else if !bytes.Equal(response.Block.Bytes.Bytes, blk.Bytes()) {
+			t.Fatal("byte representation of block in response is incorrect")
+		} else if _, err := json.Marshal(&response); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	response = GetBlockResponse{}
+	genesisHeight := cjson.Uint64(0)
+	if err := service.GetBlock(nil, &GetBlockArgs{Height: &genesisHeight}, &response); err != nil {
+		t.Fatal(err)
+	} else if response.Block.Height != 0 {
+		t.Fatalf("height should be 0 but is %d", response.Block.Height)
+	} else if response.Block.Type != "commit" {
+		t.Fatalf("genesis block should be a commit block but is %s", response.Block.Type)
+	}
+
+	if err := service.GetBlock(nil, &GetBlockArgs{}, &GetBlockResponse{}); err != errNoBlockIDOrHeight {
+		t.Fatalf("expected %q but got %v", errNoBlockIDOrHeight, err)
+	} else if err := service.GetBlock(nil, &GetBlockArgs{BlockID: blk.ID(), Height: &height}, &GetBlockResponse{}); err != errBlockIDAndHeight {
+		t.Fatalf("expected %q but got %v", errBlockIDAndHeight, err)
+	}
+}
+
+// Test fetching accepted blocks by height
+func TestGetBlockByHeight(t *testing.T) {
+	service := defaultService(t)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	service.vm.SetPreference(service.vm.LastAccepted())
+	parent, err := service.vm.getBlock(service.vm.LastAccepted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	height := cjson.Uint64(parent.Height() + 1)
+
+	tx, err := service.vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := service.vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := service.vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The block isn't indexed by height until it's accepted
+	if err := service.GetBlockByHeight(nil, &GetBlockByHeightArgs{Height: height}, &GetBlockResponse{}); err == nil {
+		t.Fatalf("should have errored because no block has been accepted at height %d", height)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	response := GetBlockResponse{}
+	if err := service.GetBlockByHeight(nil, &GetBlockByHeightArgs{Height: height}, &response); err != nil {
+		t.Fatal(err)
+	} else if !response.Block.ID.Equals(blk.ID()) {
+		t.Fatalf("block should be %s but is %s", blk.ID(), response.Block.ID)
+	} else if response.Block.Height != height {
+		t.Fatalf("height should be %d but is %d", height, response.Block.Height)
+	} else if response.Block.Status != choices.Accepted {
+		t.Fatalf("block should be accepted but is %s", response.Block.Status)
+	} else if response.Block.AcceptTime == nil {
+		t.Fatal("accepted block should have an accept time")
+	}
+
+	// The response should match the one for the block's ID
+	byID := GetBlockResponse{}
+	if err := service.GetBlock(nil, &GetBlockArgs{BlockID: blk.ID()}, &byID); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(byID.Block.Bytes.Bytes, response.Block.Bytes.Bytes) {
+		t.Fatal("block fetched by height should match the block fetched by ID")
+	}
+
+	response = GetBlockResponse{}
+	if err := service.GetBlockByHeight(nil, &GetBlockByHeightArgs{}, &response); err != nil {
+		t.Fatal(err)
+	} else if response.Block.Height != 0 {
+		t.Fatalf("height should be 0 but is %d", response.Block.Height)
+	} else if response.Block.Type != "commit" {
+		t.Fatalf("genesis block should be a commit block but is %s", response.Block.Type)
+	}
+}
+
+// Test that blocks accepted before blocks were indexed by height are indexed
+// when the VM starts
+func TestIndexAcceptedBlocks(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// Remember the accepted blocks
+	expectedIDs := []ids.ID{}
+	for blkID := vm.LastAccepted(); ; {
+		blk, err := vm.getBlock(blkID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedIDs = append([]ids.ID{blkID}, expectedIDs...)
+		if blk.Height() == 0 {
+			break
+		}
+		blkID = blk.Parent().ID()
+	}
+
+	vm.SetPreference(vm.LastAccepted())
+	tx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedIDs = append(expectedIDs, blk.ID())
+
+	// Remove the height index
+	heightDB := prefixdb.NewNested([]byte(blockHeightDBPrefix), vm.DB)
+	for height := range expectedIDs {
+		p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+		p.PackLong(uint64(height))
+		if err := heightDB.Delete(p.Bytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := vm.getAcceptedBlockID(vm.DB, 0); err != database.ErrNotFound {
+		t.Fatalf("expected %q but got %v", database.ErrNotFound, err)
+	}
+
+	if err := vm.indexAcceptedBlocks(blk.(Block)); err != nil {
+		t.Fatal(err)
+	}
+	for height, expectedID := range expectedIDs {
+		if blkID, err := vm.getAcceptedBlockID(vm.DB, uint64(height)); err != nil {
+			t.Fatal(err)
+		} else if !blkID.Equals(expectedID) {
+			t.Fatalf("block at height %d should be %s but is %s", height, expectedID, blkID)
+		}
+	}
+}
+
+// Test that the timestamps and txs of blocks accepted before they were indexed
+// are indexed when the VM starts
+func TestIndexBlocks(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.SetPreference(vm.LastAccepted())
+
+	// Advance the chain's timestamp
+	newTimestamp := defaultGenesisTime.Add(time.Second)
+	advanceTimeTx, err := vm.newAdvanceTimeTx(newTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preferredHeight, err := vm.preferredHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposalBlk, err := vm.newProposalBlock(vm.Preferred(), preferredHeight+1, *advanceTimeTx)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.State.PutBlock(vm.DB, proposalBlk); err != nil {
+		t.Fatal(err)
+	}
+	vm.clock.Set(newTimestamp)
+	if err := proposalBlk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	options, err := proposalBlk.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, ok := options[0].(*Commit)
+	if !ok {
+		t.Fatal("should prefer to commit")
+	} else if err := commit.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := proposalBlk.Accept(); err != nil {
+		t.Fatal(err)
+	} else if err := commit.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	vm.SetPreference(vm.LastAccepted())
+
+	// Accept a block executed at the new timestamp
+	createChainTx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.issueTx(createChainTx); err != nil {
+		t.Fatal(err)
+	}
+	standardBlk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := standardBlk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := standardBlk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTimes := map[[32]byte]time.Time{
+		proposalBlk.ID().Key(): defaultGenesisTime,
+		commit.ID().Key():      defaultGenesisTime,
+		standardBlk.ID().Key(): newTimestamp,
+	}
+	expectedTxBlocks := map[[32]byte]ids.ID{
+		advanceTimeTx.ID().Key(): proposalBlk.ID(),
+		createChainTx.ID().Key(): standardBlk.ID(),
+	}
+	checkIndices := func() {
+		for blkKey, expectedTime := range expectedTimes {
+			blkID := ids.NewID(blkKey)
+			if timestamp, err := vm.getBlockTime(vm.DB, blkID); err != nil {
+				t.Fatal(err)
+			} else if !timestamp.Equal(expectedTime) {
+				t.Fatalf("timestamp of block %s should be %s but is %s", blkID, expectedTime, timestamp)
+			}
+		}
+		for txKey, expectedBlkID := range expectedTxBlocks {
+			txID := ids.NewID(txKey)
+			if blkID, err := vm.getTxBlockID(vm.DB, txID); err != nil {
+				t.Fatal(err)
+			} else if !blkID.Equals(expectedBlkID) {
+				t.Fatalf("block of tx %s should be %s but is %s", txID, expectedBlkID, blkID)
+			}
+		}
+	}
+	checkIndices()
+
+	// Remove the indices
+	for _, prefix := range []string{blockTimeDBPrefix, txBlockDBPrefix, blocksIndexedDBPrefix} {
+		prefixDB := prefixdb.NewNested([]byte(prefix), vm.DB)
+		iter := prefixDB.NewIterator()
+		for iter.Next() {
+			if err := prefixDB.Delete(iter.Key()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		iter.Release()
+	}
+	if _, err := vm.getBlockTime(vm.DB, standardBlk.ID()); err != database.ErrNotFound {
+		t.Fatalf("expected %q but got %v", database.ErrNotFound, err)
+	}
+
+	if err := vm.indexBlocks(vm.DB, standardBlk.(Block).Height(), defaultGenesisTime); err != nil {
+		t.Fatal(err)
+	}
+	checkIndices()
+}
+
 // Test method GetStake
 func TestGetStake(t *testing.T) {
 	service := defaultService(t)
@@ -445,3 +809,318 @@ func TestGetStake(t *testing.T) {
 		t.Fatalf("expected stake to be %d but is %d", uint64(oldStake)+stakeAmt, response.Staked)
 	}
 }
+
+func TestAddSubnetValidators(t *testing.T) {
+	service := defaultService(t)
+	defaultAddress(t, service)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	// The user controls a threshold of testSubnet1's control keys
+	userDB, err := service.vm.Ctx.Keystore.GetDatabase(testUsername, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&user{db: userDB}).putAddress(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	weight := cjson.Uint64(defaultWeight)
+	newEntry := func(nodeID ids.ShortID, subnetID string) AddSubnetValidatorsEntry {
+		return AddSubnetValidatorsEntry{
+			APIStaker: APIStaker{
+				StartTime: cjson.Uint64(startTime.Unix()),
+				EndTime:   cjson.Uint64(endTime.Unix()),
+				Weight:    &weight,
+				NodeID:    nodeID.PrefixedString(constants.NodeIDPrefix),
+			},
+			SubnetID: subnetID,
+		}
+	}
+	args := AddSubnetValidatorsArgs{
+		UserPass: api.UserPass{
+			Username: testUsername,
+			Password: testPassword,
+		},
+		Validators: []AddSubnetValidatorsEntry{
+			newEntry(keys[0].PublicKey().Address(), testSubnet1.ID().String()),
+			newEntry(keys[1].PublicKey().Address(), ""),
+			newEntry(keys[1].PublicKey().Address(), testSubnet1.ID().String()),
+		},
+	}
+	reply := BulkStakingReply{}
+	if err := service.AddSubnetValidators(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Results) != len(args.Validators) {
+		t.Fatalf("expected %d results but got %d", len(args.Validators), len(reply.Results))
+	}
+
+	// The entry without a subnet ID fails without affecting the others
+	if reply.Results[1].Error == "" || !reply.Results[1].TxID.IsZero() {
+		t.Fatalf("expected an error but got %+v", reply.Results[1])
+	}
+
+	// The other txs are issued, and don't spend the same UTXOs
+	spent := ids.Set{}
+	for _, i := range []int{0, 2} {
+		result := reply.Results[i]
+		if result.Error != "" {
+			t.Fatalf("unexpected error adding validator %d: %s", i, result.Error)
+		}
+		if !service.vm.mempool.Has(result.TxID) {
+			t.Fatalf("tx %s should be in the mempool", result.TxID)
+		}
+		for _, tx := range service.vm.mempool.Txs() {
+			if !tx.ID().Equals(result.TxID) {
+				continue
+			}
+			for _, in := range tx.UnsignedTx.(*UnsignedAddSubnetValidatorTx).Ins {
+				if inputID := in.InputID(); spent.Contains(inputID) {
+					t.Fatalf("UTXO %s is spent twice", inputID)
+				} else {
+					spent.Add(inputID)
+				}
+			}
+		}
+	}
+	if spent.Len() == 0 {
+		t.Fatal("txs should spend UTXOs")
+	}
+
+	// Too many or no stakers can't be added
+	args.Validators = nil
+	if err := service.AddSubnetValidators(nil, &args, &reply); err != errNoStakers {
+		t.Fatalf("expected %q but got %v", errNoStakers, err)
+	}
+	args.Validators = make([]AddSubnetValidatorsEntry, maxBulkStakers+1)
+	if err := service.AddSubnetValidators(nil, &args, &reply); err != errTooManyStakers {
+		t.Fatalf("expected %q but got %v", errTooManyStakers, err)
+	}
+}
+
+func TestAddDelegatorsErrors(t *testing.T) {
+	service := defaultService(t)
+	defaultAddress(t, service)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	stakeAmt := cjson.Uint64(service.vm.stakingParams.MinDelegatorStake)
+	futureStart := time.Now().Add(time.Hour)
+	args := AddDelegatorsArgs{
+		UserPass: api.UserPass{
+			Username: testUsername,
+			Password: testPassword,
+		},
+		Delegators: []AddDelegatorsEntry{
+			{ // starts in the past
+				APIStaker: APIStaker{
+					StartTime:   cjson.Uint64(defaultGenesisTime.Add(-time.Second).Unix()),
+					EndTime:     cjson.Uint64(defaultValidateEndTime.Unix()),
+					StakeAmount: &stakeAmt,
+				},
+				RewardAddress: testAddress,
+			},
+			{ // no reward address
+				APIStaker: APIStaker{
+					StartTime:   cjson.Uint64(futureStart.Unix()),
+					EndTime:     cjson.Uint64(futureStart.Add(MinimumStakingDuration).Unix()),
+					StakeAmount: &stakeAmt,
+				},
+			},
+		},
+	}
+	reply := BulkStakingReply{}
+	if err := service.AddDelegators(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Results) != len(args.Delegators) {
+		t.Fatalf("expected %d results but got %d", len(args.Delegators), len(reply.Results))
+	}
+	for i, result := range reply.Results {
+		if result.Error == "" {
+			t.Fatalf("expected delegator %d to fail", i)
+		}
+	}
+	if reply.Results[1].Error != errNoRewardAddress.Error() {
+		t.Fatalf("expected %q but got %q", errNoRewardAddress, reply.Results[1].Error)
+	}
+}
+
+func TestAddDelegatorsReportsDependencies(t *testing.T) {
+	service := defaultService(t)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	// The user only controls keys[1], which the default VM doesn't spend from
+	userDB, err := service.vm.Ctx.Keystore.GetDatabase(testUsername, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&user{db: userDB}).putAddress(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The genesis validators can only be delegated a few times their weight
+	service.vm.stakingParams.MinDelegatorStake = defaultWeight
+
+	// The user controls a single UTXO, so each delegator spends the change of
+	// the previous one. The first delegator starts first.
+	numDelegators := 3
+	stakeAmt := cjson.Uint64(defaultWeight)
+	nodeID := keys[0].PublicKey().Address()
+	args := AddDelegatorsArgs{
+		UserPass: api.UserPass{
+			Username: testUsername,
+			Password: testPassword,
+		},
+	}
+	for i := 0; i < numDelegators; i++ {
+		startTime := defaultGenesisTime.Add(Delta).Add(time.Second).Add(time.Duration(i) * time.Minute)
+		args.Delegators = append(args.Delegators, AddDelegatorsEntry{
+			APIStaker: APIStaker{
+				NodeID:      nodeID.PrefixedString(constants.NodeIDPrefix),
+				StartTime:   cjson.Uint64(startTime.Unix()),
+				EndTime:     cjson.Uint64(startTime.Add(MinimumStakingDuration).Unix()),
+				StakeAmount: &stakeAmt,
+			},
+			RewardAddress: testAddress,
+		})
+	}
+	reply := BulkStakingReply{}
+	if err := service.AddDelegators(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Results) != numDelegators {
+		t.Fatalf("expected %d results but got %d", numDelegators, len(reply.Results))
+	}
+	for i, result := range reply.Results {
+		if result.Error != "" {
+			t.Fatalf("expected delegator %d to be added but got %q", i, result.Error)
+		}
+		if len(result.DependsOn) != i {
+			t.Fatalf("expected delegator %d to depend on %d txs but it depends on %d", i, i, len(result.DependsOn))
+		}
+		for j, txID := range result.DependsOn {
+			if !txID.Equals(reply.Results[j].TxID) {
+				t.Fatalf("expected delegator %d to depend on %s but it depends on %s", i, reply.Results[j].TxID, txID)
+			}
+		}
+	}
+
+	// Once the first delegator can no longer be added, the delegators that
+	// depend on it are dropped too
+	service.vm.clock.Set(defaultGenesisTime.Add(2 * time.Second))
+	service.vm.resetTimer()
+	for i, result := range reply.Results {
+		status := Unknown
+		if err := service.GetTxStatus(nil, &GetTxStatusArgs{TxID: result.TxID}, &status); err != nil {
+			t.Fatal(err)
+		} else if status != Dropped {
+			t.Fatalf("expected delegator %d to be dropped but its status is %s", i, status)
+		}
+	}
+}
+
+func TestAddDelegatorsChainsChange(t *testing.T) {
+	service := defaultService(t)
+	defaultAddress(t, service)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	// The genesis validators can only be delegated a few times their weight
+	service.vm.stakingParams.MinDelegatorStake = defaultWeight
+
+	// The user controls fewer UTXOs than there are delegators, so some
+	// delegators must be paid for with the change of the others. The later
+	// delegators start first.
+	numDelegators := 3
+	stakeAmt := cjson.Uint64(defaultWeight)
+	nodeID := keys[0].PublicKey().Address()
+	args := AddDelegatorsArgs{
+		UserPass: api.UserPass{
+			Username: testUsername,
+			Password: testPassword,
+		},
+	}
+	for i := 0; i < numDelegators; i++ {
+		startTime := defaultGenesisTime.Add(Delta).Add(time.Duration(numDelegators-i) * time.Minute)
+		args.Delegators = append(args.Delegators, AddDelegatorsEntry{
+			APIStaker: APIStaker{
+				NodeID:      nodeID.PrefixedString(constants.NodeIDPrefix),
+				StartTime:   cjson.Uint64(startTime.Unix()),
+				EndTime:     cjson.Uint64(startTime.Add(MinimumStakingDuration).Unix()),
+				StakeAmount: &stakeAmt,
+			},
+			RewardAddress: testAddress,
+		})
+	}
+	reply := BulkStakingReply{}
+	if err := service.AddDelegators(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Results) != numDelegators {
+		t.Fatalf("expected %d results but got %d", numDelegators, len(reply.Results))
+	}
+	issuedTxIDs := ids.Set{}
+	for i, result := range reply.Results {
+		if result.Error != "" {
+			t.Fatalf("expected delegator %d to be added but got %q", i, result.Error)
+		}
+		issuedTxIDs.Add(result.TxID)
+	}
+
+	// Each delegator is put into a block after the delegators whose change it
+	// spends
+	spendsChange := false
+	for i := 0; i < numDelegators; i++ {
+		blk, err := service.vm.BuildBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, ok := blk.(*ProposalBlock)
+		if !ok {
+			t.Fatalf("expected *ProposalBlock but got %T", blk)
+		}
+		txID := block.Tx.ID()
+		if !issuedTxIDs.Contains(txID) {
+			t.Fatalf("expected block to contain one of the issued delegators")
+		}
+		for _, in := range block.Tx.UnsignedTx.(*UnsignedAddDelegatorTx).Ins {
+			spendsChange = spendsChange || issuedTxIDs.Contains(in.TxID)
+		}
+
+		if err := block.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		options, err := block.Options()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, ok := options[0].(*Commit)
+		if !ok {
+			t.Fatal(errShouldPrefCommit)
+		} else if err := block.Accept(); err != nil {
+			t.Fatal(err)
+		} else if err := commit.Verify(); err != nil {
+			t.Fatal(err)
+		} else if err := commit.Accept(); err != nil {
+			t.Fatal(err)
+		}
+		service.vm.SetPreference(service.vm.LastAccepted())
+
+		if status, err := service.vm.getStatus(service.vm.DB, txID); err != nil {
+			t.Fatal(err)
+		} else if status != Committed {
+			t.Fatalf("status of tx should be Committed but is %s", status)
+		}
+	}
+	if !spendsChange {
+		t.Fatal("expected a delegator to spend the change of another delegator")
+	}
+	if service.vm.mempool.Len() != 0 {
+		t.Fatalf("expected the mempool to be empty but it has %d txs", service.vm.mempool.Len())
+	}
+}
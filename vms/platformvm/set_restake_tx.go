@@ -0,0 +1,270 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errNotStaker             = errors.New("tx didn't add a primary network validator or delegator")
+	errStakingPeriodFinished = errors.New("staking period has already ended")
+	errNoOwnerAuths          = errors.New("no owner authorizations")
+	errWrongNumberOfAuths    = errors.New("wrong number of owner authorizations")
+
+	_ UnsignedDecisionTx = &UnsignedSetRestakeTx{}
+)
+
+// UnsignedSetRestakeTx is an unsigned setRestakeTx.
+// It sets whether a primary network validator or delegator is renewed at the
+// end of its staking period. See restake.go.
+type UnsignedSetRestakeTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the tx that added the validator or delegator
+	TxID ids.ID `serialize:"true" json:"txID"`
+	// True if the staker should be renewed at the end of its staking period
+	Restake bool `serialize:"true" json:"restake"`
+	// Auths that allow the restaking preference to be set. If [Restake] is
+	// true, there's one per owner of the staked outputs, in the order returned
+	// by stakeOwners, as renewing the staker keeps its stake locked. Otherwise,
+	// there's one, signed by the staker's rewards owner.
+	OwnerAuths []verify.Verifiable `serialize:"true" json:"ownerAuthorizations"`
+}
+
+// Verify return nil iff [tx] is valid
+func (tx *UnsignedSetRestakeTx) Verify(
+	ctx *snow.Context,
+	c codec.Codec,
+	feeAmount uint64,
+	feeAssetID ids.ID,
+) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.TxID.IsZero():
+		return errInvalidID
+	}
+
+	if err := tx.BaseTx.Verify(ctx, c); err != nil {
+		return err
+	}
+	if len(tx.OwnerAuths) == 0 {
+		return errNoOwnerAuths
+	}
+	for _, auth := range tx.OwnerAuths {
+		if err := auth.Verify(); err != nil {
+			return err
+		}
+	}
+
+	// cache that this is valid
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// SemanticVerify returns nil if [tx] is valid given the state in [db]
+func (tx *UnsignedSetRestakeTx) SemanticVerify(
+	vm *VM,
+	db database.Database,
+	stx *Tx,
+) (
+	func() error,
+	TxError,
+) {
+	// Verify the tx is well-formed
+	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		return nil, permError{err}
+	}
+	if len(stx.Creds) < len(tx.OwnerAuths) {
+		return nil, permError{errWrongNumberOfCredentials}
+	}
+
+	baseTxCredsLen := len(stx.Creds) - len(tx.OwnerAuths)
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	ownerCreds := stx.Creds[baseTxCredsLen:]
+
+	// Verify that the staker's period hasn't ended yet
+	stakerTx, ok, err := vm.getPrimaryNetworkStaker(db, tx.TxID)
+	if err != nil {
+		return nil, tempError{err}
+	}
+	if !ok {
+		return nil, permError{fmt.Errorf("%s isn't a current or pending staker", tx.TxID)}
+	}
+	staker, ok := stakerTx.UnsignedTx.(TimedTx)
+	if !ok {
+		return nil, permError{errNotStaker}
+	}
+	owners, err := restakeOwners(stakerTx, tx.Restake)
+	if err != nil {
+		return nil, permError{err}
+	}
+	currentTime, err := vm.getTimestamp(db)
+	if err != nil {
+		return nil, tempError{err}
+	}
+	if !currentTime.Before(staker.EndTime()) {
+		return nil, permError{errStakingPeriodFinished}
+	}
+
+	// Verify that the owners allowed to set this preference authorized this tx
+	if len(tx.OwnerAuths) != len(owners) {
+		return nil, permError{errWrongNumberOfAuths}
+	}
+	for i, owner := range owners {
+		if err := vm.fx.VerifyPermission(tx, tx.OwnerAuths[i], ownerCreds[i], owner); err != nil {
+			return nil, permError{err}
+		}
+	}
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		return nil, err
+	}
+
+	txID := tx.ID()
+
+	// Consume the UTXOS
+	if err := vm.consumeInputs(db, tx.Ins); err != nil {
+		return nil, tempError{err}
+	}
+	// Produce the UTXOS
+	if err := vm.produceOutputs(db, txID, tx.Outs); err != nil {
+		return nil, tempError{err}
+	}
+	// Set the restaking preference
+	if tx.Restake {
+		err = vm.putRestake(db, tx.TxID)
+	} else {
+		err = vm.deleteRestake(db, tx.TxID)
+	}
+	if err != nil {
+		return nil, tempError{err}
+	}
+	return nil, nil
+}
+
+func (vm *VM) newSetRestakeTx(
+	stakerTxID ids.ID, // ID of the tx that added the validator or delegator
+	restake bool, // Whether the staker should be renewed at the end of its staking period
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for authorizing the change and paying the fee
+) (*Tx, error) {
+	stakerTx, ok, err := vm.getPrimaryNetworkStaker(vm.DB, stakerTxID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get staker %s: %w", stakerTxID, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a current or pending staker", stakerTxID)
+	}
+	owners, err := restakeOwners(stakerTx, restake)
+	if err != nil {
+		return nil, err
+	}
+
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.txFee)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	ownerAuths := make([]verify.Verifiable, len(owners))
+	for i, owner := range owners {
+		ownerAuth, ownerSigners, err := vm.authorizeOwner(owner, keys)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't authorize tx's owner %d: %w", i, err)
+		}
+		ownerAuths[i] = ownerAuth
+		signers = append(signers, ownerSigners)
+	}
+
+	// Create the tx
+	utx := &UnsignedSetRestakeTx{
+		BaseTx: BaseTx{BaseTx: djtx.BaseTx{
+			NetworkID:    vm.Ctx.NetworkID,
+			BlockchainID: vm.Ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		TxID:       stakerTxID,
+		Restake:    restake,
+		OwnerAuths: ownerAuths,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(vm.codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID)
+}
+
+// restakeOwners returns the owners that must authorize setting the restaking
+// preference of the staker added by [stakerTx] to [restake]. Opting in keeps
+// the stake locked, so it must be authorized by the owners of the stake.
+// Opting out must be authorized by the rewards owner.
+func restakeOwners(stakerTx *Tx, restake bool) ([]verify.Verifiable, error) {
+	var (
+		stake        []*djtx.TransferableOutput
+		rewardsOwner verify.Verifiable
+	)
+	switch utx := stakerTx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		stake = utx.Stake
+		rewardsOwner = utx.RewardsOwner
+	case *UnsignedAddDelegatorTx:
+		stake = utx.Stake
+		rewardsOwner = utx.RewardsOwner
+	default:
+		return nil, errNotStaker
+	}
+	if !restake {
+		return []verify.Verifiable{rewardsOwner}, nil
+	}
+	return stakeOwners(stake)
+}
+
+// stakeOwners returns the distinct owners of the staked outputs [stake], in the
+// order they first appear. The locktimes of the outputs are dropped, as they
+// don't restrict who owns the stake.
+func stakeOwners(stake []*djtx.TransferableOutput) ([]verify.Verifiable, error) {
+	owners := []verify.Verifiable(nil)
+	distinct := []*secp256k1fx.OutputOwners(nil)
+	for _, out := range stake {
+		innerOut := out.Out
+		if lockedOut, ok := innerOut.(*StakeableLockOut); ok {
+			innerOut = lockedOut.TransferableOut
+		}
+		transferOut, ok := innerOut.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return nil, errUnknownOwners
+		}
+		owner := &secp256k1fx.OutputOwners{
+			Threshold: transferOut.Threshold,
+			Addrs:     transferOut.Addrs,
+		}
+
+		seen := false
+		for _, other := range distinct {
+			seen = seen || other.Equals(owner)
+		}
+		if !seen {
+			distinct = append(distinct, owner)
+			owners = append(owners, owner)
+		}
+	}
+	if len(owners) == 0 {
+		return nil, errUnknownOwners
+	}
+	return owners, nil
+}
@@ -140,7 +140,7 @@ func (vm *VM) stake(
 			Out: &StakeableLockOut{
 				Locktime: out.Locktime,
 				TransferableOut: &secp256k1fx.TransferOutput{
-					Amt:          remainingValue,
+					Amt:          amountToStake,
 					OutputOwners: inner.OutputOwners,
 				},
 			},
@@ -289,13 +289,25 @@ func (vm *VM) authorize(
 	error,
 ) {
 	// Get information about the subnet we're authorizing the operation for
-	subnet, err := vm.getSubnet(db, subnetID)
+	subnetOwner, err := vm.getSubnetOwner(db, subnetID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("subnet %s doesn't exist", subnetID)
 	}
+	return vm.authorizeOwner(subnetOwner, keys)
+}
 
-	// Make sure the owners of the subnet match the provided keys
-	owner, ok := subnet.UnsignedTx.(*UnsignedCreateSubnetTx).Owner.(*secp256k1fx.OutputOwners)
+// authorizeOwner returns the input that proves that [keys] satisfy [owners],
+// along with the keys that must sign the tx.
+func (vm *VM) authorizeOwner(
+	owners verify.Verifiable,
+	keys []*crypto.PrivateKeySECP256K1R,
+) (
+	verify.Verifiable, // Input that names owners
+	[]*crypto.PrivateKeySECP256K1R, // Keys that prove ownership
+	error,
+) {
+	// Make sure the owners match the provided keys
+	owner, ok := owners.(*secp256k1fx.OutputOwners)
 	if !ok {
 		return nil, nil, errUnknownOwners
 	}
@@ -309,7 +321,7 @@ func (vm *VM) authorize(
 	// Make sure that the operation is valid after a minimum time
 	now := uint64(vm.clock.Time().Unix())
 
-	// Attempt to prove ownership of the subnet
+	// Attempt to prove ownership
 	indices, signers, matches := kc.Match(owner, now)
 	if !matches {
 		return nil, nil, errCantSign
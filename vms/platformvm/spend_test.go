@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -355,3 +356,73 @@ func TestSemanticVerifySpendUTXOs(t *testing.T) {
 		})
 	}
 }
+
+func TestStakeLockedOutputs(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// keys[0] also controls a UTXO that is locked until after the delegation
+	// starts
+	lockedAmt := 3 * vm.stakingParams.MinDelegatorStake
+	locktime := uint64(defaultValidateEndTime.Unix())
+	owners := secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+	}
+	if err := vm.putUTXO(vm.DB, &djtx.UTXO{
+		UTXOID: djtx.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  djtx.Asset{ID: vm.Ctx.DJTXAssetID},
+		Out: &StakeableLockOut{
+			Locktime: locktime,
+			TransferableOut: &secp256k1fx.TransferOutput{
+				Amt:          lockedAmt,
+				OutputOwners: owners,
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only part of the locked UTXO is staked. The rest is returned, still
+	// locked.
+	stakeAmt := vm.stakingParams.MinDelegatorStake
+	tx, err := vm.newAddDelegatorTx(
+		stakeAmt,
+		uint64(defaultValidateStartTime.Add(Delta).Unix())+1,
+		uint64(defaultValidateStartTime.Add(Delta+MinimumStakingDuration).Unix())+1,
+		keys[0].PublicKey().Address(),
+		keys[0].PublicKey().Address(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utx := tx.UnsignedTx.(*UnsignedAddDelegatorTx)
+	if len(utx.Stake) != 1 {
+		t.Fatalf("expected 1 staked output but got %d", len(utx.Stake))
+	}
+	if stakedOut, ok := utx.Stake[0].Out.(*StakeableLockOut); !ok {
+		t.Fatalf("staked output should be locked but is %T", utx.Stake[0].Out)
+	} else if stakedOut.Locktime != locktime || stakedOut.Amount() != stakeAmt {
+		t.Fatalf("expected %d staked until %d but got %d staked until %d", stakeAmt, locktime, stakedOut.Amount(), stakedOut.Locktime)
+	}
+	returnedLocked := uint64(0)
+	for _, out := range utx.Outs {
+		if lockedOut, ok := out.Out.(*StakeableLockOut); ok && lockedOut.Locktime == locktime {
+			returnedLocked += lockedOut.Amount()
+		}
+	}
+	if returnedLocked != lockedAmt-stakeAmt {
+		t.Fatalf("expected %d to be returned locked but got %d", lockedAmt-stakeAmt, returnedLocked)
+	}
+
+	// The tx's inputs and outputs must pass the flowcheck
+	outs := append(append([]*djtx.TransferableOutput{}, utx.Outs...), utx.Stake...)
+	if err := vm.semanticVerifySpend(vm.DB, utx, utx.Ins, outs, tx.Creds, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		t.Fatal(err)
+	}
+}
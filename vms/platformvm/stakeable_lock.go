@@ -27,6 +27,15 @@ func (s *StakeableLockOut) Verify() error {
 	return s.TransferableOut.Verify()
 }
 
+// Addresses returns the addresses of the owners of the locked output, so that
+// the UTXO is indexed by them
+func (s *StakeableLockOut) Addresses() [][]byte {
+	if addressable, ok := s.TransferableOut.(djtx.Addressable); ok {
+		return addressable.Addresses()
+	}
+	return nil
+}
+
 // StakeableLockIn ...
 type StakeableLockIn struct {
 	Locktime            uint64 `serialize:"true" json:"locktime"`
@@ -0,0 +1,234 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/json"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errNoSupplyCap             = errors.New("supply cap must be non-zero")
+	errConsumptionRateTooLarge = errors.New("maximum consumption rate must be at most 100%")
+	errNoConsumptionInterval   = errors.New("consumption interval must be non-zero")
+	errMinStakeDurationTooLow  = fmt.Errorf("minimum staking duration must be at least %s", MinimumStakingDuration)
+	errMaxStakeDurationTooHigh = fmt.Errorf("maximum staking duration must be at most %s", MaximumStakingDuration)
+	errStakeDurationRange      = errors.New("minimum staking duration must be at most the maximum staking duration")
+	errNoMinValidatorStake     = errors.New("minimum validator stake must be non-zero")
+	errNoMinDelegatorStake     = errors.New("minimum delegator stake must be non-zero")
+	errValidatorStakeRange     = errors.New("minimum validator stake must be at most the maximum validator stake")
+	errNoMaxDelegationFactor   = errors.New("maximum delegation factor must be non-zero")
+	errInitialSupplyAboveCap   = errors.New("initial supply must be at most the supply cap")
+)
+
+// StakingParameters are the economic parameters of staking on the primary
+// network. They are defined in the genesis so that every node on the network
+// uses the same values.
+type StakingParameters struct {
+	// Maximum amount of DJTX that should ever exist
+	SupplyCap uint64 `serialize:"true"`
+
+	// Consumption rate of the remaining supply, out of [PercentDenominator],
+	// used when calculating the reward of a staking period with duration 0
+	MinConsumptionRate uint64 `serialize:"true"`
+
+	// Difference between the consumption rate of the remaining supply used
+	// when calculating the reward of a staking period that lasts
+	// [ConsumptionInterval] and [MinConsumptionRate]
+	MaxSubMinConsumptionRate uint64 `serialize:"true"`
+
+	// Period, in seconds, used to calculate the consumption rate given a
+	// staking duration
+	ConsumptionInterval uint64 `serialize:"true"`
+
+	// Shortest amount of time, in seconds, a staker can bond their funds for.
+	// Must be at least [MinimumStakingDuration].
+	MinStakeDuration uint64 `serialize:"true"`
+
+	// Longest amount of time, in seconds, a staker can bond their funds for.
+	// Must be at most [MaximumStakingDuration].
+	MaxStakeDuration uint64 `serialize:"true"`
+
+	// Minimum amount of DJTX a validator of the primary network must stake
+	MinValidatorStake uint64 `serialize:"true"`
+
+	// Minimum amount of DJTX a delegator must stake
+	MinDelegatorStake uint64 `serialize:"true"`
+
+	// Maximum weight of a validator of the primary network, including the
+	// weight delegated to it
+	MaxValidatorStake uint64 `serialize:"true"`
+
+	// Maximum amount of DJTX that can be delegated to a validator at any
+	// point in time, as a multiple of the validator's own stake. Must be
+	// non-zero.
+	MaxDelegationFactor uint64 `serialize:"true"`
+}
+
+// DefaultStakingParameters returns the staking parameters used when the
+// genesis doesn't specify any. The weight of validators and the amount
+// delegated to them aren't capped, as existing networks were created without
+// these caps.
+func DefaultStakingParameters() StakingParameters {
+	return StakingParameters{
+		SupplyCap:                SupplyCap,
+		MinConsumptionRate:       MinConsumptionRate,
+		MaxSubMinConsumptionRate: MaxSubMinConsumptionRate,
+		ConsumptionInterval:      uint64(MaximumStakingDuration / time.Second),
+		MinStakeDuration:         uint64(MinimumStakingDuration / time.Second),
+		MaxStakeDuration:         uint64(MaximumStakingDuration / time.Second),
+		MinValidatorStake:        MinimumValidatorStake,
+		MinDelegatorStake:        MinimumDelegatorStake,
+		MaxValidatorStake:        math.MaxUint64,
+		MaxDelegationFactor:      math.MaxUint64,
+	}
+}
+
+// Verify returns nil iff these parameters are well-formed
+func (p *StakingParameters) Verify() error {
+	switch {
+	case p.SupplyCap == 0:
+		return errNoSupplyCap
+	case p.MinConsumptionRate > PercentDenominator ||
+		p.MaxSubMinConsumptionRate > PercentDenominator-p.MinConsumptionRate:
+		return errConsumptionRateTooLarge
+	case p.ConsumptionInterval == 0:
+		return errNoConsumptionInterval
+	case p.MinStakeDuration < uint64(MinimumStakingDuration/time.Second):
+		return errMinStakeDurationTooLow
+	case p.MaxStakeDuration > uint64(MaximumStakingDuration/time.Second):
+		return errMaxStakeDurationTooHigh
+	case p.MinStakeDuration > p.MaxStakeDuration:
+		return errStakeDurationRange
+	case p.MinValidatorStake == 0:
+		return errNoMinValidatorStake
+	case p.MinDelegatorStake == 0:
+		return errNoMinDelegatorStake
+	case p.MinValidatorStake > p.MaxValidatorStake:
+		return errValidatorStakeRange
+	case p.MaxDelegationFactor == 0:
+		return errNoMaxDelegationFactor
+	default:
+		return nil
+	}
+}
+
+// MinStakeDurationTime returns the shortest amount of time a staker can bond
+// their funds for
+func (p *StakingParameters) MinStakeDurationTime() time.Duration {
+	return time.Duration(p.MinStakeDuration) * time.Second
+}
+
+// MaxStakeDurationTime returns the longest amount of time a staker can bond
+// their funds for
+func (p *StakingParameters) MaxStakeDurationTime() time.Duration {
+	return time.Duration(p.MaxStakeDuration) * time.Second
+}
+
+// verifyStakeDuration returns nil iff [validator] stakes for an allowed
+// amount of time
+func (p *StakingParameters) verifyStakeDuration(validator *Validator) error {
+	switch duration := validator.Duration(); {
+	case duration < p.MinStakeDurationTime(): // Ensure staking length is not too short
+		return errStakeTooShort
+	case duration > p.MaxStakeDurationTime(): // Ensure staking length is not too long
+		return errStakeTooLong
+	default:
+		return nil
+	}
+}
+
+// maxDelegatedWeight returns the maximum amount of DJTX that can be delegated
+// to a validator that stakes [validatorWeight] at any point in time
+func (p *StakingParameters) maxDelegatedWeight(validatorWeight uint64) uint64 {
+	maxDelegated, err := safemath.Mul64(validatorWeight, p.MaxDelegationFactor)
+	if err != nil {
+		maxDelegated = math.MaxUint64
+	}
+	if validatorWeight >= p.MaxValidatorStake {
+		return 0
+	}
+	if remaining := p.MaxValidatorStake - validatorWeight; remaining < maxDelegated {
+		return remaining
+	}
+	return maxDelegated
+}
+
+// verifyDelegation returns nil iff a validator that stakes [validatorWeight]
+// may have [delegatedWeight] delegated to it at the same time
+func (p *StakingParameters) verifyDelegation(validatorWeight, delegatedWeight uint64) error {
+	maxDelegated, err := safemath.Mul64(validatorWeight, p.MaxDelegationFactor)
+	if err != nil {
+		maxDelegated = math.MaxUint64
+	}
+	if delegatedWeight > maxDelegated {
+		return fmt.Errorf("%w: %d nDJTX would be delegated to a validator staking %d nDJTX, but at most %d nDJTX may be",
+			errOverDelegated,
+			delegatedWeight,
+			validatorWeight,
+			maxDelegated)
+	}
+	totalWeight, err := safemath.Add64(validatorWeight, delegatedWeight)
+	if err != nil || totalWeight > p.MaxValidatorStake {
+		return fmt.Errorf("%w: validator staking %d nDJTX with %d nDJTX delegated would exceed the maximum weight of %d nDJTX",
+			errValidatorWeightTooLarge,
+			validatorWeight,
+			delegatedWeight,
+			p.MaxValidatorStake)
+	}
+	return nil
+}
+
+// APIStakingParameters is the representation of the staking parameters used
+// in API calls. Durations are given in seconds.
+type APIStakingParameters struct {
+	SupplyCap                json.Uint64 `json:"supplyCap"`
+	MinConsumptionRate       json.Uint64 `json:"minConsumptionRate"`
+	MaxSubMinConsumptionRate json.Uint64 `json:"maxSubMinConsumptionRate"`
+	ConsumptionInterval      json.Uint64 `json:"consumptionInterval"`
+	MinStakeDuration         json.Uint64 `json:"minStakeDuration"`
+	MaxStakeDuration         json.Uint64 `json:"maxStakeDuration"`
+	MinValidatorStake        json.Uint64 `json:"minValidatorStake"`
+	MinDelegatorStake        json.Uint64 `json:"minDelegatorStake"`
+	MaxValidatorStake        json.Uint64 `json:"maxValidatorStake"`
+	MaxDelegationFactor      json.Uint64 `json:"maxDelegationFactor"`
+}
+
+// NewAPIStakingParameters returns the API representation of [p]
+func NewAPIStakingParameters(p StakingParameters) APIStakingParameters {
+	return APIStakingParameters{
+		SupplyCap:                json.Uint64(p.SupplyCap),
+		MinConsumptionRate:       json.Uint64(p.MinConsumptionRate),
+		MaxSubMinConsumptionRate: json.Uint64(p.MaxSubMinConsumptionRate),
+		ConsumptionInterval:      json.Uint64(p.ConsumptionInterval),
+		MinStakeDuration:         json.Uint64(p.MinStakeDuration),
+		MaxStakeDuration:         json.Uint64(p.MaxStakeDuration),
+		MinValidatorStake:        json.Uint64(p.MinValidatorStake),
+		MinDelegatorStake:        json.Uint64(p.MinDelegatorStake),
+		MaxValidatorStake:        json.Uint64(p.MaxValidatorStake),
+		MaxDelegationFactor:      json.Uint64(p.MaxDelegationFactor),
+	}
+}
+
+// StakingParameters returns the staking parameters [p] represents
+func (p *APIStakingParameters) StakingParameters() StakingParameters {
+	return StakingParameters{
+		SupplyCap:                uint64(p.SupplyCap),
+		MinConsumptionRate:       uint64(p.MinConsumptionRate),
+		MaxSubMinConsumptionRate: uint64(p.MaxSubMinConsumptionRate),
+		ConsumptionInterval:      uint64(p.ConsumptionInterval),
+		MinStakeDuration:         uint64(p.MinStakeDuration),
+		MaxStakeDuration:         uint64(p.MaxStakeDuration),
+		MinValidatorStake:        uint64(p.MinValidatorStake),
+		MinDelegatorStake:        uint64(p.MinDelegatorStake),
+		MaxValidatorStake:        uint64(p.MaxValidatorStake),
+		MaxDelegationFactor:      uint64(p.MaxDelegationFactor),
+	}
+}
@@ -0,0 +1,238 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+)
+
+func TestStakingParametersVerify(t *testing.T) {
+	tests := []struct {
+		description string
+		modify      func(*StakingParameters)
+		expectedErr error
+	}{
+		{"default", func(*StakingParameters) {}, nil},
+		{"no supply cap", func(p *StakingParameters) { p.SupplyCap = 0 }, errNoSupplyCap},
+		{"min consumption rate too large", func(p *StakingParameters) { p.MinConsumptionRate = PercentDenominator + 1 }, errConsumptionRateTooLarge},
+		{"max consumption rate too large", func(p *StakingParameters) { p.MaxSubMinConsumptionRate = PercentDenominator }, errConsumptionRateTooLarge},
+		{"no consumption interval", func(p *StakingParameters) { p.ConsumptionInterval = 0 }, errNoConsumptionInterval},
+		{"no min stake duration", func(p *StakingParameters) { p.MinStakeDuration = 0 }, errMinStakeDurationTooLow},
+		{"min stake duration too low", func(p *StakingParameters) { p.MinStakeDuration-- }, errMinStakeDurationTooLow},
+		{"max stake duration too high", func(p *StakingParameters) { p.MaxStakeDuration++ }, errMaxStakeDurationTooHigh},
+		{"min stake duration above max", func(p *StakingParameters) { p.MinStakeDuration = p.MaxStakeDuration - 1; p.MaxStakeDuration -= 2 }, errStakeDurationRange},
+		{"no min validator stake", func(p *StakingParameters) { p.MinValidatorStake = 0 }, errNoMinValidatorStake},
+		{"no min delegator stake", func(p *StakingParameters) { p.MinDelegatorStake = 0 }, errNoMinDelegatorStake},
+		{"min validator stake above max", func(p *StakingParameters) { p.MaxValidatorStake = p.MinValidatorStake - 1 }, errValidatorStakeRange},
+		{"min validator stake equal to max", func(p *StakingParameters) { p.MaxValidatorStake = p.MinValidatorStake }, nil},
+		{"no max delegation factor", func(p *StakingParameters) { p.MaxDelegationFactor = 0 }, errNoMaxDelegationFactor},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			params := DefaultStakingParameters()
+			test.modify(&params)
+			assert.Equal(t, test.expectedErr, params.Verify())
+		})
+	}
+}
+
+func TestStakingParametersVerifyDelegation(t *testing.T) {
+	params := DefaultStakingParameters()
+	params.MaxValidatorStake = 10 * units.KiloDjtx
+	params.MaxDelegationFactor = 4
+
+	assert.NoError(t, params.verifyDelegation(units.KiloDjtx, 4*units.KiloDjtx))
+	assert.True(t, errors.Is(params.verifyDelegation(units.KiloDjtx, 4*units.KiloDjtx+1), errOverDelegated))
+	assert.NoError(t, params.verifyDelegation(4*units.KiloDjtx, 6*units.KiloDjtx))
+	assert.True(t, errors.Is(params.verifyDelegation(4*units.KiloDjtx, 6*units.KiloDjtx+1), errValidatorWeightTooLarge))
+
+	assert.EqualValues(t, 4*units.KiloDjtx, params.maxDelegatedWeight(units.KiloDjtx))
+	assert.EqualValues(t, 6*units.KiloDjtx, params.maxDelegatedWeight(4*units.KiloDjtx))
+	assert.Zero(t, params.maxDelegatedWeight(params.MaxValidatorStake))
+
+	// Networks that don't define the caps in their genesis don't enforce them
+	params = DefaultStakingParameters()
+	assert.NoError(t, params.verifyDelegation(units.MegaDjtx, 1000*units.MegaDjtx))
+}
+
+func TestGetCurrentValidatorsDelegationCapacity(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	service := &Service{vm: vm}
+	reply := GetCurrentValidatorsReply{}
+	assert.NoError(t, service.GetCurrentValidators(nil, &GetCurrentValidatorsArgs{}, &reply))
+	assert.Len(t, reply.Validators, len(keys))
+	for _, vdrIntf := range reply.Validators {
+		vdr, ok := vdrIntf.(APIPrimaryValidator)
+		if assert.True(t, ok) && assert.NotNil(t, vdr.DelegationCapacity) {
+			assert.EqualValues(t, vm.stakingParams.maxDelegatedWeight(defaultWeight), *vdr.DelegationCapacity)
+		}
+	}
+}
+
+func TestBuildGenesisInvalidStakingParameters(t *testing.T) {
+	args, _ := defaultGenesis()
+	params := NewAPIStakingParameters(DefaultStakingParameters())
+	params.MinStakeDuration = 0
+	args.StakingParameters = &params
+
+	reply := BuildGenesisReply{}
+	ss := StaticService{}
+	assert.Error(t, ss.BuildGenesis(nil, args, &reply))
+}
+
+func TestGenesisStakingParameters(t *testing.T) {
+	params := DefaultStakingParameters()
+	params.SupplyCap = 400 * units.MegaDjtx
+	params.MinStakeDuration = uint64((48 * time.Hour) / time.Second)
+	params.MinValidatorStake = 2 * minStake
+	params.MinDelegatorStake = 3 * minStake
+
+	args, _ := defaultGenesis()
+	apiParams := NewAPIStakingParameters(params)
+	args.StakingParameters = &apiParams
+	reply := BuildGenesisReply{}
+	ss := StaticService{}
+	assert.NoError(t, ss.BuildGenesis(nil, args, &reply))
+
+	vm := &VM{
+		SnowmanVM:    &core.SnowmanVM{},
+		chainManager: chains.MockManager{},
+		vdrMgr:       validators.NewManager(),
+		txFee:        defaultTxFee,
+	}
+	vm.clock.Set(defaultGenesisTime)
+	ctx := defaultContext()
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+	msgChan := make(chan common.Message, 1)
+	assert.NoError(t, vm.Initialize(ctx, memdb.New(), reply.Bytes.Bytes, msgChan, nil))
+	assert.Equal(t, params, vm.stakingParams)
+
+	service := &Service{vm: vm}
+	apiReply := APIStakingParameters{}
+	assert.NoError(t, service.GetStakingParameters(nil, nil, &apiReply))
+	assert.Equal(t, apiParams, apiReply)
+
+	minStakeReply := GetMinStakeReply{}
+	assert.NoError(t, service.GetMinStake(nil, nil, &minStakeReply))
+	assert.EqualValues(t, params.MinValidatorStake, minStakeReply.MinStake)
+
+	startTime := defaultGenesisTime.Add(Delta).Add(time.Second)
+	nodeID := ids.GenerateTestShortID()
+	_, err := vm.newAddValidatorTx(
+		minStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(params.MinStakeDurationTime()).Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.Equal(t, errWeightTooSmall, err)
+
+	_, err = vm.newAddValidatorTx(
+		params.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.Equal(t, errStakeTooShort, err)
+
+	_, err = vm.newAddValidatorTx(
+		params.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(params.MinStakeDurationTime()).Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.NoError(t, err)
+
+	_, err = vm.newAddDelegatorTx(
+		params.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(params.MinStakeDurationTime()).Unix()),
+		keys[0].PublicKey().Address(),
+		nodeID,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	assert.Equal(t, errWeightTooSmall, err)
+}
+
+func TestParseGenesisStakingParameters(t *testing.T) {
+	// The default staking parameters aren't serialized, so that the genesis of
+	// existing networks doesn't change
+	args, _ := defaultGenesis()
+	args.StakingParameters = nil
+	reply := BuildGenesisReply{}
+	ss := StaticService{}
+	assert.NoError(t, ss.BuildGenesis(nil, args, &reply))
+	genesis, err := ParseGenesis(reply.Bytes.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultStakingParameters(), genesis.StakingParameters)
+	genesisBytes, err := Codec.Marshal(genesis)
+	assert.NoError(t, err)
+	assert.Equal(t, reply.Bytes.Bytes, genesisBytes)
+
+	params := DefaultStakingParameters()
+	params.MinValidatorStake = 2 * minStake
+	apiParams := NewAPIStakingParameters(params)
+	args.StakingParameters = &apiParams
+	assert.NoError(t, ss.BuildGenesis(nil, args, &reply))
+	genesis, err = ParseGenesis(reply.Bytes.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, params, genesis.StakingParameters)
+
+	// Bytes following the staking parameters are invalid
+	_, err = ParseGenesis(append(reply.Bytes.Bytes, 0))
+	assert.Error(t, err)
+
+	// Truncated staking parameters are invalid
+	_, err = ParseGenesis(reply.Bytes.Bytes[:len(reply.Bytes.Bytes)-1])
+	assert.Error(t, err)
+
+	// The default staking parameters must not be wrapped with the genesis
+	defaultParams := DefaultStakingParameters()
+	paramsBytes, err := Codec.Marshal(&defaultParams)
+	assert.NoError(t, err)
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.ShortLen+2*wrappers.IntLen+len(genesisBytes)+len(paramsBytes))}
+	p.PackShort(stakingGenesisVersion)
+	p.PackBytes(genesisBytes)
+	p.PackBytes(paramsBytes)
+	assert.NoError(t, p.Err)
+	_, err = ParseGenesis(p.Bytes)
+	assert.Equal(t, errInvalidGenesisBytes, err)
+
+	// Unknown genesis versions are invalid
+	p.Bytes[1] = byte(stakingGenesisVersion + 1)
+	_, err = ParseGenesis(p.Bytes)
+	assert.Error(t, err)
+}
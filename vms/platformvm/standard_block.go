@@ -62,7 +62,7 @@ func (sb *StandardBlock) Verify() error {
 		}
 		onAccept, err := utx.SemanticVerify(sb.vm, sb.onAcceptDB, tx)
 		if err != nil {
-			sb.vm.droppedTxCache.Put(tx.ID(), nil) // cache tx as dropped
+			sb.vm.mempool.Evict(tx.ID()) // drop tx and the txs spending its outputs
 			if err := sb.Reject(); err == nil {
 				if err := sb.vm.DB.Commit(); err != nil {
 					return err
@@ -78,6 +78,8 @@ func (sb *StandardBlock) Verify() error {
 			return err
 		} else if err := sb.vm.putStatus(sb.onAcceptDB, tx.ID(), Committed); err != nil {
 			return err
+		} else if err := sb.vm.putTxBlockID(sb.onAcceptDB, tx.ID(), sb.ID()); err != nil {
+			return err
 		} else if onAccept != nil {
 			funcs = append(funcs, onAccept)
 		}
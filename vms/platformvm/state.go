@@ -18,6 +18,8 @@ import (
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/state"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
@@ -26,9 +28,25 @@ import (
 
 // TODO: Cache prefixed IDs or use different way of keying into database
 const (
-	startDBPrefix  = "start"
-	stopDBPrefix   = "stop"
-	uptimeDBPrefix = "uptime"
+	startDBPrefix       = "start"
+	stopDBPrefix        = "stop"
+	uptimeDBPrefix      = "uptime"
+	subnetOwnerDBPrefix = "subnetOwner"
+	blockHeightDBPrefix = "blockHeight"
+	blockTimeDBPrefix   = "blockTime"
+	txBlockDBPrefix     = "txBlock"
+	restakeDBPrefix     = "restake"
+
+	delegatorDBPrefix        = "delegator"
+	delegatorIndexedDBPrefix = "delegatorIndexed"
+	blocksIndexedDBPrefix    = "blocksIndexed"
+	subnetVdrWeightDBPrefix  = "subnetVdrWeight"
+
+	primaryNetworkStakerDBPrefix = "primaryNetworkStaker"
+	stakersIndexedDBPrefix       = "stakersIndexed"
+
+	blockKeysDBPrefix        = "blockKeys"
+	blockKeysIndexedDBPrefix = "blockKeysIndexed"
 )
 
 var (
@@ -106,7 +124,18 @@ func (vm *VM) enqueueStaker(db database.Database, subnetID ids.ID, stakerTx *Tx)
 	}
 	startKey := p.Bytes
 
-	return prefixStartDB.Put(startKey, txBytes)
+	if err := prefixStartDB.Put(startKey, txBytes); err != nil {
+		return err
+	}
+	if subnetID.Equals(constants.PrimaryNetworkID) {
+		if err := vm.putPrimaryNetworkStaker(db, stakerTx); err != nil {
+			return err
+		}
+	}
+	if delegator, ok := staker.(*UnsignedAddDelegatorTx); ok {
+		return vm.putDelegator(db, delegator.Validator.NodeID, stakerTx)
+	}
+	return nil
 }
 
 // Remove a staker from subnet [subnetID]'s pending validator queue. A staker
@@ -190,7 +219,20 @@ func (vm *VM) addStaker(db database.Database, subnetID ids.ID, tx *rewardTx) err
 	}
 	stopKey := p.Bytes
 
-	return prefixStopDB.Put(stopKey, txBytes)
+	if err := prefixStopDB.Put(stopKey, txBytes); err != nil {
+		return err
+	}
+	if subnetID.Equals(constants.PrimaryNetworkID) {
+		if err := vm.putPrimaryNetworkStaker(db, &tx.Tx); err != nil {
+			return err
+		}
+	}
+	if delegator, ok := staker.(*UnsignedAddDelegatorTx); ok {
+		if err := vm.putDelegator(db, delegator.Validator.NodeID, &tx.Tx); err != nil {
+			return err
+		}
+	}
+	return vm.putValidatorChange(db, subnetID, tx, true)
 }
 
 // Remove a staker from subnet [subnetID]
@@ -230,7 +272,25 @@ func (vm *VM) removeStaker(db database.Database, subnetID ids.ID, tx *rewardTx)
 	}
 	stopKey := p.Bytes
 
-	return prefixStopDB.Delete(stopKey)
+	if err := prefixStopDB.Delete(stopKey); err != nil {
+		return err
+	}
+	if err := vm.putValidatorChange(db, subnetID, tx, false); err != nil {
+		return err
+	}
+	if subnetID.Equals(constants.PrimaryNetworkID) {
+		if err := vm.deletePrimaryNetworkStaker(db, tx.Tx.ID()); err != nil {
+			return err
+		}
+	}
+	switch staker := staker.(type) {
+	case *UnsignedAddDelegatorTx:
+		return vm.removeDelegator(db, staker.Validator.NodeID, tx.Tx.ID())
+	case *UnsignedAddSubnetValidatorTx:
+		return vm.deleteSubnetValidatorWeight(db, tx.Tx.ID())
+	default:
+		return nil
+	}
 }
 
 // Returns the pending staker that will start staking next
@@ -269,6 +329,278 @@ func (vm *VM) nextStakerStop(db database.Database, subnetID ids.ID) (*rewardTx,
 	return &tx, tx.Tx.Sign(vm.codec, nil)
 }
 
+// Persist [tx], which delegates to [nodeID], in the index of the current and
+// pending delegators of [nodeID]. Delegators stay in the index from when they
+// are enqueued until they are removed from the current staker set.
+func (vm *VM) putDelegator(db database.Database, nodeID ids.ShortID, tx *Tx) error {
+	delegatorDB := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", nodeID, delegatorDBPrefix)), db)
+	defer delegatorDB.Close()
+
+	return delegatorDB.Put(tx.ID().Bytes(), tx.Bytes())
+}
+
+// Remove the delegator added by tx [txID] from the index of the delegators of
+// [nodeID]
+func (vm *VM) removeDelegator(db database.Database, nodeID ids.ShortID, txID ids.ID) error {
+	delegatorDB := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", nodeID, delegatorDBPrefix)), db)
+	defer delegatorDB.Close()
+
+	return delegatorDB.Delete(txID.Bytes())
+}
+
+// Returns the current and pending delegators of [nodeID]
+func (vm *VM) getDelegators(db database.Database, nodeID ids.ShortID) ([]*UnsignedAddDelegatorTx, error) {
+	delegatorDB := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", nodeID, delegatorDBPrefix)), db)
+	defer delegatorDB.Close()
+
+	iter := delegatorDB.NewIterator()
+	defer iter.Release()
+
+	delegators := []*UnsignedAddDelegatorTx(nil)
+	for iter.Next() {
+		tx := Tx{}
+		if err := Codec.Unmarshal(iter.Value(), &tx); err != nil {
+			return nil, err
+		}
+		if err := tx.Sign(vm.codec, nil); err != nil {
+			return nil, err
+		}
+		delegator, ok := tx.UnsignedTx.(*UnsignedAddDelegatorTx)
+		if !ok {
+			return nil, fmt.Errorf("expected delegator to be *UnsignedAddDelegatorTx but is type %T", tx.UnsignedTx)
+		}
+		delegators = append(delegators, delegator)
+	}
+	return delegators, iter.Error()
+}
+
+// indexDelegators indexes the current and pending delegators of the primary
+// network by the node they delegate to, if they were added before delegators
+// were indexed
+func (vm *VM) indexDelegators(db database.Database) error {
+	indexedDB := prefixdb.NewNested([]byte(delegatorIndexedDBPrefix), db)
+	defer indexedDB.Close()
+
+	if indexed, err := indexedDB.Has([]byte(delegatorIndexedDBPrefix)); err != nil || indexed {
+		return err
+	}
+
+	err := vm.forEachPrimaryNetworkStaker(db, func(tx *Tx) error {
+		if delegator, ok := tx.UnsignedTx.(*UnsignedAddDelegatorTx); ok {
+			return vm.putDelegator(db, delegator.Validator.NodeID, tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return indexedDB.Put([]byte(delegatorIndexedDBPrefix), nil)
+}
+
+// indexPrimaryNetworkStakers indexes the current and pending stakers of the
+// primary network by the ID of the tx that added them, if they were added
+// before stakers were indexed
+func (vm *VM) indexPrimaryNetworkStakers(db database.Database) error {
+	indexedDB := prefixdb.NewNested([]byte(stakersIndexedDBPrefix), db)
+	defer indexedDB.Close()
+
+	if indexed, err := indexedDB.Has([]byte(stakersIndexedDBPrefix)); err != nil || indexed {
+		return err
+	}
+
+	err := vm.forEachPrimaryNetworkStaker(db, func(tx *Tx) error {
+		return vm.putPrimaryNetworkStaker(db, tx)
+	})
+	if err != nil {
+		return err
+	}
+	return indexedDB.Put([]byte(stakersIndexedDBPrefix), nil)
+}
+
+// forEachPrimaryNetworkStaker calls [f] with the tx that added each current
+// and pending staker of the primary network
+func (vm *VM) forEachPrimaryNetworkStaker(db database.Database, f func(*Tx) error) error {
+	stopIter := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, stopDBPrefix)), db).NewIterator()
+	defer stopIter.Release()
+	for stopIter.Next() {
+		tx := rewardTx{}
+		if err := Codec.Unmarshal(stopIter.Value(), &tx); err != nil {
+			return err
+		}
+		if err := tx.Tx.Sign(vm.codec, nil); err != nil {
+			return err
+		}
+		if err := f(&tx.Tx); err != nil {
+			return err
+		}
+	}
+	if err := stopIter.Error(); err != nil {
+		return err
+	}
+
+	startIter := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, startDBPrefix)), db).NewIterator()
+	defer startIter.Release()
+	for startIter.Next() {
+		tx := Tx{}
+		if err := Codec.Unmarshal(startIter.Value(), &tx); err != nil {
+			return err
+		}
+		if err := tx.Sign(vm.codec, nil); err != nil {
+			return err
+		}
+		if err := f(&tx); err != nil {
+			return err
+		}
+	}
+	return startIter.Error()
+}
+
+// Persist [tx], which adds a staker to the primary network, in the index of
+// the current and pending primary network stakers. Stakers stay in the index
+// from when they are enqueued until they are removed from the current staker
+// set.
+func (vm *VM) putPrimaryNetworkStaker(db database.Database, tx *Tx) error {
+	stakerDB := prefixdb.NewNested([]byte(primaryNetworkStakerDBPrefix), db)
+	defer stakerDB.Close()
+
+	return stakerDB.Put(tx.ID().Bytes(), tx.Bytes())
+}
+
+// Remove the staker added by tx [txID] from the index of the primary network
+// stakers
+func (vm *VM) deletePrimaryNetworkStaker(db database.Database, txID ids.ID) error {
+	stakerDB := prefixdb.NewNested([]byte(primaryNetworkStakerDBPrefix), db)
+	defer stakerDB.Close()
+
+	return stakerDB.Delete(txID.Bytes())
+}
+
+// Returns the tx that added the current or pending primary network staker
+// [txID], and true, if the staker exists
+func (vm *VM) getPrimaryNetworkStaker(db database.Database, txID ids.ID) (*Tx, bool, error) {
+	stakerDB := prefixdb.NewNested([]byte(primaryNetworkStakerDBPrefix), db)
+	defer stakerDB.Close()
+
+	txBytes, err := stakerDB.Get(txID.Bytes())
+	if err == database.ErrNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	tx := Tx{}
+	if err := Codec.Unmarshal(txBytes, &tx); err != nil {
+		return nil, false, err
+	}
+	return &tx, true, tx.Sign(vm.codec, nil)
+}
+
+// Returns the current staker [delegator], along with its potential reward,
+// and true, if [delegator] is a current delegator of the primary network
+func (vm *VM) getCurrentDelegator(db database.Database, delegator *UnsignedAddDelegatorTx) (*rewardTx, bool, error) {
+	prefixStopDB := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, stopDBPrefix)), db)
+	defer prefixStopDB.Close()
+
+	// Delegators have priority 0 in the current staker set. See addStaker.
+	p := wrappers.Packer{MaxSize: wrappers.LongLen + wrappers.ByteLen + hashing.HashLen}
+	p.PackLong(uint64(delegator.EndTime().Unix()))
+	p.PackByte(0)
+	p.PackFixedBytes(delegator.ID().Bytes())
+	if p.Err != nil {
+		return nil, false, fmt.Errorf("couldn't serialize validator key: %w", p.Err)
+	}
+
+	txBytes, err := prefixStopDB.Get(p.Bytes)
+	if err == database.ErrNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	tx := rewardTx{}
+	if err := Codec.Unmarshal(txBytes, &tx); err != nil {
+		return nil, false, err
+	}
+	return &tx, true, tx.Tx.Sign(vm.codec, nil)
+}
+
+// Persist that the subnet validator added by tx [txID] has weight [weight]
+// rather than the weight it was added with
+func (vm *VM) putSubnetValidatorWeight(db database.Database, txID ids.ID, weight uint64) error {
+	weightDB := prefixdb.NewNested([]byte(subnetVdrWeightDBPrefix), db)
+	defer weightDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(weight)
+	return weightDB.Put(txID.Bytes(), p.Bytes)
+}
+
+// Returns the weight of the subnet validator added by [tx]. This is the
+// weight it was added with, unless its weight has been updated since.
+func (vm *VM) getSubnetValidatorWeight(db database.Database, tx *UnsignedAddSubnetValidatorTx) (uint64, error) {
+	weightDB := prefixdb.NewNested([]byte(subnetVdrWeightDBPrefix), db)
+	defer weightDB.Close()
+
+	weightBytes, err := weightDB.Get(tx.ID().Bytes())
+	if err == database.ErrNotFound {
+		return tx.Validator.Weight(), nil
+	} else if err != nil {
+		return 0, err
+	}
+	p := wrappers.Packer{Bytes: weightBytes}
+	weight := p.UnpackLong()
+	return weight, p.Err
+}
+
+// Remove the updated weight, if any, of the subnet validator added by tx
+// [txID]
+func (vm *VM) deleteSubnetValidatorWeight(db database.Database, txID ids.ID) error {
+	weightDB := prefixdb.NewNested([]byte(subnetVdrWeightDBPrefix), db)
+	defer weightDB.Close()
+
+	return weightDB.Delete(txID.Bytes())
+}
+
+// Returns the tx that added [nodeID] to the current validator set of subnet
+// [subnetID], and true, if [nodeID] is currently validating the subnet
+func (vm *VM) getCurrentSubnetValidator(db database.Database, subnetID ids.ID, nodeID ids.ShortID) (*rewardTx, bool, error) {
+	iter := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", subnetID, stopDBPrefix)), db).NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		tx := rewardTx{}
+		if err := Codec.Unmarshal(iter.Value(), &tx); err != nil {
+			return nil, false, err
+		}
+		if err := tx.Tx.Sign(vm.codec, nil); err != nil {
+			return nil, false, err
+		}
+		if vdr, ok := tx.Tx.UnsignedTx.(*UnsignedAddSubnetValidatorTx); ok && vdr.Validator.NodeID.Equals(nodeID) {
+			return &tx, true, nil
+		}
+	}
+	return nil, false, iter.Error()
+}
+
+// Returns the tx that added [nodeID] to the pending validator set of subnet
+// [subnetID], and true, if [nodeID] will validate the subnet
+func (vm *VM) getPendingSubnetValidator(db database.Database, subnetID ids.ID, nodeID ids.ShortID) (*Tx, bool, error) {
+	iter := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", subnetID, startDBPrefix)), db).NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		tx := Tx{}
+		if err := Codec.Unmarshal(iter.Value(), &tx); err != nil {
+			return nil, false, err
+		}
+		if err := tx.Sign(vm.codec, nil); err != nil {
+			return nil, false, err
+		}
+		if vdr, ok := tx.UnsignedTx.(*UnsignedAddSubnetValidatorTx); ok && vdr.Validator.NodeID.Equals(nodeID) {
+			return &tx, true, nil
+		}
+	}
+	return nil, false, iter.Error()
+}
+
 // Returns true if [nodeID] is a validator (not a delegator) of subnet [subnetID]
 func (vm *VM) isValidator(db database.Database, subnetID ids.ID, nodeID ids.ShortID) (TimedTx, bool, error) {
 	iter := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", subnetID, stopDBPrefix)), db).NewIterator()
@@ -576,6 +908,46 @@ func (vm *VM) getSubnet(db database.Database, id ids.ID) (*Tx, TxError) {
 	return nil, permError{fmt.Errorf("couldn't find subnet with ID %s", id)}
 }
 
+// get the current owner of the subnet with the specified ID. This is the owner
+// set by the most recent TransferSubnetOwnershipTx or, if there is none, the
+// owner the subnet was created with.
+func (vm *VM) getSubnetOwner(db database.Database, id ids.ID) (verify.Verifiable, TxError) {
+	subnet, txErr := vm.getSubnet(db, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	ownerDB := prefixdb.NewNested([]byte(subnetOwnerDBPrefix), db)
+	defer ownerDB.Close()
+
+	ownerBytes, err := ownerDB.Get(id.Bytes())
+	switch {
+	case err == database.ErrNotFound:
+		return subnet.UnsignedTx.(*UnsignedCreateSubnetTx).Owner, nil
+	case err != nil:
+		return nil, tempError{err}
+	}
+
+	var owner verify.Verifiable
+	if err := Codec.Unmarshal(ownerBytes, &owner); err != nil {
+		return nil, tempError{err}
+	}
+	return owner, nil
+}
+
+// put the current owner of the subnet with the specified ID
+func (vm *VM) putSubnetOwner(db database.Database, id ids.ID, owner verify.Verifiable) error {
+	ownerBytes, err := Codec.Marshal(&owner)
+	if err != nil {
+		return err
+	}
+
+	ownerDB := prefixdb.NewNested([]byte(subnetOwnerDBPrefix), db)
+	defer ownerDB.Close()
+
+	return ownerDB.Put(id.Bytes(), ownerBytes)
+}
+
 // Returns the height of the preferred block
 func (vm *VM) preferredHeight() (uint64, error) {
 	preferred, err := vm.getBlock(vm.Preferred())
@@ -774,6 +1146,194 @@ func (vm *VM) deleteUptime(db database.Database, nodeID ids.ShortID) error {
 	return uptimeDB.Delete(nodeID.Bytes())
 }
 
+// Persist that [blkID] is the accepted block at height [height]
+func (vm *VM) putAcceptedBlockID(db database.Database, height uint64, blkID ids.ID) error {
+	heightDB := prefixdb.NewNested([]byte(blockHeightDBPrefix), db)
+	defer heightDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(height)
+	return heightDB.Put(p.Bytes, blkID.Bytes())
+}
+
+// Returns the ID of the accepted block at height [height]
+func (vm *VM) getAcceptedBlockID(db database.Database, height uint64) (ids.ID, error) {
+	heightDB := prefixdb.NewNested([]byte(blockHeightDBPrefix), db)
+	defer heightDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(height)
+	blkIDBytes, err := heightDB.Get(p.Bytes)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(blkIDBytes)
+}
+
+// Persist that the chain's timestamp was [timestamp] when the accepted block
+// [blkID] was executed
+func (vm *VM) putBlockTime(db database.Database, blkID ids.ID, timestamp time.Time) error {
+	blockTimeDB := prefixdb.NewNested([]byte(blockTimeDBPrefix), db)
+	defer blockTimeDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(uint64(timestamp.Unix()))
+	return blockTimeDB.Put(blkID.Bytes(), p.Bytes)
+}
+
+// Returns the chain's timestamp when the accepted block [blkID] was executed
+func (vm *VM) getBlockTime(db database.Database, blkID ids.ID) (time.Time, error) {
+	blockTimeDB := prefixdb.NewNested([]byte(blockTimeDBPrefix), db)
+	defer blockTimeDB.Close()
+
+	timestampBytes, err := blockTimeDB.Get(blkID.Bytes())
+	if err != nil {
+		return time.Time{}, err
+	}
+	p := wrappers.Packer{Bytes: timestampBytes}
+	timestamp := p.UnpackLong()
+	if p.Errored() {
+		return time.Time{}, p.Err
+	}
+	return time.Unix(int64(timestamp), 0), nil
+}
+
+// Persist that the tx [txID] is included in the block [blkID]
+func (vm *VM) putTxBlockID(db database.Database, txID ids.ID, blkID ids.ID) error {
+	txBlockDB := prefixdb.NewNested([]byte(txBlockDBPrefix), db)
+	defer txBlockDB.Close()
+
+	return txBlockDB.Put(txID.Bytes(), blkID.Bytes())
+}
+
+// Returns the ID of the block that includes the tx [txID]
+func (vm *VM) getTxBlockID(db database.Database, txID ids.ID) (ids.ID, error) {
+	txBlockDB := prefixdb.NewNested([]byte(txBlockDBPrefix), db)
+	defer txBlockDB.Close()
+
+	blkIDBytes, err := txBlockDB.Get(txID.Bytes())
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(blkIDBytes)
+}
+
+// indexBlocks indexes the timestamp and the txs of every block accepted up to
+// height [lastAcceptedHeight], if they were accepted before blocks were
+// indexed. The chain's timestamp starts at [genesisTimestamp] and only changes
+// when an AdvanceTimeTx is committed, so it's recomputed from the blocks.
+// Blocks must already be indexed by height.
+func (vm *VM) indexBlocks(db database.Database, lastAcceptedHeight uint64, genesisTimestamp time.Time) error {
+	indexedDB := prefixdb.NewNested([]byte(blocksIndexedDBPrefix), db)
+	defer indexedDB.Close()
+
+	if indexed, err := indexedDB.Has([]byte(blocksIndexedDBPrefix)); err != nil || indexed {
+		return err
+	}
+
+	timestamp := genesisTimestamp
+	var parent Block
+	for height := uint64(0); height <= lastAcceptedHeight; height++ {
+		blkID, err := vm.getAcceptedBlockID(db, height)
+		if err != nil {
+			return err
+		}
+		blk, err := vm.getBlock(blkID)
+		if err != nil {
+			return err
+		}
+		if err := vm.putBlockTime(db, blkID, timestamp); err != nil {
+			return err
+		}
+
+		var txs []*Tx
+		switch blk := blk.(type) {
+		case *ProposalBlock:
+			txs = []*Tx{&blk.Tx}
+		case *StandardBlock:
+			txs = blk.Txs
+		case *AtomicBlock:
+			txs = []*Tx{&blk.Tx}
+		case *Commit:
+			// Committing an AdvanceTimeTx advances the chain's timestamp for
+			// the blocks that follow
+			if proposal, ok := parent.(*ProposalBlock); ok {
+				if tx, ok := proposal.Tx.UnsignedTx.(*UnsignedAdvanceTimeTx); ok {
+					timestamp = tx.Timestamp()
+				}
+			}
+		}
+		for _, tx := range txs {
+			if err := vm.putTxBlockID(db, tx.ID(), blkID); err != nil {
+				return err
+			}
+		}
+		parent = blk
+	}
+	return indexedDB.Put([]byte(blocksIndexedDBPrefix), nil)
+}
+
+// Persist [blk], and record the keys that it and its status are stored under.
+// Nodes also store blocks that were never accepted, so blocks and their
+// statuses aren't part of the summarized state.
+func (vm *VM) putBlock(db database.Database, blk snowman.Block) error {
+	if err := vm.State.PutBlock(db, blk); err != nil {
+		return err
+	}
+	return vm.putBlockKeys(db, blk.ID())
+}
+
+// Persist that the block [blkID], and its status, are stored under their keys
+func (vm *VM) putBlockKeys(db database.Database, blkID ids.ID) error {
+	keysDB := prefixdb.NewNested([]byte(blockKeysDBPrefix), db)
+	defer keysDB.Close()
+
+	if err := keysDB.Put(blkID.Prefix(state.BlockTypeID).Bytes(), nil); err != nil {
+		return err
+	}
+	return keysDB.Put(blkID.Prefix(state.StatusTypeID).Bytes(), nil)
+}
+
+// indexBlockKeys records the keys of the blocks that were stored before the
+// keys of blocks were recorded. Blocks are stored under the hash of their ID,
+// so they're found by parsing each value stored under such a key.
+func (vm *VM) indexBlockKeys(db database.Database) error {
+	indexedDB := prefixdb.NewNested([]byte(blockKeysIndexedDBPrefix), db)
+	defer indexedDB.Close()
+
+	if indexed, err := indexedDB.Has([]byte(blockKeysIndexedDBPrefix)); err != nil || indexed {
+		return err
+	}
+
+	blkIDs := []ids.ID(nil)
+	iter := vm.DB.GetDatabase().NewIterator()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != hashing.HashLen {
+			continue
+		}
+		blk, err := vm.unmarshalBlockFunc(iter.Value())
+		if err != nil {
+			continue
+		}
+		if blkID := blk.ID(); bytes.Equal(blkID.Prefix(state.BlockTypeID).Bytes(), key) {
+			blkIDs = append(blkIDs, blkID)
+		}
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return err
+	}
+
+	for _, blkID := range blkIDs {
+		if err := vm.putBlockKeys(db, blkID); err != nil {
+			return err
+		}
+	}
+	return indexedDB.Put([]byte(blockKeysIndexedDBPrefix), nil)
+}
+
 // Unmarshal a Block from bytes and initialize it
 // The Block being unmarshaled must have had static type Block when it was marshaled
 // i.e. don't do:
@@ -0,0 +1,652 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// The state of the chain is summarized once every [stateSummaryInterval]
+// blocks, so that a bootstrapping node can sync its state rather than execute
+// every block since genesis.
+//
+// The summarized state is every key/value pair in the database that every node
+// that accepted the same blocks has. So, the following pairs aren't summarized:
+// blocks and their statuses, since nodes also store blocks that were never
+// accepted; indices that only serve the API, since a node only has them for the
+// blocks it executed; and pairs that only describe this node, such as the
+// uptimes it observed. The pairs are split into chunks in key order, and the
+// summary is the hash of each chunk, so nodes that accepted the same blocks
+// have identical summaries.
+//
+// The state is summarized in the background, from a snapshot of the database
+// taken once the block is accepted. Each chunk is written as soon as it's
+// complete, under the height of the block. The summary is written once every
+// chunk has been, in the same batch that deletes the chunks of the previous
+// summary.
+//
+// Atomic UTXOs are stored in shared memory, not in the database. The atomic
+// txs are indexed by the height of the block that accepted them, so a node
+// that synced the state applies the atomic txs accepted after its previous
+// last accepted block to shared memory.
+
+const (
+	// The state is summarized once a decision block at a multiple of this
+	// height is accepted
+	defaultStateSummaryInterval = 4096
+
+	// Maximum size of a chunk of the summarized state, in bytes
+	maxStateChunkSize = 1 << 20
+
+	stateSummaryDBPrefix = "stateSummary"
+	stateSyncDBPrefix    = "stateSync"
+
+	atomicTxDBPrefix         = "atomicTx"
+	atomicTxsIndexedDBPrefix = "atomicTxsIndexed"
+	atomicReplayDBPrefix     = "atomicReplay"
+)
+
+var (
+	stateSummaryKey = []byte("summary")
+
+	// Pairs with these prefixes aren't summarized, and are kept when the state
+	// is replaced by synced state
+	unsummarizedDBPrefixes = [][]byte{
+		hashing.ComputeHash256([]byte(stateSummaryDBPrefix)),
+		hashing.ComputeHash256([]byte(stateSyncDBPrefix)),
+		hashing.ComputeHash256([]byte(atomicReplayDBPrefix)),
+		hashing.ComputeHash256([]byte(uptimeDBPrefix)),
+		hashing.ComputeHash256([]byte(blockKeysDBPrefix)),
+		hashing.ComputeHash256([]byte(blockKeysIndexedDBPrefix)),
+		hashing.ComputeHash256([]byte(blockHeightDBPrefix)),
+		hashing.ComputeHash256([]byte(blockTimeDBPrefix)),
+		hashing.ComputeHash256([]byte(txBlockDBPrefix)),
+		hashing.ComputeHash256([]byte(blocksIndexedDBPrefix)),
+		hashing.ComputeHash256([]byte(stakingPeriodDBPrefix)),
+		hashing.ComputeHash256([]byte(stakingPeriodUptimeDBPrefix)),
+		hashing.ComputeHash256([]byte(nodeRewardHistoryDBPrefix)),
+		hashing.ComputeHash256([]byte(addrRewardHistoryDBPrefix)),
+		hashing.ComputeHash256([]byte(vdrWeightDiffDBPrefix)),
+		hashing.ComputeHash256([]byte(vdrHistoryStartDBPrefix)),
+	}
+
+	atomicReplayKey = []byte("next")
+
+	errNoStateSummary      = errors.New("the state hasn't been summarized")
+	errWrongStateSummary   = errors.New("the state summarized by this summary isn't available")
+	errWrongSummaryBlock   = errors.New("the summary isn't of the state after this block")
+	errUnknownStateChunk   = errors.New("the summary has no chunk with this index")
+	errInvalidStateChunk   = errors.New("the chunk doesn't match the summary")
+	errStateChunkOrder     = errors.New("chunks must be added in order")
+	errWrongSyncedBlock    = errors.New("the last accepted block of the synced state isn't the summarized block")
+	errMalformedStateChunk = errors.New("the chunk is malformed")
+	errSummaryAborted      = errors.New("the VM shut down")
+
+	_ block.StateSyncableVM = &VM{}
+)
+
+// stateSummary is a summary of the state of the chain once a block was
+// accepted
+type stateSummary struct {
+	BlockID ids.ID `serialize:"true"`
+	Height  uint64 `serialize:"true"`
+	// Hash of each chunk the state was split into
+	ChunkHashes []ids.ID `serialize:"true"`
+}
+
+// stateSync tracks the chunks added by the state sync in progress
+type stateSync struct {
+	// Hash of the summary the chunks are verified against
+	summaryID ids.ID
+	// Number of chunks added
+	numChunks uint32
+}
+
+// hasUnsummarizedPrefix returns true if [key] has a prefix whose pairs aren't
+// summarized
+func hasUnsummarizedPrefix(key []byte) bool {
+	for _, prefix := range unsummarizedDBPrefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSummarizedKey returns true if the pair with key [key] is summarized.
+// [blockKeysDB] holds the keys that blocks and their statuses are stored under.
+func isSummarizedKey(blockKeysDB database.KeyValueReader, key []byte) (bool, error) {
+	if hasUnsummarizedPrefix(key) {
+		return false, nil
+	}
+	// Blocks and their statuses are stored under the hash of their ID, so
+	// every other key is longer
+	if len(key) != hashing.HashLen {
+		return true, nil
+	}
+	isBlockKey, err := blockKeysDB.Has(key)
+	return !isBlockKey, err
+}
+
+// chunkKey returns the key that chunk [index] of the summary of the state at
+// [height] is stored under
+func chunkKey(height uint64, index uint32) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+wrappers.IntLen)}
+	p.PackLong(height)
+	p.PackInt(index)
+	return p.Bytes
+}
+
+// summarizeState starts summarizing the state of the chain if the block
+// [blkID], which was just accepted and committed, is at a height the state is
+// summarized at
+func (vm *VM) summarizeState(blkID ids.ID, height uint64) {
+	if height == 0 || height%vm.stateSummaryInterval != 0 {
+		return
+	}
+	select {
+	case vm.summarizing <- struct{}{}:
+	default:
+		vm.Ctx.Log.Warn("not summarizing the state after block %s as the previous summary isn't finished", blkID)
+		return
+	}
+
+	// Chunks are read and written outside of [vm.DB] so that they're never
+	// held in memory by it. The iterator reads a snapshot of the database, so
+	// blocks can be accepted while the state is summarized.
+	db := vm.DB.GetDatabase()
+	iter := db.NewIterator()
+	vm.summaryWG.Add(1)
+	go vm.Ctx.Log.RecoverAndPanic(func() {
+		defer func() {
+			<-vm.summarizing
+			vm.summaryWG.Done()
+		}()
+
+		if err := vm.writeStateSummary(db, iter, blkID, height); err != nil {
+			vm.Ctx.Log.Error("failed to summarize the state after block %s: %s", blkID, err)
+		}
+	})
+}
+
+// writeStateSummary summarizes the state in [iter], which is the state of [db]
+// once the block [blkID] at [height] was accepted
+func (vm *VM) writeStateSummary(db database.Database, iter database.Iterator, blkID ids.ID, height uint64) error {
+	defer iter.Release()
+
+	summaryDB := prefixdb.NewNested([]byte(stateSummaryDBPrefix), db)
+	defer summaryDB.Close()
+	blockKeysDB := prefixdb.NewNested([]byte(blockKeysDBPrefix), db)
+	defer blockKeysDB.Close()
+
+	summary := stateSummary{
+		BlockID: blkID,
+		Height:  height,
+	}
+	p := wrappers.Packer{MaxSize: maxStateChunkSize}
+	putChunk := func() error {
+		if p.Errored() {
+			return p.Err
+		}
+		select {
+		case <-vm.closing:
+			return errSummaryAborted
+		default:
+		}
+		index := uint32(len(summary.ChunkHashes))
+		if err := summaryDB.Put(chunkKey(height, index), p.Bytes); err != nil {
+			return err
+		}
+		summary.ChunkHashes = append(summary.ChunkHashes, ids.NewID(hashing.ComputeHash256Array(p.Bytes)))
+		p = wrappers.Packer{MaxSize: maxStateChunkSize}
+		return nil
+	}
+	for iter.Next() {
+		key := iter.Key()
+		if summarized, err := isSummarizedKey(blockKeysDB, key); err != nil {
+			return err
+		} else if !summarized {
+			continue
+		}
+		value := iter.Value()
+		pairSize := 2*wrappers.IntLen + len(key) + len(value)
+		if len(p.Bytes) > 0 && len(p.Bytes)+pairSize > maxStateChunkSize {
+			if err := putChunk(); err != nil {
+				return err
+			}
+		}
+		if pairSize > maxStateChunkSize {
+			p.MaxSize = pairSize
+		}
+		p.PackBytes(key)
+		p.PackBytes(value)
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if len(p.Bytes) > 0 {
+		if err := putChunk(); err != nil {
+			return err
+		}
+	}
+
+	summaryBytes, err := Codec.Marshal(&summary)
+	if err != nil {
+		return err
+	}
+	batch := summaryDB.NewBatch()
+	if err := batch.Put(stateSummaryKey, summaryBytes); err != nil {
+		return err
+	}
+	// Delete the chunks of every other summary
+	chunkIter := summaryDB.NewIterator()
+	for chunkIter.Next() {
+		key := chunkIter.Key()
+		if len(key) != wrappers.LongLen+wrappers.IntLen {
+			continue
+		}
+		chunkHeight := wrappers.Packer{Bytes: key}
+		if chunkHeight.UnpackLong() == height {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			chunkIter.Release()
+			return err
+		}
+	}
+	err = chunkIter.Error()
+	chunkIter.Release()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	vm.Ctx.Log.Info("summarized the state after block %s at height %d in %d chunks", blkID, height, len(summary.ChunkHashes))
+	return nil
+}
+
+// StateSummary implements the block.StateSyncableVM interface
+func (vm *VM) StateSummary() ([]byte, error) {
+	summaryDB := prefixdb.NewNested([]byte(stateSummaryDBPrefix), vm.DB.GetDatabase())
+	defer summaryDB.Close()
+
+	summaryBytes, err := summaryDB.Get(stateSummaryKey)
+	if err == database.ErrNotFound {
+		return nil, errNoStateSummary
+	}
+	return summaryBytes, err
+}
+
+// ParseStateSummary implements the block.StateSyncableVM interface
+func (vm *VM) ParseStateSummary(summaryBytes []byte) (ids.ID, uint64, error) {
+	summary := stateSummary{}
+	if err := Codec.Unmarshal(summaryBytes, &summary); err != nil {
+		return ids.ID{}, 0, err
+	}
+	return summary.BlockID, summary.Height, nil
+}
+
+// GetStateChunk implements the block.StateSyncableVM interface
+func (vm *VM) GetStateChunk(summaryID ids.ID, index uint32) ([]byte, error) {
+	summaryBytes, err := vm.StateSummary()
+	if err != nil {
+		return nil, err
+	}
+	if !summaryID.Equals(ids.NewID(hashing.ComputeHash256Array(summaryBytes))) {
+		return nil, errWrongStateSummary
+	}
+	_, height, err := vm.ParseStateSummary(summaryBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryDB := prefixdb.NewNested([]byte(stateSummaryDBPrefix), vm.DB.GetDatabase())
+	defer summaryDB.Close()
+
+	chunk, err := summaryDB.Get(chunkKey(height, index))
+	if err == database.ErrNotFound {
+		return nil, errUnknownStateChunk
+	}
+	return chunk, err
+}
+
+// PutStateChunk implements the block.StateSyncableVM interface
+func (vm *VM) PutStateChunk(summaryBytes []byte, blk snowman.Block, index uint32, chunk []byte) (bool, error) {
+	summary := stateSummary{}
+	if err := Codec.Unmarshal(summaryBytes, &summary); err != nil {
+		return false, err
+	}
+	blkID := blk.ID()
+	pBlk, ok := blk.(Block)
+	if !ok || !summary.BlockID.Equals(blkID) || summary.Height != pBlk.Height() {
+		return false, errWrongSummaryBlock
+	}
+	if index >= uint32(len(summary.ChunkHashes)) {
+		return false, errUnknownStateChunk
+	}
+	if !summary.ChunkHashes[index].Equals(ids.NewID(hashing.ComputeHash256Array(chunk))) {
+		return false, errInvalidStateChunk
+	}
+	if _, err := unpackStateChunk(chunk); err != nil {
+		return false, err
+	}
+
+	// The chunks are staged outside of [vm.DB] until every chunk is added, so
+	// that the state is unchanged until then
+	db := vm.DB.GetDatabase()
+	syncDB := prefixdb.NewNested([]byte(stateSyncDBPrefix), db)
+	defer syncDB.Close()
+
+	summaryID := ids.NewID(hashing.ComputeHash256Array(summaryBytes))
+	if index == 0 {
+		if err := clearDB(syncDB); err != nil {
+			return false, err
+		}
+		vm.stateSync = &stateSync{summaryID: summaryID}
+	} else if vm.stateSync == nil || !vm.stateSync.summaryID.Equals(summaryID) || vm.stateSync.numChunks != index {
+		return false, errStateChunkOrder
+	}
+	if err := syncDB.Put(chunkKey(0, index), chunk); err != nil {
+		return false, err
+	}
+	vm.stateSync.numChunks++
+	if int(vm.stateSync.numChunks) < len(summary.ChunkHashes) {
+		return false, nil
+	}
+
+	vm.stateSync = nil
+	if err := vm.replaceState(syncDB, uint32(len(summary.ChunkHashes)), pBlk); err != nil {
+		return false, err
+	}
+	if err := clearDB(syncDB); err != nil {
+		return false, err
+	}
+	if !vm.LastAccepted().Equals(blkID) {
+		return false, errWrongSyncedBlock
+	}
+	if err := vm.replayAtomicTxs(); err != nil {
+		return false, err
+	}
+	vm.Ctx.Log.Info("synced the state after block %s at height %d", blkID, summary.Height)
+	return true, nil
+}
+
+// replaceState replaces the state of the chain with the [numChunks] chunks in
+// [syncDB], which are the state once [blk] was accepted, and reloads the VM
+// from the new state
+func (vm *VM) replaceState(syncDB database.Database, numChunks uint32, blk Block) error {
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		return err
+	}
+	prevHeight := lastAccepted.Height()
+
+	blockKeysDB := prefixdb.NewNested([]byte(blockKeysDBPrefix), vm.DB)
+	defer blockKeysDB.Close()
+
+	keys := [][]byte(nil)
+	iter := vm.DB.NewIterator()
+	for iter.Next() {
+		key := iter.Key()
+		if summarized, err := isSummarizedKey(blockKeysDB, key); err != nil {
+			iter.Release()
+			return err
+		} else if summarized {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+	}
+	if err := iter.Error(); err != nil {
+		iter.Release()
+		return err
+	}
+	iter.Release()
+	for _, key := range keys {
+		if err := vm.DB.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	for i := uint32(0); i < numChunks; i++ {
+		chunk, err := syncDB.Get(chunkKey(0, i))
+		if err != nil {
+			return err
+		}
+		pairs, err := unpackStateChunk(chunk)
+		if err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			if err := vm.DB.Put(pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// [blk] isn't part of the synced state, so it's stored as accepted, and
+	// indexed, here. The blocks before it aren't stored, so the validator sets
+	// are recorded from it onward.
+	blkID := blk.ID()
+	if err := vm.putBlock(vm.DB, blk); err != nil {
+		return err
+	}
+	if err := vm.State.PutStatus(vm.DB, blkID, choices.Accepted); err != nil {
+		return err
+	}
+	if err := vm.putAcceptedBlockID(vm.DB, blk.Height(), blkID); err != nil {
+		return err
+	}
+	timestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		return err
+	}
+	if err := vm.putBlockTime(vm.DB, blkID, timestamp); err != nil {
+		return err
+	}
+	if err := vm.putValidatorHistoryStart(vm.DB, blk.Height()); err != nil {
+		return err
+	}
+	// The atomic txs accepted after the previous last accepted block haven't
+	// been applied to shared memory
+	if err := vm.putAtomicReplayStart(vm.DB, prevHeight+1); err != nil {
+		return err
+	}
+	if err := vm.DB.Commit(); err != nil {
+		return err
+	}
+
+	// Reload the VM from the new state
+	lastAcceptedID, err := vm.State.GetLastAccepted(vm.DB)
+	if err != nil {
+		return err
+	}
+	vm.LastAcceptedID = lastAcceptedID
+	vm.currentBlocks = make(map[[32]byte]Block)
+	vm.droppedTxCache.Flush()
+	vm.SetPreference(lastAcceptedID)
+	if err := vm.initSubnets(); err != nil {
+		return err
+	}
+	return vm.initBlockchains()
+}
+
+// unpackStateChunk returns the key/value pairs in [chunk]
+func unpackStateChunk(chunk []byte) ([][2][]byte, error) {
+	pairs := [][2][]byte(nil)
+	p := wrappers.Packer{Bytes: chunk}
+	for p.Offset < len(chunk) && !p.Errored() {
+		key := p.UnpackBytes()
+		value := p.UnpackBytes()
+		if hasUnsummarizedPrefix(key) {
+			return nil, fmt.Errorf("%w: has a key that isn't summarized", errMalformedStateChunk)
+		}
+		pairs = append(pairs, [2][]byte{key, value})
+	}
+	if p.Errored() {
+		return nil, fmt.Errorf("%w: %s", errMalformedStateChunk, p.Err)
+	}
+	return pairs, nil
+}
+
+// clearDB deletes every key/value pair in [db]
+func clearDB(db database.Database) error {
+	keys := [][]byte(nil)
+	iter := db.NewIterator()
+	for iter.Next() {
+		keys = append(keys, append([]byte(nil), iter.Key()...))
+	}
+	if err := iter.Error(); err != nil {
+		iter.Release()
+		return err
+	}
+	iter.Release()
+	for _, key := range keys {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// atomicTxKey returns the key that the atomic tx [txID], accepted at [height],
+// is indexed under
+func atomicTxKey(height uint64, txID ids.ID) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+hashing.HashLen)}
+	p.PackLong(height)
+	p.PackFixedBytes(txID.Bytes())
+	return p.Bytes
+}
+
+// Persist that the atomic tx [txID] was accepted at height [height]
+func (vm *VM) putAtomicTx(db database.Database, height uint64, txID ids.ID) error {
+	atomicDB := prefixdb.NewNested([]byte(atomicTxDBPrefix), db)
+	defer atomicDB.Close()
+
+	return atomicDB.Put(atomicTxKey(height, txID), nil)
+}
+
+// indexAtomicTxs indexes the atomic txs accepted up to height
+// [lastAcceptedHeight], if they were accepted before atomic txs were indexed.
+// Blocks must already be indexed by height.
+func (vm *VM) indexAtomicTxs(db database.Database, lastAcceptedHeight uint64) error {
+	indexedDB := prefixdb.NewNested([]byte(atomicTxsIndexedDBPrefix), db)
+	defer indexedDB.Close()
+
+	if indexed, err := indexedDB.Has([]byte(atomicTxsIndexedDBPrefix)); err != nil || indexed {
+		return err
+	}
+
+	for height := uint64(0); height <= lastAcceptedHeight; height++ {
+		blkID, err := vm.getAcceptedBlockID(db, height)
+		if err != nil {
+			return err
+		}
+		blk, err := vm.getBlock(blkID)
+		if err != nil {
+			return err
+		}
+		if blk, ok := blk.(*AtomicBlock); ok {
+			if err := vm.putAtomicTx(db, height, blk.Tx.ID()); err != nil {
+				return err
+			}
+		}
+	}
+	return indexedDB.Put([]byte(atomicTxsIndexedDBPrefix), nil)
+}
+
+// Persist that the atomic txs accepted at [height] and after haven't been
+// applied to shared memory
+func (vm *VM) putAtomicReplayStart(db database.Database, height uint64) error {
+	replayDB := prefixdb.NewNested([]byte(atomicReplayDBPrefix), db)
+	defer replayDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(height)
+	return replayDB.Put(atomicReplayKey, p.Bytes)
+}
+
+// replayAtomicTxs applies the atomic txs that were accepted while the state
+// was synced to shared memory, if there are any. Each tx is applied in the
+// same batch that records it was applied, so a node that shuts down while
+// applying them resumes once it restarts.
+func (vm *VM) replayAtomicTxs() error {
+	replayDB := prefixdb.NewNested([]byte(atomicReplayDBPrefix), vm.DB)
+	defer replayDB.Close()
+
+	start, err := replayDB.Get(atomicReplayKey)
+	if err == database.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	atomicDB := prefixdb.NewNested([]byte(atomicTxDBPrefix), vm.DB)
+	defer atomicDB.Close()
+
+	keys := [][]byte(nil)
+	iter := atomicDB.NewIteratorWithStart(start)
+	for iter.Next() {
+		keys = append(keys, append([]byte(nil), iter.Key()...))
+	}
+	if err := iter.Error(); err != nil {
+		iter.Release()
+		return err
+	}
+	iter.Release()
+
+	for _, key := range keys {
+		txID, err := ids.ToID(key[wrappers.LongLen:])
+		if err != nil {
+			return err
+		}
+		txBytes, err := vm.getTx(vm.DB, txID)
+		if err != nil {
+			return err
+		}
+		tx := Tx{}
+		if err := vm.codec.Unmarshal(txBytes, &tx); err != nil {
+			return err
+		}
+		if err := tx.Sign(vm.codec, nil); err != nil {
+			return err
+		}
+		utx, ok := tx.UnsignedTx.(UnsignedAtomicTx)
+		if !ok {
+			return errWrongTxType
+		}
+
+		// The next tx to apply is the first one after this tx
+		if err := replayDB.Put(atomicReplayKey, append(key, 0)); err != nil {
+			return err
+		}
+		batch, err := vm.DB.CommitBatch()
+		if err != nil {
+			return err
+		}
+		if err := utx.Accept(vm.Ctx, batch); err != nil {
+			vm.DB.Abort()
+			return err
+		}
+		vm.DB.Abort()
+	}
+
+	if err := replayDB.Delete(atomicReplayKey); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		vm.Ctx.Log.Info("applied %d atomic txs accepted while the state was synced to shared memory", len(keys))
+	}
+	return vm.DB.Commit()
+}
@@ -0,0 +1,170 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+)
+
+func TestStateSync(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.stateSummaryInterval = 1
+	vm.SetPreference(vm.LastAccepted())
+
+	if _, err := vm.StateSummary(); err == nil {
+		t.Fatal("should have errored because the state hasn't been summarized")
+	}
+
+	exportTx, err := vm.newExportTx(
+		defaultTxFee,
+		vm.Ctx.XChainID,
+		keys[1].PublicKey().Address(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.issueTx(exportTx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	vm.summaryWG.Wait()
+
+	summary, err := vm.StateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blkID, height, err := vm.ParseStateSummary(summary)
+	if err != nil {
+		t.Fatal(err)
+	} else if !blkID.Equals(blk.ID()) {
+		t.Fatalf("summarized the state after block %s, expected %s", blkID, blk.ID())
+	} else if height != blk.(Block).Height() {
+		t.Fatalf("summarized the state at height %d, expected %d", height, blk.(Block).Height())
+	}
+	if _, err := vm.GetStateChunk(ids.Empty, 0); err == nil {
+		t.Fatal("should have errored because the state wasn't summarized by that summary")
+	}
+	summaryID := ids.NewID(hashing.ComputeHash256Array(summary))
+
+	chunks := [][]byte(nil)
+	for {
+		chunk, err := vm.GetStateChunk(summaryID, uint32(len(chunks)))
+		if err != nil {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("should have summarized the state in at least one chunk")
+	}
+
+	// A node that accepted the same block has an identical summary, even
+	// though it also stored a block that was never accepted
+	otherVM, _ := defaultVM()
+	otherVM.Ctx.Lock.Lock()
+	defer func() {
+		otherVM.Shutdown()
+		otherVM.Ctx.Lock.Unlock()
+	}()
+	otherVM.stateSummaryInterval = 1
+	otherVM.SetPreference(otherVM.LastAccepted())
+
+	createSubnetTx, err := otherVM.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := otherVM.issueTx(createSubnetTx); err != nil {
+		t.Fatal(err)
+	} else if _, err := otherVM.BuildBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if otherBlk, err := otherVM.ParseBlock(blk.Bytes()); err != nil {
+		t.Fatal(err)
+	} else if err := otherBlk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := otherBlk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	otherVM.summaryWG.Wait()
+	if otherSummary, err := otherVM.StateSummary(); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(otherSummary, summary) {
+		t.Fatal("nodes that accepted the same block should have identical summaries")
+	}
+
+	syncVM, syncDB := defaultVM()
+	syncVM.Ctx.Lock.Lock()
+	defer func() {
+		syncVM.Shutdown()
+		syncVM.Ctx.Lock.Unlock()
+	}()
+
+	m := &atomic.Memory{}
+	m.Initialize(logging.NoLog{}, prefixdb.New([]byte{5}, syncDB))
+	syncVM.Ctx.SharedMemory = m.NewSharedMemory(syncVM.Ctx.ChainID)
+	xChainSharedMemory := m.NewSharedMemory(syncVM.Ctx.XChainID)
+
+	syncBlk, err := syncVM.ParseBlock(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncVM.PutStateChunk(summary, syncBlk, 0, append(chunks[0], 0)); err == nil {
+		t.Fatal("should have errored because the chunk doesn't match the summary")
+	}
+	if len(chunks) > 1 {
+		if _, err := syncVM.PutStateChunk(summary, syncBlk, 1, chunks[1]); err == nil {
+			t.Fatal("should have errored because the chunks weren't added in order")
+		}
+	}
+	for i, chunk := range chunks {
+		done, err := syncVM.PutStateChunk(summary, syncBlk, uint32(i), chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done != (i == len(chunks)-1) {
+			t.Fatalf("chunk %d of %d reported done as %v", i, len(chunks), done)
+		}
+	}
+
+	if lastAcceptedID := syncVM.LastAccepted(); !lastAcceptedID.Equals(blkID) {
+		t.Fatalf("last accepted block is %s, expected %s", lastAcceptedID, blkID)
+	} else if status, err := syncVM.getStatus(syncVM.DB, exportTx.ID()); err != nil {
+		t.Fatal(err)
+	} else if status != Committed {
+		t.Fatalf("status should be Committed but is %s", status)
+	}
+
+	// The exported UTXO was put into shared memory
+	utxoID := djtx.UTXOID{
+		TxID:        exportTx.ID(),
+		OutputIndex: uint32(len(exportTx.UnsignedTx.(*UnsignedExportTx).Outs)),
+	}
+	if _, err := xChainSharedMemory.Get(syncVM.Ctx.ChainID, [][]byte{utxoID.InputID().Bytes()}); err != nil {
+		t.Fatalf("should have applied the export to shared memory but didn't: %s", err)
+	}
+}
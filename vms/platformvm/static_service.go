@@ -5,12 +5,14 @@ package platformvm
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
@@ -24,6 +26,7 @@ import (
 var (
 	errUTXOHasNoValue       = errors.New("genesis UTXO has no value")
 	errValidatorAddsNoValue = errors.New("validator would have already unstaked")
+	errInvalidGenesisBytes  = errors.New("genesis bytes aren't in canonical form")
 )
 
 // StaticService defines the static API methods exposed by the platform VM
@@ -64,6 +67,9 @@ type APIPrimaryValidator struct {
 	DelegationFee   json.Float32  `json:"delegationFee"`
 	Uptime          *json.Float32 `json:"uptime,omitempty"`
 	Connected       *bool         `json:"connected,omitempty"`
+	// Amount that can still be delegated to the validator for the remainder
+	// of its validation period
+	DelegationCapacity *json.Uint64 `json:"delegationCapacity,omitempty"`
 }
 
 // APIPrimaryDelegator is the repr. of a primary network delegator sent over APIs.
@@ -106,15 +112,18 @@ type APIChain struct {
 // [Validators] are the validators of the primary network at genesis.
 // [Chains] are the chains that exist at genesis.
 // [Time] is the Platform Chain's time at network genesis.
+// [StakingParameters] are the economic parameters of staking. If nil, the
+// default parameters are used.
 type BuildGenesisArgs struct {
-	DjtxAssetID   ids.ID                `json:"djtxAssetID"`
-	NetworkID     json.Uint32           `json:"address"`
-	UTXOs         []APIUTXO             `json:"utxos"`
-	Validators    []APIPrimaryValidator `json:"primaryNetworkValidators"`
-	Chains        []APIChain            `json:"chains"`
-	Time          json.Uint64           `json:"time"`
-	InitialSupply json.Uint64           `json:"initialSupply"`
-	Message       string                `json:"message"`
+	DjtxAssetID       ids.ID                `json:"djtxAssetID"`
+	NetworkID         json.Uint32           `json:"address"`
+	UTXOs             []APIUTXO             `json:"utxos"`
+	Validators        []APIPrimaryValidator `json:"primaryNetworkValidators"`
+	Chains            []APIChain            `json:"chains"`
+	Time              json.Uint64           `json:"time"`
+	InitialSupply     json.Uint64           `json:"initialSupply"`
+	Message           string                `json:"message"`
+	StakingParameters *APIStakingParameters `json:"stakingParameters"`
 }
 
 // BuildGenesisReply is the reply from BuildGenesis
@@ -130,6 +139,77 @@ type Genesis struct {
 	Timestamp  uint64       `serialize:"true"`
 	// InitialSupply uint64       `serialize:"true"`
 	Message string `serialize:"true"`
+
+	// The staking parameters aren't serialized with the rest of the genesis.
+	// If they aren't the default staking parameters, the genesis is wrapped
+	// with them, so that the genesis of existing networks doesn't change.
+	StakingParameters StakingParameters
+}
+
+// Serialized genesis bytes start with a version. A genesis that uses the
+// default staking parameters is serialized by the codec, so its bytes start
+// with the codec's version. Otherwise, its bytes start with
+// [stakingGenesisVersion], followed by the length-prefixed bytes of the
+// genesis and of its staking parameters.
+const (
+	codecGenesisVersion   uint16 = 0
+	stakingGenesisVersion uint16 = 1
+)
+
+// Bytes returns the byte representation of this genesis
+func (g *Genesis) Bytes() ([]byte, error) {
+	bytes, err := Codec.Marshal(g)
+	if err != nil || g.StakingParameters == DefaultStakingParameters() {
+		return bytes, err
+	}
+	paramsBytes, err := Codec.Marshal(&g.StakingParameters)
+	if err != nil {
+		return nil, err
+	}
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.ShortLen+2*wrappers.IntLen+len(bytes)+len(paramsBytes))}
+	p.PackShort(stakingGenesisVersion)
+	p.PackBytes(bytes)
+	p.PackBytes(paramsBytes)
+	return p.Bytes, p.Err
+}
+
+// ParseGenesis parses the genesis of the Platform Chain from [genesisBytes].
+// If the genesis isn't wrapped with staking parameters, the default staking
+// parameters are used.
+func ParseGenesis(genesisBytes []byte) (*Genesis, error) {
+	genesis := &Genesis{StakingParameters: DefaultStakingParameters()}
+	p := wrappers.Packer{Bytes: genesisBytes}
+	switch version := p.UnpackShort(); {
+	case p.Errored():
+		return nil, errInvalidGenesisBytes
+	case version == codecGenesisVersion:
+		if err := Codec.Unmarshal(genesisBytes, genesis); err != nil {
+			return nil, err
+		}
+		return genesis, nil
+	case version != stakingGenesisVersion:
+		return nil, fmt.Errorf("unknown genesis version %d", version)
+	}
+
+	bytes := p.UnpackBytes()
+	paramsBytes := p.UnpackBytes()
+	switch {
+	case p.Errored():
+		return nil, fmt.Errorf("couldn't unpack genesis: %w", p.Err)
+	case p.Offset != len(genesisBytes):
+		return nil, errInvalidGenesisBytes
+	}
+	if err := Codec.Unmarshal(bytes, genesis); err != nil {
+		return nil, err
+	}
+	if err := Codec.Unmarshal(paramsBytes, &genesis.StakingParameters); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal staking parameters: %w", err)
+	}
+	// A genesis with the default staking parameters must not be wrapped
+	if genesis.StakingParameters == DefaultStakingParameters() {
+		return nil, errInvalidGenesisBytes
+	}
+	return genesis, nil
 }
 
 var (
@@ -164,6 +244,14 @@ func bech32ToID(address string) (ids.ShortID, error) {
 
 // BuildGenesis build the genesis state of the Platform Chain (and thereby the Avalanche network.)
 func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, reply *BuildGenesisReply) error {
+	stakingParams := DefaultStakingParameters()
+	if args.StakingParameters != nil {
+		stakingParams = args.StakingParameters.StakingParameters()
+	}
+	if err := stakingParams.Verify(); err != nil {
+		return fmt.Errorf("invalid staking parameters: %w", err)
+	}
+
 	// Specify the UTXOs on the Platform chain that exist at genesis.
 	utxos := make([]*djtx.UTXO, 0, len(args.UTXOs))
 	for i, utxo := range args.UTXOs {
@@ -275,10 +363,12 @@ func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, r
 		Chains:     chains,
 		Timestamp:  uint64(args.Time),
 		Message:    args.Message,
+
+		StakingParameters: stakingParams,
 	}
 
 	// Marshal genesis to bytes
-	bytes, err := Codec.Marshal(genesis)
+	bytes, err := genesis.Bytes()
 	reply.Bytes.Bytes = bytes
 	return err
 }
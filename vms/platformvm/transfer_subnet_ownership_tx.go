@@ -0,0 +1,161 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errTransferPrimaryNetwork = errors.New("can't transfer ownership of the primary network")
+
+	_ UnsignedDecisionTx = &UnsignedTransferSubnetOwnershipTx{}
+)
+
+// UnsignedTransferSubnetOwnershipTx is an unsigned transferSubnetOwnershipTx.
+// It replaces the owner of a subnet, which is authorized to manage the subnet.
+type UnsignedTransferSubnetOwnershipTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the subnet whose owner is replaced
+	Subnet ids.ID `serialize:"true" json:"subnetID"`
+	// Auth that will be allowing the owner to be replaced
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+	// Who is authorized to manage the subnet once this tx is accepted
+	Owner verify.Verifiable `serialize:"true" json:"newOwner"`
+}
+
+// Verify return nil iff [tx] is valid
+func (tx *UnsignedTransferSubnetOwnershipTx) Verify(
+	ctx *snow.Context,
+	c codec.Codec,
+	feeAmount uint64,
+	feeAssetID ids.ID,
+) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.Subnet.Equals(constants.PrimaryNetworkID):
+		return errTransferPrimaryNetwork
+	}
+
+	if err := tx.BaseTx.Verify(ctx, c); err != nil {
+		return err
+	}
+	if err := verify.All(tx.SubnetAuth, tx.Owner); err != nil {
+		return err
+	}
+
+	// cache that this is valid
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// SemanticVerify returns nil if [tx] is valid given the state in [db]
+func (tx *UnsignedTransferSubnetOwnershipTx) SemanticVerify(
+	vm *VM,
+	db database.Database,
+	stx *Tx,
+) (
+	func() error,
+	TxError,
+) {
+	// Verify the tx is well-formed
+	if len(stx.Creds) == 0 {
+		return nil, permError{errWrongNumberOfCredentials}
+	}
+	if err := tx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		return nil, permError{err}
+	}
+
+	baseTxCredsLen := len(stx.Creds) - 1
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	subnetCred := stx.Creds[baseTxCredsLen]
+
+	// Verify that the current owner of the subnet authorized this tx
+	owner, txErr := vm.getSubnetOwner(db, tx.Subnet)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, owner); err != nil {
+		return nil, permError{err}
+	}
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, vm.txFee, vm.Ctx.DJTXAssetID); err != nil {
+		return nil, err
+	}
+
+	txID := tx.ID()
+
+	// Consume the UTXOS
+	if err := vm.consumeInputs(db, tx.Ins); err != nil {
+		return nil, tempError{err}
+	}
+	// Produce the UTXOS
+	if err := vm.produceOutputs(db, txID, tx.Outs); err != nil {
+		return nil, tempError{err}
+	}
+	// Replace the owner of the subnet
+	if err := vm.putSubnetOwner(db, tx.Subnet, tx.Owner); err != nil {
+		return nil, tempError{err}
+	}
+	return nil, nil
+}
+
+// [ownerAddrs] must be unique. They will be sorted by this method.
+func (vm *VM) newTransferSubnetOwnershipTx(
+	subnetID ids.ID, // ID of the subnet whose owner is replaced
+	threshold uint32, // [threshold] of [ownerAddrs] needed to manage the subnet
+	ownerAddrs []ids.ShortID, // new control addresses of the subnet
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for authorizing the transfer and paying the fee
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.txFee)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	subnetAuth, subnetSigners, err := vm.authorize(vm.DB, subnetID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
+	}
+	signers = append(signers, subnetSigners)
+
+	// Sort control addresses
+	ids.SortShortIDs(ownerAddrs)
+
+	// Create the tx
+	utx := &UnsignedTransferSubnetOwnershipTx{
+		BaseTx: BaseTx{BaseTx: djtx.BaseTx{
+			NetworkID:    vm.Ctx.NetworkID,
+			BlockchainID: vm.Ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		Subnet:     subnetID,
+		SubnetAuth: subnetAuth,
+		Owner: &secp256k1fx.OutputOwners{
+			Threshold: threshold,
+			Addrs:     ownerAddrs,
+		},
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(vm.codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID)
+}
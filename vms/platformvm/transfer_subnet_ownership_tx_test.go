@@ -0,0 +1,164 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestTransferSubnetOwnershipTxSyntacticVerify(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// Case: tx is nil
+	var unsignedTx *UnsignedTransferSubnetOwnershipTx
+	if err := unsignedTx.Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err == nil {
+		t.Fatal("should have errored because tx is nil")
+	}
+
+	// Case: valid tx
+	tx, err := vm.newTransferSubnetOwnershipTx(
+		testSubnet1.ID(),
+		1,
+		[]ids.ShortID{keys[3].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Case: new owner is invalid
+	tx.UnsignedTx.(*UnsignedTransferSubnetOwnershipTx).syntacticallyVerified = false
+	tx.UnsignedTx.(*UnsignedTransferSubnetOwnershipTx).Owner = &secp256k1fx.OutputOwners{
+		Threshold: 2,
+		Addrs:     []ids.ShortID{keys[3].PublicKey().Address()},
+	}
+	if err := tx.UnsignedTx.(*UnsignedTransferSubnetOwnershipTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err == nil {
+		t.Fatal("should have errored because the threshold is larger than the number of control keys")
+	}
+
+	// Case: transferring ownership of the primary network
+	tx.UnsignedTx.(*UnsignedTransferSubnetOwnershipTx).Subnet = constants.PrimaryNetworkID
+	if err := tx.UnsignedTx.(*UnsignedTransferSubnetOwnershipTx).Verify(vm.Ctx, vm.codec, vm.txFee, vm.Ctx.DJTXAssetID); err != errTransferPrimaryNetwork {
+		t.Fatalf("expected %q but got %v", errTransferPrimaryNetwork, err)
+	}
+}
+
+func TestTransferSubnetOwnershipAccept(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	oldControlKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+	newControlKey := keys[3]
+
+	// Case: the new owner can't transfer the ownership before it owns the
+	// subnet
+	if _, err := vm.newTransferSubnetOwnershipTx(
+		testSubnet1.ID(),
+		1,
+		[]ids.ShortID{newControlKey.PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{newControlKey},
+	); err == nil {
+		t.Fatal("should have errored because the key doesn't control the subnet")
+	}
+
+	tx, err := vm.newTransferSubnetOwnershipTx(
+		testSubnet1.ID(),
+		1,
+		[]ids.ShortID{newControlKey.PublicKey().Address()},
+		oldControlKeys,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := vm.getStatus(vm.DB, tx.ID()); err != nil {
+		t.Fatal(err)
+	} else if status != Committed {
+		t.Fatalf("status should be Committed but is %s", status)
+	}
+
+	// The subnet is reported with its new owner
+	service := &Service{vm: vm}
+	reply := GetSubnetsResponse{}
+	if err := service.GetSubnets(nil, &GetSubnetsArgs{IDs: []ids.ID{testSubnet1.ID()}}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	newControlAddr, err := vm.FormatLocalAddress(newControlKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Subnets) != 1 {
+		t.Fatalf("expected 1 subnet but got %d", len(reply.Subnets))
+	} else if subnet := reply.Subnets[0]; subnet.Threshold != 1 || len(subnet.ControlKeys) != 1 || subnet.ControlKeys[0] != newControlAddr {
+		t.Fatalf("subnet should be owned by %s but is owned by %v with threshold %d", newControlAddr, subnet.ControlKeys, subnet.Threshold)
+	}
+
+	// The old owner can no longer manage the subnet
+	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	if _, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		keys[0].PublicKey().Address(),
+		testSubnet1.ID(),
+		oldControlKeys,
+	); err == nil {
+		t.Fatal("should have errored because the old owner no longer controls the subnet")
+	}
+	if _, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		avm.ID,
+		nil,
+		"yeet",
+		oldControlKeys,
+	); err == nil {
+		t.Fatal("should have errored because the old owner no longer controls the subnet")
+	}
+
+	// The new owner can
+	addVdrTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		keys[0].PublicKey().Address(),
+		testSubnet1.ID(),
+		[]*crypto.PrivateKeySECP256K1R{newControlKey, keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := addVdrTx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, addVdrTx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -39,7 +39,8 @@ func (v *Validator) Duration() time.Duration { return v.EndTime().Sub(v.StartTim
 // Weight is this validator's weight when sampling
 func (v *Validator) Weight() uint64 { return v.Wght }
 
-// Verify validates the ID for this validator
+// Verify validates the ID for this validator. The staking parameters may
+// further restrict the staking duration.
 func (v *Validator) Verify() error {
 	duration := v.Duration()
 	switch {
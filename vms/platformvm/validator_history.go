@@ -0,0 +1,351 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// The history of the validator sets is stored as a diff per subnet per
+// accepted block. A diff is the net change of the weight of each validator of
+// the subnet when the block was accepted.
+//
+// When a staker is added to or removed from a current validator set, the
+// change is written to the pending validator changes in the same database.
+// When a block is accepted, its pending changes are summed into the block's
+// diffs.
+//
+// The validator set at height H is computed by undoing the diffs of the
+// blocks after H from the current validator set.
+
+const (
+	pendingVdrChangeDBPrefix = "pendingVdrChange"
+	vdrWeightDiffDBPrefix    = "vdrWeightDiff"
+	vdrHistoryStartDBPrefix  = "vdrHistoryStart"
+)
+
+var (
+	errHeightNotAccepted   = errors.New("height hasn't been accepted yet")
+	errHeightBeforeHistory = errors.New("validator sets before this height weren't recorded")
+
+	_ validators.State = &VM{}
+)
+
+// validatorChange is a change to a current validator set that hasn't been
+// included in a diff yet
+type validatorChange struct {
+	SubnetID ids.ID      `serialize:"true"`
+	NodeID   ids.ShortID `serialize:"true"`
+	Weight   uint64      `serialize:"true"`
+	// True if [Weight] was added to the validator
+	Added bool `serialize:"true"`
+}
+
+// weightDiff is the net change of a validator's weight
+type weightDiff struct {
+	Decrease bool   `serialize:"true"`
+	Amount   uint64 `serialize:"true"`
+}
+
+// add [amount] to the weight change if [decrease] is false, otherwise subtract
+// it
+func (d *weightDiff) add(decrease bool, amount uint64) error {
+	if d.Decrease == decrease {
+		var err error
+		d.Amount, err = safemath.Add64(d.Amount, amount)
+		return err
+	}
+	if d.Amount >= amount {
+		d.Amount -= amount
+		return nil
+	}
+	d.Decrease = decrease
+	d.Amount = amount - d.Amount
+	return nil
+}
+
+// Persist that the staker [tx] was added to, or removed from, the current
+// validator set of subnet [subnetID]
+func (vm *VM) putValidatorChange(db database.Database, subnetID ids.ID, tx *rewardTx, added bool) error {
+	var (
+		nodeID ids.ShortID
+		weight uint64
+	)
+	switch staker := tx.Tx.UnsignedTx.(type) {
+	case *UnsignedAddDelegatorTx:
+		nodeID = staker.Validator.NodeID
+		weight = staker.Validator.Weight()
+	case *UnsignedAddValidatorTx:
+		nodeID = staker.Validator.NodeID
+		weight = staker.Validator.Weight()
+	case *UnsignedAddSubnetValidatorTx:
+		nodeID = staker.Validator.NodeID
+		var err error
+		if weight, err = vm.getSubnetValidatorWeight(db, staker); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("staker is unexpected type %T", tx.Tx.UnsignedTx)
+	}
+	// A staker is added and removed at most once, so the key is unique
+	return vm.putPendingValidatorChange(db, tx.Tx.ID(), &validatorChange{
+		SubnetID: subnetID,
+		NodeID:   nodeID,
+		Weight:   weight,
+		Added:    added,
+	})
+}
+
+// Persist that the weight of validator [nodeID] of subnet [subnetID] was
+// changed from [oldWeight] to [newWeight] by tx [txID]
+func (vm *VM) putValidatorWeightChange(
+	db database.Database,
+	txID ids.ID,
+	subnetID ids.ID,
+	nodeID ids.ShortID,
+	oldWeight uint64,
+	newWeight uint64,
+) error {
+	change := &validatorChange{
+		SubnetID: subnetID,
+		NodeID:   nodeID,
+		Weight:   newWeight - oldWeight,
+		Added:    true,
+	}
+	if newWeight < oldWeight {
+		change.Weight = oldWeight - newWeight
+		change.Added = false
+	}
+	// A tx is accepted at most once, so the key is unique
+	return vm.putPendingValidatorChange(db, txID, change)
+}
+
+// Persist [change], keyed by [changeID] and whether weight was added
+func (vm *VM) putPendingValidatorChange(db database.Database, changeID ids.ID, change *validatorChange) error {
+	changeBytes, err := Codec.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	changeDB := prefixdb.NewNested([]byte(pendingVdrChangeDBPrefix), db)
+	defer changeDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, hashing.HashLen+wrappers.BoolLen)}
+	p.PackFixedBytes(changeID.Bytes())
+	p.PackBool(change.Added)
+	return changeDB.Put(p.Bytes, changeBytes)
+}
+
+// indexValidatorChanges sums the pending validator changes in [db] into the
+// diffs of the block at [height], which is being accepted
+func (vm *VM) indexValidatorChanges(db database.Database, height uint64) error {
+	changeDB := prefixdb.NewNested([]byte(pendingVdrChangeDBPrefix), db)
+	defer changeDB.Close()
+
+	// Key: Subnet ID
+	// Value: Node ID key -> The node's weight diff
+	diffs := make(map[[32]byte]map[[20]byte]*weightDiff)
+	changeKeys := [][]byte(nil)
+	iter := changeDB.NewIterator()
+	for iter.Next() {
+		change := validatorChange{}
+		if err := Codec.Unmarshal(iter.Value(), &change); err != nil {
+			iter.Release()
+			return err
+		}
+		subnetDiffs, ok := diffs[change.SubnetID.Key()]
+		if !ok {
+			subnetDiffs = make(map[[20]byte]*weightDiff)
+			diffs[change.SubnetID.Key()] = subnetDiffs
+		}
+		diff, ok := subnetDiffs[change.NodeID.Key()]
+		if !ok {
+			diff = &weightDiff{}
+			subnetDiffs[change.NodeID.Key()] = diff
+		}
+		if err := diff.add(!change.Added, change.Weight); err != nil {
+			iter.Release()
+			return err
+		}
+		changeKeys = append(changeKeys, iter.Key())
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range changeKeys {
+		if err := changeDB.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	diffDB := prefixdb.NewNested([]byte(vdrWeightDiffDBPrefix), db)
+	defer diffDB.Close()
+	for subnetKey, subnetDiffs := range diffs {
+		subnetDiffDB := prefixdb.NewNested(subnetKey[:], diffDB)
+		for nodeKey, diff := range subnetDiffs {
+			if diff.Amount == 0 {
+				continue
+			}
+			diffBytes, err := Codec.Marshal(diff)
+			if err != nil {
+				subnetDiffDB.Close()
+				return err
+			}
+			p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+hashing.AddrLen)}
+			p.PackLong(height)
+			p.PackFixedBytes(nodeKey[:])
+			if err := subnetDiffDB.Put(p.Bytes, diffBytes); err != nil {
+				subnetDiffDB.Close()
+				return err
+			}
+		}
+		if err := subnetDiffDB.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Persist that the validator sets are recorded from [height] onward
+func (vm *VM) putValidatorHistoryStart(db database.Database, height uint64) error {
+	startDB := prefixdb.NewNested([]byte(vdrHistoryStartDBPrefix), db)
+	defer startDB.Close()
+
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(height)
+	return startDB.Put([]byte(vdrHistoryStartDBPrefix), p.Bytes)
+}
+
+// Returns the height the validator sets are recorded from
+func (vm *VM) getValidatorHistoryStart(db database.Database) (uint64, error) {
+	startDB := prefixdb.NewNested([]byte(vdrHistoryStartDBPrefix), db)
+	defer startDB.Close()
+
+	heightBytes, err := startDB.Get([]byte(vdrHistoryStartDBPrefix))
+	if err != nil {
+		return 0, err
+	}
+	p := wrappers.Packer{Bytes: heightBytes}
+	height := p.UnpackLong()
+	return height, p.Err
+}
+
+// GetCurrentHeight implements the validators.State interface
+func (vm *VM) GetCurrentHeight() (uint64, error) {
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		return 0, err
+	}
+	return lastAccepted.Height(), nil
+}
+
+// GetValidatorSet implements the validators.State interface
+func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (validators.Set, error) {
+	currentHeight, err := vm.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height > currentHeight {
+		return nil, errHeightNotAccepted
+	}
+	startHeight, err := vm.getValidatorHistoryStart(vm.DB)
+	if err != nil {
+		return nil, err
+	}
+	if height < startHeight {
+		return nil, errHeightBeforeHistory
+	}
+
+	currentVdrs, err := vm.getCurrentValidatorSet(vm.DB, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	if height == currentHeight {
+		return currentVdrs, nil
+	}
+
+	// Undo the diffs of the blocks after [height]
+	// Key: Node ID key -> The node's weight diff since [height]
+	diffs := make(map[[20]byte]*weightDiff)
+	diffDB := prefixdb.NewNested([]byte(vdrWeightDiffDBPrefix), vm.DB)
+	defer diffDB.Close()
+	subnetDiffDB := prefixdb.NewNested(subnetID.Bytes(), diffDB)
+	defer subnetDiffDB.Close()
+
+	start := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	start.PackLong(height + 1)
+	iter := subnetDiffDB.NewIteratorWithStart(start.Bytes)
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != wrappers.LongLen+hashing.AddrLen {
+			return nil, errDBCorrupted
+		}
+		nodeKey := [20]byte{}
+		copy(nodeKey[:], key[wrappers.LongLen:])
+
+		diff := weightDiff{}
+		if err := Codec.Unmarshal(iter.Value(), &diff); err != nil {
+			return nil, err
+		}
+		nodeDiff, ok := diffs[nodeKey]
+		if !ok {
+			nodeDiff = &weightDiff{}
+			diffs[nodeKey] = nodeDiff
+		}
+		if err := nodeDiff.add(diff.Decrease, diff.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	vdrs := validators.NewSet()
+	for _, vdr := range currentVdrs.List() {
+		nodeID := vdr.ID()
+		weight := vdr.Weight()
+		if diff, ok := diffs[nodeID.Key()]; ok {
+			// Undoing a decrease increases the weight
+			undo := weightDiff{Amount: weight}
+			if err := undo.add(!diff.Decrease, diff.Amount); err != nil {
+				return nil, err
+			}
+			if undo.Decrease {
+				return nil, errDBCorrupted
+			}
+			weight = undo.Amount
+			delete(diffs, nodeID.Key())
+		}
+		if err := vdrs.AddWeight(nodeID, weight); err != nil {
+			return nil, err
+		}
+	}
+	// The remaining validators were removed after [height]
+	for nodeKey, diff := range diffs {
+		if diff.Amount == 0 {
+			continue
+		}
+		if !diff.Decrease {
+			return nil, errDBCorrupted
+		}
+		if err := vdrs.AddWeight(ids.NewShortID(nodeKey), diff.Amount); err != nil {
+			return nil, err
+		}
+	}
+	return vdrs, nil
+}
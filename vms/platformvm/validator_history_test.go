@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestWeightDiffAdd(t *testing.T) {
+	diff := weightDiff{}
+	assert.NoError(t, diff.add(false, 5))
+	assert.Equal(t, weightDiff{Amount: 5}, diff)
+	assert.NoError(t, diff.add(true, 2))
+	assert.Equal(t, weightDiff{Amount: 3}, diff)
+	assert.NoError(t, diff.add(true, 7))
+	assert.Equal(t, weightDiff{Decrease: true, Amount: 4}, diff)
+	assert.NoError(t, diff.add(false, 4))
+	assert.Zero(t, diff.Amount)
+	assert.Error(t, (&weightDiff{Amount: 1}).add(false, ^uint64(0)))
+}
+
+func TestGetValidatorSet(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.SetPreference(vm.LastAccepted())
+
+	genesisVdrs, err := vm.GetValidatorSet(0, constants.PrimaryNetworkID)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), genesisVdrs.Len())
+	assert.Equal(t, uint64(len(keys))*defaultWeight, genesisVdrs.Weight())
+
+	startHeight, err := vm.GetCurrentHeight()
+	assert.NoError(t, err)
+
+	// Advance time to the end of the genesis validators' staking period, then
+	// remove one of them
+	vm.clock.Set(defaultValidateEndTime)
+	for i := 0; i < 2; i++ {
+		blk, err := vm.BuildBlock()
+		assert.NoError(t, err)
+		assert.NoError(t, blk.Verify())
+		options, err := blk.(*ProposalBlock).Options()
+		assert.NoError(t, err)
+		assert.NoError(t, blk.Accept())
+		assert.NoError(t, options[0].Verify())
+		assert.NoError(t, options[0].Accept())
+		vm.SetPreference(vm.LastAccepted())
+	}
+
+	currentHeight, err := vm.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, startHeight+4, currentHeight)
+
+	currentVdrs, err := vm.GetValidatorSet(currentHeight, constants.PrimaryNetworkID)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys)-1, currentVdrs.Len())
+
+	// The removed validator is in the validator set before it was removed
+	for height := uint64(0); height < currentHeight; height++ {
+		vdrs, err := vm.GetValidatorSet(height, constants.PrimaryNetworkID)
+		assert.NoError(t, err)
+		assert.Equal(t, genesisVdrs.Len(), vdrs.Len(), "unexpected validator set at height %d", height)
+		for _, vdr := range genesisVdrs.List() {
+			weight, ok := vdrs.GetWeight(vdr.ID())
+			assert.True(t, ok)
+			assert.Equal(t, vdr.Weight(), weight)
+		}
+	}
+
+	// The subnet's validator set is unaffected
+	subnetVdrs, err := vm.GetValidatorSet(startHeight, testSubnet1.ID())
+	assert.NoError(t, err)
+	assert.Zero(t, subnetVdrs.Len())
+
+	_, err = vm.GetValidatorSet(currentHeight+1, constants.PrimaryNetworkID)
+	assert.Equal(t, errHeightNotAccepted, err)
+
+	service := &Service{vm: vm}
+	reply := GetValidatorsAtReply{}
+	assert.NoError(t, service.GetValidatorsAt(nil, &GetValidatorsAtArgs{}, &reply))
+	assert.Len(t, reply.Validators, len(keys))
+	for _, key := range keys {
+		nodeID := key.PublicKey().Address().PrefixedString(constants.NodeIDPrefix)
+		assert.EqualValues(t, defaultWeight, reply.Validators[nodeID])
+	}
+}
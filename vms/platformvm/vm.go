@@ -59,6 +59,10 @@ const (
 
 	droppedTxCacheSize = 50
 
+	// gossipedTxCacheSize is the number of recently gossiped transactions that
+	// are remembered, so that they aren't parsed and verified again
+	gossipedTxCacheSize = 1024
+
 	maxUTXOsToFetch = 1024
 
 	// TODO: Turn these constants into governable parameters
@@ -80,6 +84,14 @@ const (
 	// MaximumStakingDuration is the longest amount of time a staker can bond
 	// their funds for.
 	MaximumStakingDuration = 365 * 24 * time.Hour
+
+	// MinimumValidatorStake is the minimum amount of DJTX a validator of the
+	// primary network must stake
+	MinimumValidatorStake = 5 * units.MilliDjtx
+
+	// MinimumDelegatorStake is the minimum amount of DJTX a delegator must
+	// stake
+	MinimumDelegatorStake = 5 * units.MilliDjtx
 )
 
 var (
@@ -95,8 +107,10 @@ var (
 	errInvalidID                = errors.New("invalid ID")
 	errDSCantValidate           = errors.New("new blockchain can't be validated by primary network")
 	errUnknownTxType            = errors.New("unknown transaction type")
+	errGossipRateExceeded       = errors.New("peer exceeded its transaction gossip rate")
 
 	_ block.ChainVM        = &VM{}
+	_ block.TxGossiper     = &VM{}
 	_ validators.Connector = &VM{}
 )
 
@@ -140,6 +154,10 @@ func init() {
 
 		Codec.RegisterType(&StakeableLockIn{}),
 		Codec.RegisterType(&StakeableLockOut{}),
+
+		Codec.RegisterType(&UnsignedRemoveSubnetValidatorTx{}),
+		Codec.RegisterType(&UnsignedTransferSubnetOwnershipTx{}),
+		Codec.RegisterType(&UnsignedSetRestakeTx{}),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -174,15 +192,13 @@ type VM struct {
 	currentBlocks map[[32]byte]Block
 
 	// Transactions that have not been put into blocks yet
-	unissuedProposalTxs *EventHeap
-	unissuedDecisionTxs []*Tx
-	unissuedAtomicTxs   []*Tx
+	mempool Mempool
 
 	// Tx fee burned by a transaction
 	txFee uint64
 
-	// The minimum amount of tokens one must bond to be a staker
-	minStake uint64
+	// The economic parameters of staking, as defined in the genesis
+	stakingParams StakingParameters
 
 	// UptimePercentage is the minimum uptime required to be rewarded for
 	// staking.
@@ -197,11 +213,29 @@ type VM struct {
 	// to see if it was later committed/aborted before reporting that it's dropped
 	droppedTxCache cache.LRU
 
+	// Contains the IDs of transactions recently gossiped to this node
+	gossipedTxCache cache.LRU
+
+	// Limits the rate at which each peer may gossip transactions to this node
+	gossipLimiter gossipLimiter
+
 	// Bootstrapped remembers if this chain has finished bootstrapping or not
 	bootstrapped bool
 
 	bootstrappedTime time.Time
 
+	// The state is summarized once a decision block at a multiple of this
+	// height is accepted
+	stateSummaryInterval uint64
+
+	// The state sync in progress, if any
+	stateSync *stateSync
+	// Holds a value while the state is being summarized
+	summarizing chan struct{}
+	summaryWG   sync.WaitGroup
+	// Closed once the VM shuts down
+	closing chan struct{}
+
 	uptimeLock  sync.Mutex
 	connections map[[20]byte]time.Time
 }
@@ -230,21 +264,39 @@ func (vm *VM) Initialize(
 	vm.codec = Codec
 
 	vm.droppedTxCache = cache.LRU{Size: droppedTxCacheSize}
+	vm.gossipedTxCache = cache.LRU{Size: gossipedTxCacheSize}
+	vm.gossipLimiter.Initialize(&vm.clock)
+	if vm.stateSummaryInterval == 0 {
+		vm.stateSummaryInterval = defaultStateSummaryInterval
+	}
+	vm.summarizing = make(chan struct{}, 1)
+	vm.closing = make(chan struct{})
 	vm.connections = make(map[[20]byte]time.Time)
 
 	// Register this VM's types with the database so we can get/put structs to/from it
 	vm.registerDBTypes()
 
+	genesis, err := ParseGenesis(genesisBytes)
+	if err != nil {
+		return err
+	}
+
+	// The staking parameters are read from the genesis on every start so that
+	// every node on the network uses the same values
+	if err := genesis.StakingParameters.Verify(); err != nil {
+		return fmt.Errorf("invalid staking parameters in genesis: %w", err)
+	}
+	vm.stakingParams = genesis.StakingParameters
+
 	// If the database is empty, create the platform chain anew using
 	// the provided genesis state
 	if !vm.DBInitialized() {
-		genesis := &Genesis{}
-		if err := Codec.Unmarshal(genesisBytes, genesis); err != nil {
-			return err
-		}
 		if err := genesis.Initialize(); err != nil {
 			return err
 		}
+		if InitialSupply > vm.stakingParams.SupplyCap {
+			return errInitialSupplyAboveCap
+		}
 
 		// Persist UTXOs that exist at genesis
 		for _, utxo := range genesis.UTXOs {
@@ -309,13 +361,19 @@ func (vm *VM) Initialize(
 		if err != nil {
 			return err
 		}
-		if err := vm.State.PutBlock(vm.DB, genesisBlock); err != nil {
+		if err := vm.putBlock(vm.DB, genesisBlock); err != nil {
 			return err
 		}
 		genesisBlock.onAcceptDB = versiondb.New(vm.DB)
 		if err := genesisBlock.CommonBlock.Accept(); err != nil {
 			return fmt.Errorf("error accepting genesis block: %w", err)
 		}
+		if err := vm.indexValidatorChanges(vm.DB, 0); err != nil {
+			return err
+		}
+		if err := vm.putValidatorHistoryStart(vm.DB, 0); err != nil {
+			return err
+		}
 
 		if err := vm.SetDBInitialized(); err != nil {
 			return fmt.Errorf("error while setting db to initialized: %w", err)
@@ -328,7 +386,7 @@ func (vm *VM) Initialize(
 
 	// Transactions from clients that have not yet been put into blocks
 	// and added to consensus
-	vm.unissuedProposalTxs = &EventHeap{SortByStartTime: true}
+	vm.mempool.Initialize(vm)
 
 	vm.currentBlocks = make(map[[32]byte]Block)
 	vm.timer = timer.NewTimer(func() {
@@ -367,29 +425,147 @@ func (vm *VM) Initialize(
 		return errInvalidLastAcceptedBlock
 	}
 
+	// Index the blocks that were accepted before blocks were indexed by height
+	if err := vm.indexAcceptedBlocks(lastAcceptedIntf); err != nil {
+		return err
+	}
+	// Index the timestamps and txs of the blocks that were accepted before
+	// they were indexed
+	genesisTimestamp := time.Unix(int64(genesis.Timestamp), 0)
+	if err := vm.indexBlocks(vm.DB, lastAcceptedIntf.Height(), genesisTimestamp); err != nil {
+		return err
+	}
+
+	// Index the delegators that were added before delegators were indexed by
+	// the node they delegate to
+	if err := vm.indexDelegators(vm.DB); err != nil {
+		return err
+	}
+	// Index the primary network stakers that were added before stakers were
+	// indexed by the ID of the tx that added them
+	if err := vm.indexPrimaryNetworkStakers(vm.DB); err != nil {
+		return err
+	}
+	// Record the keys of the blocks that were stored before the keys of blocks
+	// were recorded, and index the atomic txs that were accepted before atomic
+	// txs were indexed
+	if err := vm.indexBlockKeys(vm.DB); err != nil {
+		return err
+	}
+	if err := vm.indexAtomicTxs(vm.DB, lastAcceptedIntf.Height()); err != nil {
+		return err
+	}
+	if err := vm.DB.Commit(); err != nil {
+		return err
+	}
+	// Finish applying the atomic txs accepted while the state was synced, if
+	// the node shut down while applying them
+	if err := vm.replayAtomicTxs(); err != nil {
+		return err
+	}
+
+	// The validator sets before the validator set history was recorded
+	// can't be computed, so the history starts at the last accepted block
+	if _, err := vm.getValidatorHistoryStart(vm.DB); err == database.ErrNotFound {
+		if err := vm.putValidatorHistoryStart(vm.DB, lastAcceptedIntf.Height()); err != nil {
+			return err
+		}
+		return vm.DB.Commit()
+	} else if err != nil {
+		return err
+	}
 	return nil
 }
 
-// Queue [tx] to be put into a block
+// indexAcceptedBlocks indexes [blk], and its ancestors, by height. Stops at the
+// first block that has already been indexed.
+// [blk] must be accepted.
+func (vm *VM) indexAcceptedBlocks(blk Block) error {
+	numIndexed := 0
+	for {
+		height := blk.Height()
+		if _, err := vm.getAcceptedBlockID(vm.DB, height); err == nil {
+			break
+		} else if err != database.ErrNotFound {
+			return err
+		}
+		if err := vm.putAcceptedBlockID(vm.DB, height, blk.ID()); err != nil {
+			return err
+		}
+		numIndexed++
+
+		if height == 0 {
+			break
+		}
+		parent := blk.parentBlock()
+		if parent == nil {
+			return fmt.Errorf("couldn't get parent of accepted block %s", blk.ID())
+		}
+		blk = parent
+	}
+	if numIndexed == 0 {
+		return nil
+	}
+	vm.Ctx.Log.Info("indexed %d accepted blocks by height", numIndexed)
+	return vm.DB.Commit()
+}
+
+// Queue [tx] to be put into a block and gossip it to the network
 func (vm *VM) issueTx(tx *Tx) error {
 	// Initialize the transaction
 	if err := tx.Sign(vm.codec, nil); err != nil {
 		return err
 	}
-	switch tx.UnsignedTx.(type) {
-	case TimedTx:
-		vm.unissuedProposalTxs.Add(tx)
-	case UnsignedDecisionTx:
-		vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
-	case UnsignedAtomicTx:
-		vm.unissuedAtomicTxs = append(vm.unissuedAtomicTxs, tx)
-	default:
-		return errUnknownTxType
+	if err := vm.mempool.Add(tx); err != nil {
+		return err
 	}
 	vm.resetTimer()
+	vm.SnowmanVM.NotifyGossipTxs()
 	return nil
 }
 
+// PendingGossipTxs implements the block.TxGossiper interface
+func (vm *VM) PendingGossipTxs() [][]byte {
+	txs := vm.mempool.PopGossipTxs()
+	txsBytes := make([][]byte, len(txs))
+	for i, tx := range txs {
+		txsBytes[i] = tx.Bytes()
+	}
+	return txsBytes
+}
+
+// GossipedTx implements the block.TxGossiper interface
+func (vm *VM) GossipedTx(validatorID ids.ShortID, txBytes []byte) error {
+	// Transactions can't be verified against the state until it's up to date
+	if !vm.bootstrapped {
+		return nil
+	}
+	// Transactions that were recently gossiped have already been handled
+	txID := ids.NewID(hashing.ComputeHash256Array(txBytes))
+	if _, ok := vm.gossipedTxCache.Get(txID); ok {
+		return nil
+	}
+	if !vm.gossipLimiter.Allow(validatorID) {
+		return errGossipRateExceeded
+	}
+	vm.gossipedTxCache.Put(txID, nil)
+
+	tx := &Tx{}
+	if err := vm.codec.Unmarshal(txBytes, tx); err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+	// Initialize the transaction
+	if err := tx.Sign(vm.codec, nil); err != nil {
+		return err
+	}
+	// Transactions that are already in the mempool have already been gossiped
+	if vm.mempool.Has(tx.ID()) {
+		return nil
+	}
+	vm.Ctx.Log.Verbo("adding tx %s gossiped by %s to the mempool", tx.ID(), validatorID)
+	return vm.issueTx(tx)
+}
+
 // Create all chains that exist that this node validates
 // Can only be called after initSubnets()
 func (vm *VM) initBlockchains() error {
@@ -529,6 +705,10 @@ func (vm *VM) Shutdown() error {
 	vm.timer.Stop()
 	vm.Ctx.Lock.Lock()
 
+	// Stop summarizing the state
+	close(vm.closing)
+	vm.summaryWG.Wait()
+
 	stopPrefix := []byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, stopDBPrefix))
 	stopDB := prefixdb.NewNested(stopPrefix, vm.DB)
 	defer stopDB.Close()
@@ -613,13 +793,8 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	preferredID := vm.Preferred()
 
 	// If there are pending decision txs, build a block with a batch of them
-	if len(vm.unissuedDecisionTxs) > 0 {
-		numTxs := BatchSize
-		if numTxs > len(vm.unissuedDecisionTxs) {
-			numTxs = len(vm.unissuedDecisionTxs)
-		}
-		var txs []*Tx
-		txs, vm.unissuedDecisionTxs = vm.unissuedDecisionTxs[:numTxs], vm.unissuedDecisionTxs[numTxs:]
+	if vm.mempool.HasDecisionTxs() {
+		txs := vm.mempool.PopDecisionTxs(BatchSize)
 		blk, err := vm.newStandardBlock(preferredID, preferredHeight+1, txs)
 		if err != nil {
 			vm.resetTimer()
@@ -629,7 +804,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 			vm.resetTimer()
 			return nil, err
 		}
-		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
+		if err := vm.putBlock(vm.DB, blk); err != nil {
 			vm.resetTimer()
 			return nil, err
 		}
@@ -637,9 +812,8 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	}
 
 	// If there is a pending atomic tx, build a block with it
-	if len(vm.unissuedAtomicTxs) > 0 {
-		tx := vm.unissuedAtomicTxs[0]
-		vm.unissuedAtomicTxs = vm.unissuedAtomicTxs[1:]
+	if vm.mempool.HasAtomicTxs() {
+		tx := vm.mempool.PopAtomicTx()
 		blk, err := vm.newAtomicBlock(preferredID, preferredHeight+1, *tx)
 		if err != nil {
 			return nil, err
@@ -648,7 +822,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 			vm.resetTimer()
 			return nil, err
 		}
-		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
+		if err := vm.putBlock(vm.DB, blk); err != nil {
 			return nil, err
 		}
 		return blk, vm.DB.Commit()
@@ -697,7 +871,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		if err != nil {
 			return nil, err
 		}
-		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
+		if err := vm.putBlock(vm.DB, blk); err != nil {
 			return nil, err
 		}
 		return blk, vm.DB.Commit()
@@ -720,7 +894,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		if err != nil {
 			return nil, err
 		}
-		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
+		if err := vm.putBlock(vm.DB, blk); err != nil {
 			return nil, err
 		}
 		return blk, vm.DB.Commit()
@@ -729,20 +903,17 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	// Propose adding a new validator but only if their start time is in the
 	// future relative to local time (plus Delta)
 	syncTime := localTime.Add(Delta)
-	for vm.unissuedProposalTxs.Len() > 0 {
-		tx := vm.unissuedProposalTxs.Remove()
-		utx := tx.UnsignedTx.(TimedTx)
-		if !syncTime.After(utx.StartTime()) {
-			blk, err := vm.newProposalBlock(preferredID, preferredHeight+1, *tx)
-			if err != nil {
-				return nil, err
-			}
-			if err := vm.State.PutBlock(vm.DB, blk); err != nil {
-				return nil, err
-			}
-			return blk, vm.DB.Commit()
+	vm.mempool.DropExpiredProposalTxs(syncTime)
+	if vm.mempool.HasProposalTxs() {
+		tx := vm.mempool.PopProposalTx()
+		blk, err := vm.newProposalBlock(preferredID, preferredHeight+1, *tx)
+		if err != nil {
+			return nil, err
+		}
+		if err := vm.putBlock(vm.DB, blk); err != nil {
+			return nil, err
 		}
-		vm.Ctx.Log.Debug("dropping tx to add validator because start time too late")
+		return blk, vm.DB.Commit()
 	}
 
 	vm.Ctx.Log.Debug("BuildBlock returning error (no blocks)")
@@ -763,7 +934,7 @@ func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
 		// If we have seen this block before, return it with the most up-to-date info
 		return block, nil
 	}
-	if err := vm.State.PutBlock(vm.DB, block); err != nil { // Persist the block
+	if err := vm.putBlock(vm.DB, block); err != nil { // Persist the block
 		return nil, fmt.Errorf("failed to put block due to %w", err)
 	}
 
@@ -791,12 +962,43 @@ func (vm *VM) getBlock(blkID ids.ID) (Block, error) {
 
 // SetPreference sets the preferred block to be the one with ID [blkID]
 func (vm *VM) SetPreference(blkID ids.ID) {
-	if !blkID.Equals(vm.Preferred()) {
+	if oldPreferredID := vm.Preferred(); !blkID.Equals(oldPreferredID) {
 		vm.SnowmanVM.SetPreference(blkID)
+		// Evict the txs that are no longer valid on top of the new preference
+		vm.mempool.Revalidate(oldPreferredID)
 		vm.resetTimer()
 	}
 }
 
+// preferredBlocksSince returns the blocks that are preferred now but weren't
+// when [oldPreferredID] was preferred. Returns an error if [oldPreferredID]
+// isn't an ancestor of, or equal to, the preferred block.
+func (vm *VM) preferredBlocksSince(oldPreferredID ids.ID) ([]Block, error) {
+	if oldPreferredID.IsZero() {
+		return nil, errors.New("no block was preferred")
+	}
+	oldPreferred, err := vm.getBlock(oldPreferredID)
+	if err != nil {
+		return nil, err
+	}
+
+	blk, err := vm.getBlock(vm.Preferred())
+	if err != nil {
+		return nil, err
+	}
+	blocks := []Block(nil)
+	for blk.Height() > oldPreferred.Height() {
+		blocks = append(blocks, blk)
+		if blk, err = vm.getBlock(blk.Parent().ID()); err != nil {
+			return nil, err
+		}
+	}
+	if !blk.ID().Equals(oldPreferredID) {
+		return nil, fmt.Errorf("block %s is no longer preferred", oldPreferredID)
+	}
+	return blocks, nil
+}
+
 // CreateHandlers returns a map where:
 // * keys are API endpoint extensions
 // * values are API handlers
@@ -895,7 +1097,7 @@ func (vm *VM) Disconnected(vdrID ids.ShortID) bool {
 func (vm *VM) resetTimer() {
 	// If there is a pending transaction, trigger building of a block with that
 	// transaction
-	if len(vm.unissuedDecisionTxs) > 0 || len(vm.unissuedAtomicTxs) > 0 {
+	if vm.mempool.HasDecisionTxs() || vm.mempool.HasAtomicTxs() {
 		vm.SnowmanVM.NotifyBlockReady()
 		return
 	}
@@ -946,15 +1148,11 @@ func (vm *VM) resetTimer() {
 		return
 	}
 
-	syncTime := localTime.Add(Delta)
-	for vm.unissuedProposalTxs.Len() > 0 {
-		if !syncTime.After(vm.unissuedProposalTxs.Peek().UnsignedTx.(TimedTx).StartTime()) {
-			vm.SnowmanVM.NotifyBlockReady() // Should issue a ProposeAddValidator
-			return
-		}
-		// If the tx doesn't meet the synchrony bound, drop it
-		vm.unissuedProposalTxs.Remove()
-		vm.Ctx.Log.Debug("dropping tx to add validator because its start time has passed")
+	// If a tx doesn't meet the synchrony bound, drop it
+	vm.mempool.DropExpiredProposalTxs(localTime.Add(Delta))
+	if vm.mempool.HasProposalTxs() {
+		vm.SnowmanVM.NotifyBlockReady() // Should issue a ProposeAddValidator
+		return
 	}
 
 	waitTime := nextStakerChangeTime.Sub(localTime)
@@ -1029,7 +1227,7 @@ func (vm *VM) calculateReward(db database.Database, duration time.Duration, stak
 	if err != nil {
 		return 0, err
 	}
-	reward := Reward(duration, stakeAmount, currentSupply)
+	reward := vm.stakingParams.Reward(duration, stakeAmount, currentSupply)
 	newSupply, err := safemath.Add64(currentSupply, reward)
 	if err != nil {
 		return 0, err
@@ -1213,10 +1411,19 @@ func (vm *VM) updateVdrMgr(force bool) error {
 }
 
 func (vm *VM) updateVdrSet(subnetID ids.ID) error {
+	vdrs, err := vm.getCurrentValidatorSet(vm.DB, subnetID)
+	if err != nil {
+		return err
+	}
+	return vm.vdrMgr.Set(subnetID, vdrs)
+}
+
+// Returns the current validators of subnet [subnetID] in [db]
+func (vm *VM) getCurrentValidatorSet(db database.Database, subnetID ids.ID) (validators.Set, error) {
 	vdrs := validators.NewSet()
 
 	stopPrefix := []byte(fmt.Sprintf("%s%s", subnetID, stopDBPrefix))
-	stopDB := prefixdb.NewNested(stopPrefix, vm.DB)
+	stopDB := prefixdb.NewNested(stopPrefix, db)
 	defer stopDB.Close()
 	stopIter := stopDB.NewIterator()
 	defer stopIter.Release()
@@ -1226,10 +1433,10 @@ func (vm *VM) updateVdrSet(subnetID ids.ID) error {
 
 		tx := rewardTx{}
 		if err := vm.codec.Unmarshal(txBytes, &tx); err != nil {
-			return fmt.Errorf("couldn't unmarshal validator tx: %w", err)
+			return nil, fmt.Errorf("couldn't unmarshal validator tx: %w", err)
 		}
 		if err := tx.Tx.Sign(vm.codec, nil); err != nil {
-			return err
+			return nil, err
 		}
 
 		var err error
@@ -1239,21 +1446,18 @@ func (vm *VM) updateVdrSet(subnetID ids.ID) error {
 		case *UnsignedAddValidatorTx:
 			err = vdrs.AddWeight(staker.Validator.NodeID, staker.Validator.Weight())
 		case *UnsignedAddSubnetValidatorTx:
-			err = vdrs.AddWeight(staker.Validator.NodeID, staker.Validator.Weight())
+			var weight uint64
+			if weight, err = vm.getSubnetValidatorWeight(db, staker); err == nil {
+				err = vdrs.AddWeight(staker.Validator.NodeID, weight)
+			}
 		default:
 			err = fmt.Errorf("expected validator but got %T", tx.Tx.UnsignedTx)
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-
-	errs := wrappers.Errs{}
-	errs.Add(
-		vm.vdrMgr.Set(subnetID, vdrs),
-		stopIter.Error(),
-	)
-	return errs.Err
+	return vdrs, stopIter.Error()
 }
 
 // Codec ...
@@ -38,8 +38,8 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/units"
-	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/components/djtx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/ava-labs/avalanchego/vms/timestampvm"
 
@@ -86,6 +86,11 @@ var (
 const (
 	testNetworkID = 10 // To be used in tests
 	defaultWeight = 10000
+
+	// caps on the weight of validators, and the amount delegated to them, in
+	// defaultVM
+	maxValidatorStake   = 3 * units.MegaDjtx
+	maxDelegationFactor = 4
 )
 
 func init() {
@@ -163,14 +168,20 @@ func defaultGenesis() (*BuildGenesisArgs, []byte) {
 		}
 	}
 
+	stakingParams := DefaultStakingParameters()
+	stakingParams.MaxValidatorStake = maxValidatorStake
+	stakingParams.MaxDelegationFactor = maxDelegationFactor
+	apiStakingParams := NewAPIStakingParameters(stakingParams)
+
 	buildGenesisArgs := BuildGenesisArgs{
-		NetworkID:     json.Uint32(testNetworkID),
-		DjtxAssetID:   djtxAssetID,
-		UTXOs:         genesisUTXOs,
-		Validators:    genesisValidators,
-		Chains:        nil,
-		Time:          json.Uint64(defaultGenesisTime.Unix()),
-		InitialSupply: json.Uint64(360 * units.MegaDjtx),
+		NetworkID:         json.Uint32(testNetworkID),
+		DjtxAssetID:       djtxAssetID,
+		UTXOs:             genesisUTXOs,
+		Validators:        genesisValidators,
+		Chains:            nil,
+		Time:              json.Uint64(defaultGenesisTime.Unix()),
+		InitialSupply:     json.Uint64(360 * units.MegaDjtx),
+		StakingParameters: &apiStakingParams,
 	}
 	// TODO: Remove
 	InitialSupply = 360 * units.MegaDjtx
@@ -188,7 +199,6 @@ func defaultVM() (*VM, database.Database) {
 		SnowmanVM:    &core.SnowmanVM{},
 		chainManager: chains.MockManager{},
 		txFee:        defaultTxFee,
-		minStake:     minStake,
 	}
 
 	baseDB := memdb.New()
@@ -340,7 +350,7 @@ func TestAddValidatorCommit(t *testing.T) {
 
 	// create valid tx
 	tx, err := vm.newAddValidatorTx(
-		vm.minStake,
+		vm.stakingParams.MinValidatorStake,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
 		ID,
@@ -416,7 +426,7 @@ func TestInvalidAddValidatorCommit(t *testing.T) {
 
 	// create invalid tx
 	if tx, err := vm.newAddValidatorTx(
-		vm.minStake,
+		vm.stakingParams.MinValidatorStake,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
 		ID,
@@ -462,7 +472,7 @@ func TestAddValidatorReject(t *testing.T) {
 
 	// create valid tx
 	tx, err := vm.newAddValidatorTx(
-		vm.minStake,
+		vm.stakingParams.MinValidatorStake,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
 		ID,
@@ -1899,7 +1909,7 @@ func TestNextValidatorStartTime(t *testing.T) {
 	endTime := startTime.Add(MinimumStakingDuration)
 
 	tx, err := vm.newAddValidatorTx(
-		vm.minStake,                             // stake amount
+		vm.stakingParams.MinValidatorStake,      // stake amount
 		uint64(startTime.Unix()),                // start time
 		uint64(endTime.Unix()),                  // end time
 		vm.Ctx.NodeID,                           // node ID
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -110,6 +111,56 @@ func (kc *Keychain) Match(owners *OutputOwners, time uint64) ([]uint32, []*crypt
 	return sigs, keys, uint32(len(keys)) == owners.Threshold
 }
 
+// SpendPartial attempts to create an input that is signed by the keys in this
+// keychain along with a subset of [cosigners]. The signatures of the
+// [cosigners] are expected to be provided later.
+func (kc *Keychain) SpendPartial(out verify.Verifiable, time uint64, cosigners ids.ShortSet) (verify.Verifiable, error) {
+	switch out := out.(type) {
+	case *MintOutput:
+		if sigIndices, able := kc.MatchPartial(&out.OutputOwners, time, cosigners); able {
+			return &Input{
+				SigIndices: sigIndices,
+			}, nil
+		}
+		return nil, errCantSpend
+	case *TransferOutput:
+		if sigIndices, able := kc.MatchPartial(&out.OutputOwners, time, cosigners); able {
+			return &TransferInput{
+				Amt: out.Amt,
+				Input: Input{
+					SigIndices: sigIndices,
+				},
+			}, nil
+		}
+		return nil, errCantSpend
+	}
+	return nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
+}
+
+// MatchPartial attempts to match a list of addresses up to the provided
+// threshold. Addresses controlled by this keychain are preferred, the remaining
+// signatures are filled in using addresses in [cosigners].
+func (kc *Keychain) MatchPartial(owners *OutputOwners, time uint64, cosigners ids.ShortSet) ([]uint32, bool) {
+	if time < owners.Locktime {
+		return nil, false
+	}
+	sigs := make([]uint32, 0, owners.Threshold)
+	used := make([]bool, len(owners.Addrs))
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
+		if _, exists := kc.Get(owners.Addrs[i]); exists {
+			sigs = append(sigs, i)
+			used[i] = true
+		}
+	}
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
+		if !used[i] && cosigners.Contains(owners.Addrs[i]) {
+			sigs = append(sigs, i)
+		}
+	}
+	utils.SortUint32(sigs)
+	return sigs, uint32(len(sigs)) == owners.Threshold
+}
+
 // PrefixedString returns the key chain as a string representation with [prefix]
 // added before every line.
 func (kc *Keychain) PrefixedString(prefix string) string {
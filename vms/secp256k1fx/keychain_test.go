@@ -164,6 +164,80 @@ func TestKeychainMatch(t *testing.T) {
 	}
 }
 
+func TestKeychainMatchPartial(t *testing.T) {
+	kc := NewKeychain()
+
+	cb58 := formatting.CB58{}
+	sks := []*crypto.PrivateKeySECP256K1R{}
+	for _, keyStr := range keys {
+		if err := cb58.FromString(keyStr); err != nil {
+			t.Fatal(err)
+		}
+		skBytes := cb58.Bytes
+
+		skIntf, err := kc.factory.ToPrivateKey(skBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk, ok := skIntf.(*crypto.PrivateKeySECP256K1R)
+		if !ok {
+			t.Fatalf("Factory should have returned secp256k1r private key")
+		}
+		sks = append(sks, sk)
+	}
+
+	owners := OutputOwners{
+		Threshold: 2,
+		Addrs: []ids.ShortID{
+			sks[0].PublicKey().Address(),
+			sks[1].PublicKey().Address(),
+			sks[2].PublicKey().Address(),
+		},
+	}
+	owners.Sort()
+	if err := owners.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	kc.Add(sks[2])
+
+	if _, ok := kc.MatchPartial(&owners, 0, ids.ShortSet{}); ok {
+		t.Fatalf("Shouldn't have been able to match with the owners without cosigners")
+	}
+
+	cosigners := ids.ShortSet{}
+	cosigners.Add(
+		sks[0].PublicKey().Address(),
+		sks[1].PublicKey().Address(),
+	)
+
+	indices, ok := kc.MatchPartial(&owners, 0, cosigners)
+	if !ok {
+		t.Fatalf("Should have been able to match with the owners")
+	} else if numIndices := len(indices); numIndices != 2 {
+		t.Fatalf("Should have returned two indices")
+	}
+
+	// The key held by the keychain must always be one of the signers
+	found := false
+	for _, index := range indices {
+		if owners.Addrs[index].Equals(sks[2].PublicKey().Address()) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Should have used the key in the keychain")
+	}
+	if indices[0] >= indices[1] {
+		t.Fatalf("Indices should be sorted")
+	}
+
+	owners.Locktime = 1
+	if _, ok := kc.MatchPartial(&owners, 0, cosigners); ok {
+		t.Fatalf("Shouldn't have been able to match timelocked owners")
+	}
+}
+
 func TestKeychainSpendMint(t *testing.T) {
 	kc := NewKeychain()
 